@@ -204,7 +204,7 @@ loop:
 		case model.FinishReasonError:
 			return messages, fmt.Errorf("error from model: %s", resp.Choice[0].Delta.Content)
 
-		case model.FinishReasonStop:
+		case model.FinishReasonStop, model.FinishReasonLength:
 			messages = append(messages,
 				model.TextMessage("assistant", resp.Choice[0].Delta.Content),
 			)
@@ -8,6 +8,7 @@ import (
 	"github.com/ardanlabs/kronk/cmd/kronk/security/key"
 	"github.com/ardanlabs/kronk/cmd/kronk/security/sec"
 	"github.com/ardanlabs/kronk/cmd/kronk/security/token"
+	"github.com/ardanlabs/kronk/cmd/kronk/security/usage"
 	"github.com/spf13/cobra"
 )
 
@@ -33,4 +34,5 @@ Environment Variables:
 func init() {
 	Cmd.AddCommand(key.Cmd)
 	Cmd.AddCommand(token.Cmd)
+	Cmd.AddCommand(usage.Cmd)
 }
@@ -16,16 +16,19 @@ var Cmd = &cobra.Command{
 Flags:
       --duration     Token duration (e.g., 1h, 24h, 720h)
       --endpoints    Comma-separated list of endpoints with optional rate limits
+      --priority     Highest priority class for the token's requests: low, normal (default) or high
 
 Endpoint format:
-      endpoint                  Unlimited access (default)
-      endpoint:unlimited        Unlimited access (explicit)
-      endpoint:limit/window     Rate limited (window: day, month, year)
+      endpoint                                Unlimited access (default)
+      endpoint:unlimited                      Unlimited access (explicit)
+      endpoint:limit/window                   Rate limited (window: minute, hour, day, month, year)
+      endpoint:limit/window:budget=tokens     Rate and token limited (budget: prompt, completion, tokens)
 
 Examples:
       --endpoints chat-completions,embeddings
       --endpoints "chat-completions:1000/day,embeddings:unlimited"
       --endpoints "chat-completions:100/month,embeddings:500/year"
+      --endpoints "chat-completions:60/minute:tokens=20000:completion=5000"
 
 Environment Variables (web mode - default):
       KRONK_TOKEN         (required when auth enabled)  Authentication token for the kronk server.
@@ -38,6 +41,7 @@ func init() {
 	Cmd.Flags().Bool("local", false, "Run without the model server")
 	Cmd.Flags().String("duration", "", "Token duration (e.g., 1h, 24h, 720h)")
 	Cmd.Flags().StringSlice("endpoints", []string{}, "Endpoints with optional rate limits (e.g., chat-completions:1000/day)")
+	Cmd.Flags().String("priority", "", "Highest priority class for the token's requests (low, normal, high)")
 }
 
 func main(cmd *cobra.Command, args []string) {
@@ -52,6 +56,7 @@ func run(cmd *cobra.Command) error {
 	adminToken := os.Getenv("KRONK_TOKEN")
 	flagDuration, _ := cmd.Flags().GetString("duration")
 	flagEndpoints, _ := cmd.Flags().GetStringSlice("endpoints")
+	flagPriority, _ := cmd.Flags().GetString("priority")
 
 	duration, err := time.ParseDuration(flagDuration)
 	if err != nil {
@@ -67,6 +72,7 @@ func run(cmd *cobra.Command) error {
 		AdminToken: adminToken,
 		Endpoints:  endpoints,
 		Duration:   duration,
+		Priority:   flagPriority,
 	}
 
 	switch local {
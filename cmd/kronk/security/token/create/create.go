@@ -19,12 +19,14 @@ type config struct {
 	AdminToken string
 	Endpoints  map[string]auth.RateLimit
 	Duration   time.Duration
+	Priority   string
 }
 
 func runWeb(cfg config) error {
 	fmt.Println("Token create")
 	fmt.Printf("  Duration: %s\n", cfg.Duration)
 	fmt.Printf("  Endpoints: %v\n", cfg.Endpoints)
+	fmt.Printf("  Priority: %s\n", cfg.Priority)
 
 	url, err := client.DefaultURL("/v1/security/token/create")
 	if err != nil {
@@ -37,6 +39,7 @@ func runWeb(cfg config) error {
 		"admin":     false,
 		"endpoints": cfg.Endpoints,
 		"duration":  cfg.Duration,
+		"priority":  cfg.Priority,
 	}
 
 	cln := client.New(
@@ -64,8 +67,9 @@ func runLocal(cfg config) error {
 	fmt.Println("Token create")
 	fmt.Printf("  Duration: %s\n", cfg.Duration)
 	fmt.Printf("  Endpoints: %v\n", cfg.Endpoints)
+	fmt.Printf("  Priority: %s\n", cfg.Priority)
 
-	token, err := sec.Security.GenerateToken(false, cfg.Endpoints, cfg.Duration)
+	token, err := sec.Security.GenerateToken(false, cfg.Priority, cfg.Endpoints, cfg.Duration)
 	if err != nil {
 		return fmt.Errorf("generate-token: %w", err)
 	}
@@ -79,10 +83,12 @@ func runLocal(cfg config) error {
 // =============================================================================
 
 // parseEndpoints parses endpoint specifications in the format:
-// "endpoint:limit/window" or "endpoint" (defaults to unlimited)
+// "endpoint:limit/window[:budget=tokens...]" or "endpoint" (defaults to
+// unlimited). A budget is prompt, completion or tokens for the total.
 // Examples:
 //   - "chat-completions" -> unlimited
 //   - "chat-completions:1000/day" -> 1000 requests per day
+//   - "chat-completions:60/minute:tokens=20000" -> 60 requests and 20000 tokens per minute
 //   - "embeddings:unlimited" -> unlimited
 func parseEndpoints(specs []string) (map[string]auth.RateLimit, error) {
 	result := make(map[string]auth.RateLimit)
@@ -100,7 +106,7 @@ func parseEndpoints(specs []string) (map[string]auth.RateLimit, error) {
 }
 
 func parseEndpointSpec(spec string) (string, auth.RateLimit, error) {
-	parts := strings.SplitN(spec, ":", 2)
+	parts := strings.Split(spec, ":")
 	name := strings.TrimSpace(parts[0])
 
 	if name == "" {
@@ -114,6 +120,10 @@ func parseEndpointSpec(spec string) (string, auth.RateLimit, error) {
 	limitSpec := strings.TrimSpace(parts[1])
 
 	if limitSpec == "unlimited" {
+		if len(parts) > 2 {
+			return "", auth.RateLimit{}, fmt.Errorf("token budgets need a limit/window")
+		}
+
 		return name, auth.RateLimit{Limit: 0, Window: auth.RateUnlimited}, nil
 	}
 
@@ -132,11 +142,53 @@ func parseEndpointSpec(spec string) (string, auth.RateLimit, error) {
 		return "", auth.RateLimit{}, err
 	}
 
-	return name, auth.RateLimit{Limit: limit, Window: window}, nil
+	rateLimit := auth.RateLimit{Limit: limit, Window: window}
+
+	for _, budgetSpec := range parts[2:] {
+		if err := parseBudget(strings.TrimSpace(budgetSpec), &rateLimit); err != nil {
+			return "", auth.RateLimit{}, err
+		}
+	}
+
+	return name, rateLimit, nil
+}
+
+func parseBudget(s string, rateLimit *auth.RateLimit) error {
+	budgetParts := strings.SplitN(s, "=", 2)
+	if len(budgetParts) != 2 {
+		return fmt.Errorf("expected format budget=tokens (e.g., tokens=100000)")
+	}
+
+	tokens, err := strconv.Atoi(strings.TrimSpace(budgetParts[1]))
+	if err != nil || tokens < 0 {
+		return fmt.Errorf("invalid token budget %q", budgetParts[1])
+	}
+
+	switch strings.ToLower(strings.TrimSpace(budgetParts[0])) {
+	case "prompt":
+		rateLimit.PromptTokens = tokens
+
+	case "completion":
+		rateLimit.CompletionTokens = tokens
+
+	case "tokens":
+		rateLimit.TotalTokens = tokens
+
+	default:
+		return fmt.Errorf("invalid budget %q: must be prompt, completion, or tokens", budgetParts[0])
+	}
+
+	return nil
 }
 
 func parseWindow(s string) (auth.RateWindow, error) {
 	switch strings.ToLower(s) {
+	case "minute":
+		return auth.RateMinute, nil
+
+	case "hour":
+		return auth.RateHour, nil
+
 	case "day":
 		return auth.RateDay, nil
 
@@ -150,6 +202,6 @@ func parseWindow(s string) (auth.RateWindow, error) {
 		return auth.RateUnlimited, nil
 
 	default:
-		return "", fmt.Errorf("invalid window %q: must be day, month, year, or unlimited", s)
+		return "", fmt.Errorf("invalid window %q: must be minute, hour, day, month, year, or unlimited", s)
 	}
 }
@@ -0,0 +1,65 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show the usage ledger",
+	Long: `Show the usage ledger - requests, tokens and latency per subject, model,
+endpoint and day.
+
+Flags:
+      --subject    Only show the usage for this token subject
+      --from       First day to include (YYYY-MM-DD, default first day of the month)
+      --to         Last day to include (YYYY-MM-DD, default today)
+
+Environment Variables (web mode - default):
+      KRONK_TOKEN         (required when auth enabled)  Authentication token for the kronk server.
+      KRONK_WEB_API_HOST  (default localhost:8080)  IP Address for the kronk server.`,
+	Args: cobra.NoArgs,
+	Run:  main,
+}
+
+func init() {
+	Cmd.Flags().Bool("local", false, "Run without the model server")
+	Cmd.Flags().String("subject", "", "Only show the usage for this token subject")
+	Cmd.Flags().String("from", "", "First day to include (YYYY-MM-DD)")
+	Cmd.Flags().String("to", "", "Last day to include (YYYY-MM-DD)")
+}
+
+func main(cmd *cobra.Command, args []string) {
+	if err := run(cmd); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func run(cmd *cobra.Command) error {
+	local, _ := cmd.Flags().GetBool("local")
+	subject, _ := cmd.Flags().GetString("subject")
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+
+	cfg, err := newConfig(subject, from, to)
+	if err != nil {
+		return err
+	}
+
+	switch local {
+	case true:
+		err = runLocal(cfg)
+	default:
+		err = runWeb(cfg)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+// Package usage provides the security usage command code.
+package usage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ardanlabs/kronk/cmd/kronk/client"
+	"github.com/ardanlabs/kronk/cmd/kronk/security/sec"
+	"github.com/ardanlabs/kronk/cmd/server/app/domain/toolapp"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/ledger"
+)
+
+type config struct {
+	Subject string
+	From    time.Time
+	To      time.Time
+}
+
+func newConfig(subject string, from string, to string) (config, error) {
+	now := time.Now().UTC()
+
+	cfg := config{
+		Subject: subject,
+		From:    time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		To:      now,
+	}
+
+	if from != "" {
+		t, err := time.Parse(ledger.DayFormat, from)
+		if err != nil {
+			return config{}, fmt.Errorf("parse-from: expected YYYY-MM-DD: %w", err)
+		}
+		cfg.From = t
+	}
+
+	if to != "" {
+		t, err := time.Parse(ledger.DayFormat, to)
+		if err != nil {
+			return config{}, fmt.Errorf("parse-to: expected YYYY-MM-DD: %w", err)
+		}
+		cfg.To = t
+	}
+
+	return cfg, nil
+}
+
+func runWeb(cfg config) error {
+	qs := url.Values{}
+	qs.Set("from", cfg.From.Format(ledger.DayFormat))
+	qs.Set("to", cfg.To.Format(ledger.DayFormat))
+	if cfg.Subject != "" {
+		qs.Set("subject", cfg.Subject)
+	}
+
+	url, err := client.DefaultURL("/v1/usage?" + qs.Encode())
+	if err != nil {
+		return fmt.Errorf("default-url: %w", err)
+	}
+
+	fmt.Println("URL:", url)
+
+	cln := client.New(
+		client.FmtLogger,
+		client.WithBearer(os.Getenv("KRONK_TOKEN")),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var resp toolapp.UsageResponse
+
+	if err := cln.Do(ctx, http.MethodGet, url, nil, &resp); err != nil {
+		return fmt.Errorf("do: unable to list usage: %w", err)
+	}
+
+	printUsage(resp)
+
+	return nil
+}
+
+func runLocal(cfg config) error {
+	filter := ledger.Filter{
+		Subject: cfg.Subject,
+		From:    cfg.From,
+		To:      cfg.To,
+	}
+
+	records, err := sec.Security.Usage(filter)
+	if err != nil {
+		return fmt.Errorf("usage: %w", err)
+	}
+
+	resp := make(toolapp.UsageResponse, len(records))
+	for i, rec := range records {
+		resp[i] = toolapp.UsageRecordResponse{
+			Day:              rec.Day,
+			Subject:          rec.Subject,
+			Model:            rec.Model,
+			Endpoint:         rec.Endpoint,
+			Requests:         rec.Requests,
+			PromptTokens:     rec.PromptTokens,
+			CompletionTokens: rec.CompletionTokens,
+			ReasoningTokens:  rec.ReasoningTokens,
+			TotalTokens:      rec.PromptTokens + rec.CompletionTokens,
+			LatencyMS:        rec.LatencyMS,
+		}
+	}
+
+	printUsage(resp)
+
+	return nil
+}
+
+func printUsage(usage toolapp.UsageResponse) {
+	if len(usage) == 0 {
+		fmt.Println("No usage found")
+		return
+	}
+
+	fmt.Printf("%-10s  %-36s  %-30s  %-16s  %8s  %10s  %10s  %10s  %12s\n", "DAY", "SUBJECT", "MODEL", "ENDPOINT", "REQUESTS", "PROMPT", "COMPLETION", "REASONING", "LATENCY")
+
+	for _, rec := range usage {
+		latency := time.Duration(rec.LatencyMS) * time.Millisecond
+		fmt.Printf("%-10s  %-36s  %-30s  %-16s  %8d  %10d  %10d  %10d  %12s\n", rec.Day, rec.Subject, rec.Model, rec.Endpoint, rec.Requests, rec.PromptTokens, rec.CompletionTokens, rec.ReasoningTokens, latency)
+	}
+}
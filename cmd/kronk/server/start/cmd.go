@@ -25,7 +25,12 @@ func init() {
 	Cmd.Flags().Int("model-instances", 0, "Maximum model instances")
 	Cmd.Flags().Int("models-in-cache", 0, "Maximum models in cache")
 	Cmd.Flags().String("cache-ttl", "", "Cache TTL duration (e.g., 5m, 1h)")
+	Cmd.Flags().Int64("cache-max-memory", 0, "Memory budget in bytes for the models in cache")
 	Cmd.Flags().String("model-config-file", "", "Special config file for model specific config")
+	Cmd.Flags().StringSlice("preload", nil, "Models to load at startup (e.g., Qwen3-8B-Q8_0,embeddinggemma-300m-qat-Q8_0)")
+	Cmd.Flags().Int("subject-queue-limit", 0, "Maximum requests a subject can have waiting for a model slot (0=unlimited)")
+	Cmd.Flags().String("response-store", "", "Store for the responses api: memory or badger")
+	Cmd.Flags().String("response-ttl", "", "How long stored responses are kept (e.g., 24h)")
 	Cmd.Flags().Int("llama-log", -1, "Llama log level (0=off, 1=on)")
 
 	Cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/ardanlabs/kronk/cmd/server/api/services/kronk"
 	"github.com/ardanlabs/kronk/sdk/tools/defaults"
@@ -89,6 +90,10 @@ func buildEnvVars(cmd *cobra.Command) []string {
 		envVars = append(envVars, "KRONK_CACHE_TTL="+v)
 	}
 
+	if v, _ := cmd.Flags().GetInt64("cache-max-memory"); v != 0 {
+		envVars = append(envVars, "KRONK_CACHE_MAX_MEMORY_BYTES="+strconv.FormatInt(v, 10))
+	}
+
 	if v, _ := cmd.Flags().GetBool("ignore-integrity-check"); v {
 		envVars = append(envVars, "KRONK_CACHE_IGNORE_INTEGRITY_CHECK=true")
 	}
@@ -97,6 +102,22 @@ func buildEnvVars(cmd *cobra.Command) []string {
 		envVars = append(envVars, "KRONK_MODEL_CONFIG_FILE="+v)
 	}
 
+	if v, _ := cmd.Flags().GetStringSlice("preload"); len(v) > 0 {
+		envVars = append(envVars, "KRONK_CACHE_PRELOAD="+strings.Join(v, ";"))
+	}
+
+	if v, _ := cmd.Flags().GetInt("subject-queue-limit"); v != 0 {
+		envVars = append(envVars, "KRONK_CACHE_SUBJECT_QUEUE_LIMIT="+strconv.Itoa(v))
+	}
+
+	if v, _ := cmd.Flags().GetString("response-store"); v != "" {
+		envVars = append(envVars, "KRONK_RESPONSES_STORE="+v)
+	}
+
+	if v, _ := cmd.Flags().GetString("response-ttl"); v != "" {
+		envVars = append(envVars, "KRONK_RESPONSES_TTL="+v)
+	}
+
 	if v, _ := cmd.Flags().GetInt("llama-log"); v != -1 {
 		envVars = append(envVars, "KRONK_LLAMA_LOG="+strconv.Itoa(v))
 	}
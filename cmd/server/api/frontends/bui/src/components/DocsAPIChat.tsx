@@ -46,215 +46,988 @@ export default function DocsAPIChat() {
                     <td>Yes</td>
                     <td>Must be application/json</td>
                   </tr>
+                  <tr>
+                    <td><code>X-Kronk-Priority</code></td>
+                    <td>No</td>
+                    <td>Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Request Body</h5>
+              <p><code>application/json</code></p>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Field</th>
+                    <th>Type</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>model</code></td>
+                    <td><code>string</code></td>
+                    <td>Yes</td>
+                    <td>Model ID to use for completion (e.g., 'qwen3-8b-q8_0')</td>
+                  </tr>
+                  <tr>
+                    <td><code>messages</code></td>
+                    <td><code>array</code></td>
+                    <td>Yes</td>
+                    <td>Array of message objects. See Message Formats section below for supported formats.</td>
+                  </tr>
+                  <tr>
+                    <td><code>stream</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Enable streaming responses (default: false)</td>
+                  </tr>
+                  <tr>
+                    <td><code>tools</code></td>
+                    <td><code>array</code></td>
+                    <td>No</td>
+                    <td>Array of tool definitions for function calling. See Tool Definitions section below.</td>
+                  </tr>
+                  <tr>
+                    <td><code>temperature</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Controls randomness of output (default: 0.8)</td>
+                  </tr>
+                  <tr>
+                    <td><code>top_k</code></td>
+                    <td><code>int32</code></td>
+                    <td>No</td>
+                    <td>Limits token pool to K most probable tokens (default: 40)</td>
+                  </tr>
+                  <tr>
+                    <td><code>top_p</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Nucleus sampling threshold (default: 0.9)</td>
+                  </tr>
+                  <tr>
+                    <td><code>min_p</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Dynamic sampling threshold (default: 0.0)</td>
+                  </tr>
+                  <tr>
+                    <td><code>max_tokens</code></td>
+                    <td><code>int</code></td>
+                    <td>No</td>
+                    <td>Maximum output tokens (default: 1024)</td>
+                  </tr>
+                  <tr>
+                    <td><code>n</code></td>
+                    <td><code>int</code></td>
+                    <td>No</td>
+                    <td>Number of choices to generate from one prompt, up to the model's parallel sequences (default: 1)</td>
+                  </tr>
+                  <tr>
+                    <td><code>stop</code></td>
+                    <td><code>string|[]string</code></td>
+                    <td>No</td>
+                    <td>Up to 4 strings that end generation, not included in the content (default: none)</td>
+                  </tr>
+                  <tr>
+                    <td><code>enable_thinking</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Enable model thinking for non-GPT models (default: true)</td>
+                  </tr>
+                  <tr>
+                    <td><code>reasoning_effort</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>Reasoning level for GPT models (default: medium)</td>
+                  </tr>
+                  <tr>
+                    <td><code>typical_p</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Locally typical sampling threshold, 1.0 disables it (default: 1.0)</td>
+                  </tr>
+                  <tr>
+                    <td><code>dry_multiplier</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Strength of the DRY repetition penalty, 0 disables it (default: 0.0)</td>
+                  </tr>
+                  <tr>
+                    <td><code>dry_sequence_breakers</code></td>
+                    <td><code>[]string</code></td>
+                    <td>No</td>
+                    <td>Strings that stop DRY sequence matching (default: ["\n", ":", "\"", "*"])</td>
+                  </tr>
+                  <tr>
+                    <td><code>samplers</code></td>
+                    <td><code>[]string</code></td>
+                    <td>No</td>
+                    <td>Samplers to use in order: penalties, dry, top_k, typical_p, top_p, min_p, xtc, temperature (default: all in that order)</td>
+                  </tr>
+                  <tr>
+                    <td><code>response_format</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Constrain output to JSON: &#123;"type": "json_object"&#125; or &#123;"type": "json_schema", "json_schema": &#123;"schema": &#123;...&#125;&#125;&#125; (default: text)</td>
+                  </tr>
+                  <tr>
+                    <td><code>grammar</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>GBNF grammar that constrains the output, takes precedence over response_format</td>
+                  </tr>
+                  <tr>
+                    <td><code>logit_bias</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Map of token ids to a bias from -100 to 100 added to their logits, -100 bans the token (default: none)</td>
+                  </tr>
+                  <tr>
+                    <td><code>logit_bias_text</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Map of text to a bias applied to each token of the text (default: none)</td>
+                  </tr>
+                  <tr>
+                    <td><code>logprobs</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Return the log probability of each content token (default: false)</td>
+                  </tr>
+                  <tr>
+                    <td><code>top_logprobs</code></td>
+                    <td><code>int</code></td>
+                    <td>No</td>
+                    <td>Number of most likely tokens, 0 to 20, returned at each position, requires logprobs (default: 0)</td>
+                  </tr>
+                  <tr>
+                    <td><code>seed</code></td>
+                    <td><code>uint32</code></td>
+                    <td>No</td>
+                    <td>Seed for the samplers, returned in the response so a request can be replayed (default: random)</td>
+                  </tr>
+                  <tr>
+                    <td><code>session_id</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>Binds the request to a session whose saved KV cache state is restored instead of processing the prompt again</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns a chat completion object, or streams Server-Sent Events if stream=true.</p>
+              <h5>Example</h5>
+              <p className="example-label"><strong>Simple text message:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "stream": true,
+    "model": "qwen3-8b-q8_0",
+    "messages": [
+      {
+        "role": "system",
+        "content": "You are a helpful assistant."
+      },
+      {
+        "role": "user",
+        "content": "Hello, how are you?"
+      }
+    ]
+  }'`}</code>
+              </pre>
+              <p className="example-label"><strong>Multi-turn conversation:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "stream": true,
+    "model": "qwen3-8b-q8_0",
+    "messages": [
+      {"role": "user", "content": "What is 2+2?"},
+      {"role": "assistant", "content": "2+2 equals 4."},
+      {"role": "user", "content": "And what is that multiplied by 3?"}
+    ]
+  }'`}</code>
+              </pre>
+              <p className="example-label"><strong>Vision - image from URL (requires vision model):</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "stream": true,
+    "model": "qwen2.5-vl-3b-instruct-q8_0",
+    "messages": [
+      {
+        "role": "user",
+        "content": [
+          {"type": "text", "text": "What is in this image?"},
+          {"type": "image_url", "image_url": {"url": "https://example.com/image.jpg"}}
+        ]
+      }
+    ]
+  }'`}</code>
+              </pre>
+              <p className="example-label"><strong>Vision - base64 encoded image (requires vision model):</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "stream": true,
+    "model": "qwen2.5-vl-3b-instruct-q8_0",
+    "messages": [
+      {
+        "role": "user",
+        "content": [
+          {"type": "text", "text": "Describe this image"},
+          {"type": "image_url", "image_url": {"url": "data:image/jpeg;base64,/9j/4AAQ..."}}
+        ]
+      }
+    ]
+  }'`}</code>
+              </pre>
+              <p className="example-label"><strong>Audio - base64 encoded audio (requires audio model):</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "stream": true,
+    "model": "qwen2-audio-7b-q8_0",
+    "messages": [
+      {
+        "role": "user",
+        "content": [
+          {"type": "text", "text": "What is being said in this audio?"},
+          {"type": "input_audio", "input_audio": {"data": "UklGRi...", "format": "wav"}}
+        ]
+      }
+    ]
+  }'`}</code>
+              </pre>
+              <p className="example-label"><strong>Tool/Function calling - define tools and let the model call them:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "stream": true,
+    "model": "qwen3-8b-q8_0",
+    "messages": [
+      {"role": "user", "content": "What is the weather in Tokyo?"}
+    ],
+    "tools": [
+      {
+        "type": "function",
+        "function": {
+          "name": "get_weather",
+          "description": "Get the current weather for a location",
+          "parameters": {
+            "type": "object",
+            "properties": {
+              "location": {
+                "type": "string",
+                "description": "The location to get the weather for, e.g. San Francisco, CA"
+              }
+            },
+            "required": ["location"]
+          }
+        }
+      }
+    ]
+  }'`}</code>
+              </pre>
+            </div>
+          </div>
+
+          <div className="card" id="completions">
+            <h3>Completions</h3>
+            <p>Complete a raw prompt. The prompt isn't passed through the model's chat template. Compatible with the OpenAI Completions API.</p>
+
+            <div className="doc-section" id="completions-post--completions">
+              <h4><span className="method-post">POST</span> /completions</h4>
+              <p className="doc-description">Create a completion for a raw prompt. Supports streaming responses and fill-in-the-middle with a suffix for models with FIM tokens.</p>
+              <p><strong>Authentication:</strong> Required when auth is enabled. Token must have 'completions' endpoint access.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for authentication</td>
+                  </tr>
+                  <tr>
+                    <td><code>Content-Type</code></td>
+                    <td>Yes</td>
+                    <td>Must be application/json</td>
+                  </tr>
+                  <tr>
+                    <td><code>X-Kronk-Priority</code></td>
+                    <td>No</td>
+                    <td>Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Request Body</h5>
+              <p><code>application/json</code></p>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Field</th>
+                    <th>Type</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>model</code></td>
+                    <td><code>string</code></td>
+                    <td>Yes</td>
+                    <td>Model ID to use for completion (e.g., 'qwen3-8b-q8_0')</td>
+                  </tr>
+                  <tr>
+                    <td><code>prompt</code></td>
+                    <td><code>string|array</code></td>
+                    <td>Yes</td>
+                    <td>Prompt to complete. An array of prompts is completed in turn, with the choices of each prompt indexed after the choices of the prompts before it.</td>
+                  </tr>
+                  <tr>
+                    <td><code>suffix</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>Text that comes after the completion. The model fills in the text between the prompt and the suffix. Requires a model with fill-in-the-middle tokens.</td>
+                  </tr>
+                  <tr>
+                    <td><code>echo</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Return the prompt in front of the completion (default: false)</td>
+                  </tr>
+                  <tr>
+                    <td><code>stream</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Enable streaming responses (default: false)</td>
+                  </tr>
+                  <tr>
+                    <td><code>max_tokens</code></td>
+                    <td><code>integer</code></td>
+                    <td>No</td>
+                    <td>Maximum tokens to generate. A completion cut off by max_tokens finishes with 'length'.</td>
+                  </tr>
+                  <tr>
+                    <td><code>stop</code></td>
+                    <td><code>string|array</code></td>
+                    <td>No</td>
+                    <td>Up to 4 strings that end generation. The stop string isn't included in the text.</td>
+                  </tr>
+                  <tr>
+                    <td><code>n</code></td>
+                    <td><code>integer</code></td>
+                    <td>No</td>
+                    <td>Number of completions to generate for each prompt (default: 1)</td>
+                  </tr>
+                  <tr>
+                    <td><code>logprobs</code></td>
+                    <td><code>integer</code></td>
+                    <td>No</td>
+                    <td>Return the log probabilities of the generated tokens with this many of the most likely tokens</td>
+                  </tr>
+                  <tr>
+                    <td><code>temperature</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Controls randomness of output (default: 0.8)</td>
+                  </tr>
+                  <tr>
+                    <td><code>top_p</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Nucleus sampling threshold (default: 0.9)</td>
+                  </tr>
+                  <tr>
+                    <td><code>seed</code></td>
+                    <td><code>integer</code></td>
+                    <td>No</td>
+                    <td>Seed for the samplers, so a request can be replayed</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns a text_completion object, or streams Server-Sent Events if stream=true.</p>
+              <h5>Example</h5>
+              <p className="example-label"><strong>Complete a prompt:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/completions \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "model": "qwen3-8b-q8_0",
+    "prompt": "The sky is blue because",
+    "max_tokens": 64
+  }'`}</code>
+              </pre>
+              <p className="example-label"><strong>Fill in the middle of code:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/completions \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "model": "Qwen2.5-Coder-7B-Q8_0",
+    "prompt": "func add(a, b int) int {\\n",
+    "suffix": "\\n}\\n",
+    "max_tokens": 32,
+    "stop": ["\\n\\n"]
+  }'`}</code>
+              </pre>
+            </div>
+          </div>
+
+          <div className="card" id="messages">
+            <h3>Messages</h3>
+            <p>Create messages with content blocks, tool use and thinking. Compatible with the Anthropic Messages API.</p>
+
+            <div className="doc-section" id="messages-post--messages">
+              <h4><span className="method-post">POST</span> /messages</h4>
+              <p className="doc-description">Create a message. Supports streaming with the message_start, content_block_start, content_block_delta, content_block_stop, message_delta and message_stop events.</p>
+              <p><strong>Authentication:</strong> Required when auth is enabled. Token must have 'messages' endpoint access.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for authentication. The x-api-key header is accepted in its place.</td>
+                  </tr>
+                  <tr>
+                    <td><code>Content-Type</code></td>
+                    <td>Yes</td>
+                    <td>Must be application/json</td>
+                  </tr>
+                  <tr>
+                    <td><code>X-Kronk-Priority</code></td>
+                    <td>No</td>
+                    <td>Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Request Body</h5>
+              <p><code>application/json</code></p>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Field</th>
+                    <th>Type</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>model</code></td>
+                    <td><code>string</code></td>
+                    <td>Yes</td>
+                    <td>Model ID to use for the message</td>
+                  </tr>
+                  <tr>
+                    <td><code>max_tokens</code></td>
+                    <td><code>integer</code></td>
+                    <td>Yes</td>
+                    <td>Maximum tokens to generate</td>
+                  </tr>
+                  <tr>
+                    <td><code>messages</code></td>
+                    <td><code>array</code></td>
+                    <td>Yes</td>
+                    <td>Messages with a user or assistant role. The content is a string or a list of text, image, tool_use and tool_result blocks.</td>
+                  </tr>
+                  <tr>
+                    <td><code>system</code></td>
+                    <td><code>string|array</code></td>
+                    <td>No</td>
+                    <td>System prompt as a string or a list of text blocks</td>
+                  </tr>
+                  <tr>
+                    <td><code>tools</code></td>
+                    <td><code>array</code></td>
+                    <td>No</td>
+                    <td>Tools with a name, description and input_schema</td>
+                  </tr>
+                  <tr>
+                    <td><code>tool_choice</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>How the model uses the tools: auto, any, tool or none</td>
+                  </tr>
+                  <tr>
+                    <td><code>thinking</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Set type to 'enabled' to get thinking blocks. Thinking is off by default.</td>
+                  </tr>
+                  <tr>
+                    <td><code>stop_sequences</code></td>
+                    <td><code>array</code></td>
+                    <td>No</td>
+                    <td>Strings that end generation</td>
+                  </tr>
+                  <tr>
+                    <td><code>stream</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Stream the response as server-sent events (default: false)</td>
+                  </tr>
+                  <tr>
+                    <td><code>temperature</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Controls randomness of output</td>
+                  </tr>
+                  <tr>
+                    <td><code>top_p</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Nucleus sampling threshold</td>
+                  </tr>
+                  <tr>
+                    <td><code>top_k</code></td>
+                    <td><code>integer</code></td>
+                    <td>No</td>
+                    <td>Limit sampling to the K most likely tokens</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns a message with thinking, text and tool_use content blocks, a stop_reason of end_turn, max_tokens or tool_use, and the input and output tokens.</p>
+              <h5>Example</h5>
+              <p className="example-label"><strong>Create a message:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/messages \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "model": "qwen3-8b-q8_0",
+    "max_tokens": 1024,
+    "messages": [{"role": "user", "content": "Hello"}]
+  }'`}</code>
+              </pre>
+            </div>
+          </div>
+
+          <div className="card" id="ollama-api">
+            <h3>Ollama API</h3>
+            <p>Endpoints compatible with the Ollama API so tools built for Ollama can use the models of the server. These paths are not prefixed with /v1. Responses stream as newline delimited JSON unless stream=false. Model names may carry the ':latest' tag.</p>
+
+            <div className="doc-section" id="ollama-api-post--api-chat">
+              <h4><span className="method-post">POST</span> /api/chat</h4>
+              <p className="doc-description">Create a chat completion. A request without messages loads the model.</p>
+              <p><strong>Authentication:</strong> Required when auth is enabled. Token must have 'chat-completions' endpoint access.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for authentication</td>
+                  </tr>
+                  <tr>
+                    <td><code>Content-Type</code></td>
+                    <td>Yes</td>
+                    <td>Must be application/json</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Request Body</h5>
+              <p><code>application/json</code></p>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Field</th>
+                    <th>Type</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>model</code></td>
+                    <td><code>string</code></td>
+                    <td>Yes</td>
+                    <td>Model ID to use for the chat</td>
+                  </tr>
+                  <tr>
+                    <td><code>messages</code></td>
+                    <td><code>array</code></td>
+                    <td>No</td>
+                    <td>Chat messages with role, content, images (base64), tool_calls and tool_name</td>
+                  </tr>
+                  <tr>
+                    <td><code>tools</code></td>
+                    <td><code>array</code></td>
+                    <td>No</td>
+                    <td>Tools the model may call</td>
+                  </tr>
+                  <tr>
+                    <td><code>format</code></td>
+                    <td><code>string|object</code></td>
+                    <td>No</td>
+                    <td>'json' for any JSON object or a JSON schema to match</td>
+                  </tr>
+                  <tr>
+                    <td><code>options</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Model options: num_predict, temperature, top_k, top_p, min_p, typical_p, repeat_penalty, repeat_last_n, presence_penalty, frequency_penalty, seed and stop. Other options are ignored.</td>
+                  </tr>
+                  <tr>
+                    <td><code>think</code></td>
+                    <td><code>boolean|string</code></td>
+                    <td>No</td>
+                    <td>Enable thinking, or the reasoning effort (low, medium, high) for GPT models</td>
+                  </tr>
+                  <tr>
+                    <td><code>stream</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Stream the response (default: true)</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns a chat response with done=true, or streams a line of JSON for each chunk ending with the done chunk that holds the token counts.</p>
+              <h5>Example</h5>
+              <p className="example-label"><strong>Chat with a model:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/api/chat \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "model": "qwen3-8b-q8_0",
+    "messages": [{"role": "user", "content": "Why is the sky blue?"}]
+  }'`}</code>
+              </pre>
+            </div>
+
+            <div className="doc-section" id="ollama-api-post--api-generate">
+              <h4><span className="method-post">POST</span> /api/generate</h4>
+              <p className="doc-description">Generate a response for a prompt. The prompt goes through the chat template unless raw=true or a suffix is given. A request without a prompt loads the model.</p>
+              <p><strong>Authentication:</strong> Required when auth is enabled. Token must have 'completions' endpoint access.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for authentication</td>
+                  </tr>
+                  <tr>
+                    <td><code>Content-Type</code></td>
+                    <td>Yes</td>
+                    <td>Must be application/json</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Request Body</h5>
+              <p><code>application/json</code></p>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Field</th>
+                    <th>Type</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>model</code></td>
+                    <td><code>string</code></td>
+                    <td>Yes</td>
+                    <td>Model ID to use for generation</td>
+                  </tr>
+                  <tr>
+                    <td><code>prompt</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>Prompt to generate a response for</td>
+                  </tr>
+                  <tr>
+                    <td><code>suffix</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>Text that comes after the response, for models with fill-in-the-middle tokens</td>
+                  </tr>
+                  <tr>
+                    <td><code>system</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>System message for the chat template</td>
+                  </tr>
+                  <tr>
+                    <td><code>images</code></td>
+                    <td><code>array</code></td>
+                    <td>No</td>
+                    <td>Base64 encoded images for vision models</td>
+                  </tr>
+                  <tr>
+                    <td><code>raw</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Give the prompt to the model as is, without the chat template</td>
+                  </tr>
+                  <tr>
+                    <td><code>format</code></td>
+                    <td><code>string|object</code></td>
+                    <td>No</td>
+                    <td>'json' for any JSON object or a JSON schema to match</td>
+                  </tr>
+                  <tr>
+                    <td><code>options</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Model options, the same as the chat endpoint</td>
+                  </tr>
+                  <tr>
+                    <td><code>think</code></td>
+                    <td><code>boolean|string</code></td>
+                    <td>No</td>
+                    <td>Enable thinking, or the reasoning effort for GPT models</td>
+                  </tr>
+                  <tr>
+                    <td><code>stream</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Stream the response (default: true)</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns the response text with done=true, or streams a line of JSON for each chunk.</p>
+            </div>
+
+            <div className="doc-section" id="ollama-api-post--api-embed">
+              <h4><span className="method-post">POST</span> /api/embed</h4>
+              <p className="doc-description">Generate embeddings for one or more inputs.</p>
+              <p><strong>Authentication:</strong> Required when auth is enabled. Token must have 'embeddings' endpoint access.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for authentication</td>
+                  </tr>
+                  <tr>
+                    <td><code>Content-Type</code></td>
+                    <td>Yes</td>
+                    <td>Must be application/json</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Request Body</h5>
+              <p><code>application/json</code></p>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Field</th>
+                    <th>Type</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>model</code></td>
+                    <td><code>string</code></td>
+                    <td>Yes</td>
+                    <td>Embedding model ID</td>
+                  </tr>
+                  <tr>
+                    <td><code>input</code></td>
+                    <td><code>string|array</code></td>
+                    <td>Yes</td>
+                    <td>Text or list of texts to embed</td>
+                  </tr>
+                  <tr>
+                    <td><code>truncate</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Truncate inputs that don't fit the context window (default: true)</td>
+                  </tr>
+                  <tr>
+                    <td><code>dimensions</code></td>
+                    <td><code>integer</code></td>
+                    <td>No</td>
+                    <td>Reduce the embeddings to the first N dimensions</td>
+                  </tr>
                 </tbody>
               </table>
-              <h5>Request Body</h5>
-              <p><code>application/json</code></p>
+              <h5>Response</h5>
+              <p>Returns the embeddings in the order of the inputs.</p>
+            </div>
+
+            <div className="doc-section" id="ollama-api-get--api-tags">
+              <h4><span className="method-get">GET</span> /api/tags</h4>
+              <p className="doc-description">List the installed models and aliases.</p>
+              <p><strong>Authentication:</strong> Optional when auth is enabled.</p>
+              <h5>Response</h5>
+              <p>Returns the models with their size, modified time and family.</p>
+            </div>
+
+            <div className="doc-section" id="ollama-api-post--api-show">
+              <h4><span className="method-post">POST</span> /api/show</h4>
+              <p className="doc-description">Show the template, metadata and capabilities of a model. The model is loaded if it isn't already.</p>
+              <p><strong>Authentication:</strong> Optional when auth is enabled.</p>
+              <h5>Headers</h5>
               <table className="flags-table">
                 <thead>
                   <tr>
-                    <th>Field</th>
-                    <th>Type</th>
+                    <th>Header</th>
                     <th>Required</th>
                     <th>Description</th>
                   </tr>
                 </thead>
                 <tbody>
                   <tr>
-                    <td><code>model</code></td>
-                    <td><code>string</code></td>
+                    <td><code>Authorization</code></td>
                     <td>Yes</td>
-                    <td>Model ID to use for completion (e.g., 'qwen3-8b-q8_0')</td>
+                    <td>Bearer token for authentication</td>
                   </tr>
                   <tr>
-                    <td><code>messages</code></td>
-                    <td><code>array</code></td>
+                    <td><code>Content-Type</code></td>
                     <td>Yes</td>
-                    <td>Array of message objects. See Message Formats section below for supported formats.</td>
+                    <td>Must be application/json</td>
                   </tr>
+                </tbody>
+              </table>
+              <h5>Request Body</h5>
+              <p><code>application/json</code></p>
+              <table className="flags-table">
+                <thead>
                   <tr>
-                    <td><code>stream</code></td>
-                    <td><code>boolean</code></td>
-                    <td>No</td>
-                    <td>Enable streaming responses (default: false)</td>
+                    <th>Field</th>
+                    <th>Type</th>
+                    <th>Required</th>
+                    <th>Description</th>
                   </tr>
+                </thead>
+                <tbody>
                   <tr>
-                    <td><code>tools</code></td>
-                    <td><code>array</code></td>
-                    <td>No</td>
-                    <td>Array of tool definitions for function calling. See Tool Definitions section below.</td>
+                    <td><code>model</code></td>
+                    <td><code>string</code></td>
+                    <td>Yes</td>
+                    <td>Model ID to show</td>
                   </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns the model information.</p>
+            </div>
+
+            <div className="doc-section" id="ollama-api-get--api-ps">
+              <h4><span className="method-get">GET</span> /api/ps</h4>
+              <p className="doc-description">List the models that are loaded.</p>
+              <p><strong>Authentication:</strong> Optional when auth is enabled.</p>
+              <h5>Response</h5>
+              <p>Returns the loaded models with their size and expiration time.</p>
+            </div>
+
+            <div className="doc-section" id="ollama-api-post--api-pull">
+              <h4><span className="method-post">POST</span> /api/pull</h4>
+              <p className="doc-description">Download a model from the catalog.</p>
+              <p><strong>Authentication:</strong> Optional when auth is enabled.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
                   <tr>
-                    <td><code>temperature</code></td>
-                    <td><code>float32</code></td>
-                    <td>No</td>
-                    <td>Controls randomness of output (default: 0.8)</td>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
                   </tr>
+                </thead>
+                <tbody>
                   <tr>
-                    <td><code>top_k</code></td>
-                    <td><code>int32</code></td>
-                    <td>No</td>
-                    <td>Limits token pool to K most probable tokens (default: 40)</td>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for authentication</td>
                   </tr>
                   <tr>
-                    <td><code>top_p</code></td>
-                    <td><code>float32</code></td>
-                    <td>No</td>
-                    <td>Nucleus sampling threshold (default: 0.9)</td>
+                    <td><code>Content-Type</code></td>
+                    <td>Yes</td>
+                    <td>Must be application/json</td>
                   </tr>
+                </tbody>
+              </table>
+              <h5>Request Body</h5>
+              <p><code>application/json</code></p>
+              <table className="flags-table">
+                <thead>
                   <tr>
-                    <td><code>min_p</code></td>
-                    <td><code>float32</code></td>
-                    <td>No</td>
-                    <td>Dynamic sampling threshold (default: 0.0)</td>
+                    <th>Field</th>
+                    <th>Type</th>
+                    <th>Required</th>
+                    <th>Description</th>
                   </tr>
+                </thead>
+                <tbody>
                   <tr>
-                    <td><code>max_tokens</code></td>
-                    <td><code>int</code></td>
-                    <td>No</td>
-                    <td>Maximum output tokens (default: 1024)</td>
+                    <td><code>model</code></td>
+                    <td><code>string</code></td>
+                    <td>Yes</td>
+                    <td>Catalog model ID to download</td>
                   </tr>
                   <tr>
-                    <td><code>enable_thinking</code></td>
+                    <td><code>stream</code></td>
                     <td><code>boolean</code></td>
                     <td>No</td>
-                    <td>Enable model thinking for non-GPT models (default: true)</td>
-                  </tr>
-                  <tr>
-                    <td><code>reasoning_effort</code></td>
-                    <td><code>string</code></td>
-                    <td>No</td>
-                    <td>Reasoning level for GPT models (default: medium)</td>
+                    <td>Stream the progress (default: true)</td>
                   </tr>
                 </tbody>
               </table>
               <h5>Response</h5>
-              <p>Returns a chat completion object, or streams Server-Sent Events if stream=true.</p>
-              <h5>Example</h5>
-              <p className="example-label"><strong>Simple text message:</strong></p>
-              <pre className="code-block">
-                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
-  -H "Authorization: Bearer $KRONK_TOKEN" \\
-  -H "Content-Type: application/json" \\
-  -d '{
-    "stream": true,
-    "model": "qwen3-8b-q8_0",
-    "messages": [
-      {
-        "role": "system",
-        "content": "You are a helpful assistant."
-      },
-      {
-        "role": "user",
-        "content": "Hello, how are you?"
-      }
-    ]
-  }'`}</code>
-              </pre>
-              <p className="example-label"><strong>Multi-turn conversation:</strong></p>
-              <pre className="code-block">
-                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
-  -H "Authorization: Bearer $KRONK_TOKEN" \\
-  -H "Content-Type: application/json" \\
-  -d '{
-    "stream": true,
-    "model": "qwen3-8b-q8_0",
-    "messages": [
-      {"role": "user", "content": "What is 2+2?"},
-      {"role": "assistant", "content": "2+2 equals 4."},
-      {"role": "user", "content": "And what is that multiplied by 3?"}
-    ]
-  }'`}</code>
-              </pre>
-              <p className="example-label"><strong>Vision - image from URL (requires vision model):</strong></p>
-              <pre className="code-block">
-                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
-  -H "Authorization: Bearer $KRONK_TOKEN" \\
-  -H "Content-Type: application/json" \\
-  -d '{
-    "stream": true,
-    "model": "qwen2.5-vl-3b-instruct-q8_0",
-    "messages": [
-      {
-        "role": "user",
-        "content": [
-          {"type": "text", "text": "What is in this image?"},
-          {"type": "image_url", "image_url": {"url": "https://example.com/image.jpg"}}
-        ]
-      }
-    ]
-  }'`}</code>
-              </pre>
-              <p className="example-label"><strong>Vision - base64 encoded image (requires vision model):</strong></p>
-              <pre className="code-block">
-                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
-  -H "Authorization: Bearer $KRONK_TOKEN" \\
-  -H "Content-Type: application/json" \\
-  -d '{
-    "stream": true,
-    "model": "qwen2.5-vl-3b-instruct-q8_0",
-    "messages": [
-      {
-        "role": "user",
-        "content": [
-          {"type": "text", "text": "Describe this image"},
-          {"type": "image_url", "image_url": {"url": "data:image/jpeg;base64,/9j/4AAQ..."}}
-        ]
-      }
-    ]
-  }'`}</code>
-              </pre>
-              <p className="example-label"><strong>Audio - base64 encoded audio (requires audio model):</strong></p>
-              <pre className="code-block">
-                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
-  -H "Authorization: Bearer $KRONK_TOKEN" \\
-  -H "Content-Type: application/json" \\
-  -d '{
-    "stream": true,
-    "model": "qwen2-audio-7b-q8_0",
-    "messages": [
-      {
-        "role": "user",
-        "content": [
-          {"type": "text", "text": "What is being said in this audio?"},
-          {"type": "input_audio", "input_audio": {"data": "UklGRi...", "format": "wav"}}
-        ]
-      }
-    ]
-  }'`}</code>
-              </pre>
-              <p className="example-label"><strong>Tool/Function calling - define tools and let the model call them:</strong></p>
-              <pre className="code-block">
-                <code>{`curl -X POST http://localhost:8080/v1/chat/completions \\
-  -H "Authorization: Bearer $KRONK_TOKEN" \\
-  -H "Content-Type: application/json" \\
-  -d '{
-    "stream": true,
-    "model": "qwen3-8b-q8_0",
-    "messages": [
-      {"role": "user", "content": "What is the weather in Tokyo?"}
-    ],
-    "tools": [
-      {
-        "type": "function",
-        "function": {
-          "name": "get_weather",
-          "description": "Get the current weather for a location",
-          "parameters": {
-            "type": "object",
-            "properties": {
-              "location": {
-                "type": "string",
-                "description": "The location to get the weather for, e.g. San Francisco, CA"
-              }
-            },
-            "required": ["location"]
-          }
-        }
-      }
-    ]
-  }'`}</code>
-              </pre>
+              <p>Streams a status line for the progress of the download, ending with a 'success' status.</p>
             </div>
           </div>
 
@@ -285,12 +1058,16 @@ export default function DocsAPIChat() {
   ],
   "usage": {
     "prompt_tokens": 25,
+    "prompt_tokens_details": {
+      "cached_tokens": 0
+    },
     "reasoning_tokens": 0,
     "completion_tokens": 12,
     "output_tokens": 12,
     "total_tokens": 37,
     "tokens_per_second": 85.5
-  }
+  },
+  "seed": 1234
 }`}</code>
               </pre>
             </div>
@@ -475,6 +1252,30 @@ data: [DONE]`}</code>
                 <li><a href="#chat-completions-post--chat-completions">POST /chat/completions</a></li>
               </ul>
             </div>
+            <div className="doc-index-section">
+              <a href="#completions" className="doc-index-header">Completions</a>
+              <ul>
+                <li><a href="#completions-post--completions">POST /completions</a></li>
+              </ul>
+            </div>
+            <div className="doc-index-section">
+              <a href="#messages" className="doc-index-header">Messages</a>
+              <ul>
+                <li><a href="#messages-post--messages">POST /messages</a></li>
+              </ul>
+            </div>
+            <div className="doc-index-section">
+              <a href="#ollama-api" className="doc-index-header">Ollama API</a>
+              <ul>
+                <li><a href="#ollama-api-post--api-chat">POST /api/chat</a></li>
+                <li><a href="#ollama-api-post--api-generate">POST /api/generate</a></li>
+                <li><a href="#ollama-api-post--api-embed">POST /api/embed</a></li>
+                <li><a href="#ollama-api-get--api-tags">GET /api/tags</a></li>
+                <li><a href="#ollama-api-post--api-show">POST /api/show</a></li>
+                <li><a href="#ollama-api-get--api-ps">GET /api/ps</a></li>
+                <li><a href="#ollama-api-post--api-pull">POST /api/pull</a></li>
+              </ul>
+            </div>
             <div className="doc-index-section">
               <a href="#response-formats" className="doc-index-header">Response Formats</a>
               <ul>
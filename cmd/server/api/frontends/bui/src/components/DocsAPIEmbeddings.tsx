@@ -46,6 +46,11 @@ export default function DocsAPIEmbeddings() {
                     <td>Yes</td>
                     <td>Must be application/json</td>
                   </tr>
+                  <tr>
+                    <td><code>X-Kronk-Priority</code></td>
+                    <td>No</td>
+                    <td>Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority</td>
+                  </tr>
                 </tbody>
               </table>
               <h5>Request Body</h5>
@@ -78,6 +83,48 @@ export default function DocsAPIEmbeddings() {
                     <td>No</td>
                     <td>Reduce output to first N dimensions (for Matryoshka models). Must be &lt;= model's native dimensions.</td>
                   </tr>
+                  <tr>
+                    <td><code>pooling</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>Pooling of the token embeddings: mean, cls or last. Defaults to the model's pooling. When the server has a memory budget, only the model's pooling can be used.</td>
+                  </tr>
+                  <tr>
+                    <td><code>normalize</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>L2 normalize the vectors. Defaults to true.</td>
+                  </tr>
+                  <tr>
+                    <td><code>encoding_format</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>Format of the vectors: float (default) or base64, a base64 string of little-endian float32 values.</td>
+                  </tr>
+                  <tr>
+                    <td><code>chunking</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Split inputs longer than the model can embed into overlapping token windows. Defaults to false.</td>
+                  </tr>
+                  <tr>
+                    <td><code>chunk_size</code></td>
+                    <td><code>integer</code></td>
+                    <td>No</td>
+                    <td>Tokens per window. Defaults to the largest input the model can embed.</td>
+                  </tr>
+                  <tr>
+                    <td><code>chunk_overlap</code></td>
+                    <td><code>integer</code></td>
+                    <td>No</td>
+                    <td>Tokens shared by consecutive windows. Defaults to a tenth of chunk_size.</td>
+                  </tr>
+                  <tr>
+                    <td><code>chunk_aggregate</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>How the window vectors of an input are combined: mean (default), weighted by window length, or none to return each window's vector with a chunk object holding its index and token offsets.</td>
+                  </tr>
                 </tbody>
               </table>
               <h5>Response</h5>
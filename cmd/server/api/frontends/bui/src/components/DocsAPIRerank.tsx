@@ -46,6 +46,11 @@ export default function DocsAPIRerank() {
                     <td>Yes</td>
                     <td>Must be application/json</td>
                   </tr>
+                  <tr>
+                    <td><code>X-Kronk-Priority</code></td>
+                    <td>No</td>
+                    <td>Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority</td>
+                  </tr>
                 </tbody>
               </table>
               <h5>Request Body</h5>
@@ -46,6 +46,11 @@ export default function DocsAPIResponses() {
                     <td>Yes</td>
                     <td>Must be application/json</td>
                   </tr>
+                  <tr>
+                    <td><code>X-Kronk-Priority</code></td>
+                    <td>No</td>
+                    <td>Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority</td>
+                  </tr>
                 </tbody>
               </table>
               <h5>Request Body</h5>
@@ -106,7 +111,13 @@ export default function DocsAPIResponses() {
                     <td><code>store</code></td>
                     <td><code>boolean</code></td>
                     <td>No</td>
-                    <td>Whether to store the response (default: true)</td>
+                    <td>Whether to store the response so it can be retrieved or continued (default: true)</td>
+                  </tr>
+                  <tr>
+                    <td><code>previous_response_id</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>ID of a stored response to continue. The conversation of that response is placed before the input.</td>
                   </tr>
                   <tr>
                     <td><code>truncation</code></td>
@@ -114,6 +125,12 @@ export default function DocsAPIResponses() {
                     <td>No</td>
                     <td>Truncation strategy: auto or disabled (default: disabled)</td>
                   </tr>
+                  <tr>
+                    <td><code>text</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Output format: &#123;"format": &#123;"type": "json_schema", "name": "...", "schema": &#123;...&#125;&#125;&#125; or &#123;"format": &#123;"type": "json_object"&#125;&#125;</td>
+                  </tr>
                   <tr>
                     <td><code>temperature</code></td>
                     <td><code>float32</code></td>
@@ -144,6 +161,18 @@ export default function DocsAPIResponses() {
                     <td>No</td>
                     <td>Maximum output tokens (default: 1024)</td>
                   </tr>
+                  <tr>
+                    <td><code>n</code></td>
+                    <td><code>int</code></td>
+                    <td>No</td>
+                    <td>Number of choices to generate from one prompt, up to the model's parallel sequences (default: 1)</td>
+                  </tr>
+                  <tr>
+                    <td><code>stop</code></td>
+                    <td><code>string|[]string</code></td>
+                    <td>No</td>
+                    <td>Up to 4 strings that end generation, not included in the content (default: none)</td>
+                  </tr>
                   <tr>
                     <td><code>enable_thinking</code></td>
                     <td><code>boolean</code></td>
@@ -156,6 +185,78 @@ export default function DocsAPIResponses() {
                     <td>No</td>
                     <td>Reasoning level for GPT models (default: medium)</td>
                   </tr>
+                  <tr>
+                    <td><code>typical_p</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Locally typical sampling threshold, 1.0 disables it (default: 1.0)</td>
+                  </tr>
+                  <tr>
+                    <td><code>dry_multiplier</code></td>
+                    <td><code>float32</code></td>
+                    <td>No</td>
+                    <td>Strength of the DRY repetition penalty, 0 disables it (default: 0.0)</td>
+                  </tr>
+                  <tr>
+                    <td><code>dry_sequence_breakers</code></td>
+                    <td><code>[]string</code></td>
+                    <td>No</td>
+                    <td>Strings that stop DRY sequence matching (default: ["\n", ":", "\"", "*"])</td>
+                  </tr>
+                  <tr>
+                    <td><code>samplers</code></td>
+                    <td><code>[]string</code></td>
+                    <td>No</td>
+                    <td>Samplers to use in order: penalties, dry, top_k, typical_p, top_p, min_p, xtc, temperature (default: all in that order)</td>
+                  </tr>
+                  <tr>
+                    <td><code>response_format</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Constrain output to JSON: &#123;"type": "json_object"&#125; or &#123;"type": "json_schema", "json_schema": &#123;"schema": &#123;...&#125;&#125;&#125; (default: text)</td>
+                  </tr>
+                  <tr>
+                    <td><code>grammar</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>GBNF grammar that constrains the output, takes precedence over response_format</td>
+                  </tr>
+                  <tr>
+                    <td><code>logit_bias</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Map of token ids to a bias from -100 to 100 added to their logits, -100 bans the token (default: none)</td>
+                  </tr>
+                  <tr>
+                    <td><code>logit_bias_text</code></td>
+                    <td><code>object</code></td>
+                    <td>No</td>
+                    <td>Map of text to a bias applied to each token of the text (default: none)</td>
+                  </tr>
+                  <tr>
+                    <td><code>logprobs</code></td>
+                    <td><code>boolean</code></td>
+                    <td>No</td>
+                    <td>Return the log probability of each content token (default: false)</td>
+                  </tr>
+                  <tr>
+                    <td><code>top_logprobs</code></td>
+                    <td><code>int</code></td>
+                    <td>No</td>
+                    <td>Number of most likely tokens, 0 to 20, returned at each position, requires logprobs (default: 0)</td>
+                  </tr>
+                  <tr>
+                    <td><code>seed</code></td>
+                    <td><code>uint32</code></td>
+                    <td>No</td>
+                    <td>Seed for the samplers, returned in the response so a request can be replayed (default: random)</td>
+                  </tr>
+                  <tr>
+                    <td><code>session_id</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>Binds the request to a session whose saved KV cache state is restored instead of processing the prompt again</td>
+                  </tr>
                 </tbody>
               </table>
               <h5>Response</h5>
@@ -184,6 +285,19 @@ export default function DocsAPIResponses() {
       {"role": "user", "content": "Write a short poem about coding"}
     ],
     "stream": true
+  }'`}</code>
+              </pre>
+              <p className="example-label"><strong>Continue a stored response:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/responses \\
+  -H "Authorization: Bearer $KRONK_TOKEN" \\
+  -H "Content-Type: application/json" \\
+  -d '{
+    "model": "qwen3-8b-q8_0",
+    "previous_response_id": "resp_abc123",
+    "input": [
+      {"role": "user", "content": "Now make it rhyme"}
+    ]
   }'`}</code>
               </pre>
               <p className="example-label"><strong>With tools:</strong></p>
@@ -215,6 +329,68 @@ export default function DocsAPIResponses() {
   }'`}</code>
               </pre>
             </div>
+
+            <div className="doc-section" id="responses-get--responses-id">
+              <h4><span className="method-get">GET</span> /responses/&#123;id&#125;</h4>
+              <p className="doc-description">Retrieve a stored response. Responses are kept for the configured TTL.</p>
+              <p><strong>Authentication:</strong> Required when auth is enabled. Token must have 'responses' endpoint access. Only the responses created with the same subject are returned.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for authentication</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns the response object.</p>
+              <h5>Example</h5>
+              <p className="example-label"><strong>Retrieve a response:</strong></p>
+              <pre className="code-block">
+                <code>{`curl http://localhost:8080/v1/responses/resp_abc123 \\
+  -H "Authorization: Bearer $KRONK_TOKEN"`}</code>
+              </pre>
+            </div>
+
+            <div className="doc-section" id="responses-delete--responses-id">
+              <h4><span className="method-delete">DELETE</span> /responses/&#123;id&#125;</h4>
+              <p className="doc-description">Delete a stored response.</p>
+              <p><strong>Authentication:</strong> Required when auth is enabled. Token must have 'responses' endpoint access. Only the responses created with the same subject can be deleted.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for authentication</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns the id of the deleted response with deleted set to true.</p>
+              <h5>Example</h5>
+              <p className="example-label"><strong>Delete a response:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X DELETE http://localhost:8080/v1/responses/resp_abc123 \\
+  -H "Authorization: Bearer $KRONK_TOKEN"`}</code>
+              </pre>
+            </div>
           </div>
 
           <div className="card" id="response-format">
@@ -312,6 +488,8 @@ data: {"type":"response.completed","response":{...}}`}</code>
               <a href="#responses" className="doc-index-header">Responses</a>
               <ul>
                 <li><a href="#responses-post--responses">POST /responses</a></li>
+                <li><a href="#responses-get--responses-id">GET /responses/&#123;id&#125;</a></li>
+                <li><a href="#responses-delete--responses-id">DELETE /responses/&#123;id&#125;</a></li>
               </ul>
             </div>
             <div className="doc-index-section">
@@ -207,6 +207,37 @@ export default function DocsAPITools() {
               </pre>
             </div>
 
+            <div className="doc-section" id="models-post--models-config-reload">
+              <h4><span className="method-post">POST</span> /models/config/reload</h4>
+              <p className="doc-description">Reload the model config file without restarting the server. The file is validated before it's used. Loaded models whose settings changed are loaded again with the new settings once their active streams end; the other models stay loaded. Sending SIGHUP to the server does the same.</p>
+              <p><strong>Authentication:</strong> Required when auth is enabled. Admin token required.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for admin authentication</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns the ids of the models that are loaded again.</p>
+              <h5>Example</h5>
+              <p className="example-label"><strong>Reload the model config file:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X POST http://localhost:8080/v1/models/config/reload \\
+  -H "Authorization: Bearer $KRONK_TOKEN"`}</code>
+              </pre>
+            </div>
+
             <div className="doc-section" id="models-post--models-pull">
               <h4><span className="method-post">POST</span> /models/pull</h4>
               <p className="doc-description">Pull/download a model from a URL. Returns streaming progress updates.</p>
@@ -488,6 +519,12 @@ export default function DocsAPITools() {
                     <td>Yes</td>
                     <td>Map of endpoint names to rate limit configurations</td>
                   </tr>
+                  <tr>
+                    <td><code>priority</code></td>
+                    <td><code>string</code></td>
+                    <td>No</td>
+                    <td>Highest priority class for the token's requests: low, normal (default) or high</td>
+                  </tr>
                 </tbody>
               </table>
               <h5>Response</h5>
@@ -598,6 +635,37 @@ export default function DocsAPITools() {
               <p className="example-label"><strong>Remove a key:</strong></p>
               <pre className="code-block">
                 <code>{`curl -X POST http://localhost:8080/v1/security/keys/remove/abc123 \\
+  -H "Authorization: Bearer $KRONK_TOKEN"`}</code>
+              </pre>
+            </div>
+
+            <div className="doc-section" id="security-get--usage">
+              <h4><span className="method-get">GET</span> /usage</h4>
+              <p className="doc-description">List the usage ledger - requests, prompt/completion/reasoning tokens and total latency per subject, model, endpoint and day. Filter with the subject, from and to (YYYY-MM-DD) query parameters. The range defaults to the first day of the current month through today.</p>
+              <p><strong>Authentication:</strong> Required when auth is enabled. Admin token required.</p>
+              <h5>Headers</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Header</th>
+                    <th>Required</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>Authorization</code></td>
+                    <td>Yes</td>
+                    <td>Bearer token for admin authentication</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Response</h5>
+              <p>Returns a list of usage records ordered by day.</p>
+              <h5>Example</h5>
+              <p className="example-label"><strong>List the usage for one subject in January:</strong></p>
+              <pre className="code-block">
+                <code>{`curl -X GET "http://localhost:8080/v1/usage?subject=<subject>&from=2026-01-01&to=2026-01-31" \\
   -H "Authorization: Bearer $KRONK_TOKEN"`}</code>
               </pre>
             </div>
@@ -623,6 +691,7 @@ export default function DocsAPITools() {
                 <li><a href="#models-get--models-model">GET /models/&#123;model&#125;</a></li>
                 <li><a href="#models-get--models-ps">GET /models/ps</a></li>
                 <li><a href="#models-post--models-index">POST /models/index</a></li>
+                <li><a href="#models-post--models-config-reload">POST /models/config/reload</a></li>
                 <li><a href="#models-post--models-pull">POST /models/pull</a></li>
                 <li><a href="#models-delete--models-model">DELETE /models/&#123;model&#125;</a></li>
               </ul>
@@ -643,6 +712,7 @@ export default function DocsAPITools() {
                 <li><a href="#security-get--security-keys">GET /security/keys</a></li>
                 <li><a href="#security-post--security-keys-add">POST /security/keys/add</a></li>
                 <li><a href="#security-post--security-keys-remove-keyid">POST /security/keys/remove/&#123;keyid&#125;</a></li>
+                <li><a href="#security-get--usage">GET /usage</a></li>
               </ul>
             </div>
           </div>
@@ -198,6 +198,10 @@ kronk security key list`}</code>
                     <td><code>--endpoints &lt;list&gt;</code></td>
                     <td>Endpoints with optional rate limits</td>
                   </tr>
+                  <tr>
+                    <td><code>--priority &lt;string&gt;</code></td>
+                    <td>Highest priority class for the token's requests (low, normal, high)</td>
+                  </tr>
                 </tbody>
               </table>
               <h5>Environment Variables</h5>
@@ -224,10 +228,76 @@ export KRONK_TOKEN=<admin-token>
 kronk security token create --duration 24h --endpoints chat-completions,embeddings
 
 # Create a token with rate limits
-kronk security token create --duration 720h --endpoints "chat-completions:1000/day,embeddings:unlimited"`}</code>
+kronk security token create --duration 720h --endpoints "chat-completions:1000/day,embeddings:unlimited"
+
+# Create a token with a token budget per minute
+kronk security token create --duration 720h --endpoints "chat-completions:60/minute:tokens=20000"
+
+# Create a token for batch work that yields to interactive requests
+kronk security token create --duration 720h --endpoints chat-completions --priority low`}</code>
               </pre>
             </div>
             </div>
+
+            <div className="doc-section" id="cmd-usage">
+              <h4>usage</h4>
+              <p className="doc-description">Show the usage ledger per subject, model, endpoint and day.</p>
+              <pre className="code-block">
+                <code>kronk security usage [flags]</code>
+              </pre>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Flag</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>--local</code></td>
+                    <td>Run without the model server</td>
+                  </tr>
+                  <tr>
+                    <td><code>--subject &lt;string&gt;</code></td>
+                    <td>Only show the usage for this token subject</td>
+                  </tr>
+                  <tr>
+                    <td><code>--from &lt;YYYY-MM-DD&gt;</code></td>
+                    <td>First day to include (default first day of the month)</td>
+                  </tr>
+                  <tr>
+                    <td><code>--to &lt;YYYY-MM-DD&gt;</code></td>
+                    <td>Last day to include (default today)</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Environment Variables</h5>
+              <table className="flags-table">
+                <thead>
+                  <tr>
+                    <th>Variable</th>
+                    <th>Default</th>
+                    <th>Description</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  <tr>
+                    <td><code>KRONK_TOKEN</code></td>
+                    <td></td>
+                    <td>Admin token (required when auth enabled)</td>
+                  </tr>
+                </tbody>
+              </table>
+              <h5>Example</h5>
+              <pre className="code-block">
+                <code>{`# Show this month's usage
+export KRONK_TOKEN=<admin-token>
+kronk security usage
+
+# Show the usage for one subject over a date range
+kronk security usage --subject <subject> --from 2026-01-01 --to 2026-01-31`}</code>
+              </pre>
+            </div>
           </div>
         </div>
 
@@ -245,6 +315,7 @@ kronk security token create --duration 720h --endpoints "chat-completions:1000/d
                 <li><a href="#cmd-key-list">list</a></li>
                 <li><a href="#cmd-token">token</a></li>
                 <li><a href="#cmd-token-create">create</a></li>
+                <li><a href="#cmd-usage">usage</a></li>
               </ul>
             </div>
           </div>
@@ -64,10 +64,30 @@ export default function DocsCLIServer() {
                     <td><code>--cache-ttl &lt;duration&gt;</code></td>
                     <td>Cache TTL duration (e.g., 5m, 1h)</td>
                   </tr>
+                  <tr>
+                    <td><code>--cache-max-memory &lt;bytes&gt;</code></td>
+                    <td>Memory budget in bytes for the models in cache</td>
+                  </tr>
                   <tr>
                     <td><code>--model-config-file &lt;string&gt;</code></td>
                     <td>Special config file for model specific config</td>
                   </tr>
+                  <tr>
+                    <td><code>--preload &lt;list&gt;</code></td>
+                    <td>Models to load at startup, readiness waits for them</td>
+                  </tr>
+                  <tr>
+                    <td><code>--subject-queue-limit &lt;int&gt;</code></td>
+                    <td>Maximum requests a subject can have waiting for a model slot (0=unlimited)</td>
+                  </tr>
+                  <tr>
+                    <td><code>--response-store &lt;string&gt;</code></td>
+                    <td>Store for the responses api: memory or badger</td>
+                  </tr>
+                  <tr>
+                    <td><code>--response-ttl &lt;duration&gt;</code></td>
+                    <td>How long stored responses are kept (e.g., 24h)</td>
+                  </tr>
                   <tr>
                     <td><code>--llama-log &lt;int&gt;</code></td>
                     <td>Llama log level (0=off, 1=on)</td>
@@ -26,6 +26,22 @@ export default function DocsSDKKronk() {
               <p className="doc-description">Init initializes the Kronk backend support.</p>
             </div>
 
+            <div className="doc-section" id="func-responseinputmessages">
+              <h4>ResponseInputMessages</h4>
+              <pre className="code-block">
+                <code>func ResponseInputMessages(input any) []model.D</code>
+              </pre>
+              <p className="doc-description">ResponseInputMessages converts the input of a Responses API request into chat messages.</p>
+            </div>
+
+            <div className="doc-section" id="func-responseoutputmessages">
+              <h4>ResponseOutputMessages</h4>
+              <pre className="code-block">
+                <code>func ResponseOutputMessages(items []ResponseOutputItem) []model.D</code>
+              </pre>
+              <p className="doc-description">ResponseOutputMessages converts the output items of a response into the chat messages of the assistant, so a later request can continue the conversation.</p>
+            </div>
+
             <div className="doc-section" id="func-setfmtloggertraceid">
               <h4>SetFmtLoggerTraceID</h4>
               <pre className="code-block">
@@ -34,6 +50,14 @@ export default function DocsSDKKronk() {
               <p className="doc-description">SetFmtLoggerTraceID allows you to set a trace id in the content that can be part of the output of the FmtLogger.</p>
             </div>
 
+            <div className="doc-section" id="func-setschedule">
+              <h4>SetSchedule</h4>
+              <pre className="code-block">
+                <code>func SetSchedule(ctx context.Context, s Schedule) context.Context</code>
+              </pre>
+              <p className="doc-description">SetSchedule returns a context that carries the schedule for the requests made with it.</p>
+            </div>
+
             <div className="doc-section" id="func-new">
               <h4>New</h4>
               <pre className="code-block">
@@ -41,11 +65,47 @@ export default function DocsSDKKronk() {
               </pre>
               <p className="doc-description">New provides the ability to use models in a concurrently safe way.</p>
             </div>
+
+            <div className="doc-section" id="func-parsepriority">
+              <h4>ParsePriority</h4>
+              <pre className="code-block">
+                <code>func ParsePriority(name string) (Priority, error)</code>
+              </pre>
+              <p className="doc-description">ParsePriority parses the name of a priority class. An empty name is the normal class.</p>
+            </div>
           </div>
 
           <div className="card" id="types">
             <h3>Types</h3>
 
+            <div className="doc-section" id="type-completionchoice">
+              <h4>CompletionChoice</h4>
+              <pre className="code-block">
+                <code>{`type CompletionChoice struct {
+	Text         string          \`json:"text"\`
+	Index        int             \`json:"index"\`
+	Logprobs     *model.Logprobs \`json:"logprobs"\`
+	FinishReason string          \`json:"finish_reason"\`
+}`}</code>
+              </pre>
+              <p className="doc-description">CompletionChoice represents a completion generated for a prompt.</p>
+            </div>
+
+            <div className="doc-section" id="type-completionresponse">
+              <h4>CompletionResponse</h4>
+              <pre className="code-block">
+                <code>{`type CompletionResponse struct {
+	ID      string             \`json:"id"\`
+	Object  string             \`json:"object"\`
+	Created int64              \`json:"created"\`
+	Model   string             \`json:"model"\`
+	Choices []CompletionChoice \`json:"choices"\`
+	Usage   model.Usage        \`json:"usage"\`
+}`}</code>
+              </pre>
+              <p className="doc-description">CompletionResponse represents the OpenAI completions API response format.</p>
+            </div>
+
             <div className="doc-section" id="type-incompletedetail">
               <h4>IncompleteDetail</h4>
               <pre className="code-block">
@@ -118,6 +178,14 @@ export default function DocsSDKKronk() {
               <p className="doc-description">OutputTokensDetails provides breakdown of output tokens.</p>
             </div>
 
+            <div className="doc-section" id="type-priority">
+              <h4>Priority</h4>
+              <pre className="code-block">
+                <code>{`type Priority int`}</code>
+              </pre>
+              <p className="doc-description">Priority represents the class a request is scheduled in. Requests in a higher class are given a slot before the requests in a lower class.</p>
+            </div>
+
             <div className="doc-section" id="type-responsecontentitem">
               <h4>ResponseContentItem</h4>
               <pre className="code-block">
@@ -207,6 +275,7 @@ export default function DocsSDKKronk() {
 	Usage            ResponseUsage          \`json:"usage"\`
 	User             *string                \`json:"user"\`
 	Metadata         map[string]interface{} \`json:"metadata"\`
+	Seed             uint32                 \`json:"seed"\`
 }`}</code>
               </pre>
               <p className="doc-description">ResponseResponse represents the OpenAI Responses API response format.</p>
@@ -256,6 +325,18 @@ export default function DocsSDKKronk() {
               </pre>
               <p className="doc-description">ResponseUsage contains token usage information.</p>
             </div>
+
+            <div className="doc-section" id="type-schedule">
+              <h4>Schedule</h4>
+              <pre className="code-block">
+                <code>{`type Schedule struct {
+	Subject  string
+	Priority Priority
+	Waiting  func(position int, eta time.Duration)
+}`}</code>
+              </pre>
+              <p className="doc-description">Schedule represents how a request is scheduled while it waits for a slot. Within a priority class, the slots are shared fairly across the subjects by taking turns. Waiting is called while the request waits with its position in the queue and an estimate of the time until it gets a slot.</p>
+            </div>
           </div>
 
           <div className="card" id="methods">
@@ -293,12 +374,44 @@ export default function DocsSDKKronk() {
               <p className="doc-description">ChatStreamingHTTP provides http handler support for a chat/completions call. For text models, NSeqMax controls parallel sequence processing within a single model instance. For vision/audio models, NSeqMax creates multiple model instances in a pool for concurrent request handling.</p>
             </div>
 
+            <div className="doc-section" id="method-kronk-completion">
+              <h4>Kronk.Completion</h4>
+              <pre className="code-block">
+                <code>func (krn *Kronk) Completion(ctx context.Context, d model.D) (CompletionResponse, error)</code>
+              </pre>
+              <p className="doc-description">Completion provides support to complete a raw prompt. The prompt can be a string or a list of strings, in which case each prompt is completed in turn and the choices of a prompt are indexed after the choices of the prompts before it.</p>
+            </div>
+
+            <div className="doc-section" id="method-kronk-completionstreaming">
+              <h4>Kronk.CompletionStreaming</h4>
+              <pre className="code-block">
+                <code>func (krn *Kronk) CompletionStreaming(ctx context.Context, d model.D) (&lt;-chan CompletionResponse, error)</code>
+              </pre>
+              <p className="doc-description">CompletionStreaming provides support to complete a raw prompt and stream the response. Each chunk holds the text generated for one choice.</p>
+            </div>
+
+            <div className="doc-section" id="method-kronk-completionstreaminghttp">
+              <h4>Kronk.CompletionStreamingHTTP</h4>
+              <pre className="code-block">
+                <code>func (krn *Kronk) CompletionStreamingHTTP(ctx context.Context, w http.ResponseWriter, d model.D) (CompletionResponse, error)</code>
+              </pre>
+              <p className="doc-description">CompletionStreamingHTTP provides http handler support for a completions call.</p>
+            </div>
+
+            <div className="doc-section" id="method-kronk-deletesession">
+              <h4>Kronk.DeleteSession</h4>
+              <pre className="code-block">
+                <code>func (krn *Kronk) DeleteSession(ctx context.Context, sessionID string) error</code>
+              </pre>
+              <p className="doc-description">DeleteSession removes the saved state for the session.</p>
+            </div>
+
             <div className="doc-section" id="method-kronk-embeddings">
               <h4>Kronk.Embeddings</h4>
               <pre className="code-block">
                 <code>func (krn *Kronk) Embeddings(ctx context.Context, d model.D) (model.EmbedReponse, error)</code>
               </pre>
-              <p className="doc-description">Embeddings provides support to interact with an embedding model. Supported options in d: - input (string): the text to embed (required) - truncate (bool): if true, truncate input to fit context window (default: false) - truncate_direction (string): "right" (default) or "left" - dimensions (int): reduce output to first N dimensions (for Matryoshka models) Each model instance processes calls sequentially (llama.cpp only supports sequence 0 for embedding extraction). Use NSeqMax &gt; 1 to create multiple model instances for concurrent request handling. Batch multiple texts in the input parameter for better performance within a single request.</p>
+              <p className="doc-description">Embeddings provides support to interact with an embedding model. Supported options in d: - input (string): the text to embed (required) - truncate (bool): if true, truncate input to fit context window (default: false) - truncate_direction (string): "right" (default) or "left" - dimensions (int): reduce output to first N dimensions (for Matryoshka models) - pooling (string): "mean", "cls" or "last" (default: the model's pooling) - normalize (bool): if false, return the vectors without L2 normalization (default: true) - encoding_format (string): "float" (default) or "base64" - chunking (bool): if true, split long inputs into overlapping token windows (default: false) - chunk_size (int): tokens per window (default: the largest input the model can embed) - chunk_overlap (int): tokens shared by consecutive windows (default: a tenth of chunk_size) - chunk_aggregate (string): "mean" (default), "weighted" by window length, or "none" to return each window's vector with its token offsets Each model instance processes calls sequentially, packing the inputs of a call into as few forward passes as possible. Use NSeqMax &gt; 1 to create multiple model instances for concurrent request handling. Batch multiple texts in the input parameter for better performance within a single request.</p>
             </div>
 
             <div className="doc-section" id="method-kronk-embeddingshttp">
@@ -365,6 +478,14 @@ export default function DocsSDKKronk() {
               <p className="doc-description">ResponseStreamingHTTP provides http handler support for a responses call. For text models, NSeqMax controls parallel sequence processing within a single model instance. For vision/audio models, NSeqMax creates multiple model instances in a pool for concurrent request handling.</p>
             </div>
 
+            <div className="doc-section" id="method-kronk-savesession">
+              <h4>Kronk.SaveSession</h4>
+              <pre className="code-block">
+                <code>func (krn *Kronk) SaveSession(ctx context.Context, sessionID string) error</code>
+              </pre>
+              <p className="doc-description">SaveSession writes the KV cache state and tokens held for the session to disk under the configured SessionDir. A later request that provides the same session_id restores this state instead of processing the prompt again, even after the model has been unloaded or the process restarted.</p>
+            </div>
+
             <div className="doc-section" id="method-kronk-systeminfo">
               <h4>Kronk.SystemInfo</h4>
               <pre className="code-block">
@@ -388,11 +509,27 @@ export default function DocsSDKKronk() {
               </pre>
               <p className="doc-description">Int returns the integer value.</p>
             </div>
+
+            <div className="doc-section" id="method-priority-string">
+              <h4>Priority.String</h4>
+              <pre className="code-block">
+                <code>func (p Priority) String() string</code>
+              </pre>
+              <p className="doc-description">String implements the fmt.Stringer interface.</p>
+            </div>
           </div>
 
           <div className="card" id="constants">
             <h3>Constants</h3>
 
+            <div className="doc-section" id="const-finishreasonlength">
+              <h4>FinishReasonLength</h4>
+              <pre className="code-block">
+                <code>{`const FinishReasonLength = "length"`}</code>
+              </pre>
+              <p className="doc-description">FinishReasonLength is the finish reason of a completion that was cut off by max_tokens.</p>
+            </div>
+
             <div className="doc-section" id="const-version">
               <h4>Version</h4>
               <pre className="code-block">
@@ -414,6 +551,14 @@ export default function DocsSDKKronk() {
               <p className="doc-description">DiscardLogger discards logging.</p>
             </div>
 
+            <div className="doc-section" id="var-errqueuefull">
+              <h4>ErrQueueFull</h4>
+              <pre className="code-block">
+                <code>{`var ErrQueueFull = errors.New("queue full")`}</code>
+              </pre>
+              <p className="doc-description">ErrQueueFull is returned when a subject has too many requests waiting for a slot.</p>
+            </div>
+
             <div className="doc-section" id="var-fmtlogger">
               <h4>FmtLogger</h4>
               <pre className="code-block">
@@ -445,13 +590,19 @@ export default function DocsSDKKronk() {
               <a href="#functions" className="doc-index-header">Functions</a>
               <ul>
                 <li><a href="#func-init">Init</a></li>
+                <li><a href="#func-responseinputmessages">ResponseInputMessages</a></li>
+                <li><a href="#func-responseoutputmessages">ResponseOutputMessages</a></li>
                 <li><a href="#func-setfmtloggertraceid">SetFmtLoggerTraceID</a></li>
+                <li><a href="#func-setschedule">SetSchedule</a></li>
                 <li><a href="#func-new">New</a></li>
+                <li><a href="#func-parsepriority">ParsePriority</a></li>
               </ul>
             </div>
             <div className="doc-index-section">
               <a href="#types" className="doc-index-header">Types</a>
               <ul>
+                <li><a href="#type-completionchoice">CompletionChoice</a></li>
+                <li><a href="#type-completionresponse">CompletionResponse</a></li>
                 <li><a href="#type-incompletedetail">IncompleteDetail</a></li>
                 <li><a href="#type-initoption">InitOption</a></li>
                 <li><a href="#type-inputtokensdetails">InputTokensDetails</a></li>
@@ -460,6 +611,7 @@ export default function DocsSDKKronk() {
                 <li><a href="#type-logger">Logger</a></li>
                 <li><a href="#type-option">Option</a></li>
                 <li><a href="#type-outputtokensdetails">OutputTokensDetails</a></li>
+                <li><a href="#type-priority">Priority</a></li>
                 <li><a href="#type-responsecontentitem">ResponseContentItem</a></li>
                 <li><a href="#type-responseerror">ResponseError</a></li>
                 <li><a href="#type-responseformattype">ResponseFormatType</a></li>
@@ -469,6 +621,7 @@ export default function DocsSDKKronk() {
                 <li><a href="#type-responsestreamevent">ResponseStreamEvent</a></li>
                 <li><a href="#type-responsetextformat">ResponseTextFormat</a></li>
                 <li><a href="#type-responseusage">ResponseUsage</a></li>
+                <li><a href="#type-schedule">Schedule</a></li>
               </ul>
             </div>
             <div className="doc-index-section">
@@ -478,6 +631,10 @@ export default function DocsSDKKronk() {
                 <li><a href="#method-kronk-chat">Kronk.Chat</a></li>
                 <li><a href="#method-kronk-chatstreaming">Kronk.ChatStreaming</a></li>
                 <li><a href="#method-kronk-chatstreaminghttp">Kronk.ChatStreamingHTTP</a></li>
+                <li><a href="#method-kronk-completion">Kronk.Completion</a></li>
+                <li><a href="#method-kronk-completionstreaming">Kronk.CompletionStreaming</a></li>
+                <li><a href="#method-kronk-completionstreaminghttp">Kronk.CompletionStreamingHTTP</a></li>
+                <li><a href="#method-kronk-deletesession">Kronk.DeleteSession</a></li>
                 <li><a href="#method-kronk-embeddings">Kronk.Embeddings</a></li>
                 <li><a href="#method-kronk-embeddingshttp">Kronk.EmbeddingsHTTP</a></li>
                 <li><a href="#method-kronk-modelconfig">Kronk.ModelConfig</a></li>
@@ -487,14 +644,17 @@ export default function DocsSDKKronk() {
                 <li><a href="#method-kronk-response">Kronk.Response</a></li>
                 <li><a href="#method-kronk-responsestreaming">Kronk.ResponseStreaming</a></li>
                 <li><a href="#method-kronk-responsestreaminghttp">Kronk.ResponseStreamingHTTP</a></li>
+                <li><a href="#method-kronk-savesession">Kronk.SaveSession</a></li>
                 <li><a href="#method-kronk-systeminfo">Kronk.SystemInfo</a></li>
                 <li><a href="#method-kronk-unload">Kronk.Unload</a></li>
                 <li><a href="#method-loglevel-int">LogLevel.Int</a></li>
+                <li><a href="#method-priority-string">Priority.String</a></li>
               </ul>
             </div>
             <div className="doc-index-section">
               <a href="#constants" className="doc-index-header">Constants</a>
               <ul>
+                <li><a href="#const-finishreasonlength">FinishReasonLength</a></li>
                 <li><a href="#const-version">Version</a></li>
               </ul>
             </div>
@@ -502,6 +662,7 @@ export default function DocsSDKKronk() {
               <a href="#variables" className="doc-index-header">Variables</a>
               <ul>
                 <li><a href="#var-discardlogger">DiscardLogger</a></li>
+                <li><a href="#var-errqueuefull">ErrQueueFull</a></li>
                 <li><a href="#var-fmtlogger">FmtLogger</a></li>
               </ul>
             </div>
@@ -26,6 +26,14 @@ export default function DocsSDKModel() {
               <p className="doc-description">CheckModel is check if the downloaded model is valid based on it's sha file. If no sha file exists, this check will return with no error.</p>
             </div>
 
+            <div className="doc-section" id="func-estimatememory">
+              <h4>EstimateMemory</h4>
+              <pre className="code-block">
+                <code>func EstimateMemory(cfg Config) (int64, error)</code>
+              </pre>
+              <p className="doc-description">EstimateMemory returns an estimate of the bytes needed to load the model described by the config. It covers the weights, which are sized by the model files, and the KV cache, which is calculated from the GGUF metadata, the context window and the cache types. Models that are loaded as a pool of instances are counted once per instance.</p>
+            </div>
+
             <div className="doc-section" id="func-parseggmltype">
               <h4>ParseGGMLType</h4>
               <pre className="code-block">
@@ -64,6 +72,7 @@ export default function DocsSDKModel() {
 	Choice  []Choice \`json:"choices"\`
 	Usage   Usage    \`json:"usage"\`
 	Prompt  string   \`json:"prompt"\`
+	Seed    uint32   \`json:"seed"\`
 }`}</code>
               </pre>
               <p className="doc-description">ChatResponse represents output for inference models.</p>
@@ -76,6 +85,7 @@ export default function DocsSDKModel() {
 	Index        int              \`json:"index"\`
 	Message      ResponseMessage  \`json:"message,omitempty"\`
 	Delta        *ResponseMessage \`json:"delta,omitempty"\`
+	Logprobs     *Logprobs        \`json:"logprobs,omitempty"\`
 	FinishReason string           \`json:"finish_reason"\`
 }`}</code>
               </pre>
@@ -106,9 +116,28 @@ export default function DocsSDKModel() {
 	OpOffload            *bool
 	NGpuLayers           *int32
 	SplitMode            SplitMode
+	SessionDir           string
+	SessionMaxBytes      int64
+	DraftModelFiles      []string
+	DraftTokens          int
+	ToolParser           string
+	FixedPooling         bool
+}`}</code>
+              </pre>
+              <p className="doc-description">Config represents model level configuration. These values if configured incorrectly can cause the system to panic. The defaults are used when these values are set to 0. ModelInstances is the number of instances of the model to create. Unless you have more than 1 GPU, the recommended number of instances is 1. ModelFiles is the path to the model files. This is mandatory to provide. ProjFiles is the path to the projection files. This is mandatory for media based models like vision and audio. JinjaFile is the path to the jinja file. This is not required and can be used if you want to override the templated provided by the model metadata. Device is the device to use for the model. If not set, the default device will be used. To see what devices are available, run the following command which will be found where you installed llama.cpp. $ llama-bench --list-devices ContextWindow (often referred to as context length) is the maximum number of tokens that a large language model can process and consider at one time when generating a response. It defines the model's effective "memory" for a single conversation or text generation task. When set to 0, the default value is 4096. NBatch is the logical batch size or the maximum number of tokens that can be in a single forward pass through the model at any given time. It defines the maximum capacity of the processing batch. If you are processing a very long prompt or multiple prompts simultaneously, the total number of tokens processed in one go will not exceed NBatch. Increasing n_batch can improve performance (throughput) if your hardware can handle it, as it better utilizes parallel computation. However, a very high n_batch can lead to out-of-memory errors on systems with limited VRAM. When set to 0, the default value is 2048. NUBatch is the physical batch size or the maximum number of tokens processed together during the initial prompt processing phase (also called "prompt ingestion") to populate the KV cache. It specifically optimizes the initial loading of prompt tokens into the KV cache. If a prompt is longer than NUBatch, it will be broken down and processed in chunks of n_ubatch tokens sequentially. This parameter is crucial for tuning performance on specific hardware (especially GPUs) because different values might yield better prompt processing times depending on the memory architecture. When set to 0, the default value is 512. NThreads is the number of threads to use for generation. When set to 0, the default llama.cpp value is used. NThreadsBatch is the number of threads to use for batch processing. When set to 0, the default llama.cpp value is used. CacheTypeK is the data type for the K (key) cache. This controls the precision of the key vectors in the KV cache. Lower precision types (like Q8_0 or Q4_0) reduce memory usage but may slightly affect quality. When set to GGMLTypeAuto or left as zero value, the default llama.cpp value (F16) is used. CacheTypeV is the data type for the V (value) cache. This controls the precision of the value vectors in the KV cache. When set to GGMLTypeAuto or left as zero value, the default llama.cpp value (F16) is used. FlashAttention controls Flash Attention mode. Flash Attention reduces memory usage and speeds up attention computation, especially for large context windows. When left as zero value, FlashAttentionEnabled is used (default on). Set to FlashAttentionDisabled to disable, or FlashAttentionAuto to let llama.cpp decide. IgnoreIntegrityCheck is a boolean that determines if the system should ignore a model integrity check before trying to use it. NSeqMax controls concurrency behavior based on model type. For text inference models, it sets the maximum number of sequences processed in parallel within a single model instance (batched inference). For sequential models (embeddings, reranking, vision, audio), it creates that many model instances in a pool for concurrent request handling. An embedding instance also decodes up to NSeqMax+1 inputs of a call in a single pass. When set to 0, a default of 1 is used. OffloadKQV controls whether the KV cache is offloaded to the GPU. When nil or true, the KV cache is stored on the GPU (default behavior). Set to false to keep the KV cache on the CPU, which reduces VRAM usage but may slow inference. OpOffload controls whether host tensor operations are offloaded to the device (GPU). When nil or true, operations are offloaded (default behavior). Set to false to keep operations on the CPU. NGpuLayers is the number of model layers to offload to the GPU. When set to 0, all layers are offloaded (default). Set to -1 to keep all layers on CPU. Any positive value specifies the exact number of layers to offload. SplitMode controls how the model is split across multiple GPUs: - SplitModeNone (0): single GPU - SplitModeLayer (1): split layers and KV across GPUs - SplitModeRow (2): split layers and KV across GPUs with tensor parallelism (recommended for MoE models like Qwen3-MoE, Mixtral, DeepSeek) When not set, defaults to SplitModeRow for optimal MoE performance. SessionDir is the folder where session KV cache state is saved. Each model uses its own sub-folder. When empty, sessions are not saved to disk. SessionMaxBytes bounds the total size of the saved sessions for a model. When exceeded, the least recently used sessions are removed. When set to 0, the default value is 4GB. DraftModelFiles is the path to the files of a small model used for speculative decoding. The draft model proposes tokens that the main model verifies in a single decode, which speeds up generation when the draft predicts well. The draft model must share the vocabulary of the main model. When empty, speculative decoding is not used. DraftTokens is the maximum number of tokens the draft model proposes at a time. When set to 0, the default value is 8. ToolParser is the name of the parser used to find the tool calls in the generated text: hermes, mistral, llama3, functionary, granite, deepseek or gpt-oss. When empty, the parser is selected from the chat template and the model metadata, with hermes used when nothing matches. FixedPooling rejects embedding calls that ask for a pooling other than the one of the model. Each other pooling needs a context of its own, which EstimateMemory doesn't count, so set this when memory use must stay within the estimate.</p>
+            </div>
+
+            <div className="doc-section" id="type-contentlogprob">
+              <h4>ContentLogprob</h4>
+              <pre className="code-block">
+                <code>{`type ContentLogprob struct {
+	Token       string       \`json:"token"\`
+	Logprob     float32      \`json:"logprob"\`
+	Bytes       []int        \`json:"bytes"\`
+	TopLogprobs []TopLogprob \`json:"top_logprobs"\`
 }`}</code>
               </pre>
-              <p className="doc-description">Config represents model level configuration. These values if configured incorrectly can cause the system to panic. The defaults are used when these values are set to 0. ModelInstances is the number of instances of the model to create. Unless you have more than 1 GPU, the recommended number of instances is 1. ModelFiles is the path to the model files. This is mandatory to provide. ProjFiles is the path to the projection files. This is mandatory for media based models like vision and audio. JinjaFile is the path to the jinja file. This is not required and can be used if you want to override the templated provided by the model metadata. Device is the device to use for the model. If not set, the default device will be used. To see what devices are available, run the following command which will be found where you installed llama.cpp. $ llama-bench --list-devices ContextWindow (often referred to as context length) is the maximum number of tokens that a large language model can process and consider at one time when generating a response. It defines the model's effective "memory" for a single conversation or text generation task. When set to 0, the default value is 4096. NBatch is the logical batch size or the maximum number of tokens that can be in a single forward pass through the model at any given time. It defines the maximum capacity of the processing batch. If you are processing a very long prompt or multiple prompts simultaneously, the total number of tokens processed in one go will not exceed NBatch. Increasing n_batch can improve performance (throughput) if your hardware can handle it, as it better utilizes parallel computation. However, a very high n_batch can lead to out-of-memory errors on systems with limited VRAM. When set to 0, the default value is 2048. NUBatch is the physical batch size or the maximum number of tokens processed together during the initial prompt processing phase (also called "prompt ingestion") to populate the KV cache. It specifically optimizes the initial loading of prompt tokens into the KV cache. If a prompt is longer than NUBatch, it will be broken down and processed in chunks of n_ubatch tokens sequentially. This parameter is crucial for tuning performance on specific hardware (especially GPUs) because different values might yield better prompt processing times depending on the memory architecture. When set to 0, the default value is 512. NThreads is the number of threads to use for generation. When set to 0, the default llama.cpp value is used. NThreadsBatch is the number of threads to use for batch processing. When set to 0, the default llama.cpp value is used. CacheTypeK is the data type for the K (key) cache. This controls the precision of the key vectors in the KV cache. Lower precision types (like Q8_0 or Q4_0) reduce memory usage but may slightly affect quality. When set to GGMLTypeAuto or left as zero value, the default llama.cpp value (F16) is used. CacheTypeV is the data type for the V (value) cache. This controls the precision of the value vectors in the KV cache. When set to GGMLTypeAuto or left as zero value, the default llama.cpp value (F16) is used. FlashAttention controls Flash Attention mode. Flash Attention reduces memory usage and speeds up attention computation, especially for large context windows. When left as zero value, FlashAttentionEnabled is used (default on). Set to FlashAttentionDisabled to disable, or FlashAttentionAuto to let llama.cpp decide. IgnoreIntegrityCheck is a boolean that determines if the system should ignore a model integrity check before trying to use it. NSeqMax controls concurrency behavior based on model type. For text inference models, it sets the maximum number of sequences processed in parallel within a single model instance (batched inference). For sequential models (embeddings, reranking, vision, audio), it creates that many model instances in a pool for concurrent request handling. When set to 0, a default of 1 is used. OffloadKQV controls whether the KV cache is offloaded to the GPU. When nil or true, the KV cache is stored on the GPU (default behavior). Set to false to keep the KV cache on the CPU, which reduces VRAM usage but may slow inference. OpOffload controls whether host tensor operations are offloaded to the device (GPU). When nil or true, operations are offloaded (default behavior). Set to false to keep operations on the CPU. NGpuLayers is the number of model layers to offload to the GPU. When set to 0, all layers are offloaded (default). Set to -1 to keep all layers on CPU. Any positive value specifies the exact number of layers to offload. SplitMode controls how the model is split across multiple GPUs: - SplitModeNone (0): single GPU - SplitModeLayer (1): split layers and KV across GPUs - SplitModeRow (2): split layers and KV across GPUs with tensor parallelism (recommended for MoE models like Qwen3-MoE, Mixtral, DeepSeek) When not set, defaults to SplitModeRow for optimal MoE performance.</p>
+              <p className="doc-description">ContentLogprob provides the log probability of a generated token and the most likely tokens at that position.</p>
             </div>
 
             <div className="doc-section" id="type-d">
@@ -119,16 +148,30 @@ export default function DocsSDKModel() {
               <p className="doc-description">D represents a generic docment of fields and values.</p>
             </div>
 
+            <div className="doc-section" id="type-embedchunk">
+              <h4>EmbedChunk</h4>
+              <pre className="code-block">
+                <code>{`type EmbedChunk struct {
+	Index int \`json:"index"\`
+	Start int \`json:"start"\`
+	End   int \`json:"end"\`
+}`}</code>
+              </pre>
+              <p className="doc-description">EmbedChunk identifies the window of an input a chunk embedding was made from. Start and End are the token offsets of the window within the input, not counting the special tokens.</p>
+            </div>
+
             <div className="doc-section" id="type-embeddata">
               <h4>EmbedData</h4>
               <pre className="code-block">
                 <code>{`type EmbedData struct {
-	Object    string    \`json:"object"\`
-	Index     int       \`json:"index"\`
-	Embedding []float32 \`json:"embedding"\`
+	Object         string      \`json:"object"\`
+	Index          int         \`json:"index"\`
+	Embedding      []float32   \`json:"embedding"\`
+	Chunk          *EmbedChunk \`json:"chunk,omitempty"\`
+	EncodingFormat string      \`json:"-"\`
 }`}</code>
               </pre>
-              <p className="doc-description">EmbedData represents the data associated with an embedding call.</p>
+              <p className="doc-description">EmbedData represents the data associated with an embedding call. When the EncodingFormat is base64, the embedding marshals as a base64 string of the little-endian float32 values. Chunk is set when the chunk vectors of an input are returned without being aggregated.</p>
             </div>
 
             <div className="doc-section" id="type-embedreponse">
@@ -180,6 +223,16 @@ export default function DocsSDKModel() {
               <p className="doc-description">Logger provides a function for logging messages from different APIs.</p>
             </div>
 
+            <div className="doc-section" id="type-logprobs">
+              <h4>Logprobs</h4>
+              <pre className="code-block">
+                <code>{`type Logprobs struct {
+	Content []ContentLogprob \`json:"content"\`
+}`}</code>
+              </pre>
+              <p className="doc-description">Logprobs provides the log probabilities of the content tokens.</p>
+            </div>
+
             <div className="doc-section" id="type-mediatype">
               <h4>MediaType</h4>
               <pre className="code-block">
@@ -215,11 +268,22 @@ export default function DocsSDKModel() {
 	Metadata      map[string]string
 	TemplateFile  string
 	Template      Template
+	ToolParser    string
 }`}</code>
               </pre>
               <p className="doc-description">ModelInfo represents the model's card information.</p>
             </div>
 
+            <div className="doc-section" id="type-prompttokensdetails">
+              <h4>PromptTokensDetails</h4>
+              <pre className="code-block">
+                <code>{`type PromptTokensDetails struct {
+	CachedTokens int \`json:"cached_tokens"\`
+}`}</code>
+              </pre>
+              <p className="doc-description">PromptTokensDetails provides a breakdown of the prompt tokens.</p>
+            </div>
+
             <div className="doc-section" id="type-rerankresponse">
               <h4>RerankResponse</h4>
               <pre className="code-block">
@@ -332,16 +396,29 @@ export default function DocsSDKModel() {
               <p className="doc-description">ToolCallArguments represents tool call arguments that marshal to a JSON string per OpenAI API spec, but can unmarshal from either a string or object.</p>
             </div>
 
+            <div className="doc-section" id="type-toplogprob">
+              <h4>TopLogprob</h4>
+              <pre className="code-block">
+                <code>{`type TopLogprob struct {
+	Token   string  \`json:"token"\`
+	Logprob float32 \`json:"logprob"\`
+	Bytes   []int   \`json:"bytes"\`
+}`}</code>
+              </pre>
+              <p className="doc-description">TopLogprob provides the log probability of one of the most likely tokens.</p>
+            </div>
+
             <div className="doc-section" id="type-usage">
               <h4>Usage</h4>
               <pre className="code-block">
                 <code>{`type Usage struct {
-	PromptTokens     int     \`json:"prompt_tokens"\`
-	ReasoningTokens  int     \`json:"reasoning_tokens"\`
-	CompletionTokens int     \`json:"completion_tokens"\`
-	OutputTokens     int     \`json:"output_tokens"\`
-	TotalTokens      int     \`json:"total_tokens"\`
-	TokensPerSecond  float64 \`json:"tokens_per_second"\`
+	PromptTokens        int                 \`json:"prompt_tokens"\`
+	PromptTokensDetails PromptTokensDetails \`json:"prompt_tokens_details"\`
+	ReasoningTokens     int                 \`json:"reasoning_tokens"\`
+	CompletionTokens    int                 \`json:"completion_tokens"\`
+	OutputTokens        int                 \`json:"output_tokens"\`
+	TotalTokens         int                 \`json:"total_tokens"\`
+	TokensPerSecond     float64             \`json:"tokens_per_second"\`
 }`}</code>
               </pre>
               <p className="doc-description">Usage provides details usage information for the request.</p>
@@ -367,6 +444,20 @@ export default function DocsSDKModel() {
               <p className="doc-description">LogSafe returns a copy of the document containing only fields that are safe to log. This excludes sensitive fields like messages and input which may contain private user data.</p>
             </div>
 
+            <div className="doc-section" id="method-embeddata-marshaljson">
+              <h4>EmbedData.MarshalJSON</h4>
+              <pre className="code-block">
+                <code>func (ed EmbedData) MarshalJSON() ([]byte, error)</code>
+              </pre>
+            </div>
+
+            <div className="doc-section" id="method-embeddata-unmarshaljson">
+              <h4>EmbedData.UnmarshalJSON</h4>
+              <pre className="code-block">
+                <code>func (ed *EmbedData) UnmarshalJSON(data []byte) error</code>
+              </pre>
+            </div>
+
             <div className="doc-section" id="method-flashattentiontype-unmarshalyaml">
               <h4>FlashAttentionType.UnmarshalYAML</h4>
               <pre className="code-block">
@@ -414,6 +505,14 @@ export default function DocsSDKModel() {
               <p className="doc-description">ChatStreaming performs a chat request and streams the response. Text inference requests can run concurrently based on the NSeqMax config value, which controls parallel sequence processing. However, requests that include vision or audio content are processed sequentially due to media pipeline constraints.</p>
             </div>
 
+            <div className="doc-section" id="method-model-completionstreaming">
+              <h4>Model.CompletionStreaming</h4>
+              <pre className="code-block">
+                <code>func (m *Model) CompletionStreaming(ctx context.Context, d D) &lt;-chan ChatResponse</code>
+              </pre>
+              <p className="doc-description">CompletionStreaming performs a completion request for a raw prompt and streams the response. The prompt is given to the model as is, without the chat template, and the output isn't parsed for reasoning or tool calls. When the request has a suffix, the model fills in the text between the prompt and the suffix, which requires a model with fill-in-the-middle tokens.</p>
+            </div>
+
             <div className="doc-section" id="method-model-config">
               <h4>Model.Config</h4>
               <pre className="code-block">
@@ -421,12 +520,20 @@ export default function DocsSDKModel() {
               </pre>
             </div>
 
+            <div className="doc-section" id="method-model-deletesession">
+              <h4>Model.DeleteSession</h4>
+              <pre className="code-block">
+                <code>func (m *Model) DeleteSession(ctx context.Context, sessionID string) error</code>
+              </pre>
+              <p className="doc-description">DeleteSession removes the saved state for the session.</p>
+            </div>
+
             <div className="doc-section" id="method-model-embeddings">
               <h4>Model.Embeddings</h4>
               <pre className="code-block">
                 <code>func (m *Model) Embeddings(ctx context.Context, d D) (EmbedReponse, error)</code>
               </pre>
-              <p className="doc-description">Embeddings performs batch embedding for multiple inputs in a single forward pass. This is more efficient than calling Embeddings multiple times. Supported options in d: - input ([]string): the texts to embed (required) - truncate (bool): if true, truncate inputs to fit context window (default: false) - truncate_direction (string): "right" (default) or "left" - dimensions (int): reduce output to first N dimensions (for Matryoshka models) Each model instance processes calls sequentially (llama.cpp only supports sequence 0 for embedding extraction). Use NSeqMax &gt; 1 to create multiple model instances for concurrent request handling. Batch multiple texts in the input parameter for better performance within a single request.</p>
+              <p className="doc-description">Embeddings performs batch embedding for multiple inputs. The inputs are packed into as few forward passes as possible, each input on its own sequence, and the model context is reused across calls. Supported options in d: - input ([]string): the texts to embed (required) - truncate (bool): if true, truncate inputs to fit context window (default: false) - truncate_direction (string): "right" (default) or "left" - dimensions (int): reduce output to first N dimensions (for Matryoshka models) - pooling (string): "mean", "cls" or "last" (default: the model's pooling) - normalize (bool): if false, return the vectors without L2 normalization (default: true) - encoding_format (string): "float" (default) or "base64" - chunking (bool): if true, split long inputs into overlapping token windows (default: false) - chunk_size (int): tokens per window (default: the largest input the model can embed) - chunk_overlap (int): tokens shared by consecutive windows (default: a tenth of chunk_size) - chunk_aggregate (string): "mean" (default), "weighted" by window length, or "none" to return each window's vector with its token offsets Each model instance processes calls sequentially. Use NSeqMax &gt; 1 to create multiple model instances for concurrent request handling. Batch multiple texts in the input parameter for better performance within a single request.</p>
             </div>
 
             <div className="doc-section" id="method-model-modelinfo">
@@ -444,6 +551,14 @@ export default function DocsSDKModel() {
               <p className="doc-description">Rerank performs reranking for a query against multiple documents. It scores each document's relevance to the query and returns results sorted by relevance score (highest first). Supported options in d: - query (string): the query to rank documents against (required) - documents ([]string): the documents to rank (required) - top_n (int): return only the top N results (optional, default: all) - return_documents (bool): include document text in results (default: false) Each model instance processes calls sequentially (llama.cpp only supports sequence 0 for rerank extraction). Use NSeqMax &gt; 1 to create multiple model instances for concurrent request handling. Batch multiple texts in the input parameter for better performance within a single request.</p>
             </div>
 
+            <div className="doc-section" id="method-model-savesession">
+              <h4>Model.SaveSession</h4>
+              <pre className="code-block">
+                <code>func (m *Model) SaveSession(ctx context.Context, sessionID string) error</code>
+              </pre>
+              <p className="doc-description">SaveSession writes the KV cache state and the tokens held for the session to disk. A later request that provides the same session_id restores this state instead of processing the prompt again. The state is also saved when the slot holding it is reused by another session and when the model is unloaded.</p>
+            </div>
+
             <div className="doc-section" id="method-model-unload">
               <h4>Model.Unload</h4>
               <pre className="code-block">
@@ -493,14 +608,27 @@ export default function DocsSDKModel() {
           <div className="card" id="constants">
             <h3>Constants</h3>
 
+            <div className="doc-section" id="const-chunkaggregatenone">
+              <h4>ChunkAggregateNone</h4>
+              <pre className="code-block">
+                <code>{`const (
+	ChunkAggregateNone     = "none"
+	ChunkAggregateMean     = "mean"
+	ChunkAggregateWeighted = "weighted"
+)`}</code>
+              </pre>
+              <p className="doc-description">Set of ways the chunk vectors of an input are combined.</p>
+            </div>
+
             <div className="doc-section" id="const-objectchatunknown">
               <h4>ObjectChatUnknown</h4>
               <pre className="code-block">
                 <code>{`const (
-	ObjectChatUnknown   = "chat.unknown"
-	ObjectChatText      = "chat.completion.chunk"
-	ObjectChatTextFinal = "chat.completion"
-	ObjectChatMedia     = "chat.media"
+	ObjectChatUnknown    = "chat.unknown"
+	ObjectChatText       = "chat.completion.chunk"
+	ObjectChatTextFinal  = "chat.completion"
+	ObjectChatMedia      = "chat.media"
+	ObjectTextCompletion = "text_completion"
 )`}</code>
               </pre>
               <p className="doc-description">Objects represent the different types of data that is being processed.</p>
@@ -530,6 +658,17 @@ export default function DocsSDKModel() {
               <p className="doc-description">FinishReasons represent the different reasons a response can be finished.</p>
             </div>
 
+            <div className="doc-section" id="const-encodingformatfloat">
+              <h4>EncodingFormatFloat</h4>
+              <pre className="code-block">
+                <code>{`const (
+	EncodingFormatFloat  = "float"
+	EncodingFormatBase64 = "base64"
+)`}</code>
+              </pre>
+              <p className="doc-description">Set of encoding formats for the embedding vectors.</p>
+            </div>
+
             <div className="doc-section" id="const-thinkingenabled">
               <h4>ThinkingEnabled</h4>
               <pre className="code-block">
@@ -572,6 +711,22 @@ export default function DocsSDKModel() {
 )`}</code>
               </pre>
             </div>
+
+            <div className="doc-section" id="const-toolparserhermes">
+              <h4>ToolParserHermes</h4>
+              <pre className="code-block">
+                <code>{`const (
+	ToolParserHermes      = "hermes"
+	ToolParserMistral     = "mistral"
+	ToolParserLlama3      = "llama3"
+	ToolParserFunctionary = "functionary"
+	ToolParserGranite     = "granite"
+	ToolParserDeepSeek    = "deepseek"
+	ToolParserGPT         = "gpt-oss"
+)`}</code>
+              </pre>
+              <p className="doc-description">Set of tool call parsers that can be selected in the config.</p>
+            </div>
           </div>
         </div>
 
@@ -581,6 +736,7 @@ export default function DocsSDKModel() {
               <a href="#functions" className="doc-index-header">Functions</a>
               <ul>
                 <li><a href="#func-checkmodel">CheckModel</a></li>
+                <li><a href="#func-estimatememory">EstimateMemory</a></li>
                 <li><a href="#func-parseggmltype">ParseGGMLType</a></li>
                 <li><a href="#func-newmodel">NewModel</a></li>
                 <li><a href="#func-parsesplitmode">ParseSplitMode</a></li>
@@ -592,16 +748,20 @@ export default function DocsSDKModel() {
                 <li><a href="#type-chatresponse">ChatResponse</a></li>
                 <li><a href="#type-choice">Choice</a></li>
                 <li><a href="#type-config">Config</a></li>
+                <li><a href="#type-contentlogprob">ContentLogprob</a></li>
                 <li><a href="#type-d">D</a></li>
+                <li><a href="#type-embedchunk">EmbedChunk</a></li>
                 <li><a href="#type-embeddata">EmbedData</a></li>
                 <li><a href="#type-embedreponse">EmbedReponse</a></li>
                 <li><a href="#type-embedusage">EmbedUsage</a></li>
                 <li><a href="#type-flashattentiontype">FlashAttentionType</a></li>
                 <li><a href="#type-ggmltype">GGMLType</a></li>
                 <li><a href="#type-logger">Logger</a></li>
+                <li><a href="#type-logprobs">Logprobs</a></li>
                 <li><a href="#type-mediatype">MediaType</a></li>
                 <li><a href="#type-model">Model</a></li>
                 <li><a href="#type-modelinfo">ModelInfo</a></li>
+                <li><a href="#type-prompttokensdetails">PromptTokensDetails</a></li>
                 <li><a href="#type-rerankresponse">RerankResponse</a></li>
                 <li><a href="#type-rerankresult">RerankResult</a></li>
                 <li><a href="#type-rerankusage">RerankUsage</a></li>
@@ -612,6 +772,7 @@ export default function DocsSDKModel() {
                 <li><a href="#type-template">Template</a></li>
                 <li><a href="#type-templateretriever">TemplateRetriever</a></li>
                 <li><a href="#type-toolcallarguments">ToolCallArguments</a></li>
+                <li><a href="#type-toplogprob">TopLogprob</a></li>
                 <li><a href="#type-usage">Usage</a></li>
               </ul>
             </div>
@@ -620,16 +781,21 @@ export default function DocsSDKModel() {
               <ul>
                 <li><a href="#method-d-clone">D.Clone</a></li>
                 <li><a href="#method-d-logsafe">D.LogSafe</a></li>
+                <li><a href="#method-embeddata-marshaljson">EmbedData.MarshalJSON</a></li>
+                <li><a href="#method-embeddata-unmarshaljson">EmbedData.UnmarshalJSON</a></li>
                 <li><a href="#method-flashattentiontype-unmarshalyaml">FlashAttentionType.UnmarshalYAML</a></li>
                 <li><a href="#method-ggmltype-string">GGMLType.String</a></li>
                 <li><a href="#method-ggmltype-toyzmatype">GGMLType.ToYZMAType</a></li>
                 <li><a href="#method-ggmltype-unmarshalyaml">GGMLType.UnmarshalYAML</a></li>
                 <li><a href="#method-model-chat">Model.Chat</a></li>
                 <li><a href="#method-model-chatstreaming">Model.ChatStreaming</a></li>
+                <li><a href="#method-model-completionstreaming">Model.CompletionStreaming</a></li>
                 <li><a href="#method-model-config">Model.Config</a></li>
+                <li><a href="#method-model-deletesession">Model.DeleteSession</a></li>
                 <li><a href="#method-model-embeddings">Model.Embeddings</a></li>
                 <li><a href="#method-model-modelinfo">Model.ModelInfo</a></li>
                 <li><a href="#method-model-rerank">Model.Rerank</a></li>
+                <li><a href="#method-model-savesession">Model.SaveSession</a></li>
                 <li><a href="#method-model-unload">Model.Unload</a></li>
                 <li><a href="#method-splitmode-string">SplitMode.String</a></li>
                 <li><a href="#method-splitmode-toyzmatype">SplitMode.ToYZMAType</a></li>
@@ -641,11 +807,14 @@ export default function DocsSDKModel() {
             <div className="doc-index-section">
               <a href="#constants" className="doc-index-header">Constants</a>
               <ul>
+                <li><a href="#const-chunkaggregatenone">ChunkAggregateNone</a></li>
                 <li><a href="#const-objectchatunknown">ObjectChatUnknown</a></li>
                 <li><a href="#const-roleuser">RoleUser</a></li>
                 <li><a href="#const-finishreasonstop">FinishReasonStop</a></li>
+                <li><a href="#const-encodingformatfloat">EncodingFormatFloat</a></li>
                 <li><a href="#const-thinkingenabled">ThinkingEnabled</a></li>
                 <li><a href="#const-reasoningeffortnone">ReasoningEffortNone</a></li>
+                <li><a href="#const-toolparserhermes">ToolParserHermes</a></li>
               </ul>
             </div>
           </div>
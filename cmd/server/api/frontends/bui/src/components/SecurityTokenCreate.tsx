@@ -2,15 +2,19 @@ import { useState } from 'react';
 import { Link } from 'react-router-dom';
 import { api } from '../services/api';
 import { useToken } from '../contexts/TokenContext';
-import { RateLimit, RateWindow } from '../types';
+import { Priority, RateLimit, RateWindow } from '../types';
 
 const AVAILABLE_ENDPOINTS = [
   { label: '/v1/chat/completions', value: 'chat-completions' },
+  { label: '/v1/completions', value: 'completions' },
+  { label: '/v1/messages', value: 'messages' },
   { label: '/v1/embeddings', value: 'embeddings' },
 ];
 
 const RATE_WINDOWS: { label: string; value: RateWindow }[] = [
   { label: 'Unlimited', value: 'unlimited' },
+  { label: 'Per Minute', value: 'minute' },
+  { label: 'Per Hour', value: 'hour' },
   { label: 'Per Day', value: 'day' },
   { label: 'Per Month', value: 'month' },
   { label: 'Per Year', value: 'year' },
@@ -19,6 +23,7 @@ const RATE_WINDOWS: { label: string; value: RateWindow }[] = [
 interface EndpointConfig {
   enabled: boolean;
   limit: number;
+  tokens: number;
   window: RateWindow;
 }
 
@@ -27,6 +32,7 @@ type EndpointConfigs = Record<string, EndpointConfig>;
 const defaultEndpointConfig = (): EndpointConfig => ({
   enabled: false,
   limit: 1000,
+  tokens: 0,
   window: 'unlimited',
 });
 
@@ -40,6 +46,7 @@ export default function SecurityTokenCreate() {
     });
     return configs;
   });
+  const [priority, setPriority] = useState<Priority>('normal');
   const [duration, setDuration] = useState('24');
   const [durationUnit, setDurationUnit] = useState<'h' | 'd' | 'M' | 'y'>('h');
   const [loading, setLoading] = useState(false);
@@ -87,6 +94,7 @@ export default function SecurityTokenCreate() {
         endpoints[name] = {
           limit: config.window === 'unlimited' ? 0 : config.limit,
           window: config.window,
+          total_tokens: config.window === 'unlimited' ? 0 : config.tokens,
         };
       }
     });
@@ -96,6 +104,7 @@ export default function SecurityTokenCreate() {
         admin: isAdmin,
         endpoints,
         duration: durationNs,
+        priority,
       });
       setNewToken(response.token);
     } catch (err) {
@@ -234,6 +243,32 @@ export default function SecurityTokenCreate() {
                               />
                             </div>
                           )}
+
+                          {config.window !== 'unlimited' && (
+                            <div style={{ flex: 1 }}>
+                              <label
+                                style={{
+                                  fontSize: '12px',
+                                  color: 'var(--color-gray-600)',
+                                  display: 'block',
+                                  marginBottom: '4px',
+                                }}
+                              >
+                                Max Tokens (0 = no budget)
+                              </label>
+                              <input
+                                type="number"
+                                value={config.tokens}
+                                onChange={(e) =>
+                                  updateEndpointConfig(endpoint.value, {
+                                    tokens: parseInt(e.target.value) || 0,
+                                  })
+                                }
+                                min="0"
+                                style={{ width: '100%' }}
+                              />
+                            </div>
+                          )}
                         </div>
                       )}
                     </div>
@@ -242,6 +277,19 @@ export default function SecurityTokenCreate() {
               </div>
             </div>
 
+            <div className="form-group">
+              <label htmlFor="priority">Priority</label>
+              <select
+                id="priority"
+                value={priority}
+                onChange={(e) => setPriority(e.target.value as Priority)}
+              >
+                <option value="low">Low</option>
+                <option value="normal">Normal</option>
+                <option value="high">High</option>
+              </select>
+            </div>
+
             <div className="form-row">
               <div className="form-group">
                 <label htmlFor="duration">Duration</label>
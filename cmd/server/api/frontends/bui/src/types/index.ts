@@ -7,6 +7,7 @@ export interface ListModelDetail {
   size: number;
   modified: string;
   validated: boolean;
+  alias_for?: string;
 }
 
 export interface ListModelInfoResponse {
@@ -21,6 +22,7 @@ export interface ModelDetail {
   size: number;
   expires_at: string;
   active_streams: number;
+  pinned: boolean;
 }
 
 export type ModelDetailsResponse = ModelDetail[];
@@ -113,19 +115,25 @@ export interface VersionResponse {
   current?: string;
 }
 
-export type RateWindow = 'day' | 'month' | 'year' | 'unlimited';
+export type RateWindow = 'minute' | 'hour' | 'day' | 'month' | 'year' | 'unlimited';
 
 export interface RateLimit {
   limit: number;
   window: RateWindow;
+  prompt_tokens?: number;
+  completion_tokens?: number;
+  total_tokens?: number;
 }
 
 export interface TokenRequest {
   admin: boolean;
   endpoints: Record<string, RateLimit>;
   duration: number;
+  priority?: Priority;
 }
 
+export type Priority = 'low' | 'normal' | 'high';
+
 export interface TokenResponse {
   token: string;
 }
@@ -178,6 +186,9 @@ export interface ChatChoice {
 
 export interface ChatUsage {
   prompt_tokens: number;
+  prompt_tokens_details?: {
+    cached_tokens: number;
+  };
   completion_tokens: number;
   reasoning_tokens: number;
   output_tokens: number;
@@ -191,4 +202,5 @@ export interface ChatStreamResponse {
   model: string;
   choices: ChatChoice[];
   usage?: ChatUsage;
+  seed?: number;
 }
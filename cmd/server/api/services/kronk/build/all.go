@@ -4,7 +4,10 @@ package build
 import (
 	"github.com/ardanlabs/kronk/cmd/server/app/domain/chatapp"
 	"github.com/ardanlabs/kronk/cmd/server/app/domain/checkapp"
+	"github.com/ardanlabs/kronk/cmd/server/app/domain/compapp"
 	"github.com/ardanlabs/kronk/cmd/server/app/domain/embedapp"
+	"github.com/ardanlabs/kronk/cmd/server/app/domain/messagesapp"
+	"github.com/ardanlabs/kronk/cmd/server/app/domain/ollamaapp"
 	"github.com/ardanlabs/kronk/cmd/server/app/domain/rerankapp"
 	"github.com/ardanlabs/kronk/cmd/server/app/domain/respapp"
 	"github.com/ardanlabs/kronk/cmd/server/app/domain/toolapp"
@@ -25,6 +28,7 @@ func (all) Add(app *web.App, cfg mux.Config) {
 	checkapp.Routes(app, checkapp.Config{
 		Build: cfg.Build,
 		Log:   cfg.Log,
+		Cache: cfg.Cache,
 	})
 
 	toolapp.Routes(app, toolapp.Config{
@@ -43,6 +47,12 @@ func (all) Add(app *web.App, cfg mux.Config) {
 		Cache:      cfg.Cache,
 	})
 
+	compapp.Routes(app, compapp.Config{
+		Log:        cfg.Log,
+		AuthClient: cfg.AuthClient,
+		Cache:      cfg.Cache,
+	})
+
 	embedapp.Routes(app, embedapp.Config{
 		Log:        cfg.Log,
 		AuthClient: cfg.AuthClient,
@@ -59,5 +69,20 @@ func (all) Add(app *web.App, cfg mux.Config) {
 		Log:        cfg.Log,
 		AuthClient: cfg.AuthClient,
 		Cache:      cfg.Cache,
+		Store:      cfg.RespStore,
+	})
+
+	messagesapp.Routes(app, messagesapp.Config{
+		Log:        cfg.Log,
+		AuthClient: cfg.AuthClient,
+		Cache:      cfg.Cache,
+	})
+
+	ollamaapp.Routes(app, ollamaapp.Config{
+		Log:        cfg.Log,
+		AuthClient: cfg.AuthClient,
+		Cache:      cfg.Cache,
+		Models:     cfg.Models,
+		Catalog:    cfg.Catalog,
 	})
 }
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"syscall"
 	"time"
@@ -22,6 +23,7 @@ import (
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/debug"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/mux"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/respstore"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
 	"github.com/ardanlabs/kronk/sdk/kronk"
@@ -112,8 +114,15 @@ func run(ctx context.Context, log *logger.Logger, showHelp bool) error {
 		Cache struct {
 			ModelsInCache        int           `conf:"default:3"`
 			TTL                  time.Duration `conf:"default:5m"`
+			MaxMemoryBytes       int64         `conf:"default:0"`
 			IgnoreIntegrityCheck bool          `conf:"default:true"`
 			ModelConfigFile      string
+			Preload              []string
+			SubjectQueueLimit    int `conf:"default:0"`
+		}
+		Responses struct {
+			Store string        `conf:"default:memory"`
+			TTL   time.Duration `conf:"default:24h"`
 		}
 		BasePath     string
 		LibPath      string
@@ -347,8 +356,11 @@ func run(ctx context.Context, log *logger.Logger, showHelp bool) error {
 		Templates:            tmplts,
 		ModelsInCache:        cfg.Cache.ModelsInCache,
 		CacheTTL:             cfg.Cache.TTL,
+		MaxMemoryBytes:       cfg.Cache.MaxMemoryBytes,
 		IgnoreIntegrityCheck: cfg.Cache.IgnoreIntegrityCheck,
 		ModelConfigFile:      cfg.Cache.ModelConfigFile,
+		Preload:              cfg.Cache.Preload,
+		SubjectQueueLimit:    cfg.Cache.SubjectQueueLimit,
 	})
 
 	if err != nil {
@@ -366,6 +378,62 @@ func run(ctx context.Context, log *logger.Logger, showHelp bool) error {
 		}
 	}()
 
+	// -------------------------------------------------------------------------
+	// Preload Models
+
+	// The models load while the API starts, readiness reports the service
+	// isn't ready until they are loaded.
+	go func() {
+		if err := cache.Preload(ctx); err != nil {
+			log.Error(ctx, "preload", "ERROR", err)
+		}
+	}()
+
+	// -------------------------------------------------------------------------
+	// Reload Model Config
+
+	reload := make(chan os.Signal, 1)
+	notifyReload(reload)
+	defer signal.Stop(reload)
+
+	go func() {
+		for range reload {
+			log.Info(ctx, "reload", "status", "reloading model config")
+
+			if _, err := cache.ReloadModelConfig(ctx); err != nil {
+				log.Error(ctx, "reload", "ERROR", err)
+			}
+		}
+	}()
+
+	// -------------------------------------------------------------------------
+	// Response Store
+
+	log.Info(ctx, "startup", "status", "initializing response store", "store", cfg.Responses.Store, "ttl", cfg.Responses.TTL)
+
+	var storer respstore.Storer
+
+	switch cfg.Responses.Store {
+	case "memory":
+		storer = respstore.NewMemory(cfg.Responses.TTL)
+
+	case "badger":
+		storer, err = respstore.NewBadger(respstore.BadgerConfig{
+			DBPath: filepath.Join(defaults.BaseDir(cfg.BasePath), "responses"),
+			TTL:    cfg.Responses.TTL,
+		})
+
+		if err != nil {
+			return fmt.Errorf("unable to initialize response store: %w", err)
+		}
+
+	default:
+		return fmt.Errorf("unknown response store %q, use memory or badger", cfg.Responses.Store)
+	}
+
+	respStore := respstore.New(storer)
+	defer respStore.Close()
+
 	// -------------------------------------------------------------------------
 	// Start Debug Service
 
@@ -391,6 +459,7 @@ func run(ctx context.Context, log *logger.Logger, showHelp bool) error {
 		AuthClient: authClient,
 		Tracer:     tracer,
 		Cache:      cache,
+		RespStore:  respStore,
 		Libs:       libs,
 		Models:     models,
 		Catalog:    ctlg,
@@ -400,7 +469,7 @@ func run(ctx context.Context, log *logger.Logger, showHelp bool) error {
 	webAPI := mux.WebAPI(cfgMux,
 		build.Routes(),
 		mux.WithCORS(cfg.Web.CORSAllowedOrigins),
-		mux.WithFileServer(true, static, "static", "/", []string{"v1"}),
+		mux.WithFileServer(true, static, "static", "/", []string{"v1", "api"}),
 	)
 
 	api := http.Server{
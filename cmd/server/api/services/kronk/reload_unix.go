@@ -0,0 +1,15 @@
+//go:build !windows
+
+package kronk
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyReload relays SIGHUP, which asks the server to reload the model
+// config file.
+func notifyReload(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGHUP)
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package kronk
+
+import "os"
+
+// notifyReload does nothing on Windows, which has no SIGHUP. The model config
+// file is reloaded with the admin endpoint instead.
+func notifyReload(c chan<- os.Signal) {}
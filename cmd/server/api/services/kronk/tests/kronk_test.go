@@ -38,6 +38,8 @@ func Test_API(t *testing.T) {
 	test.RunStreaming(t, chatStreamQwen3(t, tokens), "chat-stream-qwen3")
 	test.Run(t, respNonStreamQwen3(t, tokens), "resp-nonstream-qwen3")
 	test.RunStreaming(t, respStreamQwen3(t, tokens), "resp-stream-qwen3")
+	test.Run(t, ollamaQwen3(tokens), "ollama-qwen3")
+	test.Run(t, messagesQwen3(tokens), "messages-qwen3")
 
 	// -------------------------------------------------------------------------
 	// Model: Qwen2.5-VL-3B-Instruct-Q8_0 (vision)
@@ -55,6 +57,8 @@ func Test_API(t *testing.T) {
 	// Model: embeddinggemma-300m-qat-Q8_0
 
 	test.Run(t, chatEmbed200(tokens), "embedding-200")
+	test.Run(t, ollamaEmbed200(tokens), "ollama-embed-200")
+	test.Run(t, ollamaShow200(tokens), "ollama-show-200")
 
 	// -------------------------------------------------------------------------
 	// Model: bge-reranker-v2-m3-Q8_0
@@ -68,6 +72,8 @@ func Test_API(t *testing.T) {
 	test.Run(t, respEndpoint401(tokens), "respEndpoint-401")
 	test.Run(t, embed401(tokens), "embedding-401")
 	test.Run(t, rerank401(tokens), "rerank-401")
+	test.Run(t, ollama401(tokens), "ollama-401")
+	test.Run(t, messages401(tokens), "messages-401")
 }
 
 // =============================================================================
@@ -75,7 +81,7 @@ func Test_API(t *testing.T) {
 func createTokens(t *testing.T, sec *security.Security) map[string]string {
 	tokens := make(map[string]string)
 
-	token, err := sec.GenerateToken(true, nil, 60*time.Hour)
+	token, err := sec.GenerateToken(true, "", nil, 60*time.Hour)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -84,7 +90,7 @@ func createTokens(t *testing.T, sec *security.Security) map[string]string {
 
 	// -------------------------------------------------------------------------
 
-	token, err = sec.GenerateToken(true, nil, 60*time.Hour)
+	token, err = sec.GenerateToken(true, "", nil, 60*time.Hour)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -100,7 +106,7 @@ func createTokens(t *testing.T, sec *security.Security) map[string]string {
 		},
 	}
 
-	token, err = sec.GenerateToken(false, endpoints, 60*time.Hour)
+	token, err = sec.GenerateToken(false, "", endpoints, 60*time.Hour)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -109,6 +115,22 @@ func createTokens(t *testing.T, sec *security.Security) map[string]string {
 
 	// -------------------------------------------------------------------------
 
+	endpoints = map[string]auth.RateLimit{
+		"completions": {
+			Limit:  0,
+			Window: auth.RateUnlimited,
+		},
+	}
+
+	token, err = sec.GenerateToken(false, "", endpoints, 60*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens["completions"] = token
+
+	// -------------------------------------------------------------------------
+
 	endpoints = map[string]auth.RateLimit{
 		"embeddings": {
 			Limit:  0,
@@ -116,7 +138,7 @@ func createTokens(t *testing.T, sec *security.Security) map[string]string {
 		},
 	}
 
-	token, err = sec.GenerateToken(false, endpoints, 60*time.Hour)
+	token, err = sec.GenerateToken(false, "", endpoints, 60*time.Hour)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -132,7 +154,7 @@ func createTokens(t *testing.T, sec *security.Security) map[string]string {
 		},
 	}
 
-	token, err = sec.GenerateToken(false, endpoints, 60*time.Hour)
+	token, err = sec.GenerateToken(false, "", endpoints, 60*time.Hour)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -148,13 +170,29 @@ func createTokens(t *testing.T, sec *security.Security) map[string]string {
 		},
 	}
 
-	token, err = sec.GenerateToken(false, endpoints, 60*time.Hour)
+	token, err = sec.GenerateToken(false, "", endpoints, 60*time.Hour)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	tokens["rerank"] = token
 
+	// -------------------------------------------------------------------------
+
+	endpoints = map[string]auth.RateLimit{
+		"messages": {
+			Limit:  0,
+			Window: auth.RateUnlimited,
+		},
+	}
+
+	token, err = sec.GenerateToken(false, "", endpoints, 60*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens["messages"] = token
+
 	return tokens
 }
 
@@ -0,0 +1,106 @@
+package chatapi_test
+
+import (
+	"net/http"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/domain/messagesapp"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/apitest"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// messagesQwen3 returns tests of the messages endpoint for the Qwen3-8B-Q8_0
+// model.
+func messagesQwen3(tokens map[string]string) []apitest.Table {
+	return []apitest.Table{
+		{
+			Name:       "good-token",
+			URL:        "/v1/messages",
+			Token:      tokens["messages"],
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: model.D{
+				"model":      "Qwen3-8B-Q8_0",
+				"max_tokens": 2048,
+				"system":     "Answer with one word.",
+				"messages": []model.D{
+					{"role": "user", "content": "Echo back the word: Gorilla"},
+				},
+				"thinking": model.D{"type": "enabled", "budget_tokens": 1024},
+			},
+			GotResp: &messagesapp.MessageResponse{},
+			ExpResp: &messagesapp.MessageResponse{
+				Type:  "message",
+				Role:  "assistant",
+				Model: "Qwen3-8B-Q8_0",
+			},
+			CmpFunc: func(got any, exp any) string {
+				diff := cmp.Diff(got, exp,
+					cmpopts.IgnoreFields(messagesapp.MessageResponse{}, "ID", "Content", "StopReason", "Usage"),
+				)
+
+				if diff != "" {
+					return diff
+				}
+
+				resp := got.(*messagesapp.MessageResponse)
+
+				if len(resp.ID) < 5 || resp.ID[:4] != "msg_" {
+					return "expected id to start with msg_"
+				}
+
+				if resp.StopReason == nil || *resp.StopReason != "end_turn" {
+					return "expected stop_reason to be end_turn"
+				}
+
+				if resp.Usage.InputTokens <= 0 || resp.Usage.OutputTokens <= 0 {
+					return "expected input and output tokens to be greater than 0"
+				}
+
+				// Thinking is enabled, so the text block follows the
+				// thinking block.
+				var types []string
+				for _, cb := range resp.Content {
+					types = append(types, cb.Type)
+				}
+
+				if !cmp.Equal(types, []string{"thinking", "text"}) {
+					return "expected a thinking and a text block, got " + cmp.Diff(types, []string{"thinking", "text"})
+				}
+
+				return ""
+			},
+		},
+	}
+}
+
+func messages401(tokens map[string]string) []apitest.Table {
+	return []apitest.Table{
+		{
+			Name:       "bad-token",
+			URL:        "/v1/messages",
+			Token:      tokens["chat-completions"],
+			Method:     http.MethodPost,
+			StatusCode: http.StatusUnauthorized,
+			Input: model.D{
+				"model":      "Qwen3-8B-Q8_0",
+				"max_tokens": 2048,
+				"messages": []model.D{
+					{"role": "user", "content": "Hello"},
+				},
+			},
+			GotResp: &errs.Error{},
+			ExpResp: &errs.Error{
+				Code:    errs.Unauthenticated,
+				Message: "rpc error: code = Unauthenticated desc = not authorized: attempted action is not allowed: endpoint \"messages\" not authorized",
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp,
+					cmpopts.IgnoreFields(errs.Error{}, "FuncName", "FileName"),
+				)
+			},
+		},
+	}
+}
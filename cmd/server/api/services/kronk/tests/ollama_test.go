@@ -0,0 +1,249 @@
+package chatapi_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/domain/ollamaapp"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/apitest"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// ollamaQwen3 returns tests of the Ollama chat and generate endpoints for the
+// Qwen3-8B-Q8_0 model.
+func ollamaQwen3(tokens map[string]string) []apitest.Table {
+	return []apitest.Table{
+		{
+			Name:       "chat-good-token",
+			URL:        "/api/chat",
+			Token:      tokens["chat-completions"],
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: model.D{
+				"model": "Qwen3-8B-Q8_0",
+				"messages": []model.D{
+					{"role": "user", "content": "Echo back the word: Gorilla"},
+				},
+				"stream":  false,
+				"think":   false,
+				"options": model.D{"num_predict": 2048, "temperature": 0.7},
+			},
+			GotResp: &ollamaapp.ChatResponse{},
+			ExpResp: &ollamaapp.ChatResponse{
+				Model:      "Qwen3-8B-Q8_0",
+				Message:    ollamaapp.Message{Role: "assistant"},
+				Done:       true,
+				DoneReason: "stop",
+			},
+			CmpFunc: func(got any, exp any) string {
+				diff := cmp.Diff(got, exp,
+					cmpopts.IgnoreFields(ollamaapp.ChatResponse{}, "CreatedAt", "Metrics"),
+					cmpopts.IgnoreFields(ollamaapp.Message{}, "Content", "Thinking"),
+				)
+
+				if diff != "" {
+					return diff
+				}
+
+				resp := got.(*ollamaapp.ChatResponse)
+
+				if resp.Message.Content == "" {
+					return "expected content to be non-empty"
+				}
+
+				if resp.PromptEvalCount <= 0 || resp.EvalCount <= 0 {
+					return fmt.Sprintf("expected token counts, got prompt %d eval %d", resp.PromptEvalCount, resp.EvalCount)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:       "generate-good-token",
+			URL:        "/api/generate",
+			Token:      tokens["completions"],
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: model.D{
+				"model":   "Qwen3-8B-Q8_0",
+				"prompt":  "Echo back the word: Gorilla",
+				"system":  "Answer with one word.",
+				"stream":  false,
+				"think":   false,
+				"options": model.D{"num_predict": 2048, "temperature": 0.7},
+			},
+			GotResp: &ollamaapp.GenerateResponse{},
+			ExpResp: &ollamaapp.GenerateResponse{
+				Model:      "Qwen3-8B-Q8_0",
+				Done:       true,
+				DoneReason: "stop",
+			},
+			CmpFunc: func(got any, exp any) string {
+				diff := cmp.Diff(got, exp,
+					cmpopts.IgnoreFields(ollamaapp.GenerateResponse{}, "CreatedAt", "Response", "Thinking", "Metrics"),
+				)
+
+				if diff != "" {
+					return diff
+				}
+
+				resp := got.(*ollamaapp.GenerateResponse)
+
+				if resp.Response == "" {
+					return "expected response to be non-empty"
+				}
+
+				return ""
+			},
+		},
+	}
+}
+
+// ollamaEmbed200 returns tests of the Ollama embed endpoint.
+func ollamaEmbed200(tokens map[string]string) []apitest.Table {
+	return []apitest.Table{
+		{
+			Name:       "embed-good-token",
+			URL:        "/api/embed",
+			Token:      tokens["embeddings"],
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: model.D{
+				"model": "embeddinggemma-300m-qat-Q8_0",
+				"input": []string{"Embed this sentence", "and this sentence"},
+			},
+			GotResp: &ollamaapp.EmbedResponse{},
+			ExpResp: &ollamaapp.EmbedResponse{
+				Model:           "embeddinggemma-300m-qat-Q8_0",
+				PromptEvalCount: 10,
+			},
+			CmpFunc: func(got any, exp any) string {
+				diff := cmp.Diff(got, exp,
+					cmpopts.IgnoreFields(ollamaapp.EmbedResponse{}, "Embeddings", "TotalDuration", "LoadDuration"),
+				)
+
+				if diff != "" {
+					return diff
+				}
+
+				resp := got.(*ollamaapp.EmbedResponse)
+
+				if len(resp.Embeddings) != 2 {
+					return fmt.Sprintf("expected length of 2, got %d", len(resp.Embeddings))
+				}
+
+				for _, embedding := range resp.Embeddings {
+					if len(embedding) != 768 {
+						return "expecting a vector of 768 dimensions"
+					}
+				}
+
+				return ""
+			},
+		},
+	}
+}
+
+// ollamaShow200 returns tests of the Ollama show endpoint.
+func ollamaShow200(tokens map[string]string) []apitest.Table {
+	return []apitest.Table{
+		{
+			Name:       "show-good-token",
+			URL:        "/api/show",
+			Token:      tokens["admin"],
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: model.D{
+				"model": "embeddinggemma-300m-qat-Q8_0",
+			},
+			GotResp: &ollamaapp.ShowResponse{},
+			ExpResp: &ollamaapp.ShowResponse{
+				Capabilities: []string{"embedding"},
+			},
+			CmpFunc: func(got any, exp any) string {
+				diff := cmp.Diff(got, exp,
+					cmpopts.IgnoreFields(ollamaapp.ShowResponse{}, "Template", "Details", "ModelInfo", "ModifiedAt"),
+				)
+
+				if diff != "" {
+					return diff
+				}
+
+				resp := got.(*ollamaapp.ShowResponse)
+
+				if resp.Details.Family == "" {
+					return "expecting the model family from the gguf metadata"
+				}
+
+				return ""
+			},
+		},
+	}
+}
+
+func ollama401(tokens map[string]string) []apitest.Table {
+	cmpFunc := func(got any, exp any) string {
+		return cmp.Diff(got, exp,
+			cmpopts.IgnoreFields(errs.Error{}, "FuncName", "FileName"),
+		)
+	}
+
+	return []apitest.Table{
+		{
+			Name:       "chat-bad-token",
+			URL:        "/api/chat",
+			Token:      tokens["embeddings"],
+			Method:     http.MethodPost,
+			StatusCode: http.StatusUnauthorized,
+			Input: model.D{
+				"model":    "Qwen3-8B-Q8_0",
+				"messages": []model.D{{"role": "user", "content": "Hello"}},
+				"stream":   false,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: &errs.Error{
+				Code:    errs.Unauthenticated,
+				Message: "rpc error: code = Unauthenticated desc = not authorized: attempted action is not allowed: endpoint \"chat-completions\" not authorized",
+			},
+			CmpFunc: cmpFunc,
+		},
+		{
+			Name:       "generate-bad-token",
+			URL:        "/api/generate",
+			Token:      tokens["chat-completions"],
+			Method:     http.MethodPost,
+			StatusCode: http.StatusUnauthorized,
+			Input: model.D{
+				"model":  "Qwen3-8B-Q8_0",
+				"prompt": "Hello",
+				"stream": false,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: &errs.Error{
+				Code:    errs.Unauthenticated,
+				Message: "rpc error: code = Unauthenticated desc = not authorized: attempted action is not allowed: endpoint \"completions\" not authorized",
+			},
+			CmpFunc: cmpFunc,
+		},
+		{
+			Name:       "embed-bad-token",
+			URL:        "/api/embed",
+			Token:      tokens["chat-completions"],
+			Method:     http.MethodPost,
+			StatusCode: http.StatusUnauthorized,
+			Input: model.D{
+				"model": "embeddinggemma-300m-qat-Q8_0",
+				"input": "Embed this sentence",
+			},
+			GotResp: &errs.Error{},
+			ExpResp: &errs.Error{
+				Code:    errs.Unauthenticated,
+				Message: "rpc error: code = Unauthenticated desc = not authorized: attempted action is not allowed: endpoint \"embeddings\" not authorized",
+			},
+			CmpFunc: cmpFunc,
+		},
+	}
+}
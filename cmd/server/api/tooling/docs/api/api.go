@@ -450,6 +450,7 @@ func chatDoc() apiDoc {
 						Headers: []header{
 							{Name: "Authorization", Description: "Bearer token for authentication", Required: true},
 							{Name: "Content-Type", Description: "Must be application/json", Required: true},
+							{Name: "X-Kronk-Priority", Description: "Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority", Required: false},
 						},
 						RequestBody: &requestBody{
 							ContentType: "application/json",
@@ -463,12 +464,285 @@ func chatDoc() apiDoc {
 					},
 				},
 			},
+			completionsGroup(),
+			messagesGroup(),
+			ollamaGroup(),
 			chatResponseFormatsGroup(),
 			messageFormatsGroup(),
 		},
 	}
 }
 
+func completionsGroup() endpointGroup {
+	return endpointGroup{
+		Name:        "Completions",
+		Description: "Complete a raw prompt. The prompt isn't passed through the model's chat template. Compatible with the OpenAI Completions API.",
+		Endpoints: []endpoint{
+			{
+				Method:      "POST",
+				Path:        "/completions",
+				Description: "Create a completion for a raw prompt. Supports streaming responses and fill-in-the-middle with a suffix for models with FIM tokens.",
+				Auth:        "Required when auth is enabled. Token must have 'completions' endpoint access.",
+				Headers: []header{
+					{Name: "Authorization", Description: "Bearer token for authentication", Required: true},
+					{Name: "Content-Type", Description: "Must be application/json", Required: true},
+					{Name: "X-Kronk-Priority", Description: "Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority", Required: false},
+				},
+				RequestBody: &requestBody{
+					ContentType: "application/json",
+					Fields: []field{
+						{Name: "model", Type: "string", Required: true, Description: "Model ID to use for completion (e.g., 'qwen3-8b-q8_0')"},
+						{Name: "prompt", Type: "string|array", Required: true, Description: "Prompt to complete. An array of prompts is completed in turn, with the choices of each prompt indexed after the choices of the prompts before it."},
+						{Name: "suffix", Type: "string", Required: false, Description: "Text that comes after the completion. The model fills in the text between the prompt and the suffix. Requires a model with fill-in-the-middle tokens."},
+						{Name: "echo", Type: "boolean", Required: false, Description: "Return the prompt in front of the completion (default: false)"},
+						{Name: "stream", Type: "boolean", Required: false, Description: "Enable streaming responses (default: false)"},
+						{Name: "max_tokens", Type: "integer", Required: false, Description: "Maximum tokens to generate. A completion cut off by max_tokens finishes with 'length'."},
+						{Name: "stop", Type: "string|array", Required: false, Description: "Up to 4 strings that end generation. The stop string isn't included in the text."},
+						{Name: "n", Type: "integer", Required: false, Description: "Number of completions to generate for each prompt (default: 1)"},
+						{Name: "logprobs", Type: "integer", Required: false, Description: "Return the log probabilities of the generated tokens with this many of the most likely tokens"},
+						{Name: "temperature", Type: "float32", Required: false, Description: "Controls randomness of output (default: 0.8)"},
+						{Name: "top_p", Type: "float32", Required: false, Description: "Nucleus sampling threshold (default: 0.9)"},
+						{Name: "seed", Type: "integer", Required: false, Description: "Seed for the samplers, so a request can be replayed"},
+					},
+				},
+				Response: &response{
+					ContentType: "application/json or text/event-stream",
+					Description: "Returns a text_completion object, or streams Server-Sent Events if stream=true.",
+				},
+				Examples: []example{
+					{
+						Description: "Complete a prompt:",
+						Code: `curl -X POST http://localhost:8080/v1/completions \
+  -H "Authorization: Bearer $KRONK_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{
+    "model": "qwen3-8b-q8_0",
+    "prompt": "The sky is blue because",
+    "max_tokens": 64
+  }'`,
+					},
+					{
+						Description: "Fill in the middle of code:",
+						Code: `curl -X POST http://localhost:8080/v1/completions \
+  -H "Authorization: Bearer $KRONK_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{
+    "model": "Qwen2.5-Coder-7B-Q8_0",
+    "prompt": "func add(a, b int) int {\n",
+    "suffix": "\n}\n",
+    "max_tokens": 32,
+    "stop": ["\n\n"]
+  }'`,
+					},
+				},
+			},
+		},
+	}
+}
+
+func messagesGroup() endpointGroup {
+	return endpointGroup{
+		Name:        "Messages",
+		Description: "Create messages with content blocks, tool use and thinking. Compatible with the Anthropic Messages API.",
+		Endpoints: []endpoint{
+			{
+				Method:      "POST",
+				Path:        "/messages",
+				Description: "Create a message. Supports streaming with the message_start, content_block_start, content_block_delta, content_block_stop, message_delta and message_stop events.",
+				Auth:        "Required when auth is enabled. Token must have 'messages' endpoint access.",
+				Headers: []header{
+					{Name: "Authorization", Description: "Bearer token for authentication. The x-api-key header is accepted in its place.", Required: true},
+					{Name: "Content-Type", Description: "Must be application/json", Required: true},
+					{Name: "X-Kronk-Priority", Description: "Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority", Required: false},
+				},
+				RequestBody: &requestBody{
+					ContentType: "application/json",
+					Fields: []field{
+						{Name: "model", Type: "string", Required: true, Description: "Model ID to use for the message"},
+						{Name: "max_tokens", Type: "integer", Required: true, Description: "Maximum tokens to generate"},
+						{Name: "messages", Type: "array", Required: true, Description: "Messages with a user or assistant role. The content is a string or a list of text, image, tool_use and tool_result blocks."},
+						{Name: "system", Type: "string|array", Required: false, Description: "System prompt as a string or a list of text blocks"},
+						{Name: "tools", Type: "array", Required: false, Description: "Tools with a name, description and input_schema"},
+						{Name: "tool_choice", Type: "object", Required: false, Description: "How the model uses the tools: auto, any, tool or none"},
+						{Name: "thinking", Type: "object", Required: false, Description: "Set type to 'enabled' to get thinking blocks. Thinking is off by default."},
+						{Name: "stop_sequences", Type: "array", Required: false, Description: "Strings that end generation"},
+						{Name: "stream", Type: "boolean", Required: false, Description: "Stream the response as server-sent events (default: false)"},
+						{Name: "temperature", Type: "float32", Required: false, Description: "Controls randomness of output"},
+						{Name: "top_p", Type: "float32", Required: false, Description: "Nucleus sampling threshold"},
+						{Name: "top_k", Type: "integer", Required: false, Description: "Limit sampling to the K most likely tokens"},
+					},
+				},
+				Response: &response{
+					ContentType: "application/json or text/event-stream",
+					Description: "Returns a message with thinking, text and tool_use content blocks, a stop_reason of end_turn, max_tokens or tool_use, and the input and output tokens.",
+				},
+				Examples: []example{
+					{
+						Description: "Create a message:",
+						Code: `curl -X POST http://localhost:8080/v1/messages \
+  -H "Authorization: Bearer $KRONK_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{
+    "model": "qwen3-8b-q8_0",
+    "max_tokens": 1024,
+    "messages": [{"role": "user", "content": "Hello"}]
+  }'`,
+					},
+				},
+			},
+		},
+	}
+}
+
+func ollamaGroup() endpointGroup {
+	headers := []header{
+		{Name: "Authorization", Description: "Bearer token for authentication", Required: true},
+		{Name: "Content-Type", Description: "Must be application/json", Required: true},
+	}
+
+	return endpointGroup{
+		Name:        "Ollama API",
+		Description: "Endpoints compatible with the Ollama API so tools built for Ollama can use the models of the server. These paths are not prefixed with /v1. Responses stream as newline delimited JSON unless stream=false. Model names may carry the ':latest' tag.",
+		Endpoints: []endpoint{
+			{
+				Method:      "POST",
+				Path:        "/api/chat",
+				Description: "Create a chat completion. A request without messages loads the model.",
+				Auth:        "Required when auth is enabled. Token must have 'chat-completions' endpoint access.",
+				Headers:     headers,
+				RequestBody: &requestBody{
+					ContentType: "application/json",
+					Fields: []field{
+						{Name: "model", Type: "string", Required: true, Description: "Model ID to use for the chat"},
+						{Name: "messages", Type: "array", Required: false, Description: "Chat messages with role, content, images (base64), tool_calls and tool_name"},
+						{Name: "tools", Type: "array", Required: false, Description: "Tools the model may call"},
+						{Name: "format", Type: "string|object", Required: false, Description: "'json' for any JSON object or a JSON schema to match"},
+						{Name: "options", Type: "object", Required: false, Description: "Model options: num_predict, temperature, top_k, top_p, min_p, typical_p, repeat_penalty, repeat_last_n, presence_penalty, frequency_penalty, seed and stop. Other options are ignored."},
+						{Name: "think", Type: "boolean|string", Required: false, Description: "Enable thinking, or the reasoning effort (low, medium, high) for GPT models"},
+						{Name: "stream", Type: "boolean", Required: false, Description: "Stream the response (default: true)"},
+					},
+				},
+				Response: &response{
+					ContentType: "application/json or application/x-ndjson",
+					Description: "Returns a chat response with done=true, or streams a line of JSON for each chunk ending with the done chunk that holds the token counts.",
+				},
+				Examples: []example{
+					{
+						Description: "Chat with a model:",
+						Code: `curl -X POST http://localhost:8080/api/chat \
+  -H "Content-Type: application/json" \
+  -d '{
+    "model": "qwen3-8b-q8_0",
+    "messages": [{"role": "user", "content": "Why is the sky blue?"}]
+  }'`,
+					},
+				},
+			},
+			{
+				Method:      "POST",
+				Path:        "/api/generate",
+				Description: "Generate a response for a prompt. The prompt goes through the chat template unless raw=true or a suffix is given. A request without a prompt loads the model.",
+				Auth:        "Required when auth is enabled. Token must have 'completions' endpoint access.",
+				Headers:     headers,
+				RequestBody: &requestBody{
+					ContentType: "application/json",
+					Fields: []field{
+						{Name: "model", Type: "string", Required: true, Description: "Model ID to use for generation"},
+						{Name: "prompt", Type: "string", Required: false, Description: "Prompt to generate a response for"},
+						{Name: "suffix", Type: "string", Required: false, Description: "Text that comes after the response, for models with fill-in-the-middle tokens"},
+						{Name: "system", Type: "string", Required: false, Description: "System message for the chat template"},
+						{Name: "images", Type: "array", Required: false, Description: "Base64 encoded images for vision models"},
+						{Name: "raw", Type: "boolean", Required: false, Description: "Give the prompt to the model as is, without the chat template"},
+						{Name: "format", Type: "string|object", Required: false, Description: "'json' for any JSON object or a JSON schema to match"},
+						{Name: "options", Type: "object", Required: false, Description: "Model options, the same as the chat endpoint"},
+						{Name: "think", Type: "boolean|string", Required: false, Description: "Enable thinking, or the reasoning effort for GPT models"},
+						{Name: "stream", Type: "boolean", Required: false, Description: "Stream the response (default: true)"},
+					},
+				},
+				Response: &response{
+					ContentType: "application/json or application/x-ndjson",
+					Description: "Returns the response text with done=true, or streams a line of JSON for each chunk.",
+				},
+			},
+			{
+				Method:      "POST",
+				Path:        "/api/embed",
+				Description: "Generate embeddings for one or more inputs.",
+				Auth:        "Required when auth is enabled. Token must have 'embeddings' endpoint access.",
+				Headers:     headers,
+				RequestBody: &requestBody{
+					ContentType: "application/json",
+					Fields: []field{
+						{Name: "model", Type: "string", Required: true, Description: "Embedding model ID"},
+						{Name: "input", Type: "string|array", Required: true, Description: "Text or list of texts to embed"},
+						{Name: "truncate", Type: "boolean", Required: false, Description: "Truncate inputs that don't fit the context window (default: true)"},
+						{Name: "dimensions", Type: "integer", Required: false, Description: "Reduce the embeddings to the first N dimensions"},
+					},
+				},
+				Response: &response{
+					ContentType: "application/json",
+					Description: "Returns the embeddings in the order of the inputs.",
+				},
+			},
+			{
+				Method:      "GET",
+				Path:        "/api/tags",
+				Description: "List the installed models and aliases.",
+				Auth:        "Optional when auth is enabled.",
+				Response: &response{
+					ContentType: "application/json",
+					Description: "Returns the models with their size, modified time and family.",
+				},
+			},
+			{
+				Method:      "POST",
+				Path:        "/api/show",
+				Description: "Show the template, metadata and capabilities of a model. The model is loaded if it isn't already.",
+				Auth:        "Optional when auth is enabled.",
+				Headers:     headers,
+				RequestBody: &requestBody{
+					ContentType: "application/json",
+					Fields: []field{
+						{Name: "model", Type: "string", Required: true, Description: "Model ID to show"},
+					},
+				},
+				Response: &response{
+					ContentType: "application/json",
+					Description: "Returns the model information.",
+				},
+			},
+			{
+				Method:      "GET",
+				Path:        "/api/ps",
+				Description: "List the models that are loaded.",
+				Auth:        "Optional when auth is enabled.",
+				Response: &response{
+					ContentType: "application/json",
+					Description: "Returns the loaded models with their size and expiration time.",
+				},
+			},
+			{
+				Method:      "POST",
+				Path:        "/api/pull",
+				Description: "Download a model from the catalog.",
+				Auth:        "Optional when auth is enabled.",
+				Headers:     headers,
+				RequestBody: &requestBody{
+					ContentType: "application/json",
+					Fields: []field{
+						{Name: "model", Type: "string", Required: true, Description: "Catalog model ID to download"},
+						{Name: "stream", Type: "boolean", Required: false, Description: "Stream the progress (default: true)"},
+					},
+				},
+				Response: &response{
+					ContentType: "application/json or application/x-ndjson",
+					Description: "Streams a status line for the progress of the download, ending with a 'success' status.",
+				},
+			},
+		},
+	}
+}
+
 func chatResponseFormatsGroup() endpointGroup {
 	return endpointGroup{
 		Name:        "Response Formats",
@@ -498,12 +772,16 @@ func chatResponseFormatsGroup() endpointGroup {
   ],
   "usage": {
     "prompt_tokens": 25,
+    "prompt_tokens_details": {
+      "cached_tokens": 0
+    },
     "reasoning_tokens": 0,
     "completion_tokens": 12,
     "output_tokens": 12,
     "total_tokens": 37,
     "tokens_per_second": 85.5
-  }
+  },
+  "seed": 1234
 }`,
 					},
 				},
@@ -547,6 +825,7 @@ func responsesDoc() apiDoc {
 						Headers: []header{
 							{Name: "Authorization", Description: "Bearer token for authentication", Required: true},
 							{Name: "Content-Type", Description: "Must be application/json", Required: true},
+							{Name: "X-Kronk-Priority", Description: "Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority", Required: false},
 						},
 						RequestBody: &requestBody{
 							ContentType: "application/json",
@@ -558,6 +837,46 @@ func responsesDoc() apiDoc {
 						},
 						Examples: responsesExamples(),
 					},
+					{
+						Method:      "GET",
+						Path:        "/responses/{id}",
+						Description: "Retrieve a stored response. Responses are kept for the configured TTL.",
+						Auth:        "Required when auth is enabled. Token must have 'responses' endpoint access. Only the responses created with the same subject are returned.",
+						Headers: []header{
+							{Name: "Authorization", Description: "Bearer token for authentication", Required: true},
+						},
+						Response: &response{
+							ContentType: "application/json",
+							Description: "Returns the response object.",
+						},
+						Examples: []example{
+							{
+								Description: "Retrieve a response:",
+								Code: `curl http://localhost:8080/v1/responses/resp_abc123 \
+  -H "Authorization: Bearer $KRONK_TOKEN"`,
+							},
+						},
+					},
+					{
+						Method:      "DELETE",
+						Path:        "/responses/{id}",
+						Description: "Delete a stored response.",
+						Auth:        "Required when auth is enabled. Token must have 'responses' endpoint access. Only the responses created with the same subject can be deleted.",
+						Headers: []header{
+							{Name: "Authorization", Description: "Bearer token for authentication", Required: true},
+						},
+						Response: &response{
+							ContentType: "application/json",
+							Description: "Returns the id of the deleted response with deleted set to true.",
+						},
+						Examples: []example{
+							{
+								Description: "Delete a response:",
+								Code: `curl -X DELETE http://localhost:8080/v1/responses/resp_abc123 \
+  -H "Authorization: Bearer $KRONK_TOKEN"`,
+							},
+						},
+					},
 				},
 			},
 			responsesFormatsGroup(),
@@ -574,8 +893,10 @@ func responsesFields() []field {
 		{Name: "tools", Type: "array", Required: false, Description: "List of tools the model can use"},
 		{Name: "tool_choice", Type: "string", Required: false, Description: "How the model should use tools: auto, none, or required"},
 		{Name: "parallel_tool_calls", Type: "boolean", Required: false, Description: "Allow parallel tool calls (default: true)"},
-		{Name: "store", Type: "boolean", Required: false, Description: "Whether to store the response (default: true)"},
+		{Name: "store", Type: "boolean", Required: false, Description: "Whether to store the response so it can be retrieved or continued (default: true)"},
+		{Name: "previous_response_id", Type: "string", Required: false, Description: "ID of a stored response to continue. The conversation of that response is placed before the input."},
 		{Name: "truncation", Type: "string", Required: false, Description: "Truncation strategy: auto or disabled (default: disabled)"},
+		{Name: "text", Type: "object", Required: false, Description: "Output format: {\"format\": {\"type\": \"json_schema\", \"name\": \"...\", \"schema\": {...}}} or {\"format\": {\"type\": \"json_object\"}}"},
 	}
 
 	fields = append(fields, paramsToFields()...)
@@ -608,6 +929,19 @@ func responsesExamples() []example {
       {"role": "user", "content": "Write a short poem about coding"}
     ],
     "stream": true
+  }'`,
+		},
+		{
+			Description: "Continue a stored response:",
+			Code: `curl -X POST http://localhost:8080/v1/responses \
+  -H "Authorization: Bearer $KRONK_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{
+    "model": "qwen3-8b-q8_0",
+    "previous_response_id": "resp_abc123",
+    "input": [
+      {"role": "user", "content": "Now make it rhyme"}
+    ]
   }'`,
 		},
 		{
@@ -914,6 +1248,7 @@ func embeddingsDoc() apiDoc {
 						Headers: []header{
 							{Name: "Authorization", Description: "Bearer token for authentication", Required: true},
 							{Name: "Content-Type", Description: "Must be application/json", Required: true},
+							{Name: "X-Kronk-Priority", Description: "Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority", Required: false},
 						},
 						RequestBody: &requestBody{
 							ContentType: "application/json",
@@ -921,6 +1256,13 @@ func embeddingsDoc() apiDoc {
 								{Name: "model", Type: "string", Required: true, Description: "Embedding model ID (e.g., 'embeddinggemma-300m-qat-Q8_0')"},
 								{Name: "input", Type: "string|array", Required: true, Description: "Text to generate embeddings for. Can be a string or array of strings."},
 								{Name: "dimensions", Type: "integer", Required: false, Description: "Reduce output to first N dimensions (for Matryoshka models). Must be <= model's native dimensions."},
+								{Name: "pooling", Type: "string", Required: false, Description: "Pooling of the token embeddings: mean, cls or last. Defaults to the model's pooling. When the server has a memory budget, only the model's pooling can be used."},
+								{Name: "normalize", Type: "boolean", Required: false, Description: "L2 normalize the vectors. Defaults to true."},
+								{Name: "encoding_format", Type: "string", Required: false, Description: "Format of the vectors: float (default) or base64, a base64 string of little-endian float32 values."},
+								{Name: "chunking", Type: "boolean", Required: false, Description: "Split inputs longer than the model can embed into overlapping token windows. Defaults to false."},
+								{Name: "chunk_size", Type: "integer", Required: false, Description: "Tokens per window. Defaults to the largest input the model can embed."},
+								{Name: "chunk_overlap", Type: "integer", Required: false, Description: "Tokens shared by consecutive windows. Defaults to a tenth of chunk_size."},
+								{Name: "chunk_aggregate", Type: "string", Required: false, Description: "How the window vectors of an input are combined: mean (default), weighted by window length, or none to return each window's vector with a chunk object holding its index and token offsets."},
 							},
 						},
 						Response: &response{
@@ -965,6 +1307,7 @@ func rerankDoc() apiDoc {
 						Headers: []header{
 							{Name: "Authorization", Description: "Bearer token for authentication", Required: true},
 							{Name: "Content-Type", Description: "Must be application/json", Required: true},
+							{Name: "X-Kronk-Priority", Description: "Priority class while waiting for a model slot: low, normal (default) or high, up to the token's priority", Required: false},
 						},
 						RequestBody: &requestBody{
 							ContentType: "application/json",
@@ -1148,6 +1491,26 @@ func modelsEndpoints() endpointGroup {
 					},
 				},
 			},
+			{
+				Method:      "POST",
+				Path:        "/models/config/reload",
+				Description: "Reload the model config file without restarting the server. The file is validated before it's used. Loaded models whose settings changed are loaded again with the new settings once their active streams end; the other models stay loaded. Sending SIGHUP to the server does the same.",
+				Auth:        "Required when auth is enabled. Admin token required.",
+				Headers: []header{
+					{Name: "Authorization", Description: "Bearer token for admin authentication", Required: true},
+				},
+				Response: &response{
+					ContentType: "application/json",
+					Description: "Returns the ids of the models that are loaded again.",
+				},
+				Examples: []example{
+					{
+						Description: "Reload the model config file:",
+						Code: `curl -X POST http://localhost:8080/v1/models/config/reload \
+  -H "Authorization: Bearer $KRONK_TOKEN"`,
+					},
+				},
+			},
 			{
 				Method:      "POST",
 				Path:        "/models/pull",
@@ -1307,6 +1670,7 @@ func securityEndpoints() endpointGroup {
 						{Name: "admin", Type: "boolean", Required: false, Description: "Whether the token has admin privileges"},
 						{Name: "duration", Type: "duration", Required: true, Description: "Token validity duration (e.g., '24h', '720h')"},
 						{Name: "endpoints", Type: "object", Required: true, Description: "Map of endpoint names to rate limit configurations"},
+						{Name: "priority", Type: "string", Required: false, Description: "Highest priority class for the token's requests: low, normal (default) or high"},
 					},
 				},
 				Response: &response{
@@ -1386,6 +1750,26 @@ func securityEndpoints() endpointGroup {
 					{
 						Description: "Remove a key:",
 						Code: `curl -X POST http://localhost:8080/v1/security/keys/remove/abc123 \
+  -H "Authorization: Bearer $KRONK_TOKEN"`,
+					},
+				},
+			},
+			{
+				Method:      "GET",
+				Path:        "/usage",
+				Description: "List the usage ledger - requests, prompt/completion/reasoning tokens and total latency per subject, model, endpoint and day. Filter with the subject, from and to (YYYY-MM-DD) query parameters. The range defaults to the first day of the current month through today.",
+				Auth:        "Required when auth is enabled. Admin token required.",
+				Headers: []header{
+					{Name: "Authorization", Description: "Bearer token for admin authentication", Required: true},
+				},
+				Response: &response{
+					ContentType: "application/json",
+					Description: "Returns a list of usage records ordered by day.",
+				},
+				Examples: []example{
+					{
+						Description: "List the usage for one subject in January:",
+						Code: `curl -X GET "http://localhost:8080/v1/usage?subject=<subject>&from=2026-01-01&to=2026-01-31" \
   -H "Authorization: Bearer $KRONK_TOKEN"`,
 					},
 				},
@@ -142,7 +142,21 @@ func defaultParamFields() []field {
 		{Name: "top_p", Type: "float32", Required: false, Description: "Nucleus sampling threshold (default: 0.9)"},
 		{Name: "min_p", Type: "float32", Required: false, Description: "Dynamic sampling threshold (default: 0.0)"},
 		{Name: "max_tokens", Type: "int", Required: false, Description: "Maximum output tokens (default: 1024)"},
+		{Name: "n", Type: "int", Required: false, Description: "Number of choices to generate from one prompt, up to the model's parallel sequences (default: 1)"},
+		{Name: "stop", Type: "string|[]string", Required: false, Description: "Up to 4 strings that end generation, not included in the content (default: none)"},
 		{Name: "enable_thinking", Type: "boolean", Required: false, Description: "Enable model thinking for non-GPT models (default: true)"},
 		{Name: "reasoning_effort", Type: "string", Required: false, Description: "Reasoning level for GPT models (default: medium)"},
+		{Name: "typical_p", Type: "float32", Required: false, Description: "Locally typical sampling threshold, 1.0 disables it (default: 1.0)"},
+		{Name: "dry_multiplier", Type: "float32", Required: false, Description: "Strength of the DRY repetition penalty, 0 disables it (default: 0.0)"},
+		{Name: "dry_sequence_breakers", Type: "[]string", Required: false, Description: "Strings that stop DRY sequence matching (default: [\"\\n\", \":\", \"\\\"\", \"*\"])"},
+		{Name: "samplers", Type: "[]string", Required: false, Description: "Samplers to use in order: penalties, dry, top_k, typical_p, top_p, min_p, xtc, temperature (default: all in that order)"},
+		{Name: "response_format", Type: "object", Required: false, Description: "Constrain output to JSON: {\"type\": \"json_object\"} or {\"type\": \"json_schema\", \"json_schema\": {\"schema\": {...}}} (default: text)"},
+		{Name: "grammar", Type: "string", Required: false, Description: "GBNF grammar that constrains the output, takes precedence over response_format"},
+		{Name: "logit_bias", Type: "object", Required: false, Description: "Map of token ids to a bias from -100 to 100 added to their logits, -100 bans the token (default: none)"},
+		{Name: "logit_bias_text", Type: "object", Required: false, Description: "Map of text to a bias applied to each token of the text (default: none)"},
+		{Name: "logprobs", Type: "boolean", Required: false, Description: "Return the log probability of each content token (default: false)"},
+		{Name: "top_logprobs", Type: "int", Required: false, Description: "Number of most likely tokens, 0 to 20, returned at each position, requires logprobs (default: 0)"},
+		{Name: "seed", Type: "uint32", Required: false, Description: "Seed for the samplers, returned in the response so a request can be replayed (default: random)"},
+		{Name: "session_id", Type: "string", Required: false, Description: "Binds the request to a session whose saved KV cache state is restored instead of processing the prompt again"},
 	}
 }
@@ -592,6 +592,7 @@ func securityCommand() command {
 							{Name: "--local", Description: "Run without the model server"},
 							{Name: "--duration <duration>", Description: "Token duration (e.g., 1h, 24h, 720h)"},
 							{Name: "--endpoints <list>", Description: "Endpoints with optional rate limits"},
+							{Name: "--priority <string>", Description: "Highest priority class for the token's requests (low, normal, high)"},
 						},
 						EnvVars: []envVar{
 							{Name: "KRONK_TOKEN", Default: "", Description: "Admin token (required when auth enabled)"},
@@ -599,10 +600,30 @@ func securityCommand() command {
 						Examples: []string{
 							"# Create a token with 24 hour duration\nexport KRONK_TOKEN=<admin-token>\nkronk security token create --duration 24h --endpoints chat-completions,embeddings",
 							"# Create a token with rate limits\nkronk security token create --duration 720h --endpoints \"chat-completions:1000/day,embeddings:unlimited\"",
+							"# Create a token with a token budget per minute\nkronk security token create --duration 720h --endpoints \"chat-completions:60/minute:tokens=20000\"",
+							"# Create a token for batch work that yields to interactive requests\nkronk security token create --duration 720h --endpoints chat-completions --priority low",
 						},
 					},
 				},
 			},
+			{
+				Name:  "usage",
+				Short: "Show the usage ledger per subject, model, endpoint and day.",
+				Usage: "kronk security usage [flags]",
+				Flags: []flag{
+					{Name: "--local", Description: "Run without the model server"},
+					{Name: "--subject <string>", Description: "Only show the usage for this token subject"},
+					{Name: "--from <YYYY-MM-DD>", Description: "First day to include (default first day of the month)"},
+					{Name: "--to <YYYY-MM-DD>", Description: "Last day to include (default today)"},
+				},
+				EnvVars: []envVar{
+					{Name: "KRONK_TOKEN", Default: "", Description: "Admin token (required when auth enabled)"},
+				},
+				Examples: []string{
+					"# Show this month's usage\nexport KRONK_TOKEN=<admin-token>\nkronk security usage",
+					"# Show the usage for one subject over a date range\nkronk security usage --subject <subject> --from 2026-01-01 --to 2026-01-31",
+				},
+			},
 		},
 	}
 }
@@ -627,7 +648,12 @@ func serverCommand() command {
 					{Name: "--max-instances <int>", Description: "Maximum model instances"},
 					{Name: "--models-in-cache <int>", Description: "Maximum models in cache"},
 					{Name: "--cache-ttl <duration>", Description: "Cache TTL duration (e.g., 5m, 1h)"},
+					{Name: "--cache-max-memory <bytes>", Description: "Memory budget in bytes for the models in cache"},
 					{Name: "--model-config-file <string>", Description: "Special config file for model specific config"},
+					{Name: "--preload <list>", Description: "Models to load at startup, readiness waits for them"},
+					{Name: "--subject-queue-limit <int>", Description: "Maximum requests a subject can have waiting for a model slot (0=unlimited)"},
+					{Name: "--response-store <string>", Description: "Store for the responses api: memory or badger"},
+					{Name: "--response-ttl <duration>", Description: "How long stored responses are kept (e.g., 24h)"},
 					{Name: "--llama-log <int>", Description: "Llama log level (0=off, 1=on)"},
 				},
 				EnvVars: []envVar{
@@ -9,6 +9,8 @@ import (
 
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/auth"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/ledger"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/rate"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
 	"github.com/ardanlabs/kronk/sdk/kronk/observ/otel"
 	"github.com/google/uuid"
@@ -53,7 +55,8 @@ func (a *App) Authenticate(ctx context.Context, req *AuthenticateRequest) (*Auth
 		a.log.Info(ctx, "***> auth", "status", "authentication disabled")
 
 		arb := AuthenticateResponse_builder{
-			Subject: proto.String(uuid.Nil.String()),
+			Subject:  proto.String(uuid.Nil.String()),
+			Priority: proto.String(auth.PriorityHigh),
 		}
 
 		return arb.Build(), nil
@@ -78,7 +81,19 @@ func (a *App) Authenticate(ctx context.Context, req *AuthenticateRequest) (*Auth
 	}
 
 	arb := AuthenticateResponse_builder{
-		Subject: proto.String(claims.Subject),
+		Subject:  proto.String(claims.Subject),
+		Priority: proto.String(claims.MaxPriority()),
+	}
+
+	if req.GetEndpoint() != "" {
+		q, err := a.security.Quota(claims, req.GetEndpoint())
+		switch {
+		case err != nil:
+			a.log.Error(ctx, "authenticate", "quota", err)
+
+		case q.Window != auth.RateUnlimited:
+			arb.Quota = toQuota(q)
+		}
 	}
 
 	return arb.Build(), nil
@@ -94,12 +109,19 @@ func (a *App) CreateToken(ctx context.Context, req *CreateTokenRequest) (*Create
 	endpoints := make(map[string]auth.RateLimit)
 	for name, rl := range req.GetEndpoints() {
 		endpoints[name] = auth.RateLimit{
-			Limit:  int(rl.GetLimit()),
-			Window: auth.RateWindow(rl.GetWindow()),
+			Limit:            int(rl.GetLimit()),
+			Window:           auth.RateWindow(rl.GetWindow()),
+			PromptTokens:     int(rl.GetPromptTokens()),
+			CompletionTokens: int(rl.GetCompletionTokens()),
+			TotalTokens:      int(rl.GetTotalTokens()),
 		}
 	}
 
-	token, err := a.security.GenerateToken(req.GetAdmin(), endpoints, duration)
+	if !auth.ValidPriority(req.GetPriority()) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown priority[%s], use low, normal or high", req.GetPriority())
+	}
+
+	token, err := a.security.GenerateToken(req.GetAdmin(), req.GetPriority(), endpoints, duration)
 	if err != nil {
 		a.log.Error(ctx, "token", "err", err)
 		return nil, status.Error(codes.Internal, "failed to generate token")
@@ -161,16 +183,123 @@ func (a *App) RemoveKey(ctx context.Context, req *RemoveKeyRequest) (*RemoveKeyR
 	return &RemoveKeyResponse{}, nil
 }
 
+// RecordUsage records the usage of a request in the usage ledger and counts
+// the tokens against the token budgets of the caller. When authentication is
+// disabled, the usage is recorded under the nil subject.
+func (a *App) RecordUsage(ctx context.Context, req *RecordUsageRequest) (*RecordUsageResponse, error) {
+	entry := ledger.Entry{
+		Model:            req.GetModel(),
+		Endpoint:         req.GetEndpoint(),
+		PromptTokens:     int(req.GetPromptTokens()),
+		CompletionTokens: int(req.GetCompletionTokens()),
+		ReasoningTokens:  int(req.GetReasoningTokens()),
+		Latency:          time.Duration(req.GetLatencyMs()) * time.Millisecond,
+	}
+
+	if !a.enabled {
+		entry.Subject = uuid.Nil.String()
+
+		if err := a.security.RecordLedger(entry); err != nil {
+			a.log.Error(ctx, "recordusage", "err", err)
+			return nil, status.Error(codes.Internal, "failed to record usage")
+		}
+
+		return &RecordUsageResponse{}, nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("no metadata")
+	}
+
+	bearerToken := md.Get("authorization")
+	if len(bearerToken) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized: no authorization header")
+	}
+
+	if err := a.security.RecordUsage(ctx, bearerToken[0], entry); err != nil {
+		a.log.Error(ctx, "recordusage", "err", err)
+		return nil, status.Error(codes.Internal, "failed to record usage")
+	}
+
+	return &RecordUsageResponse{}, nil
+}
+
+// ListUsage returns the usage ledger records for a date range.
+func (a *App) ListUsage(ctx context.Context, req *ListUsageRequest) (*ListUsageResponse, error) {
+	from, err := time.Parse(ledger.DayFormat, req.GetFrom())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid from day: %s", err)
+	}
+
+	to, err := time.Parse(ledger.DayFormat, req.GetTo())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid to day: %s", err)
+	}
+
+	filter := ledger.Filter{
+		Subject: req.GetSubject(),
+		From:    from,
+		To:      to,
+	}
+
+	records, err := a.security.Usage(filter)
+	if err != nil {
+		a.log.Error(ctx, "listusage", "err", err)
+		return nil, status.Error(codes.Internal, "failed to list usage")
+	}
+
+	protoRecords := make([]*UsageRecord, len(records))
+	for i, rec := range records {
+		urb := UsageRecord_builder{
+			Day:              proto.String(rec.Day),
+			Subject:          proto.String(rec.Subject),
+			Model:            proto.String(rec.Model),
+			Endpoint:         proto.String(rec.Endpoint),
+			Requests:         proto.Int64(rec.Requests),
+			PromptTokens:     proto.Int64(rec.PromptTokens),
+			CompletionTokens: proto.Int64(rec.CompletionTokens),
+			ReasoningTokens:  proto.Int64(rec.ReasoningTokens),
+			LatencyMs:        proto.Int64(rec.LatencyMS),
+		}
+		protoRecords[i] = urb.Build()
+	}
+
+	lurb := ListUsageResponse_builder{
+		Records: protoRecords,
+	}
+
+	return lurb.Build(), nil
+}
+
 // =============================================================================
 
+func toQuota(q rate.Quota) *Quota {
+	qb := Quota_builder{
+		Window:                    proto.String(string(q.Window)),
+		Reset:                     proto.Int64(q.Reset.Unix()),
+		Requests:                  proto.Int64(int64(q.Requests)),
+		RequestsRemaining:         proto.Int64(int64(q.RequestsRemaining)),
+		PromptTokens:              proto.Int64(int64(q.PromptTokens)),
+		PromptTokensRemaining:     proto.Int64(int64(q.PromptTokensRemaining)),
+		CompletionTokens:          proto.Int64(int64(q.CompletionTokens)),
+		CompletionTokensRemaining: proto.Int64(int64(q.CompletionTokensRemaining)),
+		TotalTokens:               proto.Int64(int64(q.TotalTokens)),
+		TotalTokensRemaining:      proto.Int64(int64(q.TotalTokensRemaining)),
+	}
+
+	return qb.Build()
+}
+
 func (a *App) authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	ctx = otel.InjectTracing(ctx, a.tracer)
 
 	switch info.FullMethod {
-	case "/auth.Auth/CreateToken",
-		"/auth.Auth/ListKeys",
-		"/auth.Auth/AddKey",
-		"/auth.Auth/RemoveKey":
+	case Auth_CreateToken_FullMethodName,
+		Auth_ListKeys_FullMethodName,
+		Auth_AddKey_FullMethodName,
+		Auth_RemoveKey_FullMethodName,
+		Auth_ListUsage_FullMethodName:
 		return a.requireAuth(ctx, true, "", req, handler)
 
 	default:
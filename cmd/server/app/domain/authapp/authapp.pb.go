@@ -22,13 +22,16 @@ const (
 
 // RateLimit defines rate limiting for an endpoint.
 type RateLimit struct {
-	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Limit       int32                  `protobuf:"varint,1,opt,name=limit"`
-	xxx_hidden_Window      *string                `protobuf:"bytes,2,opt,name=window"`
-	XXX_raceDetectHookData protoimpl.RaceDetectHookData
-	XXX_presence           [1]uint32
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+	state                       protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Limit            int32                  `protobuf:"varint,1,opt,name=limit"`
+	xxx_hidden_Window           *string                `protobuf:"bytes,2,opt,name=window"`
+	xxx_hidden_PromptTokens     int64                  `protobuf:"varint,3,opt,name=prompt_tokens,json=promptTokens"`
+	xxx_hidden_CompletionTokens int64                  `protobuf:"varint,4,opt,name=completion_tokens,json=completionTokens"`
+	xxx_hidden_TotalTokens      int64                  `protobuf:"varint,5,opt,name=total_tokens,json=totalTokens"`
+	XXX_raceDetectHookData      protoimpl.RaceDetectHookData
+	XXX_presence                [1]uint32
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
 }
 
 func (x *RateLimit) Reset() {
@@ -73,14 +76,50 @@ func (x *RateLimit) GetWindow() string {
 	return ""
 }
 
+func (x *RateLimit) GetPromptTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_PromptTokens
+	}
+	return 0
+}
+
+func (x *RateLimit) GetCompletionTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_CompletionTokens
+	}
+	return 0
+}
+
+func (x *RateLimit) GetTotalTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_TotalTokens
+	}
+	return 0
+}
+
 func (x *RateLimit) SetLimit(v int32) {
 	x.xxx_hidden_Limit = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 5)
 }
 
 func (x *RateLimit) SetWindow(v string) {
 	x.xxx_hidden_Window = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 5)
+}
+
+func (x *RateLimit) SetPromptTokens(v int64) {
+	x.xxx_hidden_PromptTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 5)
+}
+
+func (x *RateLimit) SetCompletionTokens(v int64) {
+	x.xxx_hidden_CompletionTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 5)
+}
+
+func (x *RateLimit) SetTotalTokens(v int64) {
+	x.xxx_hidden_TotalTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 5)
 }
 
 func (x *RateLimit) HasLimit() bool {
@@ -97,6 +136,27 @@ func (x *RateLimit) HasWindow() bool {
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
 }
 
+func (x *RateLimit) HasPromptTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *RateLimit) HasCompletionTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *RateLimit) HasTotalTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
 func (x *RateLimit) ClearLimit() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
 	x.xxx_hidden_Limit = 0
@@ -107,11 +167,29 @@ func (x *RateLimit) ClearWindow() {
 	x.xxx_hidden_Window = nil
 }
 
+func (x *RateLimit) ClearPromptTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_PromptTokens = 0
+}
+
+func (x *RateLimit) ClearCompletionTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_CompletionTokens = 0
+}
+
+func (x *RateLimit) ClearTotalTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_TotalTokens = 0
+}
+
 type RateLimit_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Limit  *int32
-	Window *string
+	Limit            *int32
+	Window           *string
+	PromptTokens     *int64
+	CompletionTokens *int64
+	TotalTokens      *int64
 }
 
 func (b0 RateLimit_builder) Build() *RateLimit {
@@ -119,13 +197,25 @@ func (b0 RateLimit_builder) Build() *RateLimit {
 	b, x := &b0, m0
 	_, _ = b, x
 	if b.Limit != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 5)
 		x.xxx_hidden_Limit = *b.Limit
 	}
 	if b.Window != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 5)
 		x.xxx_hidden_Window = b.Window
 	}
+	if b.PromptTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 5)
+		x.xxx_hidden_PromptTokens = *b.PromptTokens
+	}
+	if b.CompletionTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 5)
+		x.xxx_hidden_CompletionTokens = *b.CompletionTokens
+	}
+	if b.TotalTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 5)
+		x.xxx_hidden_TotalTokens = *b.TotalTokens
+	}
 	return m0
 }
 
@@ -137,6 +227,7 @@ type CreateTokenRequest struct {
 	xxx_hidden_Admin       bool                   `protobuf:"varint,3,opt,name=admin"`
 	xxx_hidden_Duration    *string                `protobuf:"bytes,4,opt,name=duration"`
 	xxx_hidden_Endpoints   map[string]*RateLimit  `protobuf:"bytes,5,rep,name=endpoints" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	xxx_hidden_Priority    *string                `protobuf:"bytes,6,opt,name=priority"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
@@ -212,30 +303,45 @@ func (x *CreateTokenRequest) GetEndpoints() map[string]*RateLimit {
 	return nil
 }
 
+func (x *CreateTokenRequest) GetPriority() string {
+	if x != nil {
+		if x.xxx_hidden_Priority != nil {
+			return *x.xxx_hidden_Priority
+		}
+		return ""
+	}
+	return ""
+}
+
 func (x *CreateTokenRequest) SetToken(v string) {
 	x.xxx_hidden_Token = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 5)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 6)
 }
 
 func (x *CreateTokenRequest) SetUserName(v string) {
 	x.xxx_hidden_UserName = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 5)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 6)
 }
 
 func (x *CreateTokenRequest) SetAdmin(v bool) {
 	x.xxx_hidden_Admin = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 5)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 6)
 }
 
 func (x *CreateTokenRequest) SetDuration(v string) {
 	x.xxx_hidden_Duration = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 5)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 6)
 }
 
 func (x *CreateTokenRequest) SetEndpoints(v map[string]*RateLimit) {
 	x.xxx_hidden_Endpoints = v
 }
 
+func (x *CreateTokenRequest) SetPriority(v string) {
+	x.xxx_hidden_Priority = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 6)
+}
+
 func (x *CreateTokenRequest) HasToken() bool {
 	if x == nil {
 		return false
@@ -264,6 +370,13 @@ func (x *CreateTokenRequest) HasDuration() bool {
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
 }
 
+func (x *CreateTokenRequest) HasPriority() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
 func (x *CreateTokenRequest) ClearToken() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
 	x.xxx_hidden_Token = nil
@@ -284,6 +397,11 @@ func (x *CreateTokenRequest) ClearDuration() {
 	x.xxx_hidden_Duration = nil
 }
 
+func (x *CreateTokenRequest) ClearPriority() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_Priority = nil
+}
+
 type CreateTokenRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
@@ -292,6 +410,7 @@ type CreateTokenRequest_builder struct {
 	Admin     *bool
 	Duration  *string
 	Endpoints map[string]*RateLimit
+	Priority  *string
 }
 
 func (b0 CreateTokenRequest_builder) Build() *CreateTokenRequest {
@@ -299,22 +418,26 @@ func (b0 CreateTokenRequest_builder) Build() *CreateTokenRequest {
 	b, x := &b0, m0
 	_, _ = b, x
 	if b.Token != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 5)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 6)
 		x.xxx_hidden_Token = b.Token
 	}
 	if b.UserName != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 5)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 6)
 		x.xxx_hidden_UserName = b.UserName
 	}
 	if b.Admin != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 5)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 6)
 		x.xxx_hidden_Admin = *b.Admin
 	}
 	if b.Duration != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 5)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 6)
 		x.xxx_hidden_Duration = b.Duration
 	}
 	x.xxx_hidden_Endpoints = b.Endpoints
+	if b.Priority != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 6)
+		x.xxx_hidden_Priority = b.Priority
+	}
 	return m0
 }
 
@@ -543,6 +666,8 @@ func (b0 AuthenticateRequest_builder) Build() *AuthenticateRequest {
 type AuthenticateResponse struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
 	xxx_hidden_Subject     *string                `protobuf:"bytes,1,opt,name=subject"`
+	xxx_hidden_Quota       *Quota                 `protobuf:"bytes,2,opt,name=quota"`
+	xxx_hidden_Priority    *string                `protobuf:"bytes,3,opt,name=priority"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
@@ -584,9 +709,35 @@ func (x *AuthenticateResponse) GetSubject() string {
 	return ""
 }
 
+func (x *AuthenticateResponse) GetQuota() *Quota {
+	if x != nil {
+		return x.xxx_hidden_Quota
+	}
+	return nil
+}
+
+func (x *AuthenticateResponse) GetPriority() string {
+	if x != nil {
+		if x.xxx_hidden_Priority != nil {
+			return *x.xxx_hidden_Priority
+		}
+		return ""
+	}
+	return ""
+}
+
 func (x *AuthenticateResponse) SetSubject(v string) {
 	x.xxx_hidden_Subject = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
+}
+
+func (x *AuthenticateResponse) SetQuota(v *Quota) {
+	x.xxx_hidden_Quota = v
+}
+
+func (x *AuthenticateResponse) SetPriority(v string) {
+	x.xxx_hidden_Priority = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
 }
 
 func (x *AuthenticateResponse) HasSubject() bool {
@@ -596,15 +747,40 @@ func (x *AuthenticateResponse) HasSubject() bool {
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
+func (x *AuthenticateResponse) HasQuota() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Quota != nil
+}
+
+func (x *AuthenticateResponse) HasPriority() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
 func (x *AuthenticateResponse) ClearSubject() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
 	x.xxx_hidden_Subject = nil
 }
 
+func (x *AuthenticateResponse) ClearQuota() {
+	x.xxx_hidden_Quota = nil
+}
+
+func (x *AuthenticateResponse) ClearPriority() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Priority = nil
+}
+
 type AuthenticateResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Subject *string
+	Subject  *string
+	Quota    *Quota
+	Priority *string
 }
 
 func (b0 AuthenticateResponse_builder) Build() *AuthenticateResponse {
@@ -612,33 +788,50 @@ func (b0 AuthenticateResponse_builder) Build() *AuthenticateResponse {
 	b, x := &b0, m0
 	_, _ = b, x
 	if b.Subject != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
 		x.xxx_hidden_Subject = b.Subject
 	}
+	x.xxx_hidden_Quota = b.Quota
+	if b.Priority != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_Priority = b.Priority
+	}
 	return m0
 }
 
-// Request message for listing keys.
-type ListKeysRequest struct {
-	state         protoimpl.MessageState `protogen:"opaque.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ListKeysRequest) Reset() {
-	*x = ListKeysRequest{}
+// Quota represents what is left of a rate limit in the current window.
+type Quota struct {
+	state                                protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Window                    *string                `protobuf:"bytes,1,opt,name=window"`
+	xxx_hidden_Reset_                    int64                  `protobuf:"varint,2,opt,name=reset"`
+	xxx_hidden_Requests                  int64                  `protobuf:"varint,3,opt,name=requests"`
+	xxx_hidden_RequestsRemaining         int64                  `protobuf:"varint,4,opt,name=requests_remaining,json=requestsRemaining"`
+	xxx_hidden_PromptTokens              int64                  `protobuf:"varint,5,opt,name=prompt_tokens,json=promptTokens"`
+	xxx_hidden_PromptTokensRemaining     int64                  `protobuf:"varint,6,opt,name=prompt_tokens_remaining,json=promptTokensRemaining"`
+	xxx_hidden_CompletionTokens          int64                  `protobuf:"varint,7,opt,name=completion_tokens,json=completionTokens"`
+	xxx_hidden_CompletionTokensRemaining int64                  `protobuf:"varint,8,opt,name=completion_tokens_remaining,json=completionTokensRemaining"`
+	xxx_hidden_TotalTokens               int64                  `protobuf:"varint,9,opt,name=total_tokens,json=totalTokens"`
+	xxx_hidden_TotalTokensRemaining      int64                  `protobuf:"varint,10,opt,name=total_tokens_remaining,json=totalTokensRemaining"`
+	XXX_raceDetectHookData               protoimpl.RaceDetectHookData
+	XXX_presence                         [1]uint32
+	unknownFields                        protoimpl.UnknownFields
+	sizeCache                            protoimpl.SizeCache
+}
+
+func (x *Quota) Reset() {
+	*x = Quota{}
 	mi := &file_authapp_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListKeysRequest) String() string {
+func (x *Quota) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListKeysRequest) ProtoMessage() {}
+func (*Quota) ProtoMessage() {}
 
-func (x *ListKeysRequest) ProtoReflect() protoreflect.Message {
+func (x *Quota) ProtoReflect() protoreflect.Message {
 	mi := &file_authapp_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -650,212 +843,333 @@ func (x *ListKeysRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-type ListKeysRequest_builder struct {
-	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+func (x *Quota) GetWindow() string {
+	if x != nil {
+		if x.xxx_hidden_Window != nil {
+			return *x.xxx_hidden_Window
+		}
+		return ""
+	}
+	return ""
+}
 
+func (x *Quota) GetReset() int64 {
+	if x != nil {
+		return x.xxx_hidden_Reset_
+	}
+	return 0
 }
 
-func (b0 ListKeysRequest_builder) Build() *ListKeysRequest {
-	m0 := &ListKeysRequest{}
-	b, x := &b0, m0
-	_, _ = b, x
-	return m0
+func (x *Quota) GetRequests() int64 {
+	if x != nil {
+		return x.xxx_hidden_Requests
+	}
+	return 0
 }
 
-// Response message for listing keys.
-type ListKeysResponse struct {
-	state           protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Keys *[]*Key                `protobuf:"bytes,1,rep,name=keys"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *Quota) GetRequestsRemaining() int64 {
+	if x != nil {
+		return x.xxx_hidden_RequestsRemaining
+	}
+	return 0
 }
 
-func (x *ListKeysResponse) Reset() {
-	*x = ListKeysResponse{}
-	mi := &file_authapp_proto_msgTypes[6]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Quota) GetPromptTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_PromptTokens
+	}
+	return 0
 }
 
-func (x *ListKeysResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Quota) GetPromptTokensRemaining() int64 {
+	if x != nil {
+		return x.xxx_hidden_PromptTokensRemaining
+	}
+	return 0
 }
 
-func (*ListKeysResponse) ProtoMessage() {}
+func (x *Quota) GetCompletionTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_CompletionTokens
+	}
+	return 0
+}
 
-func (x *ListKeysResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_authapp_proto_msgTypes[6]
+func (x *Quota) GetCompletionTokensRemaining() int64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.xxx_hidden_CompletionTokensRemaining
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-func (x *ListKeysResponse) GetKeys() []*Key {
+func (x *Quota) GetTotalTokens() int64 {
 	if x != nil {
-		if x.xxx_hidden_Keys != nil {
-			return *x.xxx_hidden_Keys
-		}
+		return x.xxx_hidden_TotalTokens
 	}
-	return nil
+	return 0
 }
 
-func (x *ListKeysResponse) SetKeys(v []*Key) {
-	x.xxx_hidden_Keys = &v
+func (x *Quota) GetTotalTokensRemaining() int64 {
+	if x != nil {
+		return x.xxx_hidden_TotalTokensRemaining
+	}
+	return 0
 }
 
-type ListKeysResponse_builder struct {
-	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+func (x *Quota) SetWindow(v string) {
+	x.xxx_hidden_Window = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 10)
+}
 
-	Keys []*Key
+func (x *Quota) SetReset(v int64) {
+	x.xxx_hidden_Reset_ = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 10)
 }
 
-func (b0 ListKeysResponse_builder) Build() *ListKeysResponse {
-	m0 := &ListKeysResponse{}
-	b, x := &b0, m0
-	_, _ = b, x
-	x.xxx_hidden_Keys = &b.Keys
-	return m0
+func (x *Quota) SetRequests(v int64) {
+	x.xxx_hidden_Requests = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 10)
 }
 
-// Key represents a key in the system.
-type Key struct {
-	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Id          *string                `protobuf:"bytes,1,opt,name=id"`
-	xxx_hidden_Created     *string                `protobuf:"bytes,2,opt,name=created"`
-	XXX_raceDetectHookData protoimpl.RaceDetectHookData
-	XXX_presence           [1]uint32
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+func (x *Quota) SetRequestsRemaining(v int64) {
+	x.xxx_hidden_RequestsRemaining = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 10)
 }
 
-func (x *Key) Reset() {
-	*x = Key{}
-	mi := &file_authapp_proto_msgTypes[7]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Quota) SetPromptTokens(v int64) {
+	x.xxx_hidden_PromptTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 10)
 }
 
-func (x *Key) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Quota) SetPromptTokensRemaining(v int64) {
+	x.xxx_hidden_PromptTokensRemaining = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 10)
 }
 
-func (*Key) ProtoMessage() {}
+func (x *Quota) SetCompletionTokens(v int64) {
+	x.xxx_hidden_CompletionTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 6, 10)
+}
 
-func (x *Key) ProtoReflect() protoreflect.Message {
-	mi := &file_authapp_proto_msgTypes[7]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
+func (x *Quota) SetCompletionTokensRemaining(v int64) {
+	x.xxx_hidden_CompletionTokensRemaining = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 7, 10)
 }
 
-func (x *Key) GetId() string {
-	if x != nil {
-		if x.xxx_hidden_Id != nil {
-			return *x.xxx_hidden_Id
-		}
-		return ""
+func (x *Quota) SetTotalTokens(v int64) {
+	x.xxx_hidden_TotalTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 8, 10)
+}
+
+func (x *Quota) SetTotalTokensRemaining(v int64) {
+	x.xxx_hidden_TotalTokensRemaining = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 9, 10)
+}
+
+func (x *Quota) HasWindow() bool {
+	if x == nil {
+		return false
 	}
-	return ""
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *Key) GetCreated() string {
-	if x != nil {
-		if x.xxx_hidden_Created != nil {
-			return *x.xxx_hidden_Created
-		}
-		return ""
+func (x *Quota) HasReset() bool {
+	if x == nil {
+		return false
 	}
-	return ""
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
 }
 
-func (x *Key) SetId(v string) {
-	x.xxx_hidden_Id = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+func (x *Quota) HasRequests() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
 }
 
-func (x *Key) SetCreated(v string) {
-	x.xxx_hidden_Created = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+func (x *Quota) HasRequestsRemaining() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
 }
 
-func (x *Key) HasId() bool {
+func (x *Quota) HasPromptTokens() bool {
 	if x == nil {
 		return false
 	}
-	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
 }
 
-func (x *Key) HasCreated() bool {
+func (x *Quota) HasPromptTokensRemaining() bool {
 	if x == nil {
 		return false
 	}
-	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
 }
 
-func (x *Key) ClearId() {
+func (x *Quota) HasCompletionTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 6)
+}
+
+func (x *Quota) HasCompletionTokensRemaining() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 7)
+}
+
+func (x *Quota) HasTotalTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 8)
+}
+
+func (x *Quota) HasTotalTokensRemaining() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 9)
+}
+
+func (x *Quota) ClearWindow() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_Id = nil
+	x.xxx_hidden_Window = nil
 }
 
-func (x *Key) ClearCreated() {
+func (x *Quota) ClearReset() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
-	x.xxx_hidden_Created = nil
+	x.xxx_hidden_Reset_ = 0
 }
 
-type Key_builder struct {
+func (x *Quota) ClearRequests() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Requests = 0
+}
+
+func (x *Quota) ClearRequestsRemaining() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_RequestsRemaining = 0
+}
+
+func (x *Quota) ClearPromptTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_PromptTokens = 0
+}
+
+func (x *Quota) ClearPromptTokensRemaining() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_PromptTokensRemaining = 0
+}
+
+func (x *Quota) ClearCompletionTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 6)
+	x.xxx_hidden_CompletionTokens = 0
+}
+
+func (x *Quota) ClearCompletionTokensRemaining() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 7)
+	x.xxx_hidden_CompletionTokensRemaining = 0
+}
+
+func (x *Quota) ClearTotalTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 8)
+	x.xxx_hidden_TotalTokens = 0
+}
+
+func (x *Quota) ClearTotalTokensRemaining() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 9)
+	x.xxx_hidden_TotalTokensRemaining = 0
+}
+
+type Quota_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Id      *string
-	Created *string
+	Window                    *string
+	Reset                     *int64
+	Requests                  *int64
+	RequestsRemaining         *int64
+	PromptTokens              *int64
+	PromptTokensRemaining     *int64
+	CompletionTokens          *int64
+	CompletionTokensRemaining *int64
+	TotalTokens               *int64
+	TotalTokensRemaining      *int64
 }
 
-func (b0 Key_builder) Build() *Key {
-	m0 := &Key{}
+func (b0 Quota_builder) Build() *Quota {
+	m0 := &Quota{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.Id != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
-		x.xxx_hidden_Id = b.Id
+	if b.Window != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 10)
+		x.xxx_hidden_Window = b.Window
 	}
-	if b.Created != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
-		x.xxx_hidden_Created = b.Created
+	if b.Reset != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 10)
+		x.xxx_hidden_Reset_ = *b.Reset
+	}
+	if b.Requests != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 10)
+		x.xxx_hidden_Requests = *b.Requests
+	}
+	if b.RequestsRemaining != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 10)
+		x.xxx_hidden_RequestsRemaining = *b.RequestsRemaining
+	}
+	if b.PromptTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 10)
+		x.xxx_hidden_PromptTokens = *b.PromptTokens
+	}
+	if b.PromptTokensRemaining != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 10)
+		x.xxx_hidden_PromptTokensRemaining = *b.PromptTokensRemaining
+	}
+	if b.CompletionTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 6, 10)
+		x.xxx_hidden_CompletionTokens = *b.CompletionTokens
+	}
+	if b.CompletionTokensRemaining != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 7, 10)
+		x.xxx_hidden_CompletionTokensRemaining = *b.CompletionTokensRemaining
+	}
+	if b.TotalTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 8, 10)
+		x.xxx_hidden_TotalTokens = *b.TotalTokens
+	}
+	if b.TotalTokensRemaining != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 9, 10)
+		x.xxx_hidden_TotalTokensRemaining = *b.TotalTokensRemaining
 	}
 	return m0
 }
 
-// Request message for adding a key.
-type AddKeyRequest struct {
+// Request message for listing keys.
+type ListKeysRequest struct {
 	state         protoimpl.MessageState `protogen:"opaque.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddKeyRequest) Reset() {
-	*x = AddKeyRequest{}
-	mi := &file_authapp_proto_msgTypes[8]
+func (x *ListKeysRequest) Reset() {
+	*x = ListKeysRequest{}
+	mi := &file_authapp_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddKeyRequest) String() string {
+func (x *ListKeysRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddKeyRequest) ProtoMessage() {}
+func (*ListKeysRequest) ProtoMessage() {}
 
-func (x *AddKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_authapp_proto_msgTypes[8]
+func (x *ListKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -866,40 +1180,41 @@ func (x *AddKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-type AddKeyRequest_builder struct {
+type ListKeysRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
 }
 
-func (b0 AddKeyRequest_builder) Build() *AddKeyRequest {
-	m0 := &AddKeyRequest{}
+func (b0 ListKeysRequest_builder) Build() *ListKeysRequest {
+	m0 := &ListKeysRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
 	return m0
 }
 
-// Response message for adding a key.
-type AddKeyResponse struct {
-	state         protoimpl.MessageState `protogen:"opaque.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Response message for listing keys.
+type ListKeysResponse struct {
+	state           protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Keys *[]*Key                `protobuf:"bytes,1,rep,name=keys"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *AddKeyResponse) Reset() {
-	*x = AddKeyResponse{}
-	mi := &file_authapp_proto_msgTypes[9]
+func (x *ListKeysResponse) Reset() {
+	*x = ListKeysResponse{}
+	mi := &file_authapp_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddKeyResponse) String() string {
+func (x *ListKeysResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddKeyResponse) ProtoMessage() {}
+func (*ListKeysResponse) ProtoMessage() {}
 
-func (x *AddKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_authapp_proto_msgTypes[9]
+func (x *ListKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -910,43 +1225,59 @@ func (x *AddKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-type AddKeyResponse_builder struct {
+func (x *ListKeysResponse) GetKeys() []*Key {
+	if x != nil {
+		if x.xxx_hidden_Keys != nil {
+			return *x.xxx_hidden_Keys
+		}
+	}
+	return nil
+}
+
+func (x *ListKeysResponse) SetKeys(v []*Key) {
+	x.xxx_hidden_Keys = &v
+}
+
+type ListKeysResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
+	Keys []*Key
 }
 
-func (b0 AddKeyResponse_builder) Build() *AddKeyResponse {
-	m0 := &AddKeyResponse{}
+func (b0 ListKeysResponse_builder) Build() *ListKeysResponse {
+	m0 := &ListKeysResponse{}
 	b, x := &b0, m0
 	_, _ = b, x
+	x.xxx_hidden_Keys = &b.Keys
 	return m0
 }
 
-// Request message for removing a key.
-type RemoveKeyRequest struct {
+// Key represents a key in the system.
+type Key struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_KeyId       *string                `protobuf:"bytes,1,opt,name=key_id,json=keyId"`
+	xxx_hidden_Id          *string                `protobuf:"bytes,1,opt,name=id"`
+	xxx_hidden_Created     *string                `protobuf:"bytes,2,opt,name=created"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
 
-func (x *RemoveKeyRequest) Reset() {
-	*x = RemoveKeyRequest{}
-	mi := &file_authapp_proto_msgTypes[10]
+func (x *Key) Reset() {
+	*x = Key{}
+	mi := &file_authapp_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveKeyRequest) String() string {
+func (x *Key) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveKeyRequest) ProtoMessage() {}
+func (*Key) ProtoMessage() {}
 
-func (x *RemoveKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_authapp_proto_msgTypes[10]
+func (x *Key) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -957,72 +1288,737 @@ func (x *RemoveKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *RemoveKeyRequest) GetKeyId() string {
+func (x *Key) GetId() string {
 	if x != nil {
-		if x.xxx_hidden_KeyId != nil {
-			return *x.xxx_hidden_KeyId
+		if x.xxx_hidden_Id != nil {
+			return *x.xxx_hidden_Id
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *RemoveKeyRequest) SetKeyId(v string) {
-	x.xxx_hidden_KeyId = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+func (x *Key) GetCreated() string {
+	if x != nil {
+		if x.xxx_hidden_Created != nil {
+			return *x.xxx_hidden_Created
+		}
+		return ""
+	}
+	return ""
 }
 
-func (x *RemoveKeyRequest) HasKeyId() bool {
+func (x *Key) SetId(v string) {
+	x.xxx_hidden_Id = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+}
+
+func (x *Key) SetCreated(v string) {
+	x.xxx_hidden_Created = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *Key) HasId() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *RemoveKeyRequest) ClearKeyId() {
+func (x *Key) HasCreated() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *Key) ClearId() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_KeyId = nil
+	x.xxx_hidden_Id = nil
 }
 
-type RemoveKeyRequest_builder struct {
+func (x *Key) ClearCreated() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Created = nil
+}
+
+type Key_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	KeyId *string
+	Id      *string
+	Created *string
 }
 
-func (b0 RemoveKeyRequest_builder) Build() *RemoveKeyRequest {
-	m0 := &RemoveKeyRequest{}
+func (b0 Key_builder) Build() *Key {
+	m0 := &Key{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.KeyId != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
-		x.xxx_hidden_KeyId = b.KeyId
+	if b.Id != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
+		x.xxx_hidden_Id = b.Id
+	}
+	if b.Created != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_Created = b.Created
 	}
 	return m0
 }
 
-// Response message for removing a key.
+// Request message for adding a key.
+type AddKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddKeyRequest) Reset() {
+	*x = AddKeyRequest{}
+	mi := &file_authapp_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddKeyRequest) ProtoMessage() {}
+
+func (x *AddKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type AddKeyRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 AddKeyRequest_builder) Build() *AddKeyRequest {
+	m0 := &AddKeyRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+// Response message for adding a key.
+type AddKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddKeyResponse) Reset() {
+	*x = AddKeyResponse{}
+	mi := &file_authapp_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddKeyResponse) ProtoMessage() {}
+
+func (x *AddKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type AddKeyResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 AddKeyResponse_builder) Build() *AddKeyResponse {
+	m0 := &AddKeyResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+// Request message for removing a key.
+type RemoveKeyRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_KeyId       *string                `protobuf:"bytes,1,opt,name=key_id,json=keyId"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *RemoveKeyRequest) Reset() {
+	*x = RemoveKeyRequest{}
+	mi := &file_authapp_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveKeyRequest) ProtoMessage() {}
+
+func (x *RemoveKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *RemoveKeyRequest) GetKeyId() string {
+	if x != nil {
+		if x.xxx_hidden_KeyId != nil {
+			return *x.xxx_hidden_KeyId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *RemoveKeyRequest) SetKeyId(v string) {
+	x.xxx_hidden_KeyId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+}
+
+func (x *RemoveKeyRequest) HasKeyId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *RemoveKeyRequest) ClearKeyId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_KeyId = nil
+}
+
+type RemoveKeyRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	KeyId *string
+}
+
+func (b0 RemoveKeyRequest_builder) Build() *RemoveKeyRequest {
+	m0 := &RemoveKeyRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.KeyId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		x.xxx_hidden_KeyId = b.KeyId
+	}
+	return m0
+}
+
+// Response message for removing a key.
 type RemoveKeyResponse struct {
 	state         protoimpl.MessageState `protogen:"opaque.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemoveKeyResponse) Reset() {
-	*x = RemoveKeyResponse{}
-	mi := &file_authapp_proto_msgTypes[11]
+func (x *RemoveKeyResponse) Reset() {
+	*x = RemoveKeyResponse{}
+	mi := &file_authapp_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveKeyResponse) ProtoMessage() {}
+
+func (x *RemoveKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type RemoveKeyResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 RemoveKeyResponse_builder) Build() *RemoveKeyResponse {
+	m0 := &RemoveKeyResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+// Request message for recording token usage.
+type RecordUsageRequest struct {
+	state                       protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Endpoint         *string                `protobuf:"bytes,1,opt,name=endpoint"`
+	xxx_hidden_PromptTokens     int64                  `protobuf:"varint,2,opt,name=prompt_tokens,json=promptTokens"`
+	xxx_hidden_CompletionTokens int64                  `protobuf:"varint,3,opt,name=completion_tokens,json=completionTokens"`
+	xxx_hidden_Model            *string                `protobuf:"bytes,4,opt,name=model"`
+	xxx_hidden_ReasoningTokens  int64                  `protobuf:"varint,5,opt,name=reasoning_tokens,json=reasoningTokens"`
+	xxx_hidden_LatencyMs        int64                  `protobuf:"varint,6,opt,name=latency_ms,json=latencyMs"`
+	XXX_raceDetectHookData      protoimpl.RaceDetectHookData
+	XXX_presence                [1]uint32
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
+}
+
+func (x *RecordUsageRequest) Reset() {
+	*x = RecordUsageRequest{}
+	mi := &file_authapp_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordUsageRequest) ProtoMessage() {}
+
+func (x *RecordUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *RecordUsageRequest) GetEndpoint() string {
+	if x != nil {
+		if x.xxx_hidden_Endpoint != nil {
+			return *x.xxx_hidden_Endpoint
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *RecordUsageRequest) GetPromptTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_PromptTokens
+	}
+	return 0
+}
+
+func (x *RecordUsageRequest) GetCompletionTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_CompletionTokens
+	}
+	return 0
+}
+
+func (x *RecordUsageRequest) GetModel() string {
+	if x != nil {
+		if x.xxx_hidden_Model != nil {
+			return *x.xxx_hidden_Model
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *RecordUsageRequest) GetReasoningTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_ReasoningTokens
+	}
+	return 0
+}
+
+func (x *RecordUsageRequest) GetLatencyMs() int64 {
+	if x != nil {
+		return x.xxx_hidden_LatencyMs
+	}
+	return 0
+}
+
+func (x *RecordUsageRequest) SetEndpoint(v string) {
+	x.xxx_hidden_Endpoint = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 6)
+}
+
+func (x *RecordUsageRequest) SetPromptTokens(v int64) {
+	x.xxx_hidden_PromptTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 6)
+}
+
+func (x *RecordUsageRequest) SetCompletionTokens(v int64) {
+	x.xxx_hidden_CompletionTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 6)
+}
+
+func (x *RecordUsageRequest) SetModel(v string) {
+	x.xxx_hidden_Model = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 6)
+}
+
+func (x *RecordUsageRequest) SetReasoningTokens(v int64) {
+	x.xxx_hidden_ReasoningTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 6)
+}
+
+func (x *RecordUsageRequest) SetLatencyMs(v int64) {
+	x.xxx_hidden_LatencyMs = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 6)
+}
+
+func (x *RecordUsageRequest) HasEndpoint() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *RecordUsageRequest) HasPromptTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *RecordUsageRequest) HasCompletionTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *RecordUsageRequest) HasModel() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *RecordUsageRequest) HasReasoningTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *RecordUsageRequest) HasLatencyMs() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *RecordUsageRequest) ClearEndpoint() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Endpoint = nil
+}
+
+func (x *RecordUsageRequest) ClearPromptTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_PromptTokens = 0
+}
+
+func (x *RecordUsageRequest) ClearCompletionTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_CompletionTokens = 0
+}
+
+func (x *RecordUsageRequest) ClearModel() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Model = nil
+}
+
+func (x *RecordUsageRequest) ClearReasoningTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_ReasoningTokens = 0
+}
+
+func (x *RecordUsageRequest) ClearLatencyMs() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_LatencyMs = 0
+}
+
+type RecordUsageRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Endpoint         *string
+	PromptTokens     *int64
+	CompletionTokens *int64
+	Model            *string
+	ReasoningTokens  *int64
+	LatencyMs        *int64
+}
+
+func (b0 RecordUsageRequest_builder) Build() *RecordUsageRequest {
+	m0 := &RecordUsageRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Endpoint != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 6)
+		x.xxx_hidden_Endpoint = b.Endpoint
+	}
+	if b.PromptTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 6)
+		x.xxx_hidden_PromptTokens = *b.PromptTokens
+	}
+	if b.CompletionTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 6)
+		x.xxx_hidden_CompletionTokens = *b.CompletionTokens
+	}
+	if b.Model != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 6)
+		x.xxx_hidden_Model = b.Model
+	}
+	if b.ReasoningTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 6)
+		x.xxx_hidden_ReasoningTokens = *b.ReasoningTokens
+	}
+	if b.LatencyMs != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 6)
+		x.xxx_hidden_LatencyMs = *b.LatencyMs
+	}
+	return m0
+}
+
+// Response message for recording token usage.
+type RecordUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordUsageResponse) Reset() {
+	*x = RecordUsageResponse{}
+	mi := &file_authapp_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordUsageResponse) ProtoMessage() {}
+
+func (x *RecordUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type RecordUsageResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 RecordUsageResponse_builder) Build() *RecordUsageResponse {
+	m0 := &RecordUsageResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+// Request message for listing usage. The days use the YYYY-MM-DD format.
+type ListUsageRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Subject     *string                `protobuf:"bytes,1,opt,name=subject"`
+	xxx_hidden_From        *string                `protobuf:"bytes,2,opt,name=from"`
+	xxx_hidden_To          *string                `protobuf:"bytes,3,opt,name=to"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ListUsageRequest) Reset() {
+	*x = ListUsageRequest{}
+	mi := &file_authapp_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsageRequest) ProtoMessage() {}
+
+func (x *ListUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ListUsageRequest) GetSubject() string {
+	if x != nil {
+		if x.xxx_hidden_Subject != nil {
+			return *x.xxx_hidden_Subject
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ListUsageRequest) GetFrom() string {
+	if x != nil {
+		if x.xxx_hidden_From != nil {
+			return *x.xxx_hidden_From
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ListUsageRequest) GetTo() string {
+	if x != nil {
+		if x.xxx_hidden_To != nil {
+			return *x.xxx_hidden_To
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ListUsageRequest) SetSubject(v string) {
+	x.xxx_hidden_Subject = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
+}
+
+func (x *ListUsageRequest) SetFrom(v string) {
+	x.xxx_hidden_From = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *ListUsageRequest) SetTo(v string) {
+	x.xxx_hidden_To = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *ListUsageRequest) HasSubject() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *ListUsageRequest) HasFrom() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *ListUsageRequest) HasTo() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *ListUsageRequest) ClearSubject() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Subject = nil
+}
+
+func (x *ListUsageRequest) ClearFrom() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_From = nil
+}
+
+func (x *ListUsageRequest) ClearTo() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_To = nil
+}
+
+type ListUsageRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Subject *string
+	From    *string
+	To      *string
+}
+
+func (b0 ListUsageRequest_builder) Build() *ListUsageRequest {
+	m0 := &ListUsageRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Subject != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
+		x.xxx_hidden_Subject = b.Subject
+	}
+	if b.From != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_From = b.From
+	}
+	if b.To != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_To = b.To
+	}
+	return m0
+}
+
+// Response message for listing usage.
+type ListUsageResponse struct {
+	state              protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Records *[]*UsageRecord        `protobuf:"bytes,1,rep,name=records"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ListUsageResponse) Reset() {
+	*x = ListUsageResponse{}
+	mi := &file_authapp_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveKeyResponse) String() string {
+func (x *ListUsageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveKeyResponse) ProtoMessage() {}
+func (*ListUsageResponse) ProtoMessage() {}
 
-func (x *RemoveKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_authapp_proto_msgTypes[11]
+func (x *ListUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1033,15 +2029,358 @@ func (x *RemoveKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-type RemoveKeyResponse_builder struct {
+func (x *ListUsageResponse) GetRecords() []*UsageRecord {
+	if x != nil {
+		if x.xxx_hidden_Records != nil {
+			return *x.xxx_hidden_Records
+		}
+	}
+	return nil
+}
+
+func (x *ListUsageResponse) SetRecords(v []*UsageRecord) {
+	x.xxx_hidden_Records = &v
+}
+
+type ListUsageResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
+	Records []*UsageRecord
 }
 
-func (b0 RemoveKeyResponse_builder) Build() *RemoveKeyResponse {
-	m0 := &RemoveKeyResponse{}
+func (b0 ListUsageResponse_builder) Build() *ListUsageResponse {
+	m0 := &ListUsageResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Records = &b.Records
+	return m0
+}
+
+// UsageRecord represents the usage of a subject, model and endpoint on a day.
+type UsageRecord struct {
+	state                       protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Day              *string                `protobuf:"bytes,1,opt,name=day"`
+	xxx_hidden_Subject          *string                `protobuf:"bytes,2,opt,name=subject"`
+	xxx_hidden_Model            *string                `protobuf:"bytes,3,opt,name=model"`
+	xxx_hidden_Endpoint         *string                `protobuf:"bytes,4,opt,name=endpoint"`
+	xxx_hidden_Requests         int64                  `protobuf:"varint,5,opt,name=requests"`
+	xxx_hidden_PromptTokens     int64                  `protobuf:"varint,6,opt,name=prompt_tokens,json=promptTokens"`
+	xxx_hidden_CompletionTokens int64                  `protobuf:"varint,7,opt,name=completion_tokens,json=completionTokens"`
+	xxx_hidden_ReasoningTokens  int64                  `protobuf:"varint,8,opt,name=reasoning_tokens,json=reasoningTokens"`
+	xxx_hidden_LatencyMs        int64                  `protobuf:"varint,9,opt,name=latency_ms,json=latencyMs"`
+	XXX_raceDetectHookData      protoimpl.RaceDetectHookData
+	XXX_presence                [1]uint32
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
+}
+
+func (x *UsageRecord) Reset() {
+	*x = UsageRecord{}
+	mi := &file_authapp_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageRecord) ProtoMessage() {}
+
+func (x *UsageRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_authapp_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UsageRecord) GetDay() string {
+	if x != nil {
+		if x.xxx_hidden_Day != nil {
+			return *x.xxx_hidden_Day
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UsageRecord) GetSubject() string {
+	if x != nil {
+		if x.xxx_hidden_Subject != nil {
+			return *x.xxx_hidden_Subject
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UsageRecord) GetModel() string {
+	if x != nil {
+		if x.xxx_hidden_Model != nil {
+			return *x.xxx_hidden_Model
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UsageRecord) GetEndpoint() string {
+	if x != nil {
+		if x.xxx_hidden_Endpoint != nil {
+			return *x.xxx_hidden_Endpoint
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UsageRecord) GetRequests() int64 {
+	if x != nil {
+		return x.xxx_hidden_Requests
+	}
+	return 0
+}
+
+func (x *UsageRecord) GetPromptTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_PromptTokens
+	}
+	return 0
+}
+
+func (x *UsageRecord) GetCompletionTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_CompletionTokens
+	}
+	return 0
+}
+
+func (x *UsageRecord) GetReasoningTokens() int64 {
+	if x != nil {
+		return x.xxx_hidden_ReasoningTokens
+	}
+	return 0
+}
+
+func (x *UsageRecord) GetLatencyMs() int64 {
+	if x != nil {
+		return x.xxx_hidden_LatencyMs
+	}
+	return 0
+}
+
+func (x *UsageRecord) SetDay(v string) {
+	x.xxx_hidden_Day = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 9)
+}
+
+func (x *UsageRecord) SetSubject(v string) {
+	x.xxx_hidden_Subject = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 9)
+}
+
+func (x *UsageRecord) SetModel(v string) {
+	x.xxx_hidden_Model = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 9)
+}
+
+func (x *UsageRecord) SetEndpoint(v string) {
+	x.xxx_hidden_Endpoint = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 9)
+}
+
+func (x *UsageRecord) SetRequests(v int64) {
+	x.xxx_hidden_Requests = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 9)
+}
+
+func (x *UsageRecord) SetPromptTokens(v int64) {
+	x.xxx_hidden_PromptTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 9)
+}
+
+func (x *UsageRecord) SetCompletionTokens(v int64) {
+	x.xxx_hidden_CompletionTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 6, 9)
+}
+
+func (x *UsageRecord) SetReasoningTokens(v int64) {
+	x.xxx_hidden_ReasoningTokens = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 7, 9)
+}
+
+func (x *UsageRecord) SetLatencyMs(v int64) {
+	x.xxx_hidden_LatencyMs = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 8, 9)
+}
+
+func (x *UsageRecord) HasDay() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *UsageRecord) HasSubject() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *UsageRecord) HasModel() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *UsageRecord) HasEndpoint() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *UsageRecord) HasRequests() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *UsageRecord) HasPromptTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *UsageRecord) HasCompletionTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 6)
+}
+
+func (x *UsageRecord) HasReasoningTokens() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 7)
+}
+
+func (x *UsageRecord) HasLatencyMs() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 8)
+}
+
+func (x *UsageRecord) ClearDay() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Day = nil
+}
+
+func (x *UsageRecord) ClearSubject() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Subject = nil
+}
+
+func (x *UsageRecord) ClearModel() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Model = nil
+}
+
+func (x *UsageRecord) ClearEndpoint() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Endpoint = nil
+}
+
+func (x *UsageRecord) ClearRequests() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Requests = 0
+}
+
+func (x *UsageRecord) ClearPromptTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_PromptTokens = 0
+}
+
+func (x *UsageRecord) ClearCompletionTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 6)
+	x.xxx_hidden_CompletionTokens = 0
+}
+
+func (x *UsageRecord) ClearReasoningTokens() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 7)
+	x.xxx_hidden_ReasoningTokens = 0
+}
+
+func (x *UsageRecord) ClearLatencyMs() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 8)
+	x.xxx_hidden_LatencyMs = 0
+}
+
+type UsageRecord_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Day              *string
+	Subject          *string
+	Model            *string
+	Endpoint         *string
+	Requests         *int64
+	PromptTokens     *int64
+	CompletionTokens *int64
+	ReasoningTokens  *int64
+	LatencyMs        *int64
+}
+
+func (b0 UsageRecord_builder) Build() *UsageRecord {
+	m0 := &UsageRecord{}
 	b, x := &b0, m0
 	_, _ = b, x
+	if b.Day != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 9)
+		x.xxx_hidden_Day = b.Day
+	}
+	if b.Subject != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 9)
+		x.xxx_hidden_Subject = b.Subject
+	}
+	if b.Model != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 9)
+		x.xxx_hidden_Model = b.Model
+	}
+	if b.Endpoint != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 9)
+		x.xxx_hidden_Endpoint = b.Endpoint
+	}
+	if b.Requests != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 9)
+		x.xxx_hidden_Requests = *b.Requests
+	}
+	if b.PromptTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 9)
+		x.xxx_hidden_PromptTokens = *b.PromptTokens
+	}
+	if b.CompletionTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 6, 9)
+		x.xxx_hidden_CompletionTokens = *b.CompletionTokens
+	}
+	if b.ReasoningTokens != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 7, 9)
+		x.xxx_hidden_ReasoningTokens = *b.ReasoningTokens
+	}
+	if b.LatencyMs != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 8, 9)
+		x.xxx_hidden_LatencyMs = *b.LatencyMs
+	}
 	return m0
 }
 
@@ -1049,16 +2388,20 @@ var File_authapp_proto protoreflect.FileDescriptor
 
 const file_authapp_proto_rawDesc = "" +
 	"\n" +
-	"\rauthapp.proto\x12\aauthapp\"9\n" +
+	"\rauthapp.proto\x12\aauthapp\"\xae\x01\n" +
 	"\tRateLimit\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06window\x18\x02 \x01(\tR\x06window\"\x95\x02\n" +
+	"\x06window\x18\x02 \x01(\tR\x06window\x12#\n" +
+	"\rprompt_tokens\x18\x03 \x01(\x03R\fpromptTokens\x12+\n" +
+	"\x11completion_tokens\x18\x04 \x01(\x03R\x10completionTokens\x12!\n" +
+	"\ftotal_tokens\x18\x05 \x01(\x03R\vtotalTokens\"\xb1\x02\n" +
 	"\x12CreateTokenRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1b\n" +
 	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x14\n" +
 	"\x05admin\x18\x03 \x01(\bR\x05admin\x12\x1a\n" +
 	"\bduration\x18\x04 \x01(\tR\bduration\x12H\n" +
-	"\tendpoints\x18\x05 \x03(\v2*.authapp.CreateTokenRequest.EndpointsEntryR\tendpoints\x1aP\n" +
+	"\tendpoints\x18\x05 \x03(\v2*.authapp.CreateTokenRequest.EndpointsEntryR\tendpoints\x12\x1a\n" +
+	"\bpriority\x18\x06 \x01(\tR\bpriority\x1aP\n" +
 	"\x0eEndpointsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
 	"\x05value\x18\x02 \x01(\v2\x12.authapp.RateLimitR\x05value:\x028\x01\"+\n" +
@@ -1067,9 +2410,23 @@ const file_authapp_proto_rawDesc = "" +
 	"\x13AuthenticateRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12\x14\n" +
 	"\x05admin\x18\x02 \x01(\bR\x05admin\x12\x1a\n" +
-	"\bendpoint\x18\x03 \x01(\tR\bendpoint\"0\n" +
+	"\bendpoint\x18\x03 \x01(\tR\bendpoint\"r\n" +
 	"\x14AuthenticateResponse\x12\x18\n" +
-	"\asubject\x18\x01 \x01(\tR\asubject\"\x11\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12$\n" +
+	"\x05quota\x18\x02 \x01(\v2\x0e.authapp.QuotaR\x05quota\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\tR\bpriority\"\xa3\x03\n" +
+	"\x05Quota\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x14\n" +
+	"\x05reset\x18\x02 \x01(\x03R\x05reset\x12\x1a\n" +
+	"\brequests\x18\x03 \x01(\x03R\brequests\x12-\n" +
+	"\x12requests_remaining\x18\x04 \x01(\x03R\x11requestsRemaining\x12#\n" +
+	"\rprompt_tokens\x18\x05 \x01(\x03R\fpromptTokens\x126\n" +
+	"\x17prompt_tokens_remaining\x18\x06 \x01(\x03R\x15promptTokensRemaining\x12+\n" +
+	"\x11completion_tokens\x18\a \x01(\x03R\x10completionTokens\x12>\n" +
+	"\x1bcompletion_tokens_remaining\x18\b \x01(\x03R\x19completionTokensRemaining\x12!\n" +
+	"\ftotal_tokens\x18\t \x01(\x03R\vtotalTokens\x124\n" +
+	"\x16total_tokens_remaining\x18\n" +
+	" \x01(\x03R\x14totalTokensRemaining\"\x11\n" +
 	"\x0fListKeysRequest\"4\n" +
 	"\x10ListKeysResponse\x12 \n" +
 	"\x04keys\x18\x01 \x03(\v2\f.authapp.KeyR\x04keys\"/\n" +
@@ -1080,49 +2437,89 @@ const file_authapp_proto_rawDesc = "" +
 	"\x0eAddKeyResponse\")\n" +
 	"\x10RemoveKeyRequest\x12\x15\n" +
 	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"\x13\n" +
-	"\x11RemoveKeyResponse2\xdd\x02\n" +
+	"\x11RemoveKeyResponse\"\xe2\x01\n" +
+	"\x12RecordUsageRequest\x12\x1a\n" +
+	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12#\n" +
+	"\rprompt_tokens\x18\x02 \x01(\x03R\fpromptTokens\x12+\n" +
+	"\x11completion_tokens\x18\x03 \x01(\x03R\x10completionTokens\x12\x14\n" +
+	"\x05model\x18\x04 \x01(\tR\x05model\x12)\n" +
+	"\x10reasoning_tokens\x18\x05 \x01(\x03R\x0freasoningTokens\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x06 \x01(\x03R\tlatencyMs\"\x15\n" +
+	"\x13RecordUsageResponse\"P\n" +
+	"\x10ListUsageRequest\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\"C\n" +
+	"\x11ListUsageResponse\x12.\n" +
+	"\arecords\x18\x01 \x03(\v2\x14.authapp.UsageRecordR\arecords\"\xa3\x02\n" +
+	"\vUsageRecord\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x1a\n" +
+	"\bendpoint\x18\x04 \x01(\tR\bendpoint\x12\x1a\n" +
+	"\brequests\x18\x05 \x01(\x03R\brequests\x12#\n" +
+	"\rprompt_tokens\x18\x06 \x01(\x03R\fpromptTokens\x12+\n" +
+	"\x11completion_tokens\x18\a \x01(\x03R\x10completionTokens\x12)\n" +
+	"\x10reasoning_tokens\x18\b \x01(\x03R\x0freasoningTokens\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\t \x01(\x03R\tlatencyMs2\xeb\x03\n" +
 	"\x04Auth\x12H\n" +
 	"\vCreateToken\x12\x1b.authapp.CreateTokenRequest\x1a\x1c.authapp.CreateTokenResponse\x12K\n" +
 	"\fAuthenticate\x12\x1c.authapp.AuthenticateRequest\x1a\x1d.authapp.AuthenticateResponse\x12?\n" +
 	"\bListKeys\x12\x18.authapp.ListKeysRequest\x1a\x19.authapp.ListKeysResponse\x129\n" +
 	"\x06AddKey\x12\x16.authapp.AddKeyRequest\x1a\x17.authapp.AddKeyResponse\x12B\n" +
-	"\tRemoveKey\x12\x19.authapp.RemoveKeyRequest\x1a\x1a.authapp.RemoveKeyResponseB:Z8github.com/ardanlabs/kronk/cmd/server/app/domain/authappb\beditionsp\xe9\a"
+	"\tRemoveKey\x12\x19.authapp.RemoveKeyRequest\x1a\x1a.authapp.RemoveKeyResponse\x12H\n" +
+	"\vRecordUsage\x12\x1b.authapp.RecordUsageRequest\x1a\x1c.authapp.RecordUsageResponse\x12B\n" +
+	"\tListUsage\x12\x19.authapp.ListUsageRequest\x1a\x1a.authapp.ListUsageResponseB:Z8github.com/ardanlabs/kronk/cmd/server/app/domain/authappb\beditionsp\xe9\a"
 
-var file_authapp_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_authapp_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_authapp_proto_goTypes = []any{
 	(*RateLimit)(nil),            // 0: authapp.RateLimit
 	(*CreateTokenRequest)(nil),   // 1: authapp.CreateTokenRequest
 	(*CreateTokenResponse)(nil),  // 2: authapp.CreateTokenResponse
 	(*AuthenticateRequest)(nil),  // 3: authapp.AuthenticateRequest
 	(*AuthenticateResponse)(nil), // 4: authapp.AuthenticateResponse
-	(*ListKeysRequest)(nil),      // 5: authapp.ListKeysRequest
-	(*ListKeysResponse)(nil),     // 6: authapp.ListKeysResponse
-	(*Key)(nil),                  // 7: authapp.Key
-	(*AddKeyRequest)(nil),        // 8: authapp.AddKeyRequest
-	(*AddKeyResponse)(nil),       // 9: authapp.AddKeyResponse
-	(*RemoveKeyRequest)(nil),     // 10: authapp.RemoveKeyRequest
-	(*RemoveKeyResponse)(nil),    // 11: authapp.RemoveKeyResponse
-	nil,                          // 12: authapp.CreateTokenRequest.EndpointsEntry
+	(*Quota)(nil),                // 5: authapp.Quota
+	(*ListKeysRequest)(nil),      // 6: authapp.ListKeysRequest
+	(*ListKeysResponse)(nil),     // 7: authapp.ListKeysResponse
+	(*Key)(nil),                  // 8: authapp.Key
+	(*AddKeyRequest)(nil),        // 9: authapp.AddKeyRequest
+	(*AddKeyResponse)(nil),       // 10: authapp.AddKeyResponse
+	(*RemoveKeyRequest)(nil),     // 11: authapp.RemoveKeyRequest
+	(*RemoveKeyResponse)(nil),    // 12: authapp.RemoveKeyResponse
+	(*RecordUsageRequest)(nil),   // 13: authapp.RecordUsageRequest
+	(*RecordUsageResponse)(nil),  // 14: authapp.RecordUsageResponse
+	(*ListUsageRequest)(nil),     // 15: authapp.ListUsageRequest
+	(*ListUsageResponse)(nil),    // 16: authapp.ListUsageResponse
+	(*UsageRecord)(nil),          // 17: authapp.UsageRecord
+	nil,                          // 18: authapp.CreateTokenRequest.EndpointsEntry
 }
 var file_authapp_proto_depIdxs = []int32{
-	12, // 0: authapp.CreateTokenRequest.endpoints:type_name -> authapp.CreateTokenRequest.EndpointsEntry
-	7,  // 1: authapp.ListKeysResponse.keys:type_name -> authapp.Key
-	0,  // 2: authapp.CreateTokenRequest.EndpointsEntry.value:type_name -> authapp.RateLimit
-	1,  // 3: authapp.Auth.CreateToken:input_type -> authapp.CreateTokenRequest
-	3,  // 4: authapp.Auth.Authenticate:input_type -> authapp.AuthenticateRequest
-	5,  // 5: authapp.Auth.ListKeys:input_type -> authapp.ListKeysRequest
-	8,  // 6: authapp.Auth.AddKey:input_type -> authapp.AddKeyRequest
-	10, // 7: authapp.Auth.RemoveKey:input_type -> authapp.RemoveKeyRequest
-	2,  // 8: authapp.Auth.CreateToken:output_type -> authapp.CreateTokenResponse
-	4,  // 9: authapp.Auth.Authenticate:output_type -> authapp.AuthenticateResponse
-	6,  // 10: authapp.Auth.ListKeys:output_type -> authapp.ListKeysResponse
-	9,  // 11: authapp.Auth.AddKey:output_type -> authapp.AddKeyResponse
-	11, // 12: authapp.Auth.RemoveKey:output_type -> authapp.RemoveKeyResponse
-	8,  // [8:13] is the sub-list for method output_type
-	3,  // [3:8] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	18, // 0: authapp.CreateTokenRequest.endpoints:type_name -> authapp.CreateTokenRequest.EndpointsEntry
+	5,  // 1: authapp.AuthenticateResponse.quota:type_name -> authapp.Quota
+	8,  // 2: authapp.ListKeysResponse.keys:type_name -> authapp.Key
+	17, // 3: authapp.ListUsageResponse.records:type_name -> authapp.UsageRecord
+	0,  // 4: authapp.CreateTokenRequest.EndpointsEntry.value:type_name -> authapp.RateLimit
+	1,  // 5: authapp.Auth.CreateToken:input_type -> authapp.CreateTokenRequest
+	3,  // 6: authapp.Auth.Authenticate:input_type -> authapp.AuthenticateRequest
+	6,  // 7: authapp.Auth.ListKeys:input_type -> authapp.ListKeysRequest
+	9,  // 8: authapp.Auth.AddKey:input_type -> authapp.AddKeyRequest
+	11, // 9: authapp.Auth.RemoveKey:input_type -> authapp.RemoveKeyRequest
+	13, // 10: authapp.Auth.RecordUsage:input_type -> authapp.RecordUsageRequest
+	15, // 11: authapp.Auth.ListUsage:input_type -> authapp.ListUsageRequest
+	2,  // 12: authapp.Auth.CreateToken:output_type -> authapp.CreateTokenResponse
+	4,  // 13: authapp.Auth.Authenticate:output_type -> authapp.AuthenticateResponse
+	7,  // 14: authapp.Auth.ListKeys:output_type -> authapp.ListKeysResponse
+	10, // 15: authapp.Auth.AddKey:output_type -> authapp.AddKeyResponse
+	12, // 16: authapp.Auth.RemoveKey:output_type -> authapp.RemoveKeyResponse
+	14, // 17: authapp.Auth.RecordUsage:output_type -> authapp.RecordUsageResponse
+	16, // 18: authapp.Auth.ListUsage:output_type -> authapp.ListUsageResponse
+	12, // [12:19] is the sub-list for method output_type
+	5,  // [5:12] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_authapp_proto_init() }
@@ -1136,7 +2533,7 @@ func file_authapp_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_authapp_proto_rawDesc), len(file_authapp_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -24,6 +24,8 @@ const (
 	Auth_ListKeys_FullMethodName     = "/authapp.Auth/ListKeys"
 	Auth_AddKey_FullMethodName       = "/authapp.Auth/AddKey"
 	Auth_RemoveKey_FullMethodName    = "/authapp.Auth/RemoveKey"
+	Auth_RecordUsage_FullMethodName  = "/authapp.Auth/RecordUsage"
+	Auth_ListUsage_FullMethodName    = "/authapp.Auth/ListUsage"
 )
 
 // AuthClient is the client API for Auth service.
@@ -42,6 +44,10 @@ type AuthClient interface {
 	AddKey(ctx context.Context, in *AddKeyRequest, opts ...grpc.CallOption) (*AddKeyResponse, error)
 	// Remove a private key by ID.
 	RemoveKey(ctx context.Context, in *RemoveKeyRequest, opts ...grpc.CallOption) (*RemoveKeyResponse, error)
+	// Record the tokens used by a request.
+	RecordUsage(ctx context.Context, in *RecordUsageRequest, opts ...grpc.CallOption) (*RecordUsageResponse, error)
+	// List the usage ledger records for a date range.
+	ListUsage(ctx context.Context, in *ListUsageRequest, opts ...grpc.CallOption) (*ListUsageResponse, error)
 }
 
 type authClient struct {
@@ -102,6 +108,26 @@ func (c *authClient) RemoveKey(ctx context.Context, in *RemoveKeyRequest, opts .
 	return out, nil
 }
 
+func (c *authClient) RecordUsage(ctx context.Context, in *RecordUsageRequest, opts ...grpc.CallOption) (*RecordUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecordUsageResponse)
+	err := c.cc.Invoke(ctx, Auth_RecordUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListUsage(ctx context.Context, in *ListUsageRequest, opts ...grpc.CallOption) (*ListUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsageResponse)
+	err := c.cc.Invoke(ctx, Auth_ListUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServer is the server API for Auth service.
 // All implementations must embed UnimplementedAuthServer
 // for forward compatibility.
@@ -118,6 +144,10 @@ type AuthServer interface {
 	AddKey(context.Context, *AddKeyRequest) (*AddKeyResponse, error)
 	// Remove a private key by ID.
 	RemoveKey(context.Context, *RemoveKeyRequest) (*RemoveKeyResponse, error)
+	// Record the tokens used by a request.
+	RecordUsage(context.Context, *RecordUsageRequest) (*RecordUsageResponse, error)
+	// List the usage ledger records for a date range.
+	ListUsage(context.Context, *ListUsageRequest) (*ListUsageResponse, error)
 	mustEmbedUnimplementedAuthServer()
 }
 
@@ -143,6 +173,12 @@ func (UnimplementedAuthServer) AddKey(context.Context, *AddKeyRequest) (*AddKeyR
 func (UnimplementedAuthServer) RemoveKey(context.Context, *RemoveKeyRequest) (*RemoveKeyResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RemoveKey not implemented")
 }
+func (UnimplementedAuthServer) RecordUsage(context.Context, *RecordUsageRequest) (*RecordUsageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RecordUsage not implemented")
+}
+func (UnimplementedAuthServer) ListUsage(context.Context, *ListUsageRequest) (*ListUsageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUsage not implemented")
+}
 func (UnimplementedAuthServer) mustEmbedUnimplementedAuthServer() {}
 func (UnimplementedAuthServer) testEmbeddedByValue()              {}
 
@@ -254,6 +290,42 @@ func _Auth_RemoveKey_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Auth_RecordUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RecordUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RecordUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RecordUsage(ctx, req.(*RecordUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ListUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListUsage(ctx, req.(*ListUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Auth_ServiceDesc is the grpc.ServiceDesc for Auth service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -281,6 +353,14 @@ var Auth_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveKey",
 			Handler:    _Auth_RemoveKey_Handler,
 		},
+		{
+			MethodName: "RecordUsage",
+			Handler:    _Auth_RecordUsage_Handler,
+		},
+		{
+			MethodName: "ListUsage",
+			Handler:    _Auth_ListUsage_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "authapp.proto",
@@ -0,0 +1,96 @@
+package authapp_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/domain/authapp"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security"
+	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_ListUsageRequiresAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	sec, err := security.New(security.Config{
+		OverrideBaseKeysFolder: t.TempDir(),
+		Issuer:                 "kronk project",
+	})
+	if err != nil {
+		t.Fatalf("security: %s", err)
+	}
+	defer sec.Close()
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	app := authapp.Start(ctx, authapp.Config{
+		Log:      logger.New(io.Discard, logger.LevelInfo, "TEST", func(context.Context) string { return "" }),
+		Security: sec,
+		Listener: lis,
+		Tracer:   noop.NewTracerProvider().Tracer("test"),
+		Enabled:  true,
+	})
+	defer app.Shutdown(ctx)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("client: %s", err)
+	}
+	defer conn.Close()
+
+	client := authapp.NewAuthClient(conn)
+
+	day := time.Now().UTC().Format("2006-01-02")
+	req := authapp.ListUsageRequest_builder{
+		From: proto.String(day),
+		To:   proto.String(day),
+	}.Build()
+
+	adminToken, err := sec.GenerateToken(true, "", nil, time.Hour)
+	if err != nil {
+		t.Fatalf("admin token: %s", err)
+	}
+
+	userToken, err := sec.GenerateToken(false, "", nil, time.Hour)
+	if err != nil {
+		t.Fatalf("user token: %s", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"no-token", "", true},
+		{"non-admin", "Bearer " + userToken, true},
+		{"admin", "Bearer " + adminToken, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ctx
+			if tt.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.token)
+			}
+
+			_, err := client.ListUsage(ctx, req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -4,29 +4,36 @@ package chatapp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
+	"github.com/ardanlabs/kronk/sdk/kronk"
 	"github.com/ardanlabs/kronk/sdk/kronk/model"
 )
 
 type app struct {
-	log   *logger.Logger
-	cache *cache.Cache
+	log        *logger.Logger
+	authClient *authclient.Client
+	cache      *cache.Cache
 }
 
 func newApp(cfg Config) *app {
 	return &app{
-		log:   cfg.Log,
-		cache: cfg.Cache,
+		log:        cfg.Log,
+		authClient: cfg.AuthClient,
+		cache:      cfg.Cache,
 	}
 }
 
 func (a *app) chatCompletions(ctx context.Context, r *http.Request) web.Encoder {
+	start := time.Now()
+
 	var req model.D
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return errs.New(errs.InvalidArgument, err)
@@ -52,9 +59,28 @@ func (a *app) chatCompletions(ctx context.Context, r *http.Request) web.Encoder
 	ctx, cancel := context.WithTimeout(ctx, 180*time.Minute)
 	defer cancel()
 
-	d := model.MapToModelD(req)
+	d := a.cache.ModelOverrides(modelID).Apply(model.MapToModelD(req))
+
+	resp, err := krn.ChatStreamingHTTP(ctx, web.GetWriter(ctx), d)
+	a.authClient.RecordRequestUsage(ctx, r, authclient.Usage{
+		Endpoint:         "chat-completions",
+		Model:            krn.ModelInfo().ID,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		ReasoningTokens:  resp.Usage.ReasoningTokens,
+		Latency:          time.Since(start),
+	})
+
+	if err != nil {
+		// The error was sent as an event once the stream started.
+		if errors.Is(err, kronk.ErrStreamStarted) {
+			a.log.Error(ctx, "chat-completions", "stream", err)
+			return web.NewNoResponse()
+		}
 
-	if _, err := krn.ChatStreamingHTTP(ctx, web.GetWriter(ctx), d); err != nil {
+		if errors.Is(err, kronk.ErrQueueFull) {
+			return errs.New(errs.TooManyRequests, err)
+		}
 		return errs.New(errs.Internal, err)
 	}
 
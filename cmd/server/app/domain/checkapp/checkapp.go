@@ -7,6 +7,8 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
 )
@@ -14,16 +16,24 @@ import (
 type app struct {
 	build string
 	log   *logger.Logger
+	cache *cache.Cache
 }
 
 func newApp(cfg Config) *app {
 	return &app{
 		build: cfg.Build,
 		log:   cfg.Log,
+		cache: cfg.Cache,
 	}
 }
 
+// readiness reports the service isn't ready until the models configured to
+// preload are loaded.
 func (a *app) readiness(ctx context.Context, r *http.Request) web.Encoder {
+	if err := a.cache.Ready(); err != nil {
+		return errs.New(errs.Unavailable, err)
+	}
+
 	return nil
 }
 
@@ -3,6 +3,7 @@ package checkapp
 import (
 	"net/http"
 
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
 )
@@ -11,6 +12,7 @@ import (
 type Config struct {
 	Build string
 	Log   *logger.Logger
+	Cache *cache.Cache
 }
 
 // Routes adds specific routes for this group.
@@ -0,0 +1,88 @@
+// Package compapp provides the completions api endpoints.
+package compapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
+	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
+	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
+	"github.com/ardanlabs/kronk/sdk/kronk"
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+type app struct {
+	log        *logger.Logger
+	authClient *authclient.Client
+	cache      *cache.Cache
+}
+
+func newApp(cfg Config) *app {
+	return &app{
+		log:        cfg.Log,
+		authClient: cfg.AuthClient,
+		cache:      cfg.Cache,
+	}
+}
+
+func (a *app) completions(ctx context.Context, r *http.Request) web.Encoder {
+	start := time.Now()
+
+	var req model.D
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	modelIDReq, exists := req["model"]
+	if !exists {
+		return errs.Errorf(errs.InvalidArgument, "missing model field")
+	}
+
+	modelID, ok := modelIDReq.(string)
+	if !ok {
+		return errs.Errorf(errs.InvalidArgument, "model name must be a string")
+	}
+
+	krn, err := a.cache.AquireModel(ctx, modelID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a.log.Info(ctx, "completions", "request-input", req.LogSafe())
+
+	ctx, cancel := context.WithTimeout(ctx, 180*time.Minute)
+	defer cancel()
+
+	d := a.cache.ModelOverrides(modelID).Apply(model.MapToModelD(req))
+
+	resp, err := krn.CompletionStreamingHTTP(ctx, web.GetWriter(ctx), d)
+	a.authClient.RecordRequestUsage(ctx, r, authclient.Usage{
+		Endpoint:         "completions",
+		Model:            krn.ModelInfo().ID,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		ReasoningTokens:  resp.Usage.ReasoningTokens,
+		Latency:          time.Since(start),
+	})
+
+	if err != nil {
+		// The error was sent as an event once the stream started.
+		if errors.Is(err, kronk.ErrStreamStarted) {
+			a.log.Error(ctx, "completions", "stream", err)
+			return web.NewNoResponse()
+		}
+
+		if errors.Is(err, kronk.ErrQueueFull) {
+			return errs.New(errs.TooManyRequests, err)
+		}
+		return errs.New(errs.Internal, err)
+	}
+
+	return web.NewNoResponse()
+}
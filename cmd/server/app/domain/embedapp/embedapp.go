@@ -4,29 +4,36 @@ package embedapp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
+	"github.com/ardanlabs/kronk/sdk/kronk"
 	"github.com/ardanlabs/kronk/sdk/kronk/model"
 )
 
 type app struct {
-	log   *logger.Logger
-	cache *cache.Cache
+	log        *logger.Logger
+	authClient *authclient.Client
+	cache      *cache.Cache
 }
 
 func newApp(cfg Config) *app {
 	return &app{
-		log:   cfg.Log,
-		cache: cfg.Cache,
+		log:        cfg.Log,
+		authClient: cfg.AuthClient,
+		cache:      cfg.Cache,
 	}
 }
 
 func (a *app) embeddings(ctx context.Context, r *http.Request) web.Encoder {
+	start := time.Now()
+
 	var req model.D
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return errs.New(errs.InvalidArgument, err)
@@ -58,7 +65,18 @@ func (a *app) embeddings(ctx context.Context, r *http.Request) web.Encoder {
 
 	d := model.MapToModelD(req)
 
-	if _, err := krn.EmbeddingsHTTP(ctx, a.log.Info, web.GetWriter(ctx), d); err != nil {
+	resp, err := krn.EmbeddingsHTTP(ctx, a.log.Info, web.GetWriter(ctx), d)
+	a.authClient.RecordRequestUsage(ctx, r, authclient.Usage{
+		Endpoint:     "embeddings",
+		Model:        krn.ModelInfo().ID,
+		PromptTokens: resp.Usage.PromptTokens,
+		Latency:      time.Since(start),
+	})
+
+	if err != nil {
+		if errors.Is(err, kronk.ErrQueueFull) {
+			return errs.New(errs.TooManyRequests, err)
+		}
 		return errs.New(errs.Internal, err)
 	}
 
@@ -0,0 +1,175 @@
+// Package messagesapp provides the Anthropic compatible messages api
+// endpoints.
+package messagesapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
+	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
+	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
+	"github.com/ardanlabs/kronk/sdk/kronk"
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+	"github.com/google/uuid"
+)
+
+type app struct {
+	log        *logger.Logger
+	authClient *authclient.Client
+	cache      *cache.Cache
+}
+
+func newApp(cfg Config) *app {
+	return &app{
+		log:        cfg.Log,
+		authClient: cfg.AuthClient,
+		cache:      cfg.Cache,
+	}
+}
+
+func (a *app) messages(ctx context.Context, r *http.Request) web.Encoder {
+	start := time.Now()
+
+	var req MessagesRequest
+	if err := web.Decode(r, &req); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	krn, err := a.cache.AquireModel(ctx, req.Model)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	d, err := req.toModelD()
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a.log.Info(ctx, "messages", "request-input", d.LogSafe())
+
+	ctx, cancel := context.WithTimeout(ctx, 180*time.Minute)
+	defer cancel()
+
+	d = a.cache.ModelOverrides(req.Model).Apply(d)
+
+	ch, err := krn.ChatStreaming(ctx, d)
+	if err != nil {
+		if errors.Is(err, kronk.ErrQueueFull) {
+			return errs.New(errs.TooManyRequests, err)
+		}
+		return errs.New(errs.Internal, err)
+	}
+
+	id := "msg_" + uuid.New().String()
+
+	var usage model.Usage
+	var resp web.Encoder
+
+	switch req.Stream {
+	case true:
+		usage, resp = a.stream(ctx, ch, newStreamState(id, req.Model))
+
+	default:
+		usage, resp = a.message(ch, id, req.Model)
+	}
+
+	a.authClient.RecordRequestUsage(ctx, r, authclient.Usage{
+		Endpoint:         "messages",
+		Model:            krn.ModelInfo().ID,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		ReasoningTokens:  usage.ReasoningTokens,
+		Latency:          time.Since(start),
+	})
+
+	return resp
+}
+
+// message waits for the final chat response and returns it as a message. The
+// stream is drained even after an error so the model isn't left blocked on
+// sending.
+func (a *app) message(ch <-chan model.ChatResponse, id string, modelID string) (model.Usage, web.Encoder) {
+	var final model.ChatResponse
+	var errResp error
+
+	for resp := range ch {
+		if len(resp.Choice) == 0 {
+			continue
+		}
+
+		switch c := resp.Choice[0]; c.FinishReason {
+		case "":
+
+		case model.FinishReasonError:
+			if errResp == nil && c.Delta != nil {
+				errResp = errors.New(c.Delta.Content)
+			}
+
+		default:
+			final = resp
+		}
+	}
+
+	if errResp != nil {
+		return final.Usage, errs.New(errs.Internal, errResp)
+	}
+
+	return final.Usage, toMessageResponse(id, modelID, final)
+}
+
+// stream sends the chat stream as server-sent events.
+func (a *app) stream(ctx context.Context, ch <-chan model.ChatResponse, ss *streamState) (model.Usage, web.Encoder) {
+	w := web.GetWriter(ctx)
+
+	f, ok := w.(http.Flusher)
+	if !ok {
+		for range ch {
+		}
+		return model.Usage{}, errs.Errorf(errs.Internal, "streaming not supported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	for resp := range ch {
+		for _, event := range ss.process(resp) {
+			data, err := json.Marshal(event)
+			if err != nil {
+				a.log.Error(ctx, "messages", "marshal", err)
+				continue
+			}
+
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			f.Flush()
+		}
+	}
+
+	if ss.err != "" {
+		a.log.Error(ctx, "messages", "stream", ss.err)
+	}
+
+	return ss.usage, web.NewNoResponse()
+}
+
+// apiKey accepts the key in the x-api-key header Anthropic clients use when
+// the request has no authorization header.
+func apiKey(next web.HandlerFunc) web.HandlerFunc {
+	h := func(ctx context.Context, r *http.Request) web.Encoder {
+		if key := r.Header.Get("x-api-key"); key != "" && r.Header.Get("authorization") == "" {
+			r.Header.Set("authorization", "Bearer "+key)
+		}
+
+		return next(ctx, r)
+	}
+
+	return h
+}
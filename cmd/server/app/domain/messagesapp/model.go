@@ -0,0 +1,498 @@
+package messagesapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+// Anthropic stop reasons.
+const (
+	stopEndTurn   = "end_turn"
+	stopMaxTokens = "max_tokens"
+	stopToolUse   = "tool_use"
+)
+
+// =============================================================================
+// Request types
+
+// ImageSource represents the data of an image content block.
+type ImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+	URL       string `json:"url"`
+}
+
+// InputBlock represents a content block of a request message.
+type InputBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text"`
+	Source    *ImageSource    `json:"source"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     map[string]any  `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	Content   json.RawMessage `json:"content"`
+	IsError   bool            `json:"is_error"`
+}
+
+// InputMessage represents a message of the conversation. The content is a
+// string or a list of content blocks.
+type InputMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// Tool represents a tool the model may use.
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// ToolChoice controls how the model uses the tools.
+type ToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// Thinking controls extended thinking.
+type Thinking struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+// MessagesRequest represents the input for the messages endpoint.
+type MessagesRequest struct {
+	Model         string          `json:"model"`
+	MaxTokens     int             `json:"max_tokens"`
+	Messages      []InputMessage  `json:"messages"`
+	System        json.RawMessage `json:"system"`
+	StopSequences []string        `json:"stop_sequences"`
+	Stream        bool            `json:"stream"`
+	Temperature   *float64        `json:"temperature"`
+	TopP          *float64        `json:"top_p"`
+	TopK          *int            `json:"top_k"`
+	Tools         []Tool          `json:"tools"`
+	ToolChoice    *ToolChoice     `json:"tool_choice"`
+	Thinking      *Thinking       `json:"thinking"`
+}
+
+// Decode implements the decoder interface.
+func (app *MessagesRequest) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the request is usable.
+func (app MessagesRequest) Validate() error {
+	if app.Model == "" {
+		return errors.New("validate: missing model field")
+	}
+
+	if app.MaxTokens <= 0 {
+		return errors.New("validate: max_tokens must be greater than 0")
+	}
+
+	if len(app.Messages) == 0 {
+		return errors.New("validate: messages must not be empty")
+	}
+
+	return nil
+}
+
+// toModelD converts the request into the chat completions form the model
+// understands. Tool results become tool messages and tool uses become the tool
+// calls of the assistant. Thinking blocks of earlier turns are dropped.
+func (app MessagesRequest) toModelD() (model.D, error) {
+	var msgs []model.D
+
+	system, err := blocks(app.System)
+	if err != nil {
+		return nil, fmt.Errorf("to-model-d: system: %w", err)
+	}
+
+	if text := joinText(system); text != "" {
+		msgs = append(msgs, model.D{"role": "system", "content": text})
+	}
+
+	for i, msg := range app.Messages {
+		ibs, err := blocks(msg.Content)
+		if err != nil {
+			return nil, fmt.Errorf("to-model-d: message[%d]: %w", i, err)
+		}
+
+		switch msg.Role {
+		case "user":
+			for _, ib := range ibs {
+				if ib.Type != "tool_result" {
+					continue
+				}
+
+				content, err := blocks(ib.Content)
+				if err != nil {
+					return nil, fmt.Errorf("to-model-d: message[%d]: tool_result: %w", i, err)
+				}
+
+				msgs = append(msgs, model.D{
+					"role":         "tool",
+					"tool_call_id": ib.ToolUseID,
+					"content":      joinText(content),
+				})
+			}
+
+			if um, ok := userMessage(ibs); ok {
+				msgs = append(msgs, um)
+			}
+
+		case "assistant":
+			am := model.D{
+				"role":    model.RoleAssistant,
+				"content": joinText(ibs),
+			}
+
+			var toolCalls []model.D
+			for _, ib := range ibs {
+				if ib.Type != "tool_use" {
+					continue
+				}
+
+				toolCalls = append(toolCalls, model.D{
+					"id":   ib.ID,
+					"type": "function",
+					"function": model.D{
+						"name":      ib.Name,
+						"arguments": ib.Input,
+					},
+				})
+			}
+
+			if len(toolCalls) > 0 {
+				am["tool_calls"] = toolCalls
+			}
+
+			msgs = append(msgs, am)
+
+		default:
+			return nil, fmt.Errorf("to-model-d: message[%d]: role[%s] is not supported", i, msg.Role)
+		}
+	}
+
+	d := model.D{
+		"messages":   msgs,
+		"max_tokens": app.MaxTokens,
+	}
+
+	if len(app.StopSequences) > 0 {
+		d["stop"] = app.StopSequences
+	}
+
+	if app.Temperature != nil {
+		d["temperature"] = *app.Temperature
+	}
+
+	if app.TopP != nil {
+		d["top_p"] = *app.TopP
+	}
+
+	if app.TopK != nil {
+		d["top_k"] = *app.TopK
+	}
+
+	if len(app.Tools) > 0 {
+		tools := make([]model.D, len(app.Tools))
+		for i, tool := range app.Tools {
+			tools[i] = model.D{
+				"type": "function",
+				"function": model.D{
+					"name":        tool.Name,
+					"description": tool.Description,
+					"parameters":  tool.InputSchema,
+				},
+			}
+		}
+
+		d["tools"] = tools
+	}
+
+	if app.ToolChoice != nil {
+		switch app.ToolChoice.Type {
+		case "auto", "none":
+			d["tool_choice"] = app.ToolChoice.Type
+
+		case "any":
+			d["tool_choice"] = "required"
+
+		case "tool":
+			d["tool_choice"] = model.D{
+				"type":     "function",
+				"function": model.D{"name": app.ToolChoice.Name},
+			}
+		}
+	}
+
+	// Thinking is off unless the request asks for it.
+	d["enable_thinking"] = app.Thinking != nil && app.Thinking.Type == "enabled"
+
+	return model.MapToModelD(d), nil
+}
+
+// blocks decodes content that is a string or a list of content blocks.
+func blocks(raw json.RawMessage) ([]InputBlock, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	if raw[0] == '"' {
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, err
+		}
+
+		return []InputBlock{{Type: "text", Text: text}}, nil
+	}
+
+	var ibs []InputBlock
+	if err := json.Unmarshal(raw, &ibs); err != nil {
+		return nil, err
+	}
+
+	return ibs, nil
+}
+
+func joinText(ibs []InputBlock) string {
+	var texts []string
+	for _, ib := range ibs {
+		if ib.Type == "text" {
+			texts = append(texts, ib.Text)
+		}
+	}
+
+	return strings.Join(texts, "\n")
+}
+
+// userMessage returns the text and images of a user message. Each image is
+// paired with a text part the way the media processing expects.
+func userMessage(ibs []InputBlock) (model.D, bool) {
+	var images []string
+	var hasText bool
+
+	for _, ib := range ibs {
+		switch ib.Type {
+		case "text":
+			hasText = true
+
+		case "image":
+			if ib.Source == nil {
+				continue
+			}
+
+			switch ib.Source.Type {
+			case "base64":
+				images = append(images, fmt.Sprintf("data:%s;base64,%s", ib.Source.MediaType, ib.Source.Data))
+
+			case "url":
+				images = append(images, ib.Source.URL)
+			}
+		}
+	}
+
+	if !hasText && len(images) == 0 {
+		return nil, false
+	}
+
+	text := joinText(ibs)
+
+	if len(images) == 0 {
+		return model.D{"role": "user", "content": text}, true
+	}
+
+	content := make([]model.D, 0, len(images)*2)
+	for i, image := range images {
+		if i > 0 {
+			text = ""
+		}
+
+		content = append(content,
+			model.D{"type": "text", "text": text},
+			model.D{"type": "image_url", "image_url": model.D{"url": image}},
+		)
+	}
+
+	return model.D{"role": "user", "content": content}, true
+}
+
+// =============================================================================
+// Response types
+
+// ContentBlock represents a content block of the response.
+type ContentBlock struct {
+	Type     string
+	Text     string
+	Thinking string
+	ID       string
+	Name     string
+	Input    map[string]any
+}
+
+// MarshalJSON encodes the fields that belong to the type of the block.
+func (cb ContentBlock) MarshalJSON() ([]byte, error) {
+	switch cb.Type {
+	case "thinking":
+		return json.Marshal(struct {
+			Type      string `json:"type"`
+			Thinking  string `json:"thinking"`
+			Signature string `json:"signature"`
+		}{cb.Type, cb.Thinking, ""})
+
+	case "tool_use":
+		input := cb.Input
+		if input == nil {
+			input = map[string]any{}
+		}
+
+		return json.Marshal(struct {
+			Type  string         `json:"type"`
+			ID    string         `json:"id"`
+			Name  string         `json:"name"`
+			Input map[string]any `json:"input"`
+		}{cb.Type, cb.ID, cb.Name, input})
+	}
+
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}{cb.Type, cb.Text})
+}
+
+// Usage provides the token usage of a message.
+type Usage struct {
+	InputTokens          int `json:"input_tokens"`
+	OutputTokens         int `json:"output_tokens"`
+	CacheReadInputTokens int `json:"cache_read_input_tokens"`
+}
+
+func toUsage(u model.Usage) Usage {
+	return Usage{
+		InputTokens:          u.PromptTokens,
+		OutputTokens:         u.OutputTokens,
+		CacheReadInputTokens: u.PromptTokensDetails.CachedTokens,
+	}
+}
+
+// MessageResponse represents the output for the messages endpoint.
+type MessageResponse struct {
+	ID           string         `json:"id"`
+	Type         string         `json:"type"`
+	Role         string         `json:"role"`
+	Model        string         `json:"model"`
+	Content      []ContentBlock `json:"content"`
+	StopReason   *string        `json:"stop_reason"`
+	StopSequence *string        `json:"stop_sequence"`
+	Usage        Usage          `json:"usage"`
+}
+
+// Encode implements the encoder interface.
+func (app MessageResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// toMessageResponse builds the response from the final chat response, which
+// holds the whole content, reasoning and tool calls.
+func toMessageResponse(id string, modelID string, resp model.ChatResponse) MessageResponse {
+	mr := MessageResponse{
+		ID:      id,
+		Type:    "message",
+		Role:    model.RoleAssistant,
+		Model:   modelID,
+		Content: []ContentBlock{},
+		Usage:   toUsage(resp.Usage),
+	}
+
+	if len(resp.Choice) == 0 {
+		return mr
+	}
+
+	msg := resp.Choice[0].Message
+
+	if msg.Reasoning != "" {
+		mr.Content = append(mr.Content, ContentBlock{Type: "thinking", Thinking: msg.Reasoning})
+	}
+
+	if msg.Content != "" {
+		mr.Content = append(mr.Content, ContentBlock{Type: "text", Text: msg.Content})
+	}
+
+	for _, tc := range msg.ToolCalls {
+		mr.Content = append(mr.Content, ContentBlock{
+			Type:  "tool_use",
+			ID:    tc.ID,
+			Name:  tc.Function.Name,
+			Input: tc.Function.Arguments,
+		})
+	}
+
+	stopReason := toStopReason(resp)
+	mr.StopReason = &stopReason
+
+	return mr
+}
+
+func toStopReason(resp model.ChatResponse) string {
+	if len(resp.Choice) == 0 {
+		return stopEndTurn
+	}
+
+	switch resp.Choice[0].FinishReason {
+	case model.FinishReasonTool:
+		return stopToolUse
+
+	case model.FinishReasonLength:
+		return stopMaxTokens
+	}
+
+	return stopEndTurn
+}
+
+// =============================================================================
+// Streaming event types
+
+// BlockDelta represents a change to a content block.
+type BlockDelta struct {
+	Type        string `json:"type"`
+	Text        string `json:"text,omitempty"`
+	Thinking    string `json:"thinking,omitempty"`
+	PartialJSON string `json:"partial_json,omitempty"`
+}
+
+// MessageDelta represents a change to the message once it's finished.
+type MessageDelta struct {
+	StopReason   string  `json:"stop_reason"`
+	StopSequence *string `json:"stop_sequence"`
+}
+
+// DeltaUsage provides the output tokens of a finished message.
+type DeltaUsage struct {
+	OutputTokens int `json:"output_tokens"`
+}
+
+// ErrorDetail describes an error.
+type ErrorDetail struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// StreamEvent represents a server-sent event of the messages endpoint.
+type StreamEvent struct {
+	Type         string           `json:"type"`
+	Message      *MessageResponse `json:"message,omitempty"`
+	Index        *int             `json:"index,omitempty"`
+	ContentBlock *ContentBlock    `json:"content_block,omitempty"`
+	Delta        any              `json:"delta,omitempty"`
+	Usage        *DeltaUsage      `json:"usage,omitempty"`
+	Error        *ErrorDetail     `json:"error,omitempty"`
+}
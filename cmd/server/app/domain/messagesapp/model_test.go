@@ -0,0 +1,201 @@
+package messagesapp
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+func decodeRequest(t *testing.T, data string) MessagesRequest {
+	t.Helper()
+
+	var req MessagesRequest
+	if err := req.Decode([]byte(data)); err != nil {
+		t.Fatalf("decode: %s", err)
+	}
+
+	return req
+}
+
+func TestToModelD(t *testing.T) {
+	req := decodeRequest(t, `{
+		"model": "qwen3",
+		"max_tokens": 1024,
+		"system": [{"type": "text", "text": "Be brief."}, {"type": "text", "text": "Use metric units."}],
+		"messages": [
+			{"role": "user", "content": "What's the weather in Paris?"},
+			{"role": "assistant", "content": [
+				{"type": "thinking", "thinking": "I should call the tool."},
+				{"type": "text", "text": "Let me check."},
+				{"type": "tool_use", "id": "call_1", "name": "get_weather", "input": {"city": "Paris"}}
+			]},
+			{"role": "user", "content": [
+				{"type": "tool_result", "tool_use_id": "call_1", "content": [{"type": "text", "text": "18C"}]},
+				{"type": "text", "text": "And tomorrow?"}
+			]}
+		],
+		"stop_sequences": ["END"],
+		"temperature": 0.2,
+		"top_k": 20,
+		"tools": [{"name": "get_weather", "description": "Weather for a city", "input_schema": {"type": "object"}}],
+		"tool_choice": {"type": "any"},
+		"thinking": {"type": "enabled", "budget_tokens": 512}
+	}`)
+
+	d, err := req.toModelD()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	msgs, ok := d["messages"].([]model.D)
+	if !ok || len(msgs) != 5 {
+		t.Fatalf("expected 5 messages, got %v", d["messages"])
+	}
+
+	want := []struct {
+		role    string
+		content string
+	}{
+		{"system", "Be brief.\nUse metric units."},
+		{"user", "What's the weather in Paris?"},
+		{"assistant", "Let me check."},
+		{"tool", "18C"},
+		{"user", "And tomorrow?"},
+	}
+
+	for i, w := range want {
+		if msgs[i]["role"] != w.role || msgs[i]["content"] != w.content {
+			t.Errorf("message[%d] = %v %q, want %v %q", i, msgs[i]["role"], msgs[i]["content"], w.role, w.content)
+		}
+	}
+
+	toolCalls, ok := msgs[2]["tool_calls"].([]model.D)
+	if !ok || len(toolCalls) != 1 || toolCalls[0]["id"] != "call_1" {
+		t.Fatalf("expected the call_1 tool call, got %v", msgs[2]["tool_calls"])
+	}
+
+	if fn := toolCalls[0]["function"].(model.D); fn["name"] != "get_weather" {
+		t.Errorf("expected the get_weather function, got %v", fn["name"])
+	}
+
+	if msgs[3]["tool_call_id"] != "call_1" {
+		t.Errorf("expected the tool result for call_1, got %v", msgs[3]["tool_call_id"])
+	}
+
+	if d["max_tokens"] != 1024 || d["temperature"] != 0.2 || d["top_k"] != 20 {
+		t.Errorf("unexpected sampling fields: max_tokens %v temperature %v top_k %v", d["max_tokens"], d["temperature"], d["top_k"])
+	}
+
+	if _, exists := d["top_p"]; exists {
+		t.Error("expected no top_p when the request doesn't set it")
+	}
+
+	if tools, ok := d["tools"].([]model.D); !ok || len(tools) != 1 {
+		t.Errorf("expected 1 tool, got %v", d["tools"])
+	}
+
+	if d["tool_choice"] != "required" {
+		t.Errorf("expected tool choice required, got %v", d["tool_choice"])
+	}
+
+	if d["enable_thinking"] != true {
+		t.Errorf("expected thinking to be enabled, got %v", d["enable_thinking"])
+	}
+}
+
+func TestToModelDImage(t *testing.T) {
+	req := decodeRequest(t, `{
+		"model": "qwen3",
+		"max_tokens": 64,
+		"messages": [
+			{"role": "user", "content": [
+				{"type": "image", "source": {"type": "base64", "media_type": "image/png", "data": "aGVsbG8="}},
+				{"type": "text", "text": "What is this?"}
+			]}
+		]
+	}`)
+
+	d, err := req.toModelD()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if d["enable_thinking"] != false {
+		t.Errorf("expected thinking to be off by default, got %v", d["enable_thinking"])
+	}
+
+	msgs := d["messages"].([]model.D)
+	content, ok := msgs[0]["content"].([]model.D)
+	if !ok || len(content) != 2 {
+		t.Fatalf("expected a text and an image part, got %v", msgs[0]["content"])
+	}
+
+	if content[0]["text"] != "What is this?" {
+		t.Errorf("expected the text part, got %v", content[0]["text"])
+	}
+
+	if url := content[1]["image_url"].(model.D)["url"]; url != "data:image/png;base64,aGVsbG8=" {
+		t.Errorf("expected the image as a data url, got %v", url)
+	}
+}
+
+func TestToModelDToolChoice(t *testing.T) {
+	tests := []struct {
+		choice string
+		want   any
+	}{
+		{`{"type": "auto"}`, "auto"},
+		{`{"type": "none"}`, "none"},
+		{`{"type": "any"}`, "required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.choice, func(t *testing.T) {
+			req := decodeRequest(t, `{"model": "qwen3", "max_tokens": 64, "messages": [{"role": "user", "content": "Hi"}], "tool_choice": `+tt.choice+`}`)
+
+			d, err := req.toModelD()
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			if d["tool_choice"] != tt.want {
+				t.Errorf("tool_choice = %v, want %v", d["tool_choice"], tt.want)
+			}
+		})
+	}
+
+	t.Run("tool", func(t *testing.T) {
+		req := decodeRequest(t, `{"model": "qwen3", "max_tokens": 64, "messages": [{"role": "user", "content": "Hi"}], "tool_choice": {"type": "tool", "name": "get_weather"}}`)
+
+		d, err := req.toModelD()
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		choice, ok := d["tool_choice"].(model.D)
+		if !ok || choice["function"].(model.D)["name"] != "get_weather" {
+			t.Errorf("expected the get_weather function, got %v", d["tool_choice"])
+		}
+	})
+}
+
+func TestToModelDErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"bad role", `{"model": "qwen3", "max_tokens": 64, "messages": [{"role": "system", "content": "Hi"}]}`},
+		{"bad content", `{"model": "qwen3", "max_tokens": 64, "messages": [{"role": "user", "content": 42}]}`},
+		{"bad system", `{"model": "qwen3", "max_tokens": 64, "system": 42, "messages": [{"role": "user", "content": "Hi"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := decodeRequest(t, tt.data)
+
+			if _, err := req.toModelD(); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
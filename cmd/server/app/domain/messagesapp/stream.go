@@ -0,0 +1,182 @@
+package messagesapp
+
+import (
+	"encoding/json"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+// streamState converts the chat stream of the model into the events of the
+// messages api. A content block is opened for the reasoning and the text as
+// they arrive, and a tool use block is sent for each tool call once the final
+// response holds them.
+type streamState struct {
+	id      string
+	modelID string
+	started bool
+	index   int
+	open    string
+	usage   model.Usage
+	err     string
+}
+
+func newStreamState(id string, modelID string) *streamState {
+	return &streamState{
+		id:      id,
+		modelID: modelID,
+	}
+}
+
+func (ss *streamState) process(resp model.ChatResponse) []StreamEvent {
+	if len(resp.Choice) == 0 {
+		return nil
+	}
+
+	var events []StreamEvent
+
+	if !ss.started {
+		events = append(events, ss.start(resp.Usage))
+	}
+
+	c := resp.Choice[0]
+
+	switch c.FinishReason {
+	case "":
+		if c.Delta == nil {
+			return events
+		}
+
+		if c.Delta.Reasoning != "" {
+			events = append(events, ss.openBlock("thinking")...)
+			events = append(events, ss.blockDelta(BlockDelta{Type: "thinking_delta", Thinking: c.Delta.Reasoning}))
+		}
+
+		if c.Delta.Content != "" {
+			events = append(events, ss.openBlock("text")...)
+			events = append(events, ss.blockDelta(BlockDelta{Type: "text_delta", Text: c.Delta.Content}))
+		}
+
+	case model.FinishReasonError:
+		if c.Delta != nil {
+			ss.err = c.Delta.Content
+		}
+
+		events = append(events, StreamEvent{
+			Type: "error",
+			Error: &ErrorDetail{
+				Type:    "api_error",
+				Message: ss.err,
+			},
+		})
+
+	default:
+		events = append(events, ss.closeBlock()...)
+
+		for _, tc := range c.Message.ToolCalls {
+			events = append(events, ss.toolUse(tc)...)
+		}
+
+		ss.usage = resp.Usage
+
+		events = append(events,
+			StreamEvent{
+				Type:  "message_delta",
+				Delta: MessageDelta{StopReason: toStopReason(resp)},
+				Usage: &DeltaUsage{OutputTokens: resp.Usage.OutputTokens},
+			},
+			StreamEvent{
+				Type: "message_stop",
+			},
+		)
+	}
+
+	return events
+}
+
+func (ss *streamState) start(u model.Usage) StreamEvent {
+	ss.started = true
+
+	return StreamEvent{
+		Type: "message_start",
+		Message: &MessageResponse{
+			ID:      ss.id,
+			Type:    "message",
+			Role:    model.RoleAssistant,
+			Model:   ss.modelID,
+			Content: []ContentBlock{},
+			Usage: Usage{
+				InputTokens:          u.PromptTokens,
+				CacheReadInputTokens: u.PromptTokensDetails.CachedTokens,
+			},
+		},
+	}
+}
+
+// openBlock starts a block of the type unless it's already open, closing the
+// block that was open before it.
+func (ss *streamState) openBlock(typ string) []StreamEvent {
+	if ss.open == typ {
+		return nil
+	}
+
+	events := ss.closeBlock()
+
+	ss.open = typ
+	index := ss.index
+
+	return append(events, StreamEvent{
+		Type:         "content_block_start",
+		Index:        &index,
+		ContentBlock: &ContentBlock{Type: typ},
+	})
+}
+
+func (ss *streamState) closeBlock() []StreamEvent {
+	if ss.open == "" {
+		return nil
+	}
+
+	index := ss.index
+
+	ss.open = ""
+	ss.index++
+
+	return []StreamEvent{
+		{
+			Type:  "content_block_stop",
+			Index: &index,
+		},
+	}
+}
+
+func (ss *streamState) blockDelta(delta BlockDelta) StreamEvent {
+	index := ss.index
+
+	return StreamEvent{
+		Type:  "content_block_delta",
+		Index: &index,
+		Delta: delta,
+	}
+}
+
+// toolUse sends a tool call as a tool use block with the input as a single
+// JSON delta.
+func (ss *streamState) toolUse(tc model.ResponseToolCall) []StreamEvent {
+	// The arguments marshal as a string for the chat completions api, so
+	// they are marshaled as a plain map.
+	input := []byte("{}")
+	if tc.Function.Arguments != nil {
+		input, _ = json.Marshal(map[string]any(tc.Function.Arguments))
+	}
+
+	events := ss.openBlock("tool_use")
+	events[len(events)-1].ContentBlock = &ContentBlock{
+		Type: "tool_use",
+		ID:   tc.ID,
+		Name: tc.Function.Name,
+	}
+
+	events = append(events, ss.blockDelta(BlockDelta{Type: "input_json_delta", PartialJSON: string(input)}))
+
+	return append(events, ss.closeBlock()...)
+}
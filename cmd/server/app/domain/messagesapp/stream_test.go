@@ -0,0 +1,194 @@
+package messagesapp
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+func delta(reasoning string, content string) model.ChatResponse {
+	return model.ChatResponse{
+		Choice: []model.Choice{
+			{Delta: &model.ResponseMessage{Reasoning: reasoning, Content: content}},
+		},
+	}
+}
+
+func final(finishReason string, completionTokens int, toolCalls ...model.ResponseToolCall) model.ChatResponse {
+	return model.ChatResponse{
+		Choice: []model.Choice{
+			{
+				Message:      model.ResponseMessage{ToolCalls: toolCalls},
+				FinishReason: finishReason,
+			},
+		},
+		Usage: model.Usage{CompletionTokens: completionTokens, OutputTokens: completionTokens},
+	}
+}
+
+// describe returns a short form of the events that shows their order, block
+// indexes and types.
+func describe(events []StreamEvent) []string {
+	var got []string
+
+	for _, ev := range events {
+		s := ev.Type
+
+		if ev.Index != nil {
+			s += fmt.Sprintf(" %d", *ev.Index)
+		}
+
+		switch d := ev.Delta.(type) {
+		case BlockDelta:
+			s += " " + d.Type
+
+		case MessageDelta:
+			s += " " + d.StopReason
+		}
+
+		if ev.ContentBlock != nil {
+			s += " " + ev.ContentBlock.Type
+		}
+
+		got = append(got, s)
+	}
+
+	return got
+}
+
+func TestStreamStateProcess(t *testing.T) {
+	toolCall := model.ResponseToolCall{
+		ID: "call_1",
+		Function: model.ResponseToolCallFunction{
+			Name:      "get_weather",
+			Arguments: model.ToolCallArguments{"city": "Paris"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		resps []model.ChatResponse
+		want  []string
+	}{
+		{
+			name:  "text",
+			resps: []model.ChatResponse{delta("", "Hello"), delta("", " world"), final(model.FinishReasonStop, 2)},
+			want: []string{
+				"message_start",
+				"content_block_start 0 text",
+				"content_block_delta 0 text_delta",
+				"content_block_delta 0 text_delta",
+				"content_block_stop 0",
+				"message_delta end_turn",
+				"message_stop",
+			},
+		},
+		{
+			name:  "thinking then text then tool use",
+			resps: []model.ChatResponse{delta("Let me", ""), delta(" think", ""), delta("", "Checking"), final(model.FinishReasonTool, 10, toolCall)},
+			want: []string{
+				"message_start",
+				"content_block_start 0 thinking",
+				"content_block_delta 0 thinking_delta",
+				"content_block_delta 0 thinking_delta",
+				"content_block_stop 0",
+				"content_block_start 1 text",
+				"content_block_delta 1 text_delta",
+				"content_block_stop 1",
+				"content_block_start 2 tool_use",
+				"content_block_delta 2 input_json_delta",
+				"content_block_stop 2",
+				"message_delta tool_use",
+				"message_stop",
+			},
+		},
+		{
+			name:  "tool use only",
+			resps: []model.ChatResponse{final(model.FinishReasonTool, 10, toolCall, toolCall)},
+			want: []string{
+				"message_start",
+				"content_block_start 0 tool_use",
+				"content_block_delta 0 input_json_delta",
+				"content_block_stop 0",
+				"content_block_start 1 tool_use",
+				"content_block_delta 1 input_json_delta",
+				"content_block_stop 1",
+				"message_delta tool_use",
+				"message_stop",
+			},
+		},
+		{
+			name:  "max tokens",
+			resps: []model.ChatResponse{delta("", "Hello"), final(model.FinishReasonLength, 16)},
+			want: []string{
+				"message_start",
+				"content_block_start 0 text",
+				"content_block_delta 0 text_delta",
+				"content_block_stop 0",
+				"message_delta max_tokens",
+				"message_stop",
+			},
+		},
+		{
+			name:  "error",
+			resps: []model.ChatResponse{delta("", "Hello"), {Choice: []model.Choice{{Delta: &model.ResponseMessage{Content: "failed"}, FinishReason: model.FinishReasonError}}}},
+			want: []string{
+				"message_start",
+				"content_block_start 0 text",
+				"content_block_delta 0 text_delta",
+				"error",
+			},
+		},
+		{
+			name:  "no choices",
+			resps: []model.ChatResponse{{}, delta("", "Hi"), final(model.FinishReasonStop, 1)},
+			want: []string{
+				"message_start",
+				"content_block_start 0 text",
+				"content_block_delta 0 text_delta",
+				"content_block_stop 0",
+				"message_delta end_turn",
+				"message_stop",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := newStreamState("msg_1", "qwen3")
+
+			var events []StreamEvent
+			for _, resp := range tt.resps {
+				events = append(events, ss.process(resp)...)
+			}
+
+			if got := describe(events); !slices.Equal(got, tt.want) {
+				t.Errorf("events:\ngot  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamStateToolUseInput(t *testing.T) {
+	ss := newStreamState("msg_1", "qwen3")
+
+	events := ss.process(final(model.FinishReasonTool, 10, model.ResponseToolCall{
+		ID: "call_1",
+		Function: model.ResponseToolCallFunction{
+			Name:      "get_weather",
+			Arguments: model.ToolCallArguments{"city": "Paris"},
+		},
+	}))
+
+	start := events[1]
+	if start.ContentBlock.ID != "call_1" || start.ContentBlock.Name != "get_weather" {
+		t.Errorf("unexpected tool use block: %+v", start.ContentBlock)
+	}
+
+	d := events[2].Delta.(BlockDelta)
+	if d.PartialJSON != `{"city":"Paris"}` {
+		t.Errorf("expected the arguments as a JSON object, got %s", d.PartialJSON)
+	}
+}
@@ -0,0 +1,674 @@
+package ollamaapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+	"github.com/ardanlabs/kronk/sdk/tools/models"
+)
+
+// Options represents the model options of an Ollama request.
+type Options map[string]any
+
+// optionNames maps the Ollama options to the request fields of the model.
+// Options that don't map are ignored.
+var optionNames = map[string]string{
+	"num_predict":       "max_tokens",
+	"temperature":       "temperature",
+	"top_k":             "top_k",
+	"top_p":             "top_p",
+	"min_p":             "min_p",
+	"typical_p":         "typical_p",
+	"repeat_penalty":    "repeat_penalty",
+	"repeat_last_n":     "repeat_last_n",
+	"presence_penalty":  "presence_penalty",
+	"frequency_penalty": "frequency_penalty",
+	"seed":              "seed",
+	"stop":              "stop",
+}
+
+func (o Options) apply(d model.D) {
+	for name, value := range o {
+		field, exists := optionNames[name]
+		if !exists {
+			continue
+		}
+
+		// Ollama uses a negative number of tokens to generate until the
+		// model stops.
+		if n, ok := value.(float64); ok && field == "max_tokens" && n < 0 {
+			continue
+		}
+
+		d[field] = value
+	}
+}
+
+// =============================================================================
+
+// ToolCallFunction represents the function a model calls.
+type ToolCallFunction struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// ToolCall represents a tool call made by a model.
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+// Message represents a chat message.
+type Message struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	Thinking  string     `json:"thinking,omitempty"`
+	Images    []string   `json:"images,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"`
+}
+
+func (m Message) toModelD() model.D {
+	d := model.D{
+		"role":    m.Role,
+		"content": m.Content,
+	}
+
+	// Ollama sends the images as plain base64 next to the content. Each
+	// image is paired with a text part the way the media processing expects.
+	if len(m.Images) > 0 {
+		content := make([]model.D, 0, len(m.Images)*2)
+		for i, image := range m.Images {
+			var text string
+			if i == 0 {
+				text = m.Content
+			}
+
+			content = append(content,
+				model.D{
+					"type": "text",
+					"text": text,
+				},
+				model.D{
+					"type": "image_url",
+					"image_url": model.D{
+						"url": "data:image/jpeg;base64," + image,
+					},
+				},
+			)
+		}
+
+		d["content"] = content
+	}
+
+	if len(m.ToolCalls) > 0 {
+		toolCalls := make([]model.D, len(m.ToolCalls))
+		for i, tc := range m.ToolCalls {
+			toolCalls[i] = model.D{
+				"type": "function",
+				"function": model.D{
+					"name":      tc.Function.Name,
+					"arguments": tc.Function.Arguments,
+				},
+			}
+		}
+
+		d["tool_calls"] = toolCalls
+	}
+
+	if m.ToolName != "" {
+		d["name"] = m.ToolName
+	}
+
+	return d
+}
+
+func toToolCalls(respToolCalls []model.ResponseToolCall) []ToolCall {
+	if len(respToolCalls) == 0 {
+		return nil
+	}
+
+	toolCalls := make([]ToolCall, len(respToolCalls))
+	for i, tc := range respToolCalls {
+		toolCalls[i] = ToolCall{
+			Function: ToolCallFunction{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			},
+		}
+	}
+
+	return toolCalls
+}
+
+// =============================================================================
+
+// ChatRequest represents the input for the chat endpoint.
+type ChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []Message       `json:"messages"`
+	Tools    []any           `json:"tools"`
+	Format   json.RawMessage `json:"format"`
+	Options  Options         `json:"options"`
+	Stream   *bool           `json:"stream"`
+	Think    any             `json:"think"`
+}
+
+// Decode implements the decoder interface.
+func (app *ChatRequest) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the request is usable.
+func (app ChatRequest) Validate() error {
+	if app.Model == "" {
+		return fmt.Errorf("validate: missing model field")
+	}
+
+	return nil
+}
+
+func (app ChatRequest) toModelD() (model.D, error) {
+	msgs := make([]model.D, len(app.Messages))
+	for i, msg := range app.Messages {
+		msgs[i] = msg.toModelD()
+	}
+
+	d := model.D{
+		"messages": msgs,
+	}
+
+	if len(app.Tools) > 0 {
+		d["tools"] = app.Tools
+	}
+
+	if err := applyCommon(d, app.Format, app.Think, app.Options); err != nil {
+		return nil, err
+	}
+
+	return model.MapToModelD(d), nil
+}
+
+// ChatResponse represents a chunk or the final response of the chat endpoint.
+type ChatResponse struct {
+	Model      string  `json:"model"`
+	CreatedAt  string  `json:"created_at"`
+	Message    Message `json:"message"`
+	Done       bool    `json:"done"`
+	DoneReason string  `json:"done_reason,omitempty"`
+	Metrics
+}
+
+// Encode implements the encoder interface.
+func (app ChatResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// =============================================================================
+
+// GenerateRequest represents the input for the generate endpoint.
+type GenerateRequest struct {
+	Model   string          `json:"model"`
+	Prompt  string          `json:"prompt"`
+	Suffix  string          `json:"suffix"`
+	System  string          `json:"system"`
+	Images  []string        `json:"images"`
+	Format  json.RawMessage `json:"format"`
+	Options Options         `json:"options"`
+	Stream  *bool           `json:"stream"`
+	Raw     bool            `json:"raw"`
+	Think   any             `json:"think"`
+}
+
+// Decode implements the decoder interface.
+func (app *GenerateRequest) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the request is usable.
+func (app GenerateRequest) Validate() error {
+	if app.Model == "" {
+		return fmt.Errorf("validate: missing model field")
+	}
+
+	return nil
+}
+
+// completion reports if the prompt is given to the model as is instead of
+// going through the chat template. A suffix asks for fill-in-the-middle, which
+// is only supported for raw prompts.
+func (app GenerateRequest) completion() bool {
+	return app.Raw || app.Suffix != ""
+}
+
+func (app GenerateRequest) toModelD() (model.D, error) {
+	d := model.D{}
+
+	switch {
+	case app.completion():
+		d["prompt"] = app.Prompt
+		if app.Suffix != "" {
+			d["suffix"] = app.Suffix
+		}
+
+	default:
+		var msgs []model.D
+		if app.System != "" {
+			msgs = append(msgs, Message{Role: "system", Content: app.System}.toModelD())
+		}
+
+		msgs = append(msgs, Message{Role: "user", Content: app.Prompt, Images: app.Images}.toModelD())
+
+		d["messages"] = msgs
+	}
+
+	if err := applyCommon(d, app.Format, app.Think, app.Options); err != nil {
+		return nil, err
+	}
+
+	return model.MapToModelD(d), nil
+}
+
+// GenerateResponse represents a chunk or the final response of the generate
+// endpoint.
+type GenerateResponse struct {
+	Model      string `json:"model"`
+	CreatedAt  string `json:"created_at"`
+	Response   string `json:"response"`
+	Thinking   string `json:"thinking,omitempty"`
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason,omitempty"`
+	Metrics
+}
+
+// Encode implements the encoder interface.
+func (app GenerateResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// =============================================================================
+
+// Metrics provides the token counts and durations of a finished request. The
+// durations are in nanoseconds.
+type Metrics struct {
+	TotalDuration      int64 `json:"total_duration,omitempty"`
+	LoadDuration       int64 `json:"load_duration,omitempty"`
+	PromptEvalCount    int   `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64 `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int   `json:"eval_count,omitempty"`
+	EvalDuration       int64 `json:"eval_duration,omitempty"`
+}
+
+func toMetrics(u model.Usage, start time.Time) Metrics {
+	var evalDuration int64
+	if u.TokensPerSecond > 0 {
+		evalDuration = int64(float64(u.OutputTokens) / u.TokensPerSecond * float64(time.Second))
+	}
+
+	return Metrics{
+		TotalDuration:   time.Since(start).Nanoseconds(),
+		PromptEvalCount: u.PromptTokens,
+		EvalCount:       u.OutputTokens,
+		EvalDuration:    evalDuration,
+	}
+}
+
+// applyCommon adds the format, think and options fields shared by the chat
+// and generate requests.
+func applyCommon(d model.D, format json.RawMessage, think any, options Options) error {
+	if len(format) > 0 && string(format) != "null" {
+		var f any
+		if err := json.Unmarshal(format, &f); err != nil {
+			return fmt.Errorf("apply-common: format: %w", err)
+		}
+
+		switch v := f.(type) {
+		case string:
+			if v != "json" {
+				return fmt.Errorf("apply-common: format[%s] is not supported", v)
+			}
+			d["response_format"] = model.D{"type": "json_object"}
+
+		case map[string]any:
+			d["response_format"] = model.D{"type": "json_schema", "json_schema": model.D{"schema": v}}
+
+		default:
+			return fmt.Errorf("apply-common: format must be \"json\" or a JSON schema")
+		}
+	}
+
+	// Think is a flag for most models and a reasoning effort for GPT models.
+	switch v := think.(type) {
+	case bool:
+		d["enable_thinking"] = v
+
+	case string:
+		d["reasoning_effort"] = v
+	}
+
+	options.apply(d)
+
+	return nil
+}
+
+// =============================================================================
+
+// EmbedRequest represents the input for the embed endpoint.
+type EmbedRequest struct {
+	Model      string  `json:"model"`
+	Input      any     `json:"input"`
+	Truncate   *bool   `json:"truncate"`
+	Dimensions int     `json:"dimensions"`
+	Options    Options `json:"options"`
+}
+
+// Decode implements the decoder interface.
+func (app *EmbedRequest) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the request is usable.
+func (app EmbedRequest) Validate() error {
+	if app.Model == "" {
+		return fmt.Errorf("validate: missing model field")
+	}
+
+	if app.Input == nil {
+		return fmt.Errorf("validate: missing input field")
+	}
+
+	return nil
+}
+
+func (app EmbedRequest) toModelD() model.D {
+	// Ollama truncates the input to the context window by default.
+	truncate := true
+	if app.Truncate != nil {
+		truncate = *app.Truncate
+	}
+
+	d := model.D{
+		"input":    app.Input,
+		"truncate": truncate,
+	}
+
+	if app.Dimensions > 0 {
+		d["dimensions"] = float64(app.Dimensions)
+	}
+
+	return d
+}
+
+// EmbedResponse represents the output for the embed endpoint.
+type EmbedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	TotalDuration   int64       `json:"total_duration"`
+	LoadDuration    int64       `json:"load_duration"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+}
+
+// Encode implements the encoder interface.
+func (app EmbedResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toEmbedResponse(modelID string, resp model.EmbedReponse, start time.Time) EmbedResponse {
+	embeddings := make([][]float32, len(resp.Data))
+	for _, data := range resp.Data {
+		if data.Index >= 0 && data.Index < len(embeddings) {
+			embeddings[data.Index] = data.Embedding
+		}
+	}
+
+	return EmbedResponse{
+		Model:           modelID,
+		Embeddings:      embeddings,
+		TotalDuration:   time.Since(start).Nanoseconds(),
+		PromptEvalCount: resp.Usage.PromptTokens,
+	}
+}
+
+// =============================================================================
+
+// ModelDetails provides the format and family of a model.
+type ModelDetails struct {
+	Format   string   `json:"format"`
+	Family   string   `json:"family"`
+	Families []string `json:"families"`
+}
+
+func toModelDetails(family string) ModelDetails {
+	md := ModelDetails{
+		Format: "gguf",
+		Family: family,
+	}
+
+	if family != "" {
+		md.Families = []string{family}
+	}
+
+	return md
+}
+
+// TagsModel provides information about an installed model.
+type TagsModel struct {
+	Name       string       `json:"name"`
+	Model      string       `json:"model"`
+	ModifiedAt time.Time    `json:"modified_at"`
+	Size       int64        `json:"size"`
+	Digest     string       `json:"digest"`
+	Details    ModelDetails `json:"details"`
+}
+
+// TagsResponse contains the list of installed models.
+type TagsResponse struct {
+	Models []TagsModel `json:"models"`
+}
+
+// Encode implements the encoder interface.
+func (app TagsResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toTagsResponse(files []models.File, aliases []cache.Alias) TagsResponse {
+	resp := TagsResponse{
+		Models: []TagsModel{},
+	}
+
+	for _, file := range files {
+		resp.Models = append(resp.Models, TagsModel{
+			Name:       file.ID,
+			Model:      file.ID,
+			ModifiedAt: file.Modified,
+			Size:       file.Size,
+			Details:    toModelDetails(file.ModelFamily),
+		})
+	}
+
+	// Aliases are only listed when the model they refer to is installed.
+	for _, alias := range aliases {
+		for _, file := range files {
+			if !strings.EqualFold(file.ID, alias.ModelID) {
+				continue
+			}
+
+			resp.Models = append(resp.Models, TagsModel{
+				Name:       alias.Name,
+				Model:      alias.Name,
+				ModifiedAt: file.Modified,
+				Size:       file.Size,
+				Details:    toModelDetails(file.ModelFamily),
+			})
+			break
+		}
+	}
+
+	return resp
+}
+
+// =============================================================================
+
+// ShowRequest represents the input for the show endpoint.
+type ShowRequest struct {
+	Model string `json:"model"`
+}
+
+// Decode implements the decoder interface.
+func (app *ShowRequest) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the request is usable.
+func (app ShowRequest) Validate() error {
+	if app.Model == "" {
+		return fmt.Errorf("validate: missing model field")
+	}
+
+	return nil
+}
+
+// ShowResponse provides information about a model.
+type ShowResponse struct {
+	Template     string            `json:"template"`
+	Details      ModelDetails      `json:"details"`
+	ModelInfo    map[string]string `json:"model_info"`
+	Capabilities []string          `json:"capabilities"`
+	ModifiedAt   time.Time         `json:"modified_at"`
+}
+
+// Encode implements the encoder interface.
+func (app ShowResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toShowResponse(mi models.Info, info model.ModelInfo) ShowResponse {
+	var capabilities []string
+
+	switch {
+	case info.IsEmbedModel:
+		capabilities = append(capabilities, "embedding")
+
+	case info.IsRerankModel:
+
+	default:
+		capabilities = append(capabilities, "completion")
+		if info.HasProjection {
+			capabilities = append(capabilities, "vision")
+		}
+	}
+
+	return ShowResponse{
+		Template:     info.Template.Script,
+		Details:      toModelDetails(info.Metadata["general.architecture"]),
+		ModelInfo:    info.Metadata,
+		Capabilities: capabilities,
+		ModifiedAt:   time.UnixMilli(mi.Created),
+	}
+}
+
+// =============================================================================
+
+// PSModel provides information about a model that is loaded.
+type PSModel struct {
+	Name      string       `json:"name"`
+	Model     string       `json:"model"`
+	Size      int64        `json:"size"`
+	Digest    string       `json:"digest"`
+	Details   ModelDetails `json:"details"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// PSResponse contains the list of models that are loaded.
+type PSResponse struct {
+	Models []PSModel `json:"models"`
+}
+
+// Encode implements the encoder interface.
+func (app PSResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toPSResponse(details []cache.ModelDetail) PSResponse {
+	resp := PSResponse{
+		Models: []PSModel{},
+	}
+
+	for _, md := range details {
+		resp.Models = append(resp.Models, PSModel{
+			Name:      md.ID,
+			Model:     md.ID,
+			Size:      md.Size,
+			Details:   toModelDetails(md.ModelFamily),
+			ExpiresAt: md.ExpiresAt,
+		})
+	}
+
+	return resp
+}
+
+// =============================================================================
+
+// PullRequest represents the input for the pull endpoint.
+type PullRequest struct {
+	Model  string `json:"model"`
+	Stream *bool  `json:"stream"`
+}
+
+// Decode implements the decoder interface.
+func (app *PullRequest) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the request is usable.
+func (app PullRequest) Validate() error {
+	if app.Model == "" {
+		return fmt.Errorf("validate: missing model field")
+	}
+
+	return nil
+}
+
+// StatusResponse reports the progress of a pull.
+type StatusResponse struct {
+	Status string `json:"status"`
+}
+
+// Encode implements the encoder interface.
+func (app StatusResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// ErrorResponse reports an error once a stream has started.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Encode implements the encoder interface.
+func (app ErrorResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// =============================================================================
+
+// modelName removes the default tag Ollama clients add to a model name.
+func modelName(name string) string {
+	return strings.TrimSuffix(name, ":latest")
+}
+
+// stream reports if a request asked for a streamed response. Ollama streams
+// unless told otherwise.
+func stream(s *bool) bool {
+	return s == nil || *s
+}
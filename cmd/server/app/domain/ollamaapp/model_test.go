@@ -0,0 +1,210 @@
+package ollamaapp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+func TestOptionsApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		want    model.D
+	}{
+		{
+			name:    "mapped",
+			options: Options{"num_predict": float64(128), "temperature": 0.2, "top_k": float64(40), "stop": []any{"\n"}},
+			want:    model.D{"max_tokens": float64(128), "temperature": 0.2, "top_k": float64(40), "stop": []any{"\n"}},
+		},
+		{
+			name:    "unknown ignored",
+			options: Options{"num_ctx": float64(4096), "mirostat": float64(1), "seed": float64(7)},
+			want:    model.D{"seed": float64(7)},
+		},
+		{
+			name:    "unlimited tokens",
+			options: Options{"num_predict": float64(-1)},
+			want:    model.D{},
+		},
+		{
+			name:    "empty",
+			options: nil,
+			want:    model.D{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := model.D{}
+			tt.options.apply(d)
+
+			if !reflect.DeepEqual(d, tt.want) {
+				t.Errorf("apply() = %v, want %v", d, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatRequestToModelD(t *testing.T) {
+	var req ChatRequest
+	err := req.Decode([]byte(`{
+		"model": "qwen3",
+		"messages": [
+			{"role": "user", "content": "What is in this picture?", "images": ["aGVsbG8="]},
+			{"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "get_weather", "arguments": {"city": "Paris"}}}]},
+			{"role": "tool", "content": "sunny", "tool_name": "get_weather"}
+		],
+		"format": "json",
+		"think": true,
+		"options": {"num_predict": 64}
+	}`))
+	if err != nil {
+		t.Fatalf("decode: %s", err)
+	}
+
+	d, err := req.toModelD()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	msgs, ok := d["messages"].([]model.D)
+	if !ok || len(msgs) != 3 {
+		t.Fatalf("expected 3 messages, got %v", d["messages"])
+	}
+
+	content, ok := msgs[0]["content"].([]model.D)
+	if !ok || len(content) != 2 {
+		t.Fatalf("expected a text and an image part, got %v", msgs[0]["content"])
+	}
+
+	if content[0]["text"] != "What is in this picture?" {
+		t.Errorf("expected the text part to hold the content, got %v", content[0]["text"])
+	}
+
+	if url := content[1]["image_url"].(model.D)["url"]; url != "data:image/jpeg;base64,aGVsbG8=" {
+		t.Errorf("expected the image as a data url, got %v", url)
+	}
+
+	toolCalls, ok := msgs[1]["tool_calls"].([]model.D)
+	if !ok || len(toolCalls) != 1 || toolCalls[0]["function"].(model.D)["name"] != "get_weather" {
+		t.Errorf("expected the get_weather tool call, got %v", msgs[1]["tool_calls"])
+	}
+
+	if msgs[2]["name"] != "get_weather" {
+		t.Errorf("expected the tool name, got %v", msgs[2]["name"])
+	}
+
+	if format, ok := d["response_format"].(model.D); !ok || format["type"] != "json_object" {
+		t.Errorf("expected a json_object response format, got %v", d["response_format"])
+	}
+
+	if d["enable_thinking"] != true {
+		t.Errorf("expected thinking to be enabled, got %v", d["enable_thinking"])
+	}
+
+	if d["max_tokens"] != float64(64) {
+		t.Errorf("expected max_tokens 64, got %v", d["max_tokens"])
+	}
+}
+
+func TestGenerateRequestToModelD(t *testing.T) {
+	t.Run("chat", func(t *testing.T) {
+		req := GenerateRequest{Model: "qwen3", Prompt: "Hello", System: "Be brief.", Think: "low"}
+
+		d, err := req.toModelD()
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		msgs, ok := d["messages"].([]model.D)
+		if !ok || len(msgs) != 2 {
+			t.Fatalf("expected a system and a user message, got %v", d["messages"])
+		}
+
+		if msgs[0]["role"] != "system" || msgs[0]["content"] != "Be brief." {
+			t.Errorf("unexpected system message: %v", msgs[0])
+		}
+
+		if msgs[1]["role"] != "user" || msgs[1]["content"] != "Hello" {
+			t.Errorf("unexpected user message: %v", msgs[1])
+		}
+
+		if d["reasoning_effort"] != "low" {
+			t.Errorf("expected reasoning effort low, got %v", d["reasoning_effort"])
+		}
+
+		if _, exists := d["prompt"]; exists {
+			t.Error("expected no prompt for a chat request")
+		}
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		req := GenerateRequest{Model: "qwen3", Prompt: "func main() {", Suffix: "}", System: "ignored"}
+
+		d, err := req.toModelD()
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if d["prompt"] != "func main() {" || d["suffix"] != "}" {
+			t.Errorf("expected the prompt and suffix, got %v", d)
+		}
+
+		if _, exists := d["messages"]; exists {
+			t.Error("expected no messages for a raw request")
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		req := GenerateRequest{Model: "qwen3", Prompt: "Hello", Format: []byte(`"yaml"`)}
+
+		if _, err := req.toModelD(); err == nil {
+			t.Fatal("expected an error for an unsupported format")
+		}
+	})
+
+	t.Run("schema format", func(t *testing.T) {
+		req := GenerateRequest{Model: "qwen3", Prompt: "Hello", Format: []byte(`{"type": "object"}`)}
+
+		d, err := req.toModelD()
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		format, ok := d["response_format"].(model.D)
+		if !ok || format["type"] != "json_schema" {
+			t.Errorf("expected a json_schema response format, got %v", d["response_format"])
+		}
+	})
+}
+
+func TestEmbedRequestToModelD(t *testing.T) {
+	truncate := false
+
+	tests := []struct {
+		name string
+		req  EmbedRequest
+		want model.D
+	}{
+		{
+			name: "defaults",
+			req:  EmbedRequest{Model: "embed", Input: "hello"},
+			want: model.D{"input": "hello", "truncate": true},
+		},
+		{
+			name: "no truncate with dimensions",
+			req:  EmbedRequest{Model: "embed", Input: []any{"a", "b"}, Truncate: &truncate, Dimensions: 256},
+			want: model.D{"input": []any{"a", "b"}, "truncate": false, "dimensions": float64(256)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.toModelD(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("toModelD() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,551 @@
+// Package ollamaapp provides Ollama compatible api endpoints so tools built
+// for Ollama can use the models of the server.
+package ollamaapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
+	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
+	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
+	"github.com/ardanlabs/kronk/sdk/kronk"
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+	"github.com/ardanlabs/kronk/sdk/tools/catalog"
+	"github.com/ardanlabs/kronk/sdk/tools/models"
+)
+
+type app struct {
+	log        *logger.Logger
+	authClient *authclient.Client
+	cache      *cache.Cache
+	models     *models.Models
+	catalog    *catalog.Catalog
+}
+
+func newApp(cfg Config) *app {
+	return &app{
+		log:        cfg.Log,
+		authClient: cfg.AuthClient,
+		cache:      cfg.Cache,
+		models:     cfg.Models,
+		catalog:    cfg.Catalog,
+	}
+}
+
+func (a *app) chat(ctx context.Context, r *http.Request) web.Encoder {
+	start := time.Now()
+
+	var req ChatRequest
+	if err := web.Decode(r, &req); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	modelID := modelName(req.Model)
+
+	krn, err := a.cache.AquireModel(ctx, modelID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	// Ollama clients load a model by sending a request without messages.
+	if len(req.Messages) == 0 {
+		return ChatResponse{
+			Model:      req.Model,
+			CreatedAt:  createdAt(),
+			Message:    Message{Role: model.RoleAssistant},
+			Done:       true,
+			DoneReason: "load",
+		}
+	}
+
+	d, err := req.toModelD()
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a.log.Info(ctx, "ollama-chat", "request-input", d.LogSafe())
+
+	ctx, cancel := context.WithTimeout(ctx, 180*time.Minute)
+	defer cancel()
+
+	d = a.cache.ModelOverrides(modelID).Apply(d)
+
+	ch, err := krn.ChatStreaming(ctx, d)
+	if err != nil {
+		return toError(err)
+	}
+
+	toResp := func(c chunk) web.Encoder {
+		resp := ChatResponse{
+			Model:     req.Model,
+			CreatedAt: createdAt(),
+			Message: Message{
+				Role:      model.RoleAssistant,
+				Content:   c.content,
+				Thinking:  c.thinking,
+				ToolCalls: toToolCalls(c.toolCalls),
+			},
+			Done:       c.done,
+			DoneReason: c.doneReason,
+		}
+
+		if c.done {
+			resp.Metrics = toMetrics(c.usage, start)
+		}
+
+		return resp
+	}
+
+	return a.respond(ctx, r, "chat-completions", krn.ModelInfo().ID, start, stream(req.Stream), chatChunks(ch), toResp)
+}
+
+func (a *app) generate(ctx context.Context, r *http.Request) web.Encoder {
+	start := time.Now()
+
+	var req GenerateRequest
+	if err := web.Decode(r, &req); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	modelID := modelName(req.Model)
+
+	krn, err := a.cache.AquireModel(ctx, modelID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	// Ollama clients load a model by sending a request without a prompt.
+	if req.Prompt == "" && req.Suffix == "" && len(req.Images) == 0 {
+		return GenerateResponse{
+			Model:      req.Model,
+			CreatedAt:  createdAt(),
+			Done:       true,
+			DoneReason: "load",
+		}
+	}
+
+	d, err := req.toModelD()
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a.log.Info(ctx, "ollama-generate", "request-input", d.LogSafe())
+
+	ctx, cancel := context.WithTimeout(ctx, 180*time.Minute)
+	defer cancel()
+
+	d = a.cache.ModelOverrides(modelID).Apply(d)
+
+	var chunks <-chan chunk
+
+	switch req.completion() {
+	case true:
+		ch, err := krn.CompletionStreaming(ctx, d)
+		if err != nil {
+			return toError(err)
+		}
+		chunks = completionChunks(ch)
+
+	default:
+		ch, err := krn.ChatStreaming(ctx, d)
+		if err != nil {
+			return toError(err)
+		}
+		chunks = chatChunks(ch)
+	}
+
+	toResp := func(c chunk) web.Encoder {
+		resp := GenerateResponse{
+			Model:      req.Model,
+			CreatedAt:  createdAt(),
+			Response:   c.content,
+			Thinking:   c.thinking,
+			Done:       c.done,
+			DoneReason: c.doneReason,
+		}
+
+		if c.done {
+			resp.Metrics = toMetrics(c.usage, start)
+		}
+
+		return resp
+	}
+
+	return a.respond(ctx, r, "completions", krn.ModelInfo().ID, start, stream(req.Stream), chunks, toResp)
+}
+
+func (a *app) embed(ctx context.Context, r *http.Request) web.Encoder {
+	start := time.Now()
+
+	var req EmbedRequest
+	if err := web.Decode(r, &req); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	modelID := modelName(req.Model)
+
+	krn, err := a.cache.AquireModel(ctx, modelID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if !krn.ModelInfo().IsEmbedModel {
+		return errs.Errorf(errs.InvalidArgument, "model doesn't support embedding")
+	}
+
+	d := req.toModelD()
+
+	a.log.Info(ctx, "ollama-embed", "request-input", d.LogSafe())
+
+	ctx, cancel := context.WithTimeout(ctx, 180*time.Minute)
+	defer cancel()
+
+	resp, err := krn.Embeddings(ctx, d)
+	a.authClient.RecordRequestUsage(ctx, r, authclient.Usage{
+		Endpoint:     "embeddings",
+		Model:        krn.ModelInfo().ID,
+		PromptTokens: resp.Usage.PromptTokens,
+		Latency:      time.Since(start),
+	})
+
+	if err != nil {
+		return toError(err)
+	}
+
+	return toEmbedResponse(req.Model, resp, start)
+}
+
+func (a *app) tags(ctx context.Context, r *http.Request) web.Encoder {
+	files, err := a.models.RetrieveFiles()
+	if err != nil {
+		return errs.Errorf(errs.Internal, "unable to retrieve model list: %s", err)
+	}
+
+	return toTagsResponse(files, a.cache.Aliases())
+}
+
+func (a *app) show(ctx context.Context, r *http.Request) web.Encoder {
+	var req ShowRequest
+	if err := web.Decode(r, &req); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	// The information is read from disk so the model isn't loaded. The
+	// cache resolves an alias to its model.
+	info, err := a.cache.ModelInfo(modelName(req.Model))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	mi, err := a.models.RetrieveInfo(info.ID)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	return toShowResponse(mi, info)
+}
+
+func (a *app) ps(ctx context.Context, r *http.Request) web.Encoder {
+	details, err := a.cache.ModelStatus()
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	return toPSResponse(details)
+}
+
+func (a *app) pull(ctx context.Context, r *http.Request) web.Encoder {
+	var req PullRequest
+	if err := web.Decode(r, &req); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	modelID := modelName(req.Model)
+
+	catModel, err := a.catalog.RetrieveModelDetails(modelID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if catModel.GatedModel {
+		if os.Getenv("KRONK_HF_TOKEN") == "" {
+			return errs.Errorf(errs.FailedPrecondition, "gated model requires KRONK_HF_TOKEN to be set with HF token")
+		}
+	}
+
+	a.log.Info(ctx, "ollama-pull", "model", modelID)
+
+	// -------------------------------------------------------------------------
+
+	if !stream(req.Stream) {
+		if _, err := a.models.DownloadShards(ctx, a.log.Info, catModel.Files.ToModelURLS(), catModel.Files.Proj.URL); err != nil {
+			return errs.Errorf(errs.Internal, "unable to install model: %s", err)
+		}
+
+		return StatusResponse{
+			Status: "success",
+		}
+	}
+
+	// -------------------------------------------------------------------------
+
+	sw, err := newStreamWriter(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	logger := func(ctx context.Context, msg string, args ...any) {
+		var sb strings.Builder
+		for i := 0; i < len(args); i += 2 {
+			if i+1 < len(args) {
+				sb.WriteString(fmt.Sprintf(" %v[%v]", args[i], args[i+1]))
+			}
+		}
+
+		status := msg + sb.String()
+
+		a.log.Info(ctx, "ollama-pull", "info", status)
+		sw.write(StatusResponse{Status: status})
+	}
+
+	if _, err := a.models.DownloadShards(ctx, logger, catModel.Files.ToModelURLS(), catModel.Files.Proj.URL); err != nil {
+		a.log.Info(ctx, "ollama-pull", "ERROR", err)
+		sw.write(ErrorResponse{Error: fmt.Sprintf("unable to install model: %s", err)})
+
+		return web.NewNoResponse()
+	}
+
+	sw.write(StatusResponse{Status: "success"})
+
+	return web.NewNoResponse()
+}
+
+// =============================================================================
+
+// respond sends the chunks of a chat or generate request. A stream sends
+// every chunk as a line of JSON, otherwise the chunks are put together into a
+// single response.
+func (a *app) respond(ctx context.Context, r *http.Request, endpoint string, modelID string, start time.Time, streamResp bool, chunks <-chan chunk, toResp func(chunk) web.Encoder) web.Encoder {
+	var sw *streamWriter
+	if streamResp {
+		var err error
+		if sw, err = newStreamWriter(ctx); err != nil {
+			for range chunks {
+			}
+			return errs.New(errs.Internal, err)
+		}
+	}
+
+	// The chunks are drained even after an error so the model isn't left
+	// blocked on sending.
+	var all chunk
+	var errResp error
+
+	for c := range chunks {
+		if errResp != nil {
+			continue
+		}
+
+		if c.err != nil {
+			errResp = c.err
+			continue
+		}
+
+		if c.done {
+			all.usage = c.usage
+		}
+
+		switch streamResp {
+		case true:
+			if c.done || c.content != "" || c.thinking != "" {
+				sw.write(toResp(c))
+			}
+
+		default:
+			all.content += c.content
+			all.thinking += c.thinking
+			if c.done {
+				all.toolCalls = c.toolCalls
+				all.done = true
+				all.doneReason = c.doneReason
+			}
+		}
+	}
+
+	a.authClient.RecordRequestUsage(ctx, r, authclient.Usage{
+		Endpoint:         endpoint,
+		Model:            modelID,
+		PromptTokens:     all.usage.PromptTokens,
+		CompletionTokens: all.usage.CompletionTokens,
+		ReasoningTokens:  all.usage.ReasoningTokens,
+		Latency:          time.Since(start),
+	})
+
+	switch {
+	case errResp != nil && streamResp:
+		a.log.Info(ctx, endpoint, "ERROR", errResp)
+		sw.write(ErrorResponse{Error: errResp.Error()})
+		return web.NewNoResponse()
+
+	case errResp != nil:
+		return errs.New(errs.Internal, errResp)
+
+	case streamResp:
+		return web.NewNoResponse()
+	}
+
+	return toResp(all)
+}
+
+// =============================================================================
+
+// chunk is a piece of a chat or generate response in a form shared by the chat
+// and completion streams.
+type chunk struct {
+	content    string
+	thinking   string
+	toolCalls  []model.ResponseToolCall
+	done       bool
+	doneReason string
+	usage      model.Usage
+	err        error
+}
+
+// chatChunks converts the responses of a chat stream into chunks. The final
+// response of a chat stream repeats the content, so only its tool calls and
+// usage are kept.
+func chatChunks(ch <-chan model.ChatResponse) <-chan chunk {
+	chunks := make(chan chunk)
+
+	go func() {
+		defer close(chunks)
+
+		for resp := range ch {
+			if len(resp.Choice) == 0 {
+				continue
+			}
+
+			c := resp.Choice[0]
+
+			switch c.FinishReason {
+			case "":
+				if c.Delta != nil {
+					chunks <- chunk{content: c.Delta.Content, thinking: c.Delta.Reasoning}
+				}
+
+			case model.FinishReasonError:
+				var msg string
+				if c.Delta != nil {
+					msg = c.Delta.Content
+				}
+				chunks <- chunk{err: errors.New(msg)}
+
+			default:
+				doneReason := model.FinishReasonStop
+				if c.FinishReason == model.FinishReasonLength {
+					doneReason = model.FinishReasonLength
+				}
+
+				chunks <- chunk{
+					toolCalls:  c.Message.ToolCalls,
+					done:       true,
+					doneReason: doneReason,
+					usage:      resp.Usage,
+				}
+			}
+		}
+	}()
+
+	return chunks
+}
+
+// completionChunks converts the responses of a completion stream into chunks.
+func completionChunks(ch <-chan kronk.CompletionResponse) <-chan chunk {
+	chunks := make(chan chunk)
+
+	go func() {
+		defer close(chunks)
+
+		for resp := range ch {
+			if len(resp.Choices) == 0 {
+				continue
+			}
+
+			c := resp.Choices[0]
+
+			switch c.FinishReason {
+			case "":
+				chunks <- chunk{content: c.Text}
+
+			case model.FinishReasonError:
+				chunks <- chunk{err: errors.New(c.Text)}
+
+			default:
+				chunks <- chunk{
+					content:    c.Text,
+					done:       true,
+					doneReason: c.FinishReason,
+					usage:      resp.Usage,
+				}
+			}
+		}
+	}()
+
+	return chunks
+}
+
+// =============================================================================
+
+// streamWriter writes the lines of an NDJSON stream.
+type streamWriter struct {
+	w http.ResponseWriter
+	f http.Flusher
+}
+
+func newStreamWriter(ctx context.Context) (*streamWriter, error) {
+	w := web.GetWriter(ctx)
+
+	f, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("streaming not supported")
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	return &streamWriter{w: w, f: f}, nil
+}
+
+func (sw *streamWriter) write(resp web.Encoder) {
+	data, _, err := resp.Encode()
+	if err != nil {
+		data, _ = json.Marshal(ErrorResponse{Error: err.Error()})
+	}
+
+	sw.w.Write(append(data, '\n'))
+	sw.f.Flush()
+}
+
+// =============================================================================
+
+func createdAt() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+func toError(err error) *errs.Error {
+	if errors.Is(err, kronk.ErrQueueFull) {
+		return errs.New(errs.TooManyRequests, err)
+	}
+
+	return errs.New(errs.Internal, err)
+}
@@ -0,0 +1,43 @@
+package ollamaapp
+
+import (
+	"net/http"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/mid"
+	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
+	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
+	"github.com/ardanlabs/kronk/sdk/tools/catalog"
+	"github.com/ardanlabs/kronk/sdk/tools/models"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	AuthClient *authclient.Client
+	Cache      *cache.Cache
+	Models     *models.Models
+	Catalog    *catalog.Catalog
+}
+
+// Routes adds specific routes for this group. The routes use the paths of the
+// Ollama api, which aren't versioned.
+func Routes(app *web.App, cfg Config) {
+	const group = ""
+
+	api := newApp(cfg)
+
+	auth := mid.Authenticate(cfg.AuthClient, false, "")
+	authChat := mid.Authenticate(cfg.AuthClient, false, "chat-completions")
+	authGenerate := mid.Authenticate(cfg.AuthClient, false, "completions")
+	authEmbed := mid.Authenticate(cfg.AuthClient, false, "embeddings")
+
+	app.HandlerFunc(http.MethodPost, group, "/api/chat", api.chat, authChat)
+	app.HandlerFunc(http.MethodPost, group, "/api/generate", api.generate, authGenerate)
+	app.HandlerFunc(http.MethodPost, group, "/api/embed", api.embed, authEmbed)
+	app.HandlerFunc(http.MethodGet, group, "/api/tags", api.tags, auth)
+	app.HandlerFunc(http.MethodPost, group, "/api/show", api.show, auth)
+	app.HandlerFunc(http.MethodGet, group, "/api/ps", api.ps, auth)
+	app.HandlerFunc(http.MethodPost, group, "/api/pull", api.pull, auth)
+}
@@ -0,0 +1,29 @@
+package respapp
+
+import (
+	"encoding/json"
+
+	"github.com/ardanlabs/kronk/sdk/kronk"
+)
+
+// Response represents a stored response.
+type Response kronk.ResponseResponse
+
+// Encode implements the encoder interface.
+func (app Response) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// DeleteResponse is returned when a stored response is deleted.
+type DeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+// Encode implements the encoder interface.
+func (app DeleteResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
@@ -4,29 +4,40 @@ package respapp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/mid"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/respstore"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
+	"github.com/ardanlabs/kronk/sdk/kronk"
 	"github.com/ardanlabs/kronk/sdk/kronk/model"
 )
 
 type app struct {
-	log   *logger.Logger
-	cache *cache.Cache
+	log        *logger.Logger
+	authClient *authclient.Client
+	cache      *cache.Cache
+	store      *respstore.Store
 }
 
 func newApp(cfg Config) *app {
 	return &app{
-		log:   cfg.Log,
-		cache: cfg.Cache,
+		log:        cfg.Log,
+		authClient: cfg.AuthClient,
+		cache:      cfg.Cache,
+		store:      cfg.Store,
 	}
 }
 
 func (a *app) responses(ctx context.Context, r *http.Request) web.Encoder {
+	start := time.Now()
+
 	var req model.D
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return errs.New(errs.InvalidArgument, err)
@@ -54,9 +65,105 @@ func (a *app) responses(ctx context.Context, r *http.Request) web.Encoder {
 
 	d := model.MapToModelD(req)
 
-	if _, err := krn.ResponseStreamingHTTP(ctx, web.GetWriter(ctx), d); err != nil {
+	// The input of the request is stored with the response, and the
+	// conversation of the previous response is placed in front of it.
+	subject := mid.GetSubject(ctx)
+	prevID, _ := d["previous_response_id"].(string)
+
+	input := kronk.ResponseInputMessages(d["input"])
+	if msgs, ok := d["messages"].([]model.D); ok {
+		input = msgs
+	}
+
+	messages := input
+	if prevID != "" {
+		history, err := a.store.Messages(ctx, subject, prevID)
+		if err != nil {
+			if errors.Is(err, respstore.ErrNotFound) {
+				return errs.Errorf(errs.NotFound, "previous response %q not found", prevID)
+			}
+			return errs.New(errs.Internal, err)
+		}
+
+		messages = append(history, input...)
+	}
+
+	// The overrides are applied once to the whole conversation, so the
+	// system prompt of the overrides is never stored with the input.
+	if _, exists := d["input"]; exists || prevID != "" {
+		d["messages"] = messages
+		delete(d, "input")
+	}
+
+	d = a.cache.ModelOverrides(modelID).Apply(d)
+
+	resp, err := krn.ResponseStreamingHTTP(ctx, web.GetWriter(ctx), d)
+	a.authClient.RecordRequestUsage(ctx, r, authclient.Usage{
+		Endpoint:         "responses",
+		Model:            krn.ModelInfo().ID,
+		PromptTokens:     resp.Usage.InputTokens,
+		CompletionTokens: resp.Usage.OutputTokens,
+		ReasoningTokens:  resp.Usage.OutputTokenDetail.ReasoningTokens,
+		Latency:          time.Since(start),
+	})
+
+	if err != nil {
+		// The error was sent as an event once the stream started.
+		if errors.Is(err, kronk.ErrStreamStarted) {
+			a.log.Error(ctx, "responses", "stream", err)
+			return web.NewNoResponse()
+		}
+
+		if errors.Is(err, kronk.ErrQueueFull) {
+			return errs.New(errs.TooManyRequests, err)
+		}
 		return errs.New(errs.Internal, err)
 	}
 
+	if store, ok := d["store"].(bool); (!ok || store) && resp.Status == "completed" {
+		entry := respstore.Entry{
+			ID:             resp.ID,
+			Subject:        subject,
+			PrevResponseID: prevID,
+			Input:          input,
+			Response:       resp,
+		}
+
+		if err := a.store.Save(ctx, entry); err != nil {
+			a.log.Error(ctx, "responses", "store", err)
+		}
+	}
+
 	return web.NewNoResponse()
 }
+
+func (a *app) getResponse(ctx context.Context, r *http.Request) web.Encoder {
+	id := web.Param(r, "id")
+
+	entry, err := a.store.Get(ctx, mid.GetSubject(ctx), id)
+	if err != nil {
+		if errors.Is(err, respstore.ErrNotFound) {
+			return errs.Errorf(errs.NotFound, "response %q not found", id)
+		}
+		return errs.New(errs.Internal, err)
+	}
+
+	return Response(entry.Response)
+}
+
+func (a *app) deleteResponse(ctx context.Context, r *http.Request) web.Encoder {
+	id := web.Param(r, "id")
+
+	if err := a.store.Delete(ctx, mid.GetSubject(ctx), id); err != nil {
+		if errors.Is(err, respstore.ErrNotFound) {
+			return errs.Errorf(errs.NotFound, "response %q not found", id)
+		}
+		return errs.New(errs.Internal, err)
+	}
+
+	return DeleteResponse{
+		ID:      id,
+		Object:  "response",
+		Deleted: true,
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/mid"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/respstore"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
 )
@@ -15,6 +16,7 @@ type Config struct {
 	Log        *logger.Logger
 	AuthClient *authclient.Client
 	Cache      *cache.Cache
+	Store      *respstore.Store
 }
 
 // Routes adds specific routes for this group.
@@ -26,4 +28,6 @@ func Routes(app *web.App, cfg Config) {
 	auth := mid.Authenticate(cfg.AuthClient, false, "responses")
 
 	app.HandlerFunc(http.MethodPost, version, "/responses", api.responses, auth)
+	app.HandlerFunc(http.MethodGet, version, "/responses/{id}", api.getResponse, auth)
+	app.HandlerFunc(http.MethodDelete, version, "/responses/{id}", api.deleteResponse, auth)
 }
@@ -3,6 +3,7 @@ package toolapp
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
@@ -63,6 +64,7 @@ type ListModelDetail struct {
 	Size        int64     `json:"size"`
 	Modified    time.Time `json:"modified"`
 	Validated   bool      `json:"validated"`
+	AliasFor    string    `json:"alias_for,omitempty"`
 }
 
 // ListModelInfoResponse contains the list of models loaded in the system.
@@ -77,7 +79,7 @@ func (app ListModelInfoResponse) Encode() ([]byte, string, error) {
 	return data, "application/json", err
 }
 
-func toListModelsInfo(models []models.File) ListModelInfoResponse {
+func toListModelsInfo(models []models.File, aliases []cache.Alias) ListModelInfoResponse {
 	list := ListModelInfoResponse{
 		Object: "list",
 	}
@@ -95,11 +97,47 @@ func toListModelsInfo(models []models.File) ListModelInfoResponse {
 		})
 	}
 
+	// Aliases are only listed when the model they refer to is installed.
+	for _, alias := range aliases {
+		for _, model := range models {
+			if !strings.EqualFold(model.ID, alias.ModelID) {
+				continue
+			}
+
+			list.Data = append(list.Data, ListModelDetail{
+				ID:          alias.Name,
+				Object:      "model",
+				Created:     model.Modified.UnixMilli(),
+				OwnedBy:     model.OwnedBy,
+				ModelFamily: model.ModelFamily,
+				Size:        model.Size,
+				Modified:    model.Modified,
+				Validated:   model.Validated,
+				AliasFor:    model.ID,
+			})
+			break
+		}
+	}
+
 	return list
 }
 
 // =============================================================================
 
+// ReloadModelConfigResponse returns the models that are loaded again because
+// their settings changed.
+type ReloadModelConfigResponse struct {
+	Reloaded []string `json:"reloaded"`
+}
+
+// Encode implements the encoder interface.
+func (app ReloadModelConfigResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// =============================================================================
+
 // PullRequest represents the input for the pull command.
 type PullRequest struct {
 	ModelURL string `json:"model_url"`
@@ -194,6 +232,7 @@ type ModelDetail struct {
 	Size          int64     `json:"size"`
 	ExpiresAt     time.Time `json:"expires_at"`
 	ActiveStreams int       `json:"active_streams"`
+	Pinned        bool      `json:"pinned"`
 }
 
 // ModelDetailsResponse is a collection of model detail.
@@ -216,6 +255,7 @@ func toModelDetails(models []cache.ModelDetail) ModelDetailsResponse {
 			Size:          model.Size,
 			ExpiresAt:     model.ExpiresAt,
 			ActiveStreams: model.ActiveStreams,
+			Pinned:        model.Pinned,
 		}
 	}
 
@@ -366,10 +406,60 @@ func toKeys(keys []authclient.Key) KeysResponse {
 
 // =============================================================================
 
+// UsageRecordResponse represents the usage of a subject, model and endpoint
+// on a day.
+type UsageRecordResponse struct {
+	Day              string `json:"day"`
+	Subject          string `json:"subject"`
+	Model            string `json:"model"`
+	Endpoint         string `json:"endpoint"`
+	Requests         int64  `json:"requests"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+	ReasoningTokens  int64  `json:"reasoning_tokens"`
+	TotalTokens      int64  `json:"total_tokens"`
+	LatencyMS        int64  `json:"latency_ms"`
+}
+
+// UsageResponse is a collection of usage records.
+type UsageResponse []UsageRecordResponse
+
+// Encode implements the encoder interface.
+func (app UsageResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toUsage(records []authclient.UsageRecord) UsageResponse {
+	usage := make(UsageResponse, len(records))
+
+	for i, rec := range records {
+		usage[i] = UsageRecordResponse{
+			Day:              rec.Day,
+			Subject:          rec.Subject,
+			Model:            rec.Model,
+			Endpoint:         rec.Endpoint,
+			Requests:         rec.Requests,
+			PromptTokens:     rec.PromptTokens,
+			CompletionTokens: rec.CompletionTokens,
+			ReasoningTokens:  rec.ReasoningTokens,
+			TotalTokens:      rec.PromptTokens + rec.CompletionTokens,
+			LatencyMS:        rec.LatencyMS,
+		}
+	}
+
+	return usage
+}
+
+// =============================================================================
+
 // RateLimit defines the rate limit configuration for an endpoint.
 type RateLimit struct {
-	Limit  int    `json:"limit"`
-	Window string `json:"window"`
+	Limit            int    `json:"limit"`
+	Window           string `json:"window"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
 }
 
 // TokenRequest represents the input for the create token command.
@@ -377,6 +467,7 @@ type TokenRequest struct {
 	Admin     bool                 `json:"admin"`
 	Endpoints map[string]RateLimit `json:"endpoints"`
 	Duration  time.Duration        `json:"duration"`
+	Priority  string               `json:"priority,omitempty"`
 }
 
 // Decode implements the decoder interface.
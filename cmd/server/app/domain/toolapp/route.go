@@ -42,6 +42,7 @@ func Routes(app *web.App, cfg Config) {
 	app.HandlerFunc(http.MethodGet, version, "/models/{model}", api.showModel, auth)
 	app.HandlerFunc(http.MethodGet, version, "/models/ps", api.modelPS, auth)
 	app.HandlerFunc(http.MethodPost, version, "/models/index", api.indexModels, authAdmin)
+	app.HandlerFunc(http.MethodPost, version, "/models/config/reload", api.reloadModelConfig, authAdmin)
 	app.HandlerFunc(http.MethodPost, version, "/models/pull", api.pullModels, authAdmin)
 	app.HandlerFunc(http.MethodDelete, version, "/models/{model}", api.removeModel, authAdmin)
 
@@ -50,6 +51,8 @@ func Routes(app *web.App, cfg Config) {
 	app.HandlerFunc(http.MethodGet, version, "/catalog/{model}", api.showCatalogModel, auth)
 	app.HandlerFunc(http.MethodPost, version, "/catalog/pull/{model}", api.pullCatalog, auth)
 
+	app.HandlerFunc(http.MethodGet, version, "/usage", api.listUsage, authAdmin)
+
 	// Auth is handled by the auth service for these calls.
 	app.HandlerFunc(http.MethodPost, version, "/security/token/create", api.createToken)
 	app.HandlerFunc(http.MethodGet, version, "/security/keys", api.listKeys)
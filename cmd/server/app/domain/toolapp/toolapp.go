@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ardanlabs/kronk/cmd/server/app/domain/authapp"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
@@ -112,13 +113,24 @@ func (a *app) indexModels(ctx context.Context, r *http.Request) web.Encoder {
 	return nil
 }
 
+func (a *app) reloadModelConfig(ctx context.Context, r *http.Request) web.Encoder {
+	reloaded, err := a.cache.ReloadModelConfig(ctx)
+	if err != nil {
+		return errs.Errorf(errs.FailedPrecondition, "unable to reload model config: %s", err)
+	}
+
+	return ReloadModelConfigResponse{
+		Reloaded: reloaded,
+	}
+}
+
 func (a *app) listModels(ctx context.Context, r *http.Request) web.Encoder {
 	models, err := a.models.RetrieveFiles()
 	if err != nil {
 		return errs.Errorf(errs.Internal, "unable to retrieve model list: %s", err)
 	}
 
-	return toListModelsInfo(models)
+	return toListModelsInfo(models, a.cache.Aliases())
 }
 
 func (a *app) pullModels(ctx context.Context, r *http.Request) web.Encoder {
@@ -350,12 +362,15 @@ func (a *app) createToken(ctx context.Context, r *http.Request) web.Encoder {
 	for name, rl := range req.Endpoints {
 		window := string(rl.Window)
 		endpoints[name] = authapp.RateLimit_builder{
-			Limit:  proto.Int32(int32(rl.Limit)),
-			Window: proto.String(window),
+			Limit:            proto.Int32(int32(rl.Limit)),
+			Window:           proto.String(window),
+			PromptTokens:     proto.Int64(int64(rl.PromptTokens)),
+			CompletionTokens: proto.Int64(int64(rl.CompletionTokens)),
+			TotalTokens:      proto.Int64(int64(rl.TotalTokens)),
 		}.Build()
 	}
 
-	resp, err := a.authClient.CreateToken(ctx, bearerToken, req.Admin, endpoints, req.Duration)
+	resp, err := a.authClient.CreateToken(ctx, bearerToken, req.Admin, req.Priority, endpoints, req.Duration)
 	if err != nil {
 		return errs.New(errs.Internal, err)
 	}
@@ -365,6 +380,41 @@ func (a *app) createToken(ctx context.Context, r *http.Request) web.Encoder {
 	}
 }
 
+// listUsage returns the usage ledger for a date range. The from and to query
+// parameters use the YYYY-MM-DD format and default to the current month. The
+// subject query parameter limits the records to a single subject.
+func (a *app) listUsage(ctx context.Context, r *http.Request) web.Encoder {
+	const dayFormat = "2006-01-02"
+
+	qs := r.URL.Query()
+	now := time.Now().UTC()
+
+	to := qs.Get("to")
+	if to == "" {
+		to = now.Format(dayFormat)
+	}
+
+	from := qs.Get("from")
+	if from == "" {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format(dayFormat)
+	}
+
+	for _, day := range []string{from, to} {
+		if _, err := time.Parse(dayFormat, day); err != nil {
+			return errs.Errorf(errs.InvalidArgument, "invalid day %q, expected YYYY-MM-DD", day)
+		}
+	}
+
+	bearerToken := r.Header.Get("Authorization")
+
+	resp, err := a.authClient.ListUsage(ctx, bearerToken, qs.Get("subject"), from, to)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	return toUsage(resp.Records)
+}
+
 func (a *app) addKey(ctx context.Context, r *http.Request) web.Encoder {
 	bearerToken := r.Header.Get("Authorization")
 
@@ -12,6 +12,7 @@ import (
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/mux"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/respstore"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/auth"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
@@ -200,6 +201,7 @@ func New(t *testing.T, testName string) *Test {
 		AuthClient: authClient,
 		Tracer:     tracer,
 		Cache:      cache,
+		RespStore:  respstore.New(respstore.NewMemory(time.Hour)),
 		Libs:       libs,
 		Models:     models,
 		Catalog:    ctlg,
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"time"
 
 	"github.com/ardanlabs/kronk/cmd/server/app/domain/authapp"
@@ -87,12 +88,15 @@ func (cln *Client) Authenticate(ctx context.Context, bearerToken string, admin b
 }
 
 // CreateToken calls the auth service to create a new token.
-func (cln *Client) CreateToken(ctx context.Context, bearerToken string, admin bool, endpoints map[string]*authapp.RateLimit, duration time.Duration) (CreateTokenResponse, error) {
+func (cln *Client) CreateToken(ctx context.Context, bearerToken string, admin bool, priority string, endpoints map[string]*authapp.RateLimit, duration time.Duration) (CreateTokenResponse, error) {
 	protoEndpoints := make(map[string]*authapp.RateLimit)
 	for name, rl := range endpoints {
 		protoEndpoints[name] = authapp.RateLimit_builder{
-			Limit:  proto.Int32(rl.GetLimit()),
-			Window: proto.String(rl.GetWindow()),
+			Limit:            proto.Int32(rl.GetLimit()),
+			Window:           proto.String(rl.GetWindow()),
+			PromptTokens:     proto.Int64(rl.GetPromptTokens()),
+			CompletionTokens: proto.Int64(rl.GetCompletionTokens()),
+			TotalTokens:      proto.Int64(rl.GetTotalTokens()),
 		}.Build()
 	}
 
@@ -100,6 +104,7 @@ func (cln *Client) CreateToken(ctx context.Context, bearerToken string, admin bo
 		Admin:     proto.Bool(admin),
 		Endpoints: protoEndpoints,
 		Duration:  proto.String(duration.String()),
+		Priority:  proto.String(priority),
 	}
 
 	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", bearerToken)
@@ -143,3 +148,52 @@ func (cln *Client) RemoveKey(ctx context.Context, bearerToken string, keyID stri
 	_, err := cln.grpc.RemoveKey(ctx, rkb.Build())
 	return err
 }
+
+// RecordUsage calls the auth service to record the usage of a request in the
+// usage ledger and against the token budgets of the bearer token.
+func (cln *Client) RecordUsage(ctx context.Context, bearerToken string, usage Usage) error {
+	rub := authapp.RecordUsageRequest_builder{
+		Endpoint:         proto.String(usage.Endpoint),
+		Model:            proto.String(usage.Model),
+		PromptTokens:     proto.Int64(int64(usage.PromptTokens)),
+		CompletionTokens: proto.Int64(int64(usage.CompletionTokens)),
+		ReasoningTokens:  proto.Int64(int64(usage.ReasoningTokens)),
+		LatencyMs:        proto.Int64(usage.Latency.Milliseconds()),
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", bearerToken)
+
+	_, err := cln.grpc.RecordUsage(ctx, rub.Build())
+	return err
+}
+
+// RecordRequestUsage records the usage of a request with the bearer token the
+// request was authenticated with. The usage is recorded even when the request
+// was canceled, and errors are logged since the response is already sent.
+func (cln *Client) RecordRequestUsage(ctx context.Context, r *http.Request, usage Usage) {
+	ctx = context.WithoutCancel(ctx)
+
+	if err := cln.RecordUsage(ctx, r.Header.Get("authorization"), usage); err != nil {
+		cln.log.Error(ctx, usage.Endpoint, "record-usage", err)
+	}
+}
+
+// ListUsage calls the auth service to list the usage ledger records between
+// the from and to days, which use the YYYY-MM-DD format. An empty subject
+// lists all subjects.
+func (cln *Client) ListUsage(ctx context.Context, bearerToken string, subject string, from string, to string) (ListUsageResponse, error) {
+	lub := authapp.ListUsageRequest_builder{
+		Subject: proto.String(subject),
+		From:    proto.String(from),
+		To:      proto.String(to),
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", bearerToken)
+
+	resp, err := cln.grpc.ListUsage(ctx, lub.Build())
+	if err != nil {
+		return ListUsageResponse{}, err
+	}
+
+	return toListUsageResponse(resp), nil
+}
@@ -1,16 +1,57 @@
 package authclient
 
-import "github.com/ardanlabs/kronk/cmd/server/app/domain/authapp"
+import (
+	"time"
 
-// AuthenticateReponse is the response for the auth service.
+	"github.com/ardanlabs/kronk/cmd/server/app/domain/authapp"
+)
+
+// Quota represents what is left of a rate limit in the current window. A
+// limit of 0 means the limit isn't set.
+type Quota struct {
+	Window                    string
+	Reset                     time.Time
+	Requests                  int64
+	RequestsRemaining         int64
+	PromptTokens              int64
+	PromptTokensRemaining     int64
+	CompletionTokens          int64
+	CompletionTokensRemaining int64
+	TotalTokens               int64
+	TotalTokensRemaining      int64
+}
+
+// AuthenticateReponse is the response for the auth service. The Quota is nil
+// when the endpoint isn't rate limited.
 type AuthenticateReponse struct {
-	Subject string
+	Subject  string
+	Priority string
+	Quota    *Quota
 }
 
 func toAuthenticateReponse(req *authapp.AuthenticateResponse) AuthenticateReponse {
-	return AuthenticateReponse{
-		Subject: req.GetSubject(),
+	ar := AuthenticateReponse{
+		Subject:  req.GetSubject(),
+		Priority: req.GetPriority(),
 	}
+
+	if req.HasQuota() {
+		q := req.GetQuota()
+		ar.Quota = &Quota{
+			Window:                    q.GetWindow(),
+			Reset:                     time.Unix(q.GetReset(), 0),
+			Requests:                  q.GetRequests(),
+			RequestsRemaining:         q.GetRequestsRemaining(),
+			PromptTokens:              q.GetPromptTokens(),
+			PromptTokensRemaining:     q.GetPromptTokensRemaining(),
+			CompletionTokens:          q.GetCompletionTokens(),
+			CompletionTokensRemaining: q.GetCompletionTokensRemaining(),
+			TotalTokens:               q.GetTotalTokens(),
+			TotalTokensRemaining:      q.GetTotalTokensRemaining(),
+		}
+	}
+
+	return ar
 }
 
 // CreateTokenResponse is the response for the auth service.
@@ -45,3 +86,50 @@ func toListKeysResponse(req *authapp.ListKeysResponse) ListKeysResponse {
 	}
 	return ListKeysResponse{Keys: keys}
 }
+
+// Usage represents the usage of a request to record. The model is the id of
+// the model that served the request, so an alias is recorded as its model.
+type Usage struct {
+	Endpoint         string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	ReasoningTokens  int
+	Latency          time.Duration
+}
+
+// UsageRecord represents the usage of a subject, model and endpoint on a day.
+type UsageRecord struct {
+	Day              string
+	Subject          string
+	Model            string
+	Endpoint         string
+	Requests         int64
+	PromptTokens     int64
+	CompletionTokens int64
+	ReasoningTokens  int64
+	LatencyMS        int64
+}
+
+// ListUsageResponse is the response for listing usage.
+type ListUsageResponse struct {
+	Records []UsageRecord
+}
+
+func toListUsageResponse(resp *authapp.ListUsageResponse) ListUsageResponse {
+	records := make([]UsageRecord, len(resp.GetRecords()))
+	for i, rec := range resp.GetRecords() {
+		records[i] = UsageRecord{
+			Day:              rec.GetDay(),
+			Subject:          rec.GetSubject(),
+			Model:            rec.GetModel(),
+			Endpoint:         rec.GetEndpoint(),
+			Requests:         rec.GetRequests(),
+			PromptTokens:     rec.GetPromptTokens(),
+			CompletionTokens: rec.GetCompletionTokens(),
+			ReasoningTokens:  rec.GetReasoningTokens(),
+			LatencyMS:        rec.GetLatencyMs(),
+		}
+	}
+	return ListUsageResponse{Records: records}
+}
@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kronk/sdk/kronk"
+	"github.com/ardanlabs/kronk/sdk/tools/templates"
+)
+
+func newBudgetCache(t *testing.T, maxMemoryBytes int64) *Cache {
+	t.Helper()
+
+	basePath := t.TempDir()
+
+	tmpls, err := templates.New(templates.WithBasePath(basePath))
+	if err != nil {
+		t.Fatalf("creating templates system: %s", err)
+	}
+
+	c, err := New(Config{
+		Log:            func(context.Context, string, ...any) {},
+		BasePath:       basePath,
+		Templates:      tmpls,
+		MaxMemoryBytes: maxMemoryBytes,
+	})
+	if err != nil {
+		t.Fatalf("creating cache: %s", err)
+	}
+	t.Cleanup(func() { c.Shutdown(context.Background()) })
+
+	return c
+}
+
+// addFootprint tracks a model that was loaded the specified time ago.
+func addFootprint(c *Cache, modelID string, bytes int64, age time.Duration) *kronk.Kronk {
+	krn := new(kronk.Kronk)
+
+	c.footprints[krn] = &footprint{
+		modelID:  modelID,
+		bytes:    bytes,
+		lastUsed: time.Now().Add(-age),
+		unloaded: make(chan struct{}),
+	}
+	c.usedBytes += bytes
+
+	return krn
+}
+
+// markActive makes the models report active streams for the test.
+func markActive(t *testing.T, active ...*kronk.Kronk) {
+	t.Helper()
+
+	orig := activeStreams
+	t.Cleanup(func() { activeStreams = orig })
+
+	activeStreams = func(krn *kronk.Kronk) int {
+		for _, a := range active {
+			if a == krn {
+				return 1
+			}
+		}
+		return 0
+	}
+}
+
+// waitEvicting waits until the reserve marked the models for eviction, which
+// it does before it waits for them to unload.
+func waitEvicting(c *Cache, krns ...*kronk.Kronk) {
+	for _, krn := range krns {
+		for {
+			c.mu.Lock()
+			evicting := c.footprints[krn].evicting
+			c.mu.Unlock()
+
+			if evicting {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestReserveEvictionOrder(t *testing.T) {
+	c := newBudgetCache(t, 100)
+
+	oldest := addFootprint(c, "oldest", 30, 3*time.Minute)
+	middle := addFootprint(c, "middle", 30, 2*time.Minute)
+	newest := addFootprint(c, "newest", 30, time.Minute)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- c.reserve(context.Background(), "model", 60) }()
+
+	// The two least recently used models are evicted, the reserve waits
+	// for them to unload.
+	waitEvicting(c, oldest, middle)
+	c.untrack(oldest)
+	c.untrack(middle)
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if _, exists := c.footprints[newest]; !exists || c.footprints[newest].evicting {
+		t.Error("expected the most recently used model to be kept")
+	}
+
+	if c.usedBytes != 90 {
+		t.Errorf("expected 90 used bytes, got %d", c.usedBytes)
+	}
+}
+
+func TestReserveSkipsPinnedAndActive(t *testing.T) {
+	c := newBudgetCache(t, 100)
+
+	pinned := addFootprint(c, "pinned", 30, 3*time.Minute)
+	c.footprints[pinned].pinned = true
+
+	active := addFootprint(c, "active", 30, 2*time.Minute)
+	markActive(t, active)
+
+	idle := addFootprint(c, "idle", 30, time.Minute)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- c.reserve(context.Background(), "model", 30) }()
+
+	waitEvicting(c, idle)
+	c.untrack(idle)
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	for _, krn := range []*kronk.Kronk{pinned, active} {
+		if fp := c.footprints[krn]; fp == nil || fp.evicting {
+			t.Errorf("expected model %v to be kept", fp)
+		}
+	}
+
+	// Only the pinned and active models are left, so nothing can be
+	// freed and the used bytes don't change.
+	if err := c.reserve(context.Background(), "model", 50); err == nil {
+		t.Fatal("expected an error when only pinned and active models can be evicted")
+	}
+
+	if c.usedBytes != 90 {
+		t.Errorf("expected 90 used bytes, got %d", c.usedBytes)
+	}
+}
+
+func TestReleaseAfterFailedLoad(t *testing.T) {
+	c := newBudgetCache(t, 100)
+
+	addFootprint(c, "loaded", 40, time.Minute)
+
+	if err := c.reserve(context.Background(), "model", 50); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if c.usedBytes != 90 {
+		t.Fatalf("expected 90 used bytes after the reserve, got %d", c.usedBytes)
+	}
+
+	// The load failed, so the reserved bytes are given back.
+	c.release(50)
+
+	if c.usedBytes != 40 {
+		t.Errorf("expected 40 used bytes after the release, got %d", c.usedBytes)
+	}
+}
+
+func TestReserveCanceledWhileUnloading(t *testing.T) {
+	c := newBudgetCache(t, 100)
+
+	evicted := addFootprint(c, "evicted", 60, 2*time.Minute)
+	addFootprint(c, "kept", 30, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- c.reserve(ctx, "model", 50) }()
+
+	// Cancel while the evicted model hasn't unloaded yet.
+	waitEvicting(c, evicted)
+	cancel()
+
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a canceled error, got: %v", err)
+	}
+
+	// The reserved bytes are given back and the evicted model no longer
+	// counts, before or after it unloads.
+	if c.usedBytes != 30 {
+		t.Errorf("expected 30 used bytes after the cancel, got %d", c.usedBytes)
+	}
+
+	c.untrack(evicted)
+
+	if c.usedBytes != 30 {
+		t.Errorf("expected 30 used bytes after the unload, got %d", c.usedBytes)
+	}
+}
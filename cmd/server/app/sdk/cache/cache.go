@@ -5,14 +5,19 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/ardanlabs/kronk/sdk/kronk"
 	"github.com/ardanlabs/kronk/sdk/kronk/model"
+	"github.com/ardanlabs/kronk/sdk/tools/defaults"
 	"github.com/ardanlabs/kronk/sdk/tools/models"
 	"github.com/ardanlabs/kronk/sdk/tools/templates"
 	"github.com/maypok86/otter/v2"
@@ -39,14 +44,30 @@ import (
 //
 // CacheTTL: Defines the time an existing model can live in the cache without
 // being used.
+//
+// MaxMemoryBytes: Defines the memory budget for the models in the cache. The
+// memory a model needs is estimated from its weights and KV cache before it's
+// loaded. When the model doesn't fit, the least recently used idle models are
+// unloaded to make room. No budget is applied if the value is 0. With a budget,
+// embedding requests can only use the pooling of the model, since another
+// pooling needs a context the estimate doesn't count.
+//
+// Preload: Defines the models to load when the server starts, in addition to
+// the models with preload set in the model config file.
+//
+// The session state of the models is saved in the sessions folder under the
+// BasePath.
 type Config struct {
 	Log                  model.Logger
 	BasePath             string
 	Templates            *templates.Templates
 	ModelsInCache        int
 	CacheTTL             time.Duration
+	MaxMemoryBytes       int64
 	IgnoreIntegrityCheck bool
 	ModelConfigFile      string
+	Preload              []string
+	SubjectQueueLimit    int
 }
 
 func validateConfig(cfg Config) (Config, error) {
@@ -89,6 +110,111 @@ type modelConfig struct {
 	OpOffload            *bool                    `yaml:"op-offload"`
 	NGpuLayers           *int32                   `yaml:"ngpu-layers"`
 	SplitMode            model.SplitMode          `yaml:"split-mode"`
+	DraftModel           string                   `yaml:"draft-model"`
+	DraftTokens          int                      `yaml:"draft-tokens"`
+	ToolParser           string                   `yaml:"tool-parser"`
+	Model                string                   `yaml:"model"`
+	Temperature          *float64                 `yaml:"temperature"`
+	SystemPrompt         string                   `yaml:"system-prompt"`
+	MaxTokens            int                      `yaml:"max-tokens"`
+	Preload              bool                     `yaml:"preload"`
+	Pinned               bool                     `yaml:"pinned"`
+}
+
+// loadSettings returns the settings used to load the model. A change to these
+// requires the model to be loaded again.
+func (mc modelConfig) loadSettings() loadSettings {
+	ls := loadSettings{
+		Device:               mc.Device,
+		ContextWindow:        mc.ContextWindow,
+		NBatch:               mc.NBatch,
+		NUBatch:              mc.NUBatch,
+		NThreads:             mc.NThreads,
+		NThreadsBatch:        mc.NThreadsBatch,
+		CacheTypeK:           mc.CacheTypeK,
+		CacheTypeV:           mc.CacheTypeV,
+		UseDirectIO:          mc.UseDirectIO,
+		FlashAttention:       mc.FlashAttention,
+		IgnoreIntegrityCheck: mc.IgnoreIntegrityCheck,
+		NSeqMax:              mc.NSeqMax,
+		SplitMode:            mc.SplitMode,
+		DraftModel:           strings.ToLower(mc.DraftModel),
+		DraftTokens:          mc.DraftTokens,
+		ToolParser:           mc.ToolParser,
+		Pinned:               mc.Pinned,
+		OffloadKQV:           "default",
+		OpOffload:            "default",
+		NGpuLayers:           "default",
+	}
+
+	if mc.OffloadKQV != nil {
+		ls.OffloadKQV = fmt.Sprint(*mc.OffloadKQV)
+	}
+
+	if mc.OpOffload != nil {
+		ls.OpOffload = fmt.Sprint(*mc.OpOffload)
+	}
+
+	if mc.NGpuLayers != nil {
+		ls.NGpuLayers = fmt.Sprint(*mc.NGpuLayers)
+	}
+
+	return ls
+}
+
+// loadSettings is a comparable form of the settings used to load a model.
+// The optional settings hold "default" when they aren't set.
+type loadSettings struct {
+	Device               string
+	ContextWindow        int
+	NBatch               int
+	NUBatch              int
+	NThreads             int
+	NThreadsBatch        int
+	CacheTypeK           model.GGMLType
+	CacheTypeV           model.GGMLType
+	UseDirectIO          bool
+	FlashAttention       model.FlashAttentionType
+	IgnoreIntegrityCheck bool
+	NSeqMax              int
+	SplitMode            model.SplitMode
+	DraftModel           string
+	DraftTokens          int
+	ToolParser           string
+	Pinned               bool
+	OffloadKQV           string
+	OpOffload            string
+	NGpuLayers           string
+}
+
+func (mc modelConfig) overrides() Overrides {
+	return Overrides{
+		Temperature:  mc.Temperature,
+		SystemPrompt: mc.SystemPrompt,
+		MaxTokens:    mc.MaxTokens,
+	}
+}
+
+// drainTimeout is how long a model removed by a reload of the model config
+// file waits for its active streams to end before it's unloaded.
+const drainTimeout = 180 * time.Minute
+
+// pinnedTTL is the time a pinned model can live in the cache, which is long
+// enough to never expire.
+const pinnedTTL = 100 * 365 * 24 * time.Hour
+
+// activeStreams returns the number of requests a model is serving. It's a
+// variable so the tests can mark models as in use.
+var activeStreams = (*kronk.Kronk).ActiveStreams
+
+// footprint tracks the memory used by a model in the cache.
+type footprint struct {
+	modelID  string
+	bytes    int64
+	lastUsed time.Time
+	pinned   bool
+	evicting bool
+	unloaded chan struct{}
 }
 
 // Cache manages a set of Kronk APIs for use. It maintains a cache of these
@@ -100,7 +226,33 @@ type Cache struct {
 	itemsInCache         atomic.Int32
 	models               *models.Models
 	ignoreIntegrityCheck bool
-	modelConfig          map[string]modelConfig
+	modelConfigFile      string
+	maxMemoryBytes       int64
+	subjectQueueLimit    int
+	sessionDir           string
+
+	// cfgMu protects the model config, which is replaced when the model
+	// config file is reloaded.
+	cfgMu       sync.RWMutex
+	modelConfig map[string]modelConfig
+	aliases     map[string]Alias
+
+	// mu protects the memory accounting. The used bytes include the models
+	// being loaded, but not the models being evicted.
+	mu         sync.Mutex
+	usedBytes  int64
+	footprints map[*kronk.Kronk]*footprint
+
+	// draining holds the models removed from the cache by a reload of the
+	// model config file. These are unloaded once their active streams end.
+	draining map[*kronk.Kronk]struct{}
+
+	// preload holds the models to load at startup. The cache isn't ready
+	// until these are loaded.
+	preload    []string
+	preloadMu  sync.Mutex
+	preloading bool
+	preloadErr error
 }
 
 // New constructs the manager for use.
@@ -116,8 +268,9 @@ func New(cfg Config) (*Cache, error) {
 	}
 
 	var mc map[string]modelConfig
+	var aliases map[string]Alias
 	if cfg.ModelConfigFile != "" {
-		mc, err = loadModelConfig(cfg.ModelConfigFile)
+		mc, aliases, err = loadModelConfig(cfg.ModelConfigFile, installed(models))
 		if err != nil {
 			return nil, fmt.Errorf("new: loading model config: %w", err)
 		}
@@ -128,13 +281,33 @@ func New(cfg Config) (*Cache, error) {
 		templates:            cfg.Templates,
 		models:               models,
 		ignoreIntegrityCheck: cfg.IgnoreIntegrityCheck,
+		modelConfigFile:      cfg.ModelConfigFile,
+		maxMemoryBytes:       cfg.MaxMemoryBytes,
+		subjectQueueLimit:    cfg.SubjectQueueLimit,
+		sessionDir:           filepath.Join(defaults.BaseDir(cfg.BasePath), "sessions"),
 		modelConfig:          mc,
+		aliases:              aliases,
+		footprints:           make(map[*kronk.Kronk]*footprint),
+		draining:             make(map[*kronk.Kronk]struct{}),
 	}
 
+	// Pinned models have no weight, so they don't count against the models
+	// in cache and are never evicted for size, and they never expire.
 	opt := otter.Options[string, *kronk.Kronk]{
-		MaximumSize:      cfg.ModelsInCache,
-		ExpiryCalculator: otter.ExpiryWriting[string, *kronk.Kronk](cfg.CacheTTL),
-		OnDeletion:       c.eviction,
+		MaximumWeight: uint64(cfg.ModelsInCache),
+		Weigher: func(modelID string, krn *kronk.Kronk) uint32 {
+			if c.pinned(modelID) {
+				return 0
+			}
+			return 1
+		},
+		ExpiryCalculator: otter.ExpiryWritingFunc(func(entry otter.Entry[string, *kronk.Kronk]) time.Duration {
+			if c.pinned(entry.Key) {
+				return pinnedTTL
+			}
+			return cfg.CacheTTL
+		}),
+		OnDeletion: c.eviction,
 	}
 
 	cache, err := otter.New(&opt)
@@ -144,9 +317,52 @@ func New(cfg Config) (*Cache, error) {
 
 	c.cache = cache
 
+	c.preload = preloadModels(cfg.Preload, mc)
+	c.preloading = len(c.preload) > 0
+
 	return &c, nil
 }
 
+// Preload loads the models that are configured to load at startup. The cache
+// reports it isn't ready until this is complete.
+func (c *Cache) Preload(ctx context.Context) error {
+	var errs []error
+	for _, modelID := range c.preload {
+		c.log(ctx, "kronk cache preload", "status", "loading", "model", modelID)
+
+		if _, err := c.AquireModel(ctx, modelID); err != nil {
+			errs = append(errs, fmt.Errorf("preload: model[%s]: %w", modelID, err))
+		}
+	}
+
+	err := errors.Join(errs...)
+
+	c.preloadMu.Lock()
+	defer c.preloadMu.Unlock()
+
+	c.preloading = false
+	c.preloadErr = err
+
+	return err
+}
+
+// Ready returns an error while the preload models are loading or when any of
+// them failed to load.
+func (c *Cache) Ready() error {
+	c.preloadMu.Lock()
+	defer c.preloadMu.Unlock()
+
+	if c.preloading {
+		return fmt.Errorf("ready: preloading models %v", c.preload)
+	}
+
+	if c.preloadErr != nil {
+		return fmt.Errorf("ready: %w", c.preloadErr)
+	}
+
+	return nil
+}
+
 // Shutdown releases all apis from the cache and performs a proper unloading.
 func (c *Cache) Shutdown(ctx context.Context) error {
 	if _, exists := ctx.Deadline(); !exists {
@@ -200,6 +416,7 @@ ids:
 					Size:          mi.Size,
 					ExpiresAt:     model.ExpiresAt(),
 					ActiveStreams: model.Value.ActiveStreams(),
+					Pinned:        c.pinned(model.Key),
 				})
 				continue ids
 			}
@@ -209,13 +426,149 @@ ids:
 	return ps, nil
 }
 
+// ReloadModelConfig reads the model config file again and replaces the model
+// config when the file is valid. The models in the cache whose settings
+// changed are removed from the cache, so the next request loads them with the
+// new settings. They are unloaded once their active streams end. The ids of
+// these models are returned.
+func (c *Cache) ReloadModelConfig(ctx context.Context) ([]string, error) {
+	if c.modelConfigFile == "" {
+		return nil, fmt.Errorf("reload-model-config: no model config file is configured")
+	}
+
+	mc, aliases, err := loadModelConfig(c.modelConfigFile, installed(c.models))
+	if err != nil {
+		return nil, fmt.Errorf("reload-model-config: %w", err)
+	}
+
+	c.cfgMu.Lock()
+	old := c.modelConfig
+	c.modelConfig = mc
+	c.aliases = aliases
+	c.cfgMu.Unlock()
+
+	var reloaded []string
+	for modelID, krn := range c.cache.All() {
+		if old[modelID].loadSettings() == mc[modelID].loadSettings() {
+			continue
+		}
+
+		c.mu.Lock()
+		c.draining[krn] = struct{}{}
+		c.mu.Unlock()
+
+		// The entry may have been replaced or removed since it was read.
+		if v, exists := c.cache.GetIfPresent(modelID); !exists || v != krn {
+			c.mu.Lock()
+			delete(c.draining, krn)
+			c.mu.Unlock()
+			continue
+		}
+
+		c.log(ctx, "kronk cache reload", "key", modelID, "active-streams", krn.ActiveStreams())
+
+		c.cache.Invalidate(modelID)
+		reloaded = append(reloaded, modelID)
+	}
+
+	c.log(ctx, "kronk cache reload", "status", "model config reloaded", "file", c.modelConfigFile, "reloaded", fmt.Sprintf("%v", reloaded))
+
+	return reloaded, nil
+}
+
+// Aliases returns the aliases defined in the model config file.
+func (c *Cache) Aliases() []Alias {
+	c.cfgMu.RLock()
+	defer c.cfgMu.RUnlock()
+
+	aliases := make([]Alias, 0, len(c.aliases))
+	for _, alias := range c.aliases {
+		aliases = append(aliases, alias)
+	}
+
+	slices.SortFunc(aliases, func(a, b Alias) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return aliases
+}
+
+// ModelOverrides returns the request parameter overrides the model config
+// file defines for the model id or alias.
+func (c *Cache) ModelOverrides(modelID string) Overrides {
+	modelID = strings.ToLower(modelID)
+
+	c.cfgMu.RLock()
+	defer c.cfgMu.RUnlock()
+
+	if alias, exists := c.aliases[modelID]; exists {
+		return alias.Overrides
+	}
+
+	return c.modelConfig[modelID].overrides()
+}
+
+// pinned reports if the model is pinned in the cache.
+func (c *Cache) pinned(modelID string) bool {
+	c.cfgMu.RLock()
+	defer c.cfgMu.RUnlock()
+
+	return c.modelConfig[modelID].Pinned
+}
+
+// resolve returns the model id an alias refers to. Any other model id is
+// returned as is.
+func (c *Cache) resolve(modelID string) string {
+	modelID = strings.ToLower(modelID)
+
+	c.cfgMu.RLock()
+	defer c.cfgMu.RUnlock()
+
+	if alias, exists := c.aliases[modelID]; exists {
+		return strings.ToLower(alias.ModelID)
+	}
+
+	return modelID
+}
+
+// ModelInfo provides the information for the specified model from the files
+// on disk without loading the model. The model can be specified by an alias
+// from the model config file.
+func (c *Cache) ModelInfo(modelID string) (model.ModelInfo, error) {
+	modelID = c.resolve(modelID)
+
+	fi, err := c.models.RetrievePath(modelID)
+	if err != nil {
+		return model.ModelInfo{}, fmt.Errorf("model-info: unable to retrieve path: %w", err)
+	}
+
+	c.cfgMu.RLock()
+	mc := c.modelConfig[modelID]
+	c.cfgMu.RUnlock()
+
+	cfg := model.Config{
+		ModelFiles: fi.ModelFiles,
+		ProjFile:   fi.ProjFile,
+		ToolParser: mc.ToolParser,
+	}
+
+	mi, err := model.ReadModelInfo(c.templates, cfg)
+	if err != nil {
+		return model.ModelInfo{}, fmt.Errorf("model-info: %w", err)
+	}
+
+	return mi, nil
+}
+
 // AquireModel will provide a kronk API for the specified model. If the model
-// is not in the cache, an API for the model will be created.
+// is not in the cache, an API for the model will be created. The model can be
+// specified by an alias from the model config file.
 func (c *Cache) AquireModel(ctx context.Context, modelID string) (*kronk.Kronk, error) {
-	modelID = strings.ToLower(modelID)
+	modelID = c.resolve(modelID)
 
 	krn, exists := c.cache.GetIfPresent(modelID)
 	if exists {
+		c.touch(krn)
 		return krn, nil
 	}
 
@@ -224,6 +577,7 @@ func (c *Cache) AquireModel(ctx context.Context, modelID string) (*kronk.Kronk,
 		return nil, fmt.Errorf("acquire-model: unable to retrieve path: %w", err)
 	}
 
+	c.cfgMu.RLock()
 	c.log(ctx, "model config lookup", "modelID", modelID, "available-keys", fmt.Sprintf("%v", func() []string {
 		keys := make([]string, 0, len(c.modelConfig))
 		for k := range c.modelConfig {
@@ -233,12 +587,23 @@ func (c *Cache) AquireModel(ctx context.Context, modelID string) (*kronk.Kronk,
 	}()))
 
 	mc, found := c.modelConfig[strings.ToLower(modelID)]
+	c.cfgMu.RUnlock()
+
 	c.log(ctx, "model config result", "found", found, "mc", fmt.Sprintf("%#v", mc))
 
 	if c.ignoreIntegrityCheck {
 		mc.IgnoreIntegrityCheck = true
 	}
 
+	var draftModelFiles []string
+	if mc.DraftModel != "" {
+		dfi, err := c.models.RetrievePath(mc.DraftModel)
+		if err != nil {
+			return nil, fmt.Errorf("acquire-model: unable to retrieve draft model path: %w", err)
+		}
+		draftModelFiles = dfi.ModelFiles
+	}
+
 	cfg := model.Config{
 		Log:                  c.log,
 		ModelFiles:           fi.ModelFiles,
@@ -259,21 +624,44 @@ func (c *Cache) AquireModel(ctx context.Context, modelID string) (*kronk.Kronk,
 		OpOffload:            mc.OpOffload,
 		NGpuLayers:           mc.NGpuLayers,
 		SplitMode:            mc.SplitMode,
+		SessionDir:           c.sessionDir,
+		DraftModelFiles:      draftModelFiles,
+		DraftTokens:          mc.DraftTokens,
+		ToolParser:           mc.ToolParser,
+		FixedPooling:         c.maxMemoryBytes > 0,
+	}
+
+	var memoryBytes int64
+	if c.maxMemoryBytes > 0 {
+		memoryBytes, err = model.EstimateMemory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("acquire-model: unable to estimate memory: %w", err)
+		}
+
+		if err := c.reserve(ctx, modelID, memoryBytes); err != nil {
+			return nil, fmt.Errorf("acquire-model: %w", err)
+		}
 	}
 
 	krn, err = kronk.New(cfg,
 		kronk.WithTemplateRetriever(c.templates),
 		kronk.WithContext(ctx),
+		kronk.WithSubjectQueueLimit(c.subjectQueueLimit),
 	)
 
 	if err != nil {
+		c.release(memoryBytes)
 		return nil, fmt.Errorf("acquire-model: unable to create inference model: %w", err)
 	}
 
+	if c.maxMemoryBytes > 0 {
+		c.track(modelID, krn, memoryBytes)
+	}
+
 	c.cache.Set(modelID, krn)
 	c.itemsInCache.Add(1)
 
-	totalEntries := len(krn.SystemInfo())*2 + (5 * 2)
+	totalEntries := len(krn.SystemInfo())*2 + (6 * 2)
 	info := make([]any, 0, totalEntries)
 	for k, v := range krn.SystemInfo() {
 		info = append(info, k)
@@ -290,6 +678,8 @@ func (c *Cache) AquireModel(ctx context.Context, modelID string) (*kronk.Kronk,
 	info = append(info, krn.ModelInfo().IsGPTModel)
 	info = append(info, "isEmbedModel")
 	info = append(info, krn.ModelInfo().IsEmbedModel)
+	info = append(info, "memoryBytes")
+	info = append(info, memoryBytes)
 
 	c.log(ctx, "acquire-model", info...)
 
@@ -297,7 +687,19 @@ func (c *Cache) AquireModel(ctx context.Context, modelID string) (*kronk.Kronk,
 }
 
 func (c *Cache) eviction(event otter.DeletionEvent[string, *kronk.Kronk]) {
-	const unloadTimeout = 5 * time.Second
+	unloadTimeout := 5 * time.Second
+
+	// A model removed by a reload waits for its active streams to end, which
+	// can take as long as a request is allowed to run.
+	c.mu.Lock()
+	_, draining := c.draining[event.Value]
+	delete(c.draining, event.Value)
+	c.mu.Unlock()
+
+	if draining {
+		unloadTimeout = drainTimeout
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), unloadTimeout)
 	defer cancel()
 
@@ -306,25 +708,239 @@ func (c *Cache) eviction(event otter.DeletionEvent[string, *kronk.Kronk]) {
 		c.log(ctx, "kronk cache eviction", "key", event.Key, "ERROR", err)
 	}
 
+	c.untrack(event.Value)
 	c.itemsInCache.Add(-1)
 }
 
-func loadModelConfig(modelConfigFile string) (map[string]modelConfig, error) {
+// =============================================================================
+// Memory budget
+
+// reserve makes room in the memory budget for a model by evicting the least
+// recently used idle models. It waits for the evicted models to unload so the
+// memory is free before the model is loaded.
+func (c *Cache) reserve(ctx context.Context, modelID string, bytes int64) error {
+	if bytes > c.maxMemoryBytes {
+		return fmt.Errorf("reserve: model[%s] needs an estimated %d bytes, more than the cache budget of %d bytes", modelID, bytes, c.maxMemoryBytes)
+	}
+
+	var unloading []chan struct{}
+
+	err := func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for c.usedBytes+bytes > c.maxMemoryBytes {
+			krn, fp := c.leastRecentlyUsedIdle()
+			if fp == nil {
+				return fmt.Errorf("reserve: model[%s] needs an estimated %d bytes and only %d of %d bytes can be freed, the other models are in use or pinned", modelID, bytes, c.maxMemoryBytes-c.usedBytes, c.maxMemoryBytes)
+			}
+
+			c.log(ctx, "kronk cache budget", "status", "evicting", "key", fp.modelID, "bytes", fp.bytes, "for", modelID)
+
+			fp.evicting = true
+			c.usedBytes -= fp.bytes
+			unloading = append(unloading, fp.unloaded)
+
+			// The entry may already be gone when it expired, in which
+			// case the eviction is in progress.
+			if v, exists := c.cache.GetIfPresent(fp.modelID); exists && v == krn {
+				c.cache.Invalidate(fp.modelID)
+			}
+		}
+
+		c.usedBytes += bytes
+
+		return nil
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range unloading {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			c.release(bytes)
+			return fmt.Errorf("reserve: waiting for models to unload: %w", ctx.Err())
+		}
+	}
+
+	return nil
+}
+
+// leastRecentlyUsedIdle returns the model with no active streams that was used
+// the longest time ago. Pinned models are never chosen.
+func (c *Cache) leastRecentlyUsedIdle() (*kronk.Kronk, *footprint) {
+	var lruKrn *kronk.Kronk
+	var lru *footprint
+
+	for krn, fp := range c.footprints {
+		if fp.evicting || fp.pinned || activeStreams(krn) > 0 {
+			continue
+		}
+
+		if lru == nil || fp.lastUsed.Before(lru.lastUsed) {
+			lruKrn, lru = krn, fp
+		}
+	}
+
+	return lruKrn, lru
+}
+
+// release returns reserved bytes when a model failed to load.
+func (c *Cache) release(bytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.usedBytes -= bytes
+}
+
+// track records the footprint of a loaded model. The bytes were added to the
+// used bytes when they were reserved.
+func (c *Cache) track(modelID string, krn *kronk.Kronk, bytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.footprints[krn] = &footprint{
+		modelID:  modelID,
+		bytes:    bytes,
+		lastUsed: time.Now(),
+		pinned:   c.pinned(modelID),
+		unloaded: make(chan struct{}),
+	}
+}
+
+// touch marks the model as used.
+func (c *Cache) touch(krn *kronk.Kronk) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if fp, exists := c.footprints[krn]; exists {
+		fp.lastUsed = time.Now()
+	}
+}
+
+// untrack removes the footprint of an unloaded model.
+func (c *Cache) untrack(krn *kronk.Kronk) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fp, exists := c.footprints[krn]
+	if !exists {
+		return
+	}
+
+	if !fp.evicting {
+		c.usedBytes -= fp.bytes
+	}
+
+	delete(c.footprints, krn)
+	close(fp.unloaded)
+}
+
+func loadModelConfig(modelConfigFile string, installed func(modelID string) bool) (map[string]modelConfig, map[string]Alias, error) {
 	data, err := os.ReadFile(modelConfigFile)
 	if err != nil {
-		return nil, fmt.Errorf("load-model-config: reading model config file: %w", err)
+		return nil, nil, fmt.Errorf("load-model-config: reading model config file: %w", err)
 	}
 
 	var configs map[string]modelConfig
 	if err := yaml.Unmarshal(data, &configs); err != nil {
-		return nil, fmt.Errorf("load-model-config: unmarshaling model config: %w", err)
+		return nil, nil, fmt.Errorf("load-model-config: unmarshaling model config: %w", err)
 	}
 
-	// Normalize keys to lowercase for case-insensitive lookup.
+	// Normalize keys to lowercase for case-insensitive lookup. Entries with
+	// a model field are aliases for that model and don't have load settings
+	// of their own.
 	normalized := make(map[string]modelConfig, len(configs))
+	aliasConfigs := make(map[string]modelConfig)
+	aliasNames := make(map[string]struct{})
 	for k, v := range configs {
-		normalized[strings.ToLower(k)] = v
+		switch v.Model {
+		case "":
+			normalized[strings.ToLower(k)] = v
+		default:
+			aliasConfigs[k] = v
+			aliasNames[strings.ToLower(k)] = struct{}{}
+		}
 	}
 
-	return normalized, nil
+	aliases := make(map[string]Alias, len(aliasConfigs))
+	for name, ac := range aliasConfigs {
+		if _, exists := aliasNames[strings.ToLower(ac.Model)]; exists {
+			return nil, nil, fmt.Errorf("load-model-config: alias[%s] refers to alias[%s], an alias must refer to a model", name, ac.Model)
+		}
+
+		// The model of the alias must be in the model config or installed.
+		if _, exists := normalized[strings.ToLower(ac.Model)]; !exists && !installed(ac.Model) {
+			return nil, nil, fmt.Errorf("load-model-config: alias[%s] refers to unknown model[%s]", name, ac.Model)
+		}
+
+		// The alias falls back to the overrides of the model for the
+		// parameters it doesn't set.
+		overrides := ac.overrides()
+		target := normalized[strings.ToLower(ac.Model)].overrides()
+
+		if overrides.Temperature == nil {
+			overrides.Temperature = target.Temperature
+		}
+
+		if overrides.SystemPrompt == "" {
+			overrides.SystemPrompt = target.SystemPrompt
+		}
+
+		if overrides.MaxTokens == 0 {
+			overrides.MaxTokens = target.MaxTokens
+		}
+
+		// Preloading and pinning an alias applies to its model.
+		if ac.Preload || ac.Pinned {
+			key := strings.ToLower(ac.Model)
+			mc := normalized[key]
+			mc.Preload = mc.Preload || ac.Preload
+			mc.Pinned = mc.Pinned || ac.Pinned
+			normalized[key] = mc
+		}
+
+		aliases[strings.ToLower(name)] = Alias{
+			Name:      name,
+			ModelID:   ac.Model,
+			Overrides: overrides,
+		}
+	}
+
+	return normalized, aliases, nil
+}
+
+// installed returns a function that reports if a model is installed locally.
+func installed(m *models.Models) func(modelID string) bool {
+	return func(modelID string) bool {
+		_, err := m.RetrievePath(modelID)
+		return err == nil
+	}
+}
+
+// preloadModels returns the models to load at startup, from the preload list
+// and the model config. Aliases in the list resolve to their model when
+// they're loaded.
+func preloadModels(preload []string, configs map[string]modelConfig) []string {
+	var models []string
+	for _, modelID := range preload {
+		if modelID = strings.TrimSpace(modelID); modelID != "" {
+			models = append(models, modelID)
+		}
+	}
+
+	for modelID, mc := range configs {
+		if mc.Preload {
+			models = append(models, modelID)
+		}
+	}
+
+	slices.SortFunc(models, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	return slices.CompactFunc(models, strings.EqualFold)
 }
@@ -5,6 +5,9 @@ import (
 	"context"
 	"io"
 	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -16,6 +19,7 @@ import (
 	"github.com/ardanlabs/kronk/sdk/tools/defaults"
 	"github.com/ardanlabs/kronk/sdk/tools/libs"
 	"github.com/ardanlabs/kronk/sdk/tools/models"
+	"github.com/ardanlabs/kronk/sdk/tools/templates"
 )
 
 var log model.Logger
@@ -28,6 +32,271 @@ func Test_Cache(t *testing.T) {
 	t.Run("eviction", eviction)
 }
 
+func Test_Aliases(t *testing.T) {
+	basePath := t.TempDir()
+
+	const modelConfig = `
+Qwen3-8B-Q8_0:
+  context-window: 32768
+  max-tokens: 2048
+default-chat:
+  model: Qwen3-8B-Q8_0
+  temperature: 0.2
+  system-prompt: You are a helpful assistant.
+gpt-4o-mini:
+  model: Qwen3-8B-Q8_0
+  max-tokens: 512
+`
+
+	modelConfigFile := filepath.Join(basePath, "model_config.yaml")
+	if err := os.WriteFile(modelConfigFile, []byte(modelConfig), 0644); err != nil {
+		t.Fatalf("writing model config: %s", err)
+	}
+
+	tmpls, err := templates.New(templates.WithBasePath(basePath))
+	if err != nil {
+		t.Fatalf("creating templates system: %s", err)
+	}
+
+	mgr, err := cache.New(cache.Config{
+		Log:             log,
+		BasePath:        basePath,
+		Templates:       tmpls,
+		ModelConfigFile: modelConfigFile,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer mgr.Shutdown(context.Background())
+
+	t.Run("list", func(t *testing.T) {
+		aliases := mgr.Aliases()
+		if len(aliases) != 2 {
+			t.Fatalf("expected 2 aliases, got %d", len(aliases))
+		}
+
+		if aliases[0].Name != "default-chat" || aliases[0].ModelID != "Qwen3-8B-Q8_0" {
+			t.Errorf("unexpected alias: %+v", aliases[0])
+		}
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		o := mgr.ModelOverrides("DEFAULT-CHAT")
+		if o.Temperature == nil || *o.Temperature != 0.2 {
+			t.Errorf("expected temperature 0.2, got %v", o.Temperature)
+		}
+
+		if o.MaxTokens != 2048 {
+			t.Errorf("expected the model's max tokens 2048, got %d", o.MaxTokens)
+		}
+
+		if o := mgr.ModelOverrides("gpt-4o-mini"); o.MaxTokens != 512 {
+			t.Errorf("expected the alias max tokens 512, got %d", o.MaxTokens)
+		}
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		d := model.D{
+			"max_tokens": float64(4096),
+			"messages": []model.D{
+				{"role": "system", "content": "Answer in French."},
+				{"role": "user", "content": "Hello"},
+			},
+		}
+
+		d = mgr.ModelOverrides("default-chat").Apply(d)
+
+		if d["temperature"] != 0.2 {
+			t.Errorf("expected temperature 0.2, got %v", d["temperature"])
+		}
+
+		if d["max_tokens"] != 2048 {
+			t.Errorf("expected max_tokens to be capped at 2048, got %v", d["max_tokens"])
+		}
+
+		messages := d["messages"].([]model.D)
+		if got, exp := messages[0]["content"], "You are a helpful assistant.\n\nAnswer in French."; got != exp {
+			t.Errorf("expected system prompt %q, got %q", exp, got)
+		}
+
+		d = mgr.ModelOverrides("default-chat").Apply(model.D{
+			"temperature": 0.9,
+			"max_tokens":  float64(100),
+			"messages":    []model.D{{"role": "user", "content": "Hello"}},
+		})
+
+		if d["temperature"] != 0.9 {
+			t.Errorf("expected the request temperature 0.9, got %v", d["temperature"])
+		}
+
+		if d["max_tokens"] != float64(100) {
+			t.Errorf("expected the request max_tokens 100, got %v", d["max_tokens"])
+		}
+
+		messages = d["messages"].([]model.D)
+		if len(messages) != 2 || messages[0]["role"] != "system" {
+			t.Errorf("expected a system message to be added, got %v", messages)
+		}
+	})
+
+	t.Run("alias of alias", func(t *testing.T) {
+		file := filepath.Join(basePath, "bad_config.yaml")
+		data := "a:\n  model: B\nb:\n  model: Qwen3-8B-Q8_0\nQwen3-8B-Q8_0:\n  context-window: 8192\n"
+		if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+			t.Fatalf("writing model config: %s", err)
+		}
+
+		_, err := cache.New(cache.Config{
+			Log:             log,
+			BasePath:        basePath,
+			Templates:       tmpls,
+			ModelConfigFile: file,
+		})
+		if err == nil {
+			t.Fatal("expected an error for an alias that refers to an alias")
+		}
+	})
+
+	t.Run("unknown model", func(t *testing.T) {
+		file := filepath.Join(basePath, "unknown_config.yaml")
+		data := "a:\n  model: missing-model\n"
+		if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+			t.Fatalf("writing model config: %s", err)
+		}
+
+		_, err := cache.New(cache.Config{
+			Log:             log,
+			BasePath:        basePath,
+			Templates:       tmpls,
+			ModelConfigFile: file,
+		})
+		if err == nil {
+			t.Fatal("expected an error for an alias that refers to an unknown model")
+		}
+	})
+}
+
+func Test_ReloadModelConfig(t *testing.T) {
+	basePath := t.TempDir()
+
+	modelConfigFile := filepath.Join(basePath, "model_config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(modelConfigFile, []byte(data), 0644); err != nil {
+			t.Fatalf("writing model config: %s", err)
+		}
+	}
+
+	const models = "Qwen3-8B-Q8_0:\n  context-window: 8192\nQwen3-0.6B-Q8_0:\n  context-window: 8192\n"
+
+	write(models + "default-chat:\n  model: Qwen3-8B-Q8_0\n")
+
+	tmpls, err := templates.New(templates.WithBasePath(basePath))
+	if err != nil {
+		t.Fatalf("creating templates system: %s", err)
+	}
+
+	mgr, err := cache.New(cache.Config{
+		Log:             func(context.Context, string, ...any) {},
+		BasePath:        basePath,
+		Templates:       tmpls,
+		ModelConfigFile: modelConfigFile,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer mgr.Shutdown(context.Background())
+
+	t.Run("valid", func(t *testing.T) {
+		write(models + "default-chat:\n  model: Qwen3-8B-Q8_0\nfast-chat:\n  model: Qwen3-0.6B-Q8_0\n  max-tokens: 256\n")
+
+		reloaded, err := mgr.ReloadModelConfig(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if len(reloaded) != 0 {
+			t.Errorf("expected no models to reload, got %v", reloaded)
+		}
+
+		if n := len(mgr.Aliases()); n != 2 {
+			t.Errorf("expected 2 aliases, got %d", n)
+		}
+
+		if o := mgr.ModelOverrides("fast-chat"); o.MaxTokens != 256 {
+			t.Errorf("expected max tokens 256, got %d", o.MaxTokens)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		write("default-chat: [")
+
+		if _, err := mgr.ReloadModelConfig(context.Background()); err == nil {
+			t.Fatal("expected an error for an invalid model config")
+		}
+
+		if n := len(mgr.Aliases()); n != 2 {
+			t.Errorf("expected the previous 2 aliases to be kept, got %d", n)
+		}
+	})
+}
+
+func Test_Preload(t *testing.T) {
+	basePath := t.TempDir()
+
+	const modelConfig = `
+Qwen3-8B-Q8_0:
+  preload: true
+  pinned: true
+Qwen3-0.6B-Q8_0:
+  context-window: 8192
+default-chat:
+  model: Qwen3-0.6B-Q8_0
+  preload: true
+`
+
+	modelConfigFile := filepath.Join(basePath, "model_config.yaml")
+	if err := os.WriteFile(modelConfigFile, []byte(modelConfig), 0644); err != nil {
+		t.Fatalf("writing model config: %s", err)
+	}
+
+	tmpls, err := templates.New(templates.WithBasePath(basePath))
+	if err != nil {
+		t.Fatalf("creating templates system: %s", err)
+	}
+
+	mgr, err := cache.New(cache.Config{
+		Log:             func(context.Context, string, ...any) {},
+		BasePath:        basePath,
+		Templates:       tmpls,
+		ModelConfigFile: modelConfigFile,
+		Preload:         []string{"missing-model"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer mgr.Shutdown(context.Background())
+
+	if err := mgr.Ready(); err == nil {
+		t.Fatal("expected the cache not to be ready before the preload")
+	}
+
+	// None of the models are installed, so the preload fails for each.
+	err = mgr.Preload(context.Background())
+	if err == nil {
+		t.Fatal("expected an error for the models that aren't installed")
+	}
+
+	for _, modelID := range []string{"missing-model", "qwen3-8b-q8_0", "qwen3-0.6b-q8_0"} {
+		if !strings.Contains(err.Error(), modelID) {
+			t.Errorf("expected the preload of %s, got: %v", modelID, err)
+		}
+	}
+
+	if err := mgr.Ready(); err == nil {
+		t.Fatal("expected the cache not to be ready after a failed preload")
+	}
+}
+
 func newManager(t *testing.T) {
 	t.Run("default config values", func(t *testing.T) {
 		cfg := cache.Config{
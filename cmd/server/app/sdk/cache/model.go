@@ -1,6 +1,11 @@
 package cache
 
-import "time"
+import (
+	"slices"
+	"time"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
 
 // ModelDetail provides details for the models in the cache.
 type ModelDetail struct {
@@ -10,4 +15,90 @@ type ModelDetail struct {
 	Size          int64
 	ExpiresAt     time.Time
 	ActiveStreams int
+	Pinned        bool
+}
+
+// Alias represents a virtual model name from the model config file that
+// resolves to a local model.
+type Alias struct {
+	Name      string
+	ModelID   string
+	Overrides Overrides
+}
+
+// Overrides represents the request parameters the model config file sets for
+// a model or alias.
+//
+// Temperature: Used when the request doesn't provide a temperature.
+//
+// SystemPrompt: Placed in front of the system prompt of the request's
+// messages. A system message is added when the request doesn't have one.
+//
+// MaxTokens: Caps the max_tokens the request asks for. Used when the request
+// doesn't provide max_tokens.
+type Overrides struct {
+	Temperature  *float64
+	SystemPrompt string
+	MaxTokens    int
+}
+
+// Apply applies the overrides to the request document.
+func (o Overrides) Apply(d model.D) model.D {
+	if o.Temperature != nil {
+		if _, exists := d["temperature"]; !exists {
+			d["temperature"] = *o.Temperature
+		}
+	}
+
+	if o.MaxTokens > 0 {
+		maxTokens, ok := toInt(d["max_tokens"])
+		if !ok || maxTokens <= 0 || maxTokens > o.MaxTokens {
+			d["max_tokens"] = o.MaxTokens
+		}
+	}
+
+	if o.SystemPrompt != "" {
+		if messages, ok := d["messages"].([]model.D); ok {
+			d["messages"] = prefixSystemPrompt(messages, o.SystemPrompt)
+		}
+	}
+
+	return d
+}
+
+func prefixSystemPrompt(messages []model.D, prompt string) []model.D {
+	if len(messages) > 0 && messages[0]["role"] == "system" {
+		if content, ok := messages[0]["content"].(string); ok {
+			msg := make(model.D, len(messages[0]))
+			for k, v := range messages[0] {
+				msg[k] = v
+			}
+			msg["content"] = prompt + "\n\n" + content
+
+			result := slices.Clone(messages)
+			result[0] = msg
+
+			return result
+		}
+	}
+
+	system := model.D{
+		"role":    "system",
+		"content": prompt,
+	}
+
+	return append([]model.D{system}, messages...)
+}
+
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+
+	return 0, false
 }
@@ -3,10 +3,13 @@ package mid
 import (
 	"context"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/errs"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
+	"github.com/ardanlabs/kronk/sdk/kronk"
 )
 
 // Authenticate calls out to the auth service to authenticate the call.
@@ -18,7 +21,24 @@ func Authenticate(client *authclient.Client, admin bool, endpoint string) web.Mi
 				return errs.New(errs.Unauthenticated, err)
 			}
 
+			if ar.Quota != nil {
+				if w := web.GetWriter(ctx); w != nil {
+					setQuotaHeaders(w.Header(), *ar.Quota)
+				}
+			}
+
+			priority, err := requestPriority(r.Header.Get("X-Kronk-Priority"), ar.Priority)
+			if err != nil {
+				return errs.New(errs.InvalidArgument, err)
+			}
+
+			// The model requests of the handler are scheduled by the subject
+			// and priority class.
 			ctx = setSubject(ctx, ar.Subject)
+			ctx = kronk.SetSchedule(ctx, kronk.Schedule{
+				Subject:  ar.Subject,
+				Priority: priority,
+			})
 
 			return next(ctx, r)
 		}
@@ -28,3 +48,38 @@ func Authenticate(client *authclient.Client, admin bool, endpoint string) web.Mi
 
 	return m
 }
+
+// requestPriority returns the priority class for the request. The request can
+// ask for a class with the X-Kronk-Priority header, up to the highest class
+// the token allows. Without the header the normal class is used.
+func requestPriority(requested string, allowed string) (kronk.Priority, error) {
+	priority, err := kronk.ParsePriority(requested)
+	if err != nil {
+		return 0, err
+	}
+
+	maxPriority, err := kronk.ParsePriority(allowed)
+	if err != nil {
+		return 0, err
+	}
+
+	return min(priority, maxPriority), nil
+}
+
+// setQuotaHeaders reports what is left of the rate limit, only including the
+// limits that are set.
+func setQuotaHeaders(h http.Header, q authclient.Quota) {
+	set := func(name string, limit int64, remaining int64) {
+		if limit > 0 {
+			h.Set("X-Ratelimit-Limit-"+name, strconv.FormatInt(limit, 10))
+			h.Set("X-Ratelimit-Remaining-"+name, strconv.FormatInt(remaining, 10))
+		}
+	}
+
+	set("Requests", q.Requests, q.RequestsRemaining)
+	set("Tokens", q.TotalTokens, q.TotalTokensRemaining)
+	set("Prompt-Tokens", q.PromptTokens, q.PromptTokensRemaining)
+	set("Completion-Tokens", q.CompletionTokens, q.CompletionTokensRemaining)
+
+	h.Set("X-Ratelimit-Reset", time.Until(q.Reset).Round(time.Second).String())
+}
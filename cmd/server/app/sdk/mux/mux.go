@@ -9,6 +9,7 @@ import (
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/authclient"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/cache"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/mid"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/respstore"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/logger"
 	"github.com/ardanlabs/kronk/cmd/server/foundation/web"
 	"github.com/ardanlabs/kronk/sdk/tools/catalog"
@@ -60,6 +61,7 @@ type Config struct {
 	AuthClient *authclient.Client
 	Tracer     trace.Tracer
 	Cache      *cache.Cache
+	RespStore  *respstore.Store
 	Libs       *libs.Libs
 	Models     *models.Models
 	Catalog    *catalog.Catalog
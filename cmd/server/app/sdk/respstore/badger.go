@@ -0,0 +1,117 @@
+package respstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// BadgerConfig holds the configuration for the badger storer.
+type BadgerConfig struct {
+	DBPath string
+	TTL    time.Duration
+}
+
+// Badger stores the responses in an embedded badger database. Responses are
+// removed by badger once they are older than the ttl.
+type Badger struct {
+	db  *badger.DB
+	ttl time.Duration
+}
+
+// NewBadger constructs a badger storer with the specified configuration.
+func NewBadger(cfg BadgerConfig) (*Badger, error) {
+	opts := badger.DefaultOptions(cfg.DBPath)
+	opts.Logger = nil
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("new-badger: unable to open badger db: %w", err)
+	}
+
+	b := Badger{
+		db:  db,
+		ttl: cfg.TTL,
+	}
+
+	return &b, nil
+}
+
+// Save stores the response.
+func (b *Badger) Save(ctx context.Context, entry Entry) error {
+	val, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("save: unable to marshal response: %w", err)
+	}
+
+	f := func(txn *badger.Txn) error {
+		e := badger.NewEntry(buildKey(entry.ID), val)
+		if b.ttl > 0 {
+			e = e.WithTTL(b.ttl)
+		}
+
+		return txn.SetEntry(e)
+	}
+
+	if err := b.db.Update(f); err != nil {
+		return fmt.Errorf("save: unable to store response: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the response with the id.
+func (b *Badger) Get(ctx context.Context, id string) (Entry, error) {
+	var entry Entry
+
+	f := func(txn *badger.Txn) error {
+		item, err := txn.Get(buildKey(id))
+		if err != nil {
+			return err
+		}
+
+		return item.Value(func(val []byte) error {
+			return json.Unmarshal(val, &entry)
+		})
+	}
+
+	if err := b.db.View(f); err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return Entry{}, ErrNotFound
+		}
+
+		return Entry{}, fmt.Errorf("get: unable to read response: %w", err)
+	}
+
+	return entry, nil
+}
+
+// Delete removes the response with the id.
+func (b *Badger) Delete(ctx context.Context, id string) error {
+	f := func(txn *badger.Txn) error {
+		return txn.Delete(buildKey(id))
+	}
+
+	if err := b.db.Update(f); err != nil {
+		return fmt.Errorf("delete: unable to remove response: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the underlying database.
+func (b *Badger) Close() error {
+	return b.db.Close()
+}
+
+// =============================================================================
+
+const keyPrefix = "response:"
+
+func buildKey(id string) []byte {
+	return []byte(keyPrefix + id)
+}
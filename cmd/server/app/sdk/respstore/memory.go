@@ -0,0 +1,77 @@
+package respstore
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory stores the responses in memory. Responses are removed once they are
+// older than the ttl.
+type Memory struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	entry   Entry
+	expires time.Time
+}
+
+// NewMemory constructs a memory storer that keeps responses for the ttl.
+func NewMemory(ttl time.Duration) *Memory {
+	return &Memory{
+		ttl:     ttl,
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Save stores the response and removes the responses that have expired.
+func (m *Memory) Save(ctx context.Context, entry Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	for id, me := range m.entries {
+		if now.After(me.expires) {
+			delete(m.entries, id)
+		}
+	}
+
+	m.entries[entry.ID] = memoryEntry{
+		entry:   entry,
+		expires: now.Add(m.ttl),
+	}
+
+	return nil
+}
+
+// Get returns the response with the id.
+func (m *Memory) Get(ctx context.Context, id string) (Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	me, exists := m.entries[id]
+	if !exists || time.Now().After(me.expires) {
+		return Entry{}, ErrNotFound
+	}
+
+	return me.entry, nil
+}
+
+// Delete removes the response with the id.
+func (m *Memory) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, id)
+
+	return nil
+}
+
+// Close implements the storer interface.
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package respstore provides storage for the responses of the responses api,
+// so a request can continue the conversation of an earlier response.
+package respstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/kronk/sdk/kronk"
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+// ErrNotFound is returned when a response isn't in the store.
+var ErrNotFound = errors.New("response not found")
+
+// maxChain limits how many responses are followed to rebuild a conversation.
+const maxChain = 1000
+
+// Entry represents a stored response with the input items that produced it.
+type Entry struct {
+	ID             string                 `json:"id"`
+	Subject        string                 `json:"subject"`
+	PrevResponseID string                 `json:"previous_response_id"`
+	Input          []model.D              `json:"input"`
+	Response       kronk.ResponseResponse `json:"response"`
+}
+
+// Storer defines the behavior required to store responses.
+type Storer interface {
+	Save(ctx context.Context, entry Entry) error
+	Get(ctx context.Context, id string) (Entry, error)
+	Delete(ctx context.Context, id string) error
+	Close() error
+}
+
+// Store manages the responses for the responses api.
+type Store struct {
+	storer Storer
+}
+
+// New constructs a store using the specified storer.
+func New(storer Storer) *Store {
+	return &Store{
+		storer: storer,
+	}
+}
+
+// Close closes the underlying storer.
+func (s *Store) Close() error {
+	return s.storer.Close()
+}
+
+// Save stores the response.
+func (s *Store) Save(ctx context.Context, entry Entry) error {
+	if err := s.storer.Save(ctx, entry); err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the response with the id. A response stored for another subject
+// is reported as not found.
+func (s *Store) Get(ctx context.Context, subject string, id string) (Entry, error) {
+	entry, err := s.storer.Get(ctx, id)
+	if err != nil {
+		return Entry{}, fmt.Errorf("get: %w", err)
+	}
+
+	if entry.Subject != subject {
+		return Entry{}, fmt.Errorf("get: %w", ErrNotFound)
+	}
+
+	return entry, nil
+}
+
+// Delete removes the response with the id.
+func (s *Store) Delete(ctx context.Context, subject string, id string) error {
+	if _, err := s.Get(ctx, subject, id); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	if err := s.storer.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Messages rebuilds the conversation that ends with the response with the id
+// by following the previous responses back to the first one.
+func (s *Store) Messages(ctx context.Context, subject string, id string) ([]model.D, error) {
+	var chain []Entry
+
+	for next := id; next != ""; {
+		if len(chain) == maxChain {
+			return nil, fmt.Errorf("messages: conversation is longer than %d responses", maxChain)
+		}
+
+		entry, err := s.Get(ctx, subject, next)
+		if err != nil {
+			return nil, fmt.Errorf("messages: response[%s]: %w", next, err)
+		}
+
+		chain = append(chain, entry)
+		next = entry.PrevResponseID
+	}
+
+	var msgs []model.D
+	for i := len(chain) - 1; i >= 0; i-- {
+		msgs = append(msgs, chain[i].Input...)
+		msgs = append(msgs, kronk.ResponseOutputMessages(chain[i].Response.Output)...)
+	}
+
+	return msgs, nil
+}
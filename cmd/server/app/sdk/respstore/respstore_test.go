@@ -0,0 +1,134 @@
+package respstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/respstore"
+	"github.com/ardanlabs/kronk/sdk/kronk"
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+func Test_Store(t *testing.T) {
+	b, err := respstore.NewBadger(respstore.BadgerConfig{
+		DBPath: t.TempDir(),
+		TTL:    time.Hour,
+	})
+
+	if err != nil {
+		t.Fatalf("should be able to construct badger storer: %s", err)
+	}
+
+	storers := map[string]respstore.Storer{
+		"memory": respstore.NewMemory(time.Hour),
+		"badger": b,
+	}
+
+	for name, storer := range storers {
+		t.Run(name, func(t *testing.T) {
+			testStore(t, respstore.New(storer))
+		})
+	}
+}
+
+func testStore(t *testing.T, store *respstore.Store) {
+	defer store.Close()
+
+	ctx := context.Background()
+
+	entries := []respstore.Entry{
+		{
+			ID:      "resp_1",
+			Subject: "team-a",
+			Input:   []model.D{{"role": "user", "content": "What is the weather in London?"}},
+			Response: kronk.ResponseResponse{
+				ID: "resp_1",
+				Output: []kronk.ResponseOutputItem{
+					{Type: "function_call", CallID: "call_1", Name: "get_weather", Arguments: `{"location":"London"}`},
+				},
+			},
+		},
+		{
+			ID:             "resp_2",
+			Subject:        "team-a",
+			PrevResponseID: "resp_1",
+			Input:          []model.D{{"role": "tool", "tool_call_id": "call_1", "content": "Sunny"}},
+			Response: kronk.ResponseResponse{
+				ID: "resp_2",
+				Output: []kronk.ResponseOutputItem{
+					{Type: "message", Role: "assistant", Content: []kronk.ResponseContentItem{{Type: "output_text", Text: "It's sunny."}}},
+				},
+			},
+		},
+	}
+
+	for _, entry := range entries {
+		if err := store.Save(ctx, entry); err != nil {
+			t.Fatalf("should be able to save: %s", err)
+		}
+	}
+
+	entry, err := store.Get(ctx, "team-a", "resp_2")
+	if err != nil {
+		t.Fatalf("should be able to get: %s", err)
+	}
+
+	if entry.PrevResponseID != "resp_1" || entry.Response.Output[0].Content[0].Text != "It's sunny." {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	if _, err := store.Get(ctx, "team-b", "resp_2"); !errors.Is(err, respstore.ErrNotFound) {
+		t.Errorf("expected not found for another subject, got %v", err)
+	}
+
+	msgs, err := store.Messages(ctx, "team-a", "resp_2")
+	if err != nil {
+		t.Fatalf("should be able to rebuild messages: %s", err)
+	}
+
+	roles := []string{"user", "assistant", "tool", "assistant"}
+	if len(msgs) != len(roles) {
+		t.Fatalf("expected %d messages, got %d: %v", len(roles), len(msgs), msgs)
+	}
+
+	for i, role := range roles {
+		if msgs[i]["role"] != role {
+			t.Errorf("message %d: expected role %s, got %v", i, role, msgs[i]["role"])
+		}
+	}
+
+	if toolCalls, ok := msgs[1]["tool_calls"].([]model.D); !ok || len(toolCalls) != 1 {
+		t.Errorf("expected the tool call in the assistant message, got %v", msgs[1])
+	}
+
+	if err := store.Delete(ctx, "team-a", "resp_1"); err != nil {
+		t.Fatalf("should be able to delete: %s", err)
+	}
+
+	if _, err := store.Messages(ctx, "team-a", "resp_2"); !errors.Is(err, respstore.ErrNotFound) {
+		t.Errorf("expected not found for a broken chain, got %v", err)
+	}
+
+	if err := store.Delete(ctx, "team-a", "resp_1"); !errors.Is(err, respstore.ErrNotFound) {
+		t.Errorf("expected not found for a deleted response, got %v", err)
+	}
+}
+
+func Test_MemoryTTL(t *testing.T) {
+	store := respstore.New(respstore.NewMemory(time.Millisecond))
+	defer store.Close()
+
+	ctx := context.Background()
+
+	if err := store.Save(ctx, respstore.Entry{ID: "resp_1"}); err != nil {
+		t.Fatalf("should be able to save: %s", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := store.Get(ctx, "", "resp_1"); !errors.Is(err, respstore.ErrNotFound) {
+		t.Errorf("expected the response to expire, got %v", err)
+	}
+}
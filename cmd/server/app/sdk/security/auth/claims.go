@@ -7,6 +7,8 @@ type RateWindow string
 
 // Set of rate limit units.
 const (
+	RateMinute    RateWindow = "minute"
+	RateHour      RateWindow = "hour"
 	RateDay       RateWindow = "day"
 	RateMonth     RateWindow = "month"
 	RateYear      RateWindow = "year"
@@ -18,14 +20,61 @@ const (
 // given Window period. A value of 0 means no requests are allowed. When Window
 // is set to RateUnlimited, the Limit field is ignored and unlimited requests
 // are permitted.
+//
+// The PromptTokens, CompletionTokens and TotalTokens fields specify token
+// budgets within the same Window period. Requests are rejected once a budget
+// has been used up. A budget of 0 is not enforced.
 type RateLimit struct {
-	Limit  int        `json:"limit"`
-	Window RateWindow `json:"window"`
+	Limit            int        `json:"limit"`
+	Window           RateWindow `json:"window"`
+	PromptTokens     int        `json:"prompt_tokens,omitempty"`
+	CompletionTokens int        `json:"completion_tokens,omitempty"`
+	TotalTokens      int        `json:"total_tokens,omitempty"`
+}
+
+// HasTokenBudget reports whether any of the token budgets are set.
+func (rl RateLimit) HasTokenBudget() bool {
+	return rl.Window != RateUnlimited && (rl.PromptTokens > 0 || rl.CompletionTokens > 0 || rl.TotalTokens > 0)
+}
+
+// Set of priority classes for scheduling the requests of a token.
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// ValidPriority reports whether the priority is a known class. An empty
+// priority is the normal class.
+func ValidPriority(priority string) bool {
+	switch priority {
+	case "", PriorityLow, PriorityNormal, PriorityHigh:
+		return true
+	}
+
+	return false
 }
 
 // Claims represents the authorization claims transmitted via a JWT.
+//
+// The Priority field specifies the highest priority class the requests of
+// the token are scheduled in. Requests can ask for a lower class.
 type Claims struct {
 	jwt.RegisteredClaims
 	Admin     bool                 `json:"admin"`
 	Endpoints map[string]RateLimit `json:"endpoints"`
+	Priority  string               `json:"priority,omitempty"`
+}
+
+// MaxPriority returns the highest priority class for the token. Admin tokens
+// can use every class and tokens without a priority the normal class.
+func (c Claims) MaxPriority() string {
+	switch {
+	case c.Priority != "":
+		return c.Priority
+	case c.Admin:
+		return PriorityHigh
+	}
+
+	return PriorityNormal
 }
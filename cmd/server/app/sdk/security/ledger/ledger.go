@@ -0,0 +1,193 @@
+// Package ledger provides usage accounting using an embedded database.
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// DayFormat is the layout used for the days in the ledger.
+const DayFormat = "2006-01-02"
+
+// Config holds the configuration for the ledger.
+type Config struct {
+	DBPath string
+}
+
+// Entry represents the usage of a single request.
+type Entry struct {
+	Subject          string
+	Model            string
+	Endpoint         string
+	PromptTokens     int
+	CompletionTokens int
+	ReasoningTokens  int
+	Latency          time.Duration
+}
+
+// Record represents the usage accumulated for a subject, model and endpoint
+// on a given day.
+type Record struct {
+	Day              string `json:"day"`
+	Subject          string `json:"subject"`
+	Model            string `json:"model"`
+	Endpoint         string `json:"endpoint"`
+	Requests         int64  `json:"requests"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+	ReasoningTokens  int64  `json:"reasoning_tokens"`
+	LatencyMS        int64  `json:"latency_ms"`
+}
+
+// Filter selects the records to return from a query. The From and To days are
+// inclusive. An empty Subject matches all subjects.
+type Filter struct {
+	Subject string
+	From    time.Time
+	To      time.Time
+}
+
+// Ledger records usage per subject, model, endpoint and day using an embedded
+// badger database.
+type Ledger struct {
+	db *badger.DB
+	mu sync.Mutex
+}
+
+// New creates a new ledger with the specified configuration.
+func New(cfg Config) (*Ledger, error) {
+	opts := badger.DefaultOptions(cfg.DBPath)
+	opts.Logger = nil
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("new: unable to open badger db: %w", err)
+	}
+
+	l := Ledger{
+		db: db,
+	}
+
+	return &l, nil
+}
+
+// Close closes the underlying database.
+func (l *Ledger) Close() error {
+	return l.db.Close()
+}
+
+// Record adds the usage of a request to the record for the current day.
+func (l *Ledger) Record(entry Entry) error {
+	day := time.Now().UTC().Format(DayFormat)
+	key := buildKey(day, entry.Subject, entry.Model, entry.Endpoint)
+
+	// Records are updated with a read and a write, so concurrent updates of
+	// the same record would conflict.
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f := func(txn *badger.Txn) error {
+		rec := Record{
+			Day:      day,
+			Subject:  entry.Subject,
+			Model:    entry.Model,
+			Endpoint: entry.Endpoint,
+		}
+
+		item, err := txn.Get(key)
+		switch {
+		case err == nil:
+			err := item.Value(func(val []byte) error {
+				return json.Unmarshal(val, &rec)
+			})
+
+			if err != nil {
+				return err
+			}
+
+		case !errors.Is(err, badger.ErrKeyNotFound):
+			return err
+		}
+
+		rec.Requests++
+		rec.PromptTokens += int64(entry.PromptTokens)
+		rec.CompletionTokens += int64(entry.CompletionTokens)
+		rec.ReasoningTokens += int64(entry.ReasoningTokens)
+		rec.LatencyMS += entry.Latency.Milliseconds()
+
+		val, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+
+		return txn.Set(key, val)
+	}
+
+	if err := l.db.Update(f); err != nil {
+		return fmt.Errorf("record: unable to update ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Query returns the records that match the filter, ordered by day, subject,
+// model and endpoint.
+func (l *Ledger) Query(filter Filter) ([]Record, error) {
+	from := []byte(keyPrefix + filter.From.UTC().Format(DayFormat))
+	to := filter.To.UTC().Format(DayFormat)
+
+	var records []Record
+
+	f := func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(keyPrefix)
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(from); it.Valid(); it.Next() {
+			var rec Record
+			err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &rec)
+			})
+
+			if err != nil {
+				return err
+			}
+
+			if rec.Day > to {
+				break
+			}
+
+			if filter.Subject != "" && rec.Subject != filter.Subject {
+				continue
+			}
+
+			records = append(records, rec)
+		}
+
+		return nil
+	}
+
+	if err := l.db.View(f); err != nil {
+		return nil, fmt.Errorf("query: unable to read ledger: %w", err)
+	}
+
+	return records, nil
+}
+
+// =============================================================================
+
+const keyPrefix = "usage:"
+
+// buildKey starts with the day so a date range is a range of keys. The other
+// fields are separated with a zero byte since model ids and endpoints can
+// hold most characters.
+func buildKey(day, subject, model, endpoint string) []byte {
+	return fmt.Appendf(nil, "%s%s:%s\x00%s\x00%s", keyPrefix, day, subject, model, endpoint)
+}
@@ -0,0 +1,84 @@
+package ledger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/ledger"
+)
+
+func Test_Ledger(t *testing.T) {
+	l, err := ledger.New(ledger.Config{
+		DBPath: t.TempDir(),
+	})
+
+	if err != nil {
+		t.Fatalf("should be able to construct ledger: %s", err)
+	}
+
+	defer l.Close()
+
+	entries := []ledger.Entry{
+		{Subject: "team-a", Model: "qwen3", Endpoint: "chat-completions", PromptTokens: 10, CompletionTokens: 20, ReasoningTokens: 5, Latency: time.Second},
+		{Subject: "team-a", Model: "qwen3", Endpoint: "chat-completions", PromptTokens: 30, CompletionTokens: 40, ReasoningTokens: 0, Latency: 500 * time.Millisecond},
+		{Subject: "team-a", Model: "embed", Endpoint: "embeddings", PromptTokens: 100},
+		{Subject: "team-b", Model: "qwen3", Endpoint: "chat-completions", PromptTokens: 1, CompletionTokens: 2},
+	}
+
+	for _, entry := range entries {
+		if err := l.Record(entry); err != nil {
+			t.Fatalf("should be able to record usage: %s", err)
+		}
+	}
+
+	today := time.Now().UTC()
+
+	t.Run("subject", func(t *testing.T) {
+		records, err := l.Query(ledger.Filter{Subject: "team-a", From: today, To: today})
+		if err != nil {
+			t.Fatalf("should be able to query: %s", err)
+		}
+
+		if len(records) != 2 {
+			t.Fatalf("should get 2 records, got %d: %+v", len(records), records)
+		}
+
+		exp := ledger.Record{
+			Day:              today.Format(ledger.DayFormat),
+			Subject:          "team-a",
+			Model:            "qwen3",
+			Endpoint:         "chat-completions",
+			Requests:         2,
+			PromptTokens:     40,
+			CompletionTokens: 60,
+			ReasoningTokens:  5,
+			LatencyMS:        1500,
+		}
+
+		if records[1] != exp {
+			t.Errorf("got %+v, exp %+v", records[1], exp)
+		}
+	})
+
+	t.Run("all subjects", func(t *testing.T) {
+		records, err := l.Query(ledger.Filter{From: today, To: today})
+		if err != nil {
+			t.Fatalf("should be able to query: %s", err)
+		}
+
+		if len(records) != 3 {
+			t.Fatalf("should get 3 records, got %d", len(records))
+		}
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		records, err := l.Query(ledger.Filter{From: today.AddDate(0, 0, -10), To: today.AddDate(0, 0, -1)})
+		if err != nil {
+			t.Fatalf("should be able to query: %s", err)
+		}
+
+		if len(records) != 0 {
+			t.Fatalf("should get no records before today, got %d", len(records))
+		}
+	})
+}
@@ -14,6 +14,30 @@ import (
 // ErrRateLimitExceeded is returned when the rate limit has been exceeded.
 var ErrRateLimitExceeded = errors.New("rate limit exceeded")
 
+// ErrTokenLimitExceeded is returned when a token budget has been used up.
+var ErrTokenLimitExceeded = errors.New("token limit exceeded")
+
+// Usage represents the tokens used by a request.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Quota represents what is left of a rate limit in the current window. The
+// remaining values are only meaningful when the matching limit is set.
+type Quota struct {
+	Window                    auth.RateWindow
+	Reset                     time.Time
+	Requests                  int
+	RequestsRemaining         int
+	PromptTokens              int
+	PromptTokensRemaining     int
+	CompletionTokens          int
+	CompletionTokensRemaining int
+	TotalTokens               int
+	TotalTokensRemaining      int
+}
+
 // Config holds the configuration for the rate limiter.
 type Config struct {
 	DBPath string
@@ -49,71 +73,119 @@ func (l *Limiter) Close() error {
 // Check validates that the rate limit has not been exceeded for the given
 // subject and endpoint. If the limit has not been reached, the count is
 // incremented. It returns ErrRateLimitExceeded if the limit has been reached,
-// nil otherwise. Unlimited endpoints always return nil.
+// ErrTokenLimitExceeded if a token budget has been used up, nil otherwise.
+// Unlimited endpoints always return nil.
 func (l *Limiter) Check(subject string, endpoint string, limit auth.RateLimit) error {
 	if limit.Window == auth.RateUnlimited {
 		return nil
 	}
 
-	key := l.buildKey(subject, endpoint, limit.Window)
+	count, used, err := l.read(subject, endpoint, limit)
+	if err != nil {
+		return fmt.Errorf("check: unable to read rate limit: %w", err)
+	}
+
+	if count >= limit.Limit {
+		return ErrRateLimitExceeded
+	}
+
+	if tokenBudgetUsed(limit, used) {
+		return ErrTokenLimitExceeded
+	}
+
+	return l.record(l.buildKey(subject, endpoint, limit.Window), limit.Window, 1)
+}
+
+// RecordUsage adds the tokens used by a request to the token counts for the
+// given subject and endpoint. Nothing is recorded for limits without a token
+// budget.
+func (l *Limiter) RecordUsage(subject string, endpoint string, limit auth.RateLimit, usage Usage) error {
+	if !limit.HasTokenBudget() {
+		return nil
+	}
+
+	key := l.buildTokenKey(subject, endpoint, limit.Window)
+
+	if err := l.record(key, limit.Window, uint64(max(usage.PromptTokens, 0)), uint64(max(usage.CompletionTokens, 0))); err != nil {
+		return fmt.Errorf("record-usage: %w", err)
+	}
+
+	return nil
+}
+
+// Quota returns what is left of the rate limit for the given subject and
+// endpoint in the current window.
+func (l *Limiter) Quota(subject string, endpoint string, limit auth.RateLimit) (Quota, error) {
+	if limit.Window == auth.RateUnlimited {
+		return Quota{Window: auth.RateUnlimited}, nil
+	}
+
+	count, used, err := l.read(subject, endpoint, limit)
+	if err != nil {
+		return Quota{}, fmt.Errorf("quota: unable to read rate limit: %w", err)
+	}
+
+	total := used.PromptTokens + used.CompletionTokens
+
+	q := Quota{
+		Window:                    limit.Window,
+		Reset:                     l.windowEnd(limit.Window),
+		Requests:                  limit.Limit,
+		RequestsRemaining:         max(limit.Limit-count, 0),
+		PromptTokens:              limit.PromptTokens,
+		PromptTokensRemaining:     max(limit.PromptTokens-used.PromptTokens, 0),
+		CompletionTokens:          limit.CompletionTokens,
+		CompletionTokensRemaining: max(limit.CompletionTokens-used.CompletionTokens, 0),
+		TotalTokens:               limit.TotalTokens,
+		TotalTokensRemaining:      max(limit.TotalTokens-total, 0),
+	}
+
+	return q, nil
+}
+
+// =============================================================================
 
+func (l *Limiter) read(subject string, endpoint string, limit auth.RateLimit) (int, Usage, error) {
 	var count int
+	var used Usage
+
 	err := l.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(key)
-		if errors.Is(err, badger.ErrKeyNotFound) {
-			count = 0
+		counts, err := readCounts(txn, l.buildKey(subject, endpoint, limit.Window), 1)
+		if err != nil {
+			return err
+		}
+		count = int(counts[0])
+
+		if !limit.HasTokenBudget() {
 			return nil
 		}
 
+		counts, err = readCounts(txn, l.buildTokenKey(subject, endpoint, limit.Window), 2)
 		if err != nil {
 			return err
 		}
+		used = Usage{PromptTokens: int(counts[0]), CompletionTokens: int(counts[1])}
 
-		return item.Value(func(val []byte) error {
-			count = int(binary.BigEndian.Uint64(val))
-			return nil
-		})
+		return nil
 	})
 
-	if err != nil {
-		return fmt.Errorf("check: unable to read rate limit: %w", err)
-	}
-
-	if count >= limit.Limit {
-		return ErrRateLimitExceeded
-	}
-
-	return l.record(key, limit.Window)
+	return count, used, err
 }
 
-func (l *Limiter) record(key []byte, window auth.RateWindow) error {
+// record adds the deltas to the counts stored under the key.
+func (l *Limiter) record(key []byte, window auth.RateWindow, deltas ...uint64) error {
 	ttl := l.calculateTTL(window)
 
 	f := func(txn *badger.Txn) error {
-		var count uint64
-
-		item, err := txn.Get(key)
-		switch err {
-		case nil:
-			err = item.Value(func(val []byte) error {
-				count = binary.BigEndian.Uint64(val)
-				return nil
-			})
-
-			if err != nil {
-				return err
-			}
-
-		default:
-			if !errors.Is(err, badger.ErrKeyNotFound) {
-				return err
-			}
+		counts, err := readCounts(txn, key, len(deltas))
+		if err != nil {
+			return err
 		}
 
-		count++
-
-		val := make([]byte, 8)
-		binary.BigEndian.PutUint64(val, count)
+		val := make([]byte, 8*len(deltas))
+		for i, delta := range deltas {
+			binary.BigEndian.PutUint64(val[i*8:], counts[i]+delta)
+		}
 
 		entry := badger.NewEntry(key, val).WithTTL(ttl)
 		return txn.SetEntry(entry)
@@ -126,15 +198,68 @@ func (l *Limiter) record(key []byte, window auth.RateWindow) error {
 	return nil
 }
 
+// readCounts returns the n counts stored under the key, which are all 0 when
+// the key doesn't exist.
+func readCounts(txn *badger.Txn, key []byte, n int) ([]uint64, error) {
+	counts := make([]uint64, n)
+
+	item, err := txn.Get(key)
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return counts, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = item.Value(func(val []byte) error {
+		for i := range counts {
+			if len(val) < (i+1)*8 {
+				break
+			}
+			counts[i] = binary.BigEndian.Uint64(val[i*8:])
+		}
+		return nil
+	})
+
+	return counts, err
+}
+
+func tokenBudgetUsed(limit auth.RateLimit, used Usage) bool {
+	switch {
+	case limit.PromptTokens > 0 && used.PromptTokens >= limit.PromptTokens:
+		return true
+
+	case limit.CompletionTokens > 0 && used.CompletionTokens >= limit.CompletionTokens:
+		return true
+
+	case limit.TotalTokens > 0 && used.PromptTokens+used.CompletionTokens >= limit.TotalTokens:
+		return true
+	}
+
+	return false
+}
+
 func (l *Limiter) buildKey(subject, endpoint string, window auth.RateWindow) []byte {
 	windowStart := l.windowStart(window)
 	return fmt.Appendf(nil, "rate:%s:%s:%d", subject, endpoint, windowStart.Unix())
 }
 
+func (l *Limiter) buildTokenKey(subject, endpoint string, window auth.RateWindow) []byte {
+	windowStart := l.windowStart(window)
+	return fmt.Appendf(nil, "tokens:%s:%s:%d", subject, endpoint, windowStart.Unix())
+}
+
 func (l *Limiter) windowStart(window auth.RateWindow) time.Time {
 	now := time.Now().UTC()
 
 	switch window {
+	case auth.RateMinute:
+		return now.Truncate(time.Minute)
+
+	case auth.RateHour:
+		return now.Truncate(time.Hour)
+
 	case auth.RateDay:
 		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 
@@ -149,23 +274,30 @@ func (l *Limiter) windowStart(window auth.RateWindow) time.Time {
 	}
 }
 
-func (l *Limiter) calculateTTL(window auth.RateWindow) time.Duration {
+func (l *Limiter) windowEnd(window auth.RateWindow) time.Time {
 	now := time.Now().UTC()
-	var end time.Time
 
 	switch window {
+	case auth.RateMinute:
+		return now.Truncate(time.Minute).Add(time.Minute)
+
+	case auth.RateHour:
+		return now.Truncate(time.Hour).Add(time.Hour)
+
 	case auth.RateDay:
-		end = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
 
 	case auth.RateMonth:
-		end = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
 
 	case auth.RateYear:
-		end = time.Date(now.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)
+		return time.Date(now.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	default:
-		return 24 * time.Hour
+		return now.Add(24 * time.Hour)
 	}
+}
 
-	return end.Sub(now)
+func (l *Limiter) calculateTTL(window auth.RateWindow) time.Duration {
+	return time.Until(l.windowEnd(window))
 }
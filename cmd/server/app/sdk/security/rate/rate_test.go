@@ -3,6 +3,7 @@ package rate_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/auth"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/rate"
@@ -23,6 +24,9 @@ func Test_Rate(t *testing.T) {
 	t.Run("day", day(limiter))
 	t.Run("month", month(limiter))
 	t.Run("year", year(limiter))
+	t.Run("hour", hour(limiter))
+	t.Run("tokens", tokens(limiter))
+	t.Run("quota", quota(limiter))
 }
 
 func unlimited(limiter *rate.Limiter) func(t *testing.T) {
@@ -111,3 +115,115 @@ func year(limiter *rate.Limiter) func(t *testing.T) {
 		}
 	}
 }
+
+func hour(limiter *rate.Limiter) func(t *testing.T) {
+	return func(t *testing.T) {
+		limit := auth.RateLimit{
+			Limit:  1,
+			Window: auth.RateHour,
+		}
+
+		if err := limiter.Check("user-hour", "endpoint", limit); err != nil {
+			t.Fatalf("should not exceed limit on check 1: %s", err)
+		}
+
+		err := limiter.Check("user-hour", "endpoint", limit)
+		switch {
+		case err == nil:
+			t.Fatal("should exceed limit after 1 request")
+
+		case !errors.Is(err, rate.ErrRateLimitExceeded):
+			t.Fatalf("should return ErrRateLimitExceeded: %s", err)
+		}
+	}
+}
+
+func tokens(limiter *rate.Limiter) func(t *testing.T) {
+	return func(t *testing.T) {
+		tests := []struct {
+			name  string
+			limit auth.RateLimit
+			usage rate.Usage
+		}{
+			{"prompt", auth.RateLimit{Limit: 100, Window: auth.RateMinute, PromptTokens: 100}, rate.Usage{PromptTokens: 60}},
+			{"completion", auth.RateLimit{Limit: 100, Window: auth.RateDay, CompletionTokens: 100}, rate.Usage{CompletionTokens: 60}},
+			{"total", auth.RateLimit{Limit: 100, Window: auth.RateMonth, TotalTokens: 100}, rate.Usage{PromptTokens: 30, CompletionTokens: 30}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				subject := "user-tokens-" + tt.name
+
+				// The budget is checked before a request, so the request
+				// that crosses it is allowed.
+				for i := range 2 {
+					if err := limiter.Check(subject, "endpoint", tt.limit); err != nil {
+						t.Fatalf("should not exceed token limit on check %d: %s", i+1, err)
+					}
+
+					if err := limiter.RecordUsage(subject, "endpoint", tt.limit, tt.usage); err != nil {
+						t.Fatalf("should be able to record usage: %s", err)
+					}
+				}
+
+				err := limiter.Check(subject, "endpoint", tt.limit)
+				switch {
+				case err == nil:
+					t.Fatal("should exceed token limit after 120 tokens")
+
+				case !errors.Is(err, rate.ErrTokenLimitExceeded):
+					t.Fatalf("should return ErrTokenLimitExceeded: %s", err)
+				}
+
+				if err := limiter.Check(subject, "other-endpoint", tt.limit); err != nil {
+					t.Fatalf("should not share the budget with other endpoints: %s", err)
+				}
+			})
+		}
+	}
+}
+
+func quota(limiter *rate.Limiter) func(t *testing.T) {
+	return func(t *testing.T) {
+		limit := auth.RateLimit{
+			Limit:            10,
+			Window:           auth.RateHour,
+			PromptTokens:     1000,
+			CompletionTokens: 500,
+			TotalTokens:      1200,
+		}
+
+		if err := limiter.Check("user-quota", "endpoint", limit); err != nil {
+			t.Fatalf("should not exceed limit: %s", err)
+		}
+
+		if err := limiter.RecordUsage("user-quota", "endpoint", limit, rate.Usage{PromptTokens: 300, CompletionTokens: 200}); err != nil {
+			t.Fatalf("should be able to record usage: %s", err)
+		}
+
+		q, err := limiter.Quota("user-quota", "endpoint", limit)
+		if err != nil {
+			t.Fatalf("should be able to get quota: %s", err)
+		}
+
+		if q.RequestsRemaining != 9 {
+			t.Errorf("requests remaining got %d, exp 9", q.RequestsRemaining)
+		}
+
+		if q.PromptTokensRemaining != 700 {
+			t.Errorf("prompt tokens remaining got %d, exp 700", q.PromptTokensRemaining)
+		}
+
+		if q.CompletionTokensRemaining != 300 {
+			t.Errorf("completion tokens remaining got %d, exp 300", q.CompletionTokensRemaining)
+		}
+
+		if q.TotalTokensRemaining != 700 {
+			t.Errorf("total tokens remaining got %d, exp 700", q.TotalTokensRemaining)
+		}
+
+		if until := time.Until(q.Reset); until <= 0 || until > time.Hour {
+			t.Errorf("reset got %s, exp within the hour", q.Reset)
+		}
+	}
+}
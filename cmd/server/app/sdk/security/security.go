@@ -11,6 +11,7 @@ import (
 
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/auth"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/keystore"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/ledger"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/rate"
 	"github.com/ardanlabs/kronk/sdk/tools/defaults"
 	"github.com/golang-jwt/jwt/v4"
@@ -32,6 +33,7 @@ type Config struct {
 type Security struct {
 	auth    *auth.Auth
 	limiter *rate.Limiter
+	ledger  *ledger.Ledger
 	cfg     Config
 	ks      *keystore.KeyStore
 }
@@ -64,9 +66,21 @@ func New(cfg Config) (*Security, error) {
 
 	// -------------------------------------------------------------------------
 
+	ldgr, err := ledger.New(ledger.Config{
+		DBPath: filepath.Join(basePath, "ledger"),
+	})
+
+	if err != nil {
+		limiter.Close()
+		return nil, fmt.Errorf("new: ledger: %w", err)
+	}
+
+	// -------------------------------------------------------------------------
+
 	sec := Security{
 		auth:    a,
 		limiter: limiter,
+		ledger:  ldgr,
 		cfg:     cfg,
 		ks:      ks,
 	}
@@ -80,7 +94,7 @@ func New(cfg Config) (*Security, error) {
 
 // Close shutdown the security system.
 func (sec *Security) Close() error {
-	return sec.limiter.Close()
+	return errors.Join(sec.limiter.Close(), sec.ledger.Close())
 }
 
 // BaseKeysFolder returns the location of the base keys folder being used.
@@ -111,8 +125,12 @@ func (sec *Security) Authenticate(ctx context.Context, bearerToken string, admin
 	limit := claims.Endpoints[endpoint]
 
 	if err := sec.limiter.Check(claims.Subject, endpoint, limit); err != nil {
-		if errors.Is(err, rate.ErrRateLimitExceeded) {
+		switch {
+		case errors.Is(err, rate.ErrRateLimitExceeded):
 			return auth.Claims{}, fmt.Errorf("rate limit exceeded: %w", err)
+
+		case errors.Is(err, rate.ErrTokenLimitExceeded):
+			return auth.Claims{}, fmt.Errorf("token limit exceeded: %w", err)
 		}
 
 		return auth.Claims{}, fmt.Errorf("rate limit check failed: %w", err)
@@ -121,8 +139,79 @@ func (sec *Security) Authenticate(ctx context.Context, bearerToken string, admin
 	return claims, nil
 }
 
+// Quota returns what is left of the rate limit for the endpoint in the
+// current window. Admins are not rate limited.
+func (sec *Security) Quota(claims auth.Claims, endpoint string) (rate.Quota, error) {
+	if claims.Admin {
+		return rate.Quota{Window: auth.RateUnlimited}, nil
+	}
+
+	q, err := sec.limiter.Quota(claims.Subject, endpoint, claims.Endpoints[endpoint])
+	if err != nil {
+		return rate.Quota{}, fmt.Errorf("quota: %w", err)
+	}
+
+	return q, nil
+}
+
+// RecordUsage records the usage of a request in the usage ledger under the
+// subject of the bearer token, and counts the tokens against the token budgets
+// of the bearer token for the endpoint.
+func (sec *Security) RecordUsage(ctx context.Context, bearerToken string, entry ledger.Entry) error {
+	claims, err := sec.auth.Authenticate(ctx, bearerToken)
+	if err != nil {
+		return fmt.Errorf("record-usage: invalid token: %w", err)
+	}
+
+	entry.Subject = claims.Subject
+
+	if err := sec.ledger.Record(entry); err != nil {
+		return fmt.Errorf("record-usage: %w", err)
+	}
+
+	if claims.Admin {
+		return nil
+	}
+
+	usage := rate.Usage{
+		PromptTokens:     entry.PromptTokens,
+		CompletionTokens: entry.CompletionTokens,
+	}
+
+	if err := sec.limiter.RecordUsage(claims.Subject, entry.Endpoint, claims.Endpoints[entry.Endpoint], usage); err != nil {
+		return fmt.Errorf("record-usage: %w", err)
+	}
+
+	return nil
+}
+
+// RecordLedger records the usage of a request in the usage ledger under the
+// subject provided in the entry. This is used when authentication is disabled
+// and there is no bearer token to take the subject from.
+func (sec *Security) RecordLedger(entry ledger.Entry) error {
+	if err := sec.ledger.Record(entry); err != nil {
+		return fmt.Errorf("record-ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Usage returns the usage ledger records that match the filter.
+func (sec *Security) Usage(filter ledger.Filter) ([]ledger.Record, error) {
+	records, err := sec.ledger.Query(filter)
+	if err != nil {
+		return nil, fmt.Errorf("usage: %w", err)
+	}
+
+	return records, nil
+}
+
 // GenerateToken generates a new token with the specified claims.
-func (sec *Security) GenerateToken(admin bool, endpoints map[string]auth.RateLimit, duration time.Duration) (string, error) {
+func (sec *Security) GenerateToken(admin bool, priority string, endpoints map[string]auth.RateLimit, duration time.Duration) (string, error) {
+	if !auth.ValidPriority(priority) {
+		return "", fmt.Errorf("generate-token: unknown priority[%s], use low, normal or high", priority)
+	}
+
 	claims := auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    sec.cfg.Issuer,
@@ -132,6 +221,7 @@ func (sec *Security) GenerateToken(admin bool, endpoints map[string]auth.RateLim
 		},
 		Admin:     admin,
 		Endpoints: endpoints,
+		Priority:  priority,
 	}
 
 	token, err := sec.auth.GenerateToken(claims)
@@ -269,7 +359,7 @@ func (sec *Security) generateAdminToken(keysPath string) error {
 
 	const tenYears = time.Minute * 526000
 
-	token, err := sec.GenerateToken(admin, endpoints, tenYears)
+	token, err := sec.GenerateToken(admin, auth.PriorityHigh, endpoints, tenYears)
 	if err != nil {
 		return fmt.Errorf("generate-admin-token: unable to generate token: %w", err)
 	}
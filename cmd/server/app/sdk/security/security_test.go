@@ -1,6 +1,8 @@
 package security_test
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,6 +10,8 @@ import (
 
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security"
 	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/auth"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/ledger"
+	"github.com/ardanlabs/kronk/cmd/server/app/sdk/security/rate"
 )
 
 func TestGenerateToken(t *testing.T) {
@@ -25,7 +29,7 @@ func TestGenerateToken(t *testing.T) {
 		"chat-completions": {Limit: 0, Window: auth.RateUnlimited},
 	}
 
-	token, err := sec.GenerateToken(true, endpoints, time.Hour)
+	token, err := sec.GenerateToken(true, "", endpoints, time.Hour)
 	if err != nil {
 		t.Fatalf("failed to generate token: %v", err)
 	}
@@ -109,6 +113,123 @@ func TestDeletePrivateKey_NotFound(t *testing.T) {
 	}
 }
 
+func TestTokenBudget(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	sec, err := security.New(security.Config{
+		OverrideBaseKeysFolder: tmpDir,
+		Issuer:                 "test-issuer",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create security: %v", err)
+	}
+
+	defer sec.Close()
+
+	endpoints := map[string]auth.RateLimit{
+		"chat-completions": {Limit: 100, Window: auth.RateDay, TotalTokens: 1000},
+	}
+
+	token, err := sec.GenerateToken(false, "", endpoints, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	bearerToken := "Bearer " + token
+	ctx := context.Background()
+
+	claims, err := sec.Authenticate(ctx, bearerToken, false, "chat-completions")
+	if err != nil {
+		t.Fatalf("failed to authenticate: %v", err)
+	}
+
+	entry := ledger.Entry{
+		Model:            "qwen3",
+		Endpoint:         "chat-completions",
+		PromptTokens:     800,
+		CompletionTokens: 400,
+	}
+
+	if err := sec.RecordUsage(ctx, bearerToken, entry); err != nil {
+		t.Fatalf("failed to record usage: %v", err)
+	}
+
+	today := time.Now().UTC()
+
+	records, err := sec.Usage(ledger.Filter{Subject: claims.Subject, From: today, To: today})
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+
+	if len(records) != 1 || records[0].Requests != 1 || records[0].PromptTokens != 800 {
+		t.Errorf("expected 1 request with 800 prompt tokens in the ledger, got %+v", records)
+	}
+
+	q, err := sec.Quota(claims, "chat-completions")
+	if err != nil {
+		t.Fatalf("failed to get quota: %v", err)
+	}
+
+	if q.TotalTokens != 1000 || q.TotalTokensRemaining != 0 {
+		t.Errorf("expected 0 of 1000 tokens remaining, got %d of %d", q.TotalTokensRemaining, q.TotalTokens)
+	}
+
+	_, err = sec.Authenticate(ctx, bearerToken, false, "chat-completions")
+	if !errors.Is(err, rate.ErrTokenLimitExceeded) {
+		t.Fatalf("expected token limit exceeded, got %v", err)
+	}
+}
+
+func TestTokenPriority(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	sec, err := security.New(security.Config{
+		OverrideBaseKeysFolder: tmpDir,
+		Issuer:                 "test-issuer",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create security: %v", err)
+	}
+
+	defer sec.Close()
+
+	endpoints := map[string]auth.RateLimit{
+		"chat-completions": {Limit: 0, Window: auth.RateUnlimited},
+	}
+
+	if _, err := sec.GenerateToken(false, "urgent", endpoints, time.Hour); err == nil {
+		t.Fatal("expected an error for an unknown priority")
+	}
+
+	tests := []struct {
+		admin    bool
+		priority string
+		exp      string
+	}{
+		{admin: false, priority: "", exp: auth.PriorityNormal},
+		{admin: false, priority: auth.PriorityLow, exp: auth.PriorityLow},
+		{admin: true, priority: "", exp: auth.PriorityHigh},
+	}
+
+	for _, tt := range tests {
+		token, err := sec.GenerateToken(tt.admin, tt.priority, endpoints, time.Hour)
+		if err != nil {
+			t.Fatalf("failed to generate token: %v", err)
+		}
+
+		claims, err := sec.Authenticate(context.Background(), "Bearer "+token, false, "chat-completions")
+		if err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		if got := claims.MaxPriority(); got != tt.exp {
+			t.Errorf("admin[%t] priority[%s]: expected %s, got %s", tt.admin, tt.priority, tt.exp, got)
+		}
+	}
+}
+
 // =============================================================================
 
 func countKeys(t *testing.T, keysPath string) int {
@@ -194,7 +194,7 @@ loop:
 		lr = resp
 
 		switch resp.Choice[0].FinishReason {
-		case model.FinishReasonStop:
+		case model.FinishReasonStop, model.FinishReasonLength:
 			break loop
 
 		case model.FinishReasonError:
@@ -153,7 +153,7 @@ func question(krn *kronk.Kronk) error {
 		case model.FinishReasonError:
 			return fmt.Errorf("error from model: %s", resp.Choice[0].Delta.Content)
 
-		case model.FinishReasonStop:
+		case model.FinishReasonStop, model.FinishReasonLength:
 			return nil
 
 		default:
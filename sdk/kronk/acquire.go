@@ -7,7 +7,9 @@ import (
 	"github.com/ardanlabs/kronk/sdk/kronk/model"
 )
 
-func (krn *Kronk) acquireModel(ctx context.Context) (*model.Model, error) {
+// acquireModel waits for a slot and a model instance. The returned function
+// must be called to give them back.
+func (krn *Kronk) acquireModel(ctx context.Context) (*model.Model, func(), error) {
 	err := func() error {
 		krn.shutdown.Lock()
 		defer krn.shutdown.Unlock()
@@ -21,18 +23,16 @@ func (krn *Kronk) acquireModel(ctx context.Context) (*model.Model, error) {
 	}()
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// -------------------------------------------------------------------------
-	// Stage 1: Acquire backpressure slot
+	// Stage 1: Acquire backpressure slot, scheduled by priority and subject
 
-	select {
-	case <-ctx.Done():
+	releaseSlot, err := krn.sched.acquire(ctx, getSchedule(ctx))
+	if err != nil {
 		krn.activeStreams.Add(-1)
-		return nil, ctx.Err()
-
-	case krn.sem <- struct{}{}:
+		return nil, nil, err
 	}
 
 	// -------------------------------------------------------------------------
@@ -41,23 +41,25 @@ func (krn *Kronk) acquireModel(ctx context.Context) (*model.Model, error) {
 	if krn.pool != nil {
 		select {
 		case <-ctx.Done():
-			<-krn.sem
+			releaseSlot()
 			krn.activeStreams.Add(-1)
-			return nil, ctx.Err()
+			return nil, nil, ctx.Err()
 
 		case m := <-krn.pool:
-			return m, nil
+			release := func() {
+				krn.pool <- m
+				releaseSlot()
+				krn.activeStreams.Add(-1)
+			}
+
+			return m, release, nil
 		}
 	}
 
-	return krn.models[0], nil
-}
-
-func (krn *Kronk) releaseModel(m *model.Model) {
-	if krn.pool != nil {
-		krn.pool <- m
+	release := func() {
+		releaseSlot()
+		krn.activeStreams.Add(-1)
 	}
 
-	<-krn.sem
-	krn.activeStreams.Add(-1)
+	return krn.models[0], release, nil
 }
@@ -88,15 +88,14 @@ func (krn *Kronk) ChatStreamingHTTP(ctx context.Context, w http.ResponseWriter,
 		return model.ChatResponse{}, fmt.Errorf("chat-streaming-http: streaming not supported")
 	}
 
-	ch, err := krn.ChatStreaming(ctx, d)
+	es := eventStream{w: w, f: f}
+
+	ch, err := krn.ChatStreaming(es.reportWaiting(ctx), d)
 	if err != nil {
-		return model.ChatResponse{}, fmt.Errorf("chat-streaming-http: stream-response: %w", err)
+		return model.ChatResponse{}, es.fail(fmt.Errorf("chat-streaming-http: stream-response: %w", err))
 	}
 
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Transfer-Encoding", "chunked")
-	w.WriteHeader(http.StatusOK)
-	f.Flush()
+	es.writeHeaders()
 
 	var lr model.ChatResponse
 
@@ -109,8 +108,10 @@ func (krn *Kronk) ChatStreamingHTTP(ctx context.Context, w http.ResponseWriter,
 
 		// OpenAI does not expect the final delta to have content or reasoning.
 		// Kronk returns the entire streamed content in the final chunk.
-		if resp.Choice[0].FinishReason == model.FinishReasonStop {
+		switch resp.Choice[0].FinishReason {
+		case model.FinishReasonStop, model.FinishReasonLength:
 			resp.Choice[0].Message = model.ResponseMessage{}
+			resp.Choice[0].Logprobs = nil
 		}
 
 		d, err := json.Marshal(resp)
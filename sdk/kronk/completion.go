@@ -0,0 +1,443 @@
+package kronk
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// OpenAI Completions API types
+
+// CompletionResponse represents the OpenAI completions API response format.
+type CompletionResponse struct {
+	ID      string             `json:"id"`
+	Object  string             `json:"object"`
+	Created int64              `json:"created"`
+	Model   string             `json:"model"`
+	Choices []CompletionChoice `json:"choices"`
+	Usage   model.Usage        `json:"usage"`
+}
+
+// CompletionChoice represents a completion generated for a prompt.
+type CompletionChoice struct {
+	Text         string          `json:"text"`
+	Index        int             `json:"index"`
+	Logprobs     *model.Logprobs `json:"logprobs"`
+	FinishReason string          `json:"finish_reason"`
+}
+
+// =============================================================================
+
+// Completion provides support to complete a raw prompt. The prompt can be a
+// string or a list of strings, in which case each prompt is completed in turn
+// and the choices of a prompt are indexed after the choices of the prompts
+// before it.
+func (krn *Kronk) Completion(ctx context.Context, d model.D) (CompletionResponse, error) {
+	if _, exists := ctx.Deadline(); !exists {
+		return CompletionResponse{}, fmt.Errorf("completion: context has no deadline, provide a reasonable timeout")
+	}
+
+	req, err := parseCompletionRequest(d)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("completion: %w", err)
+	}
+
+	f := func(m *model.Model) (CompletionResponse, error) {
+		cs := newCompletionState(krn.ModelInfo().ID, req)
+
+		// The stream is drained even after an error so the model isn't
+		// left blocked on sending.
+		var choices []CompletionChoice
+		var errResp error
+
+		for resp := range completionStream(ctx, m, req) {
+			if len(resp.Choice) == 0 {
+				continue
+			}
+
+			c := resp.Choice[0]
+
+			switch c.FinishReason {
+			case "":
+				continue
+
+			case model.FinishReasonError:
+				if errResp == nil {
+					errResp = fmt.Errorf("completion: %s", c.Delta.Content)
+				}
+				continue
+			}
+
+			cs.count(c.Index, resp.Usage)
+
+			choices = append(choices, CompletionChoice{
+				Text:         cs.echo(c.Index) + c.Message.Content,
+				Index:        c.Index,
+				Logprobs:     c.Logprobs,
+				FinishReason: finishReason(c.FinishReason),
+			})
+		}
+
+		if errResp != nil {
+			return CompletionResponse{}, errResp
+		}
+
+		slices.SortFunc(choices, func(a, b CompletionChoice) int {
+			return cmp.Compare(a.Index, b.Index)
+		})
+
+		return cs.response(choices, cs.usage), nil
+	}
+
+	return nonStreaming(ctx, krn, f)
+}
+
+// CompletionStreaming provides support to complete a raw prompt and stream
+// the response. Each chunk holds the text generated for one choice.
+func (krn *Kronk) CompletionStreaming(ctx context.Context, d model.D) (<-chan CompletionResponse, error) {
+	if _, exists := ctx.Deadline(); !exists {
+		return nil, fmt.Errorf("completion-streaming: context has no deadline, provide a reasonable timeout")
+	}
+
+	req, err := parseCompletionRequest(d)
+	if err != nil {
+		return nil, fmt.Errorf("completion-streaming: %w", err)
+	}
+
+	cs := newCompletionState(krn.ModelInfo().ID, req)
+
+	f := func(m *model.Model) <-chan model.ChatResponse {
+		return completionStream(ctx, m, req)
+	}
+
+	p := streamProcessor[model.ChatResponse, CompletionResponse]{
+		Start:    func() []CompletionResponse { return nil },
+		Process:  cs.process,
+		Complete: func(model.ChatResponse) []CompletionResponse { return nil },
+	}
+
+	ef := func(err error) CompletionResponse {
+		choice := CompletionChoice{
+			Text:         err.Error(),
+			FinishReason: model.FinishReasonError,
+		}
+
+		return cs.response([]CompletionChoice{choice}, cs.usage)
+	}
+
+	return streamingWith(ctx, krn, f, p, ef)
+}
+
+// CompletionStreamingHTTP provides http handler support for a completions
+// call.
+func (krn *Kronk) CompletionStreamingHTTP(ctx context.Context, w http.ResponseWriter, d model.D) (CompletionResponse, error) {
+	if _, exists := ctx.Deadline(); !exists {
+		return CompletionResponse{}, fmt.Errorf("completion-streaming-http: context has no deadline, provide a reasonable timeout")
+	}
+
+	var stream bool
+	if streamReq, ok := d["stream"].(bool); ok {
+		stream = streamReq
+	}
+
+	// -------------------------------------------------------------------------
+
+	if !stream {
+		resp, err := krn.Completion(ctx, d)
+		if err != nil {
+			return CompletionResponse{}, fmt.Errorf("completion-streaming-http: completion: %w", err)
+		}
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return resp, fmt.Errorf("completion-streaming-http: marshal: %w", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+
+		return resp, nil
+	}
+
+	// -------------------------------------------------------------------------
+
+	f, ok := w.(http.Flusher)
+	if !ok {
+		return CompletionResponse{}, fmt.Errorf("completion-streaming-http: streaming not supported")
+	}
+
+	es := eventStream{w: w, f: f}
+
+	ch, err := krn.CompletionStreaming(es.reportWaiting(ctx), d)
+	if err != nil {
+		return CompletionResponse{}, es.fail(fmt.Errorf("completion-streaming-http: stream-response: %w", err))
+	}
+
+	es.writeHeaders()
+
+	var lr CompletionResponse
+
+	for resp := range ch {
+		if err := ctx.Err(); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return lr, errors.New("completion-streaming-http: client disconnected, do not send response")
+			}
+		}
+
+		d, err := json.Marshal(resp)
+		if err != nil {
+			return lr, fmt.Errorf("completion-streaming-http: marshal: %w", err)
+		}
+
+		fmt.Fprintf(w, "data: %s\n", d)
+		f.Flush()
+
+		lr = resp
+	}
+
+	w.Write([]byte("data: [DONE]\n"))
+	f.Flush()
+
+	return lr, nil
+}
+
+// =============================================================================
+
+// completionRequest holds a completions request with a document for each of
+// its prompts.
+type completionRequest struct {
+	prompts []string
+	docs    []model.D
+	n       int
+	echo    bool
+}
+
+func parseCompletionRequest(d model.D) (completionRequest, error) {
+	var prompts []string
+
+	switch v := d["prompt"].(type) {
+	case string:
+		prompts = []string{v}
+
+	case []string:
+		prompts = v
+
+	case []any:
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return completionRequest{}, errors.New("parse-completion-request: prompt must be a string or a list of strings")
+			}
+			prompts = append(prompts, s)
+		}
+
+	default:
+		return completionRequest{}, errors.New("parse-completion-request: prompt must be a string or a list of strings")
+	}
+
+	if len(prompts) == 0 {
+		return completionRequest{}, errors.New("parse-completion-request: prompt is empty")
+	}
+
+	req := completionRequest{
+		prompts: prompts,
+		n:       1,
+	}
+
+	if n, ok := toInt(d["n"]); ok && n > 0 {
+		req.n = n
+	}
+
+	req.echo, _ = d["echo"].(bool)
+
+	for _, prompt := range prompts {
+		pd := d.Clone()
+		pd["prompt"] = prompt
+		delete(pd, "echo")
+
+		// The completions API asks for the number of most likely tokens
+		// with logprobs instead of a flag.
+		if topLogprobs, ok := toInt(d["logprobs"]); ok {
+			pd["logprobs"] = true
+			pd["top_logprobs"] = topLogprobs
+		}
+
+		req.docs = append(req.docs, pd)
+	}
+
+	return req, nil
+}
+
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+
+	case float64:
+		return int(n), true
+
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+
+	return 0, false
+}
+
+// completionStream completes the prompts of the request in turn and sends
+// the responses on one channel. The choices of a prompt are indexed after the
+// choices of the prompts before it.
+func completionStream(ctx context.Context, m *model.Model, req completionRequest) <-chan model.ChatResponse {
+	ch := make(chan model.ChatResponse)
+
+	go func() {
+		defer close(ch)
+
+		for i, d := range req.docs {
+			var failed bool
+
+			for resp := range m.CompletionStreaming(ctx, d) {
+				if len(resp.Choice) > 0 {
+					resp.Choice[0].Index += i * req.n
+					failed = failed || resp.Choice[0].FinishReason == model.FinishReasonError
+				}
+
+				select {
+				case ch <- resp:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if failed {
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// =============================================================================
+
+// completionState converts the responses of the model into completions. The
+// usage adds up the prompts and the choices that have finished.
+type completionState struct {
+	id      string
+	created int64
+	modelID string
+	req     completionRequest
+	echoed  map[int]bool
+	counted map[int]bool
+	usage   model.Usage
+}
+
+func newCompletionState(modelID string, req completionRequest) *completionState {
+	return &completionState{
+		id:      "cmpl-" + uuid.New().String(),
+		created: time.Now().Unix(),
+		modelID: modelID,
+		req:     req,
+		echoed:  make(map[int]bool),
+		counted: make(map[int]bool),
+	}
+}
+
+func (cs *completionState) process(resp model.ChatResponse) []CompletionResponse {
+	if len(resp.Choice) == 0 {
+		return nil
+	}
+
+	c := resp.Choice[0]
+
+	choice := CompletionChoice{
+		Index:    c.Index,
+		Logprobs: c.Logprobs,
+	}
+
+	usage := resp.Usage
+
+	switch c.FinishReason {
+	case "":
+		if c.Delta == nil || c.Delta.Content == "" {
+			return nil
+		}
+
+		choice.Text = cs.echo(c.Index) + c.Delta.Content
+
+	case model.FinishReasonError:
+		choice.Text = c.Delta.Content
+		choice.FinishReason = model.FinishReasonError
+
+	default:
+		cs.count(c.Index, resp.Usage)
+
+		// The text was sent with the deltas, but the prompt is still
+		// owed when nothing was generated.
+		choice.Text = cs.echo(c.Index)
+		choice.Logprobs = nil
+		choice.FinishReason = finishReason(c.FinishReason)
+		usage = cs.usage
+	}
+
+	return []CompletionResponse{cs.response([]CompletionChoice{choice}, usage)}
+}
+
+// echo returns the prompt of the choice the first time it's called for the
+// choice when the request asks for the prompt to be echoed.
+func (cs *completionState) echo(index int) string {
+	if !cs.req.echo || cs.echoed[index] {
+		return ""
+	}
+
+	cs.echoed[index] = true
+
+	return cs.req.prompts[index/cs.req.n]
+}
+
+// count adds the usage of a finished choice. The choices of a prompt share
+// the prompt, so its tokens are only counted once.
+func (cs *completionState) count(index int, u model.Usage) {
+	prompt := index / cs.req.n
+	if !cs.counted[prompt] {
+		cs.counted[prompt] = true
+		cs.usage.PromptTokens += u.PromptTokens
+		cs.usage.PromptTokensDetails.CachedTokens += u.PromptTokensDetails.CachedTokens
+	}
+
+	cs.usage.ReasoningTokens += u.ReasoningTokens
+	cs.usage.CompletionTokens += u.CompletionTokens
+	cs.usage.OutputTokens += u.OutputTokens
+	cs.usage.TotalTokens = cs.usage.PromptTokens + cs.usage.OutputTokens
+}
+
+// finishReason maps the reason the model finished a choice to the reasons of
+// a completion, which are the max tokens or a stop.
+func finishReason(reason string) string {
+	if reason == model.FinishReasonLength {
+		return model.FinishReasonLength
+	}
+
+	return model.FinishReasonStop
+}
+
+func (cs *completionState) response(choices []CompletionChoice, usage model.Usage) CompletionResponse {
+	return CompletionResponse{
+		ID:      cs.id,
+		Object:  model.ObjectTextCompletion,
+		Created: cs.created,
+		Model:   cs.modelID,
+		Choices: choices,
+		Usage:   usage,
+	}
+}
@@ -0,0 +1,106 @@
+package kronk
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+func Test_CompletionRequest(t *testing.T) {
+	d := model.D{
+		"prompt":     []any{"a", "b"},
+		"n":          float64(2),
+		"echo":       true,
+		"max_tokens": float64(8),
+		"logprobs":   float64(3),
+	}
+
+	req, err := parseCompletionRequest(d)
+	if err != nil {
+		t.Fatalf("parse: %s", err)
+	}
+
+	if len(req.docs) != 2 || req.n != 2 || !req.echo {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+
+	for i, pd := range req.docs {
+		if pd["prompt"] != req.prompts[i] {
+			t.Errorf("doc %d: expected prompt %q, got %v", i, req.prompts[i], pd["prompt"])
+		}
+
+		if _, exists := pd["echo"]; exists {
+			t.Errorf("doc %d: expected echo to be removed", i)
+		}
+
+		if pd["logprobs"] != true || pd["top_logprobs"] != 3 {
+			t.Errorf("doc %d: expected logprobs with 3 top logprobs, got %v and %v", i, pd["logprobs"], pd["top_logprobs"])
+		}
+	}
+
+	if _, err := parseCompletionRequest(model.D{"prompt": []any{1}}); err == nil {
+		t.Error("expected an error for a prompt that isn't a string")
+	}
+}
+
+func Test_CompletionState(t *testing.T) {
+	req, err := parseCompletionRequest(model.D{
+		"prompt":     []any{"a", "b"},
+		"echo":       true,
+		"max_tokens": 2,
+	})
+	if err != nil {
+		t.Fatalf("parse: %s", err)
+	}
+
+	cs := newCompletionState("test", req)
+
+	delta := func(index int, text string) model.ChatResponse {
+		return model.ChatResponse{
+			Choice: []model.Choice{{Index: index, Delta: &model.ResponseMessage{Content: text}}},
+		}
+	}
+
+	final := func(index int, completion int, reason string) model.ChatResponse {
+		return model.ChatResponse{
+			Choice: []model.Choice{{Index: index, FinishReason: reason}},
+			Usage:  model.Usage{PromptTokens: 3, CompletionTokens: completion, OutputTokens: completion},
+		}
+	}
+
+	var texts [2]string
+	var reasons [2]string
+	var last CompletionResponse
+
+	for _, resp := range []model.ChatResponse{
+		delta(0, " x"),
+		delta(0, " y"),
+		final(0, 2, model.FinishReasonLength),
+		final(1, 0, model.FinishReasonStop),
+	} {
+		for _, cr := range cs.process(resp) {
+			c := cr.Choices[0]
+			texts[c.Index] += c.Text
+			if c.FinishReason != "" {
+				reasons[c.Index] = c.FinishReason
+			}
+			last = cr
+		}
+	}
+
+	if texts[0] != "a x y" || texts[1] != "b" {
+		t.Errorf("expected the echoed prompts, got %q and %q", texts[0], texts[1])
+	}
+
+	if reasons[0] != model.FinishReasonLength || reasons[1] != model.FinishReasonStop {
+		t.Errorf("expected length and stop, got %s and %s", reasons[0], reasons[1])
+	}
+
+	if last.Usage.PromptTokens != 6 || last.Usage.CompletionTokens != 2 || last.Usage.TotalTokens != 8 {
+		t.Errorf("expected the usage of both prompts, got %+v", last.Usage)
+	}
+
+	if last.Object != model.ObjectTextCompletion {
+		t.Errorf("expected object %s, got %s", model.ObjectTextCompletion, last.Object)
+	}
+}
@@ -13,11 +13,11 @@ type nonStreamingFunc[T any] func(llama *model.Model) (T, error)
 func nonStreaming[T any](ctx context.Context, krn *Kronk, f nonStreamingFunc[T]) (T, error) {
 	var zero T
 
-	mdl, err := krn.acquireModel(ctx)
+	mdl, release, err := krn.acquireModel(ctx)
 	if err != nil {
 		return zero, err
 	}
-	defer krn.releaseModel(mdl)
+	defer release()
 
 	return f(mdl)
 }
@@ -28,7 +28,7 @@ type streamingFunc[T any] func(llama *model.Model) <-chan T
 type errorFunc[T any] func(err error) T
 
 func streaming[T any](ctx context.Context, krn *Kronk, f streamingFunc[T], ef errorFunc[T]) (<-chan T, error) {
-	mdl, err := krn.acquireModel(ctx)
+	mdl, release, err := krn.acquireModel(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +42,7 @@ func streaming[T any](ctx context.Context, krn *Kronk, f streamingFunc[T], ef er
 			}
 
 			close(ch)
-			krn.releaseModel(mdl)
+			release()
 		}()
 
 		lch := f(mdl)
@@ -99,7 +99,7 @@ type streamProcessor[T, U any] struct {
 }
 
 func streamingWith[T, U any](ctx context.Context, krn *Kronk, f streamingFunc[T], p streamProcessor[T, U], ef errorFunc[U]) (<-chan U, error) {
-	mdl, err := krn.acquireModel(ctx)
+	mdl, release, err := krn.acquireModel(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +119,7 @@ func streamingWith[T, U any](ctx context.Context, krn *Kronk, f streamingFunc[T]
 			}
 
 			close(ch)
-			krn.releaseModel(mdl)
+			release()
 		}()
 
 		for _, msg := range p.Start() {
@@ -16,9 +16,17 @@ import (
 //   - truncate (bool): if true, truncate input to fit context window (default: false)
 //   - truncate_direction (string): "right" (default) or "left"
 //   - dimensions (int): reduce output to first N dimensions (for Matryoshka models)
+//   - pooling (string): "mean", "cls" or "last" (default: the model's pooling)
+//   - normalize (bool): if false, return the vectors without L2 normalization (default: true)
+//   - encoding_format (string): "float" (default) or "base64"
+//   - chunking (bool): if true, split long inputs into overlapping token windows (default: false)
+//   - chunk_size (int): tokens per window (default: the largest input the model can embed)
+//   - chunk_overlap (int): tokens shared by consecutive windows (default: a tenth of chunk_size)
+//   - chunk_aggregate (string): "mean" (default), "weighted" by window length, or "none"
+//     to return each window's vector with its token offsets
 //
-// Each model instance processes calls sequentially (llama.cpp only supports
-// sequence 0 for embedding extraction). Use NSeqMax > 1 to create multiple
+// Each model instance processes calls sequentially, packing the inputs of a
+// call into as few forward passes as possible. Use NSeqMax > 1 to create multiple
 // model instances for concurrent request handling. Batch multiple texts in the
 // input parameter for better performance within a single request.
 func (krn *Kronk) Embeddings(ctx context.Context, d model.D) (model.EmbedReponse, error) {
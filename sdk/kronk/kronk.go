@@ -20,9 +20,10 @@ const Version = "1.13.9"
 // =============================================================================
 
 type options struct {
-	tr         model.TemplateRetriever
-	ctx        context.Context
-	queueDepth int
+	tr                model.TemplateRetriever
+	ctx               context.Context
+	queueDepth        int
+	subjectQueueLimit int
 }
 
 // Option represents options for configuring Kronk.
@@ -46,7 +47,8 @@ func WithContext(ctx context.Context) Option {
 // WithQueueDepth sets the multiplier for semaphore capacity when using the
 // batch engine (NSeqMax > 1). This controls how many requests can queue while
 // the current batch is processing. Default is 2, meaning NSeqMax * 2 requests
-// can be in-flight. Only applies to text inference models.
+// can be in-flight. The batch engine gives the in-flight requests a slot by
+// the same priority and subject turns. Only applies to text inference models.
 func WithQueueDepth(multiplier int) Option {
 	return func(o *options) {
 		if multiplier > 0 {
@@ -55,6 +57,17 @@ func WithQueueDepth(multiplier int) Option {
 	}
 }
 
+// WithSubjectQueueLimit sets the maximum number of requests a subject can have
+// waiting for a slot. Requests beyond the limit fail with ErrQueueFull. The
+// subject is set with SetSchedule. Default is 0, meaning no limit.
+func WithSubjectQueueLimit(limit int) Option {
+	return func(o *options) {
+		if limit > 0 {
+			o.subjectQueueLimit = limit
+		}
+	}
+}
+
 // =============================================================================
 
 // Kronk provides a concurrently safe api for using llama.cpp to access models.
@@ -62,7 +75,7 @@ type Kronk struct {
 	cfg           model.Config
 	models        []*model.Model
 	pool          chan *model.Model
-	sem           chan struct{}
+	sched         *scheduler
 	activeStreams atomic.Int32
 	shutdown      sync.Mutex
 	shutdownFlag  bool
@@ -158,7 +171,7 @@ func New(cfg model.Config, opts ...Option) (*Kronk, error) {
 		cfg:       firstModel.Config(),
 		models:    models,
 		pool:      pool,
-		sem:       make(chan struct{}, semCapacity),
+		sched:     newScheduler(semCapacity, o.subjectQueueLimit),
 		modelInfo: mi,
 	}
 
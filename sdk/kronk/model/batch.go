@@ -2,7 +2,10 @@ package model
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -27,7 +30,16 @@ type chatJob struct {
 	media   [][]byte
 	params  params
 	mtmdCtx mtmd.Context
+	session *sessionState
 	ch      chan<- ChatResponse
+
+	// raw is set for completion requests. The output isn't parsed for
+	// reasoning or tool calls and is returned as generated.
+	raw bool
+
+	// nActive is the number of slots generating a choice for the job. The
+	// channel is closed when the last of them finishes.
+	nActive int
 }
 
 // slot represents a processing slot for parallel inference.
@@ -36,12 +48,18 @@ type slot struct {
 	seqID llama.SeqId
 
 	job     *chatJob
+	choice  int
 	proc    *processor
 	sampler llama.Sampler
 
-	nPast    llama.Pos
-	nPrompt  int
-	nDecoded int
+	// forks holds the slots generating the other choices of the job. They
+	// wait for this slot to process the prompt and then copy its KV cache.
+	forks []*slot
+
+	nPast     llama.Pos
+	nPrompt   int
+	nDecoded  int
+	truncated bool
 
 	reasonTokens     int
 	completionTokens int
@@ -55,24 +73,85 @@ type slot struct {
 	finalReasoning strings.Builder
 	finalTooling   strings.Builder
 	respToolCalls  []ResponseToolCall
+	logprobs       []ContentLogprob
+	nSentLogprobs  int
+	stop           stopMatcher
 
 	startTime   time.Time
 	span        trace.Span
 	iBatch      int32
 	sampled     llama.Token
+	drafted     []llama.Token
 	active      bool
 	prefillDone bool
 
 	prefillTokens []llama.Token
 	nPrefilled    int
+
+	// chunkStart holds the prefill state from before the chunk in the current
+	// batch, so the chunk can be undone when the batch fails to decode.
+	chunkStart prefillState
+
+	// cachedTokens holds the tokens whose KV entries are currently stored in
+	// this slot's sequence. It survives reset so the next job assigned to the
+	// slot can reuse any shared prefix instead of prefilling it again.
+	cachedTokens []llama.Token
+	nCached      int
+
+	// sessionID is the session the cached tokens belong to. When sessionDirty
+	// is set, the state changed since it was last written to disk.
+	sessionID    string
+	sessionDirty bool
+}
+
+// unsentLogprobs returns the log probabilities of the content tokens that
+// haven't been sent in a delta yet.
+func (s *slot) unsentLogprobs() *Logprobs {
+	if !s.job.params.Logprobs || s.nSentLogprobs == len(s.logprobs) {
+		return nil
+	}
+
+	logprobs := Logprobs{Content: s.logprobs[s.nSentLogprobs:]}
+	s.nSentLogprobs = len(s.logprobs)
+
+	return &logprobs
+}
+
+// acceptDraft moves the slot past the accepted draft tokens. The positions of
+// the rejected ones are handed back so they can be used again.
+func (s *slot) acceptDraft(draft []llama.Token, nAccepted int) {
+	s.nPast -= llama.Pos(len(draft) - nAccepted)
+	s.cachedTokens = append(s.cachedTokens, draft[:nAccepted]...)
+}
+
+// rollbackStep undoes the sampled token and the draft tokens that were added
+// to a batch that failed to decode.
+func (s *slot) rollbackStep() {
+	s.nPast -= llama.Pos(1 + len(s.drafted))
+	s.cachedTokens = s.cachedTokens[:len(s.cachedTokens)-1]
+	s.nDecoded--
+	s.drafted = nil
+	s.iBatch = -1
+}
+
+// rollbackChunk undoes the prefill chunk that was added to a batch that failed
+// to decode, so the same tokens are added again on the next iteration.
+func (s *slot) rollbackChunk() {
+	s.prefillTokens = s.chunkStart.tokens
+	s.nPrefilled = s.chunkStart.nPrefilled
+	s.nPast = s.chunkStart.nPast
+	s.cachedTokens = s.cachedTokens[:s.chunkStart.nCached]
+	s.iBatch = -1
 }
 
 func (s *slot) reset() {
-	s.seqID = -1
 	s.job = nil
+	s.choice = 0
+	s.forks = nil
 	s.nPast = 0
 	s.nPrompt = 0
 	s.nDecoded = 0
+	s.truncated = false
 	s.reasonTokens = 0
 	s.completionTokens = 0
 	s.reasonFlag = 0
@@ -83,19 +162,33 @@ func (s *slot) reset() {
 	s.finalReasoning.Reset()
 	s.finalTooling.Reset()
 	s.respToolCalls = nil
+	s.logprobs = nil
+	s.nSentLogprobs = 0
+	s.stop = stopMatcher{}
 	s.span = nil
 	s.iBatch = -1
 	s.sampled = 0
+	s.drafted = nil
 	s.active = false
 	s.prefillDone = false
 	s.prefillTokens = nil
 	s.nPrefilled = 0
+	s.chunkStart = prefillState{}
+	s.nCached = 0
 
 	if s.proc != nil {
 		s.proc.resetState()
 	}
 }
 
+// prefillState is the prefill progress of a slot.
+type prefillState struct {
+	tokens     []llama.Token
+	nPrefilled int
+	nPast      llama.Pos
+	nCached    int
+}
+
 // batchEngine manages parallel inference slots.
 type batchEngine struct {
 	model      *Model
@@ -103,6 +196,12 @@ type batchEngine struct {
 	slots      []*slot
 	batch      llama.Batch
 	requestQ   chan *chatJob
+	waiting    []*chatJob
+	pending    *chatJob
+	turn       uint64
+	served     map[string]uint64
+	prefilling []*slot
+	cmdQ       chan func()
 	shutdownCh chan struct{}
 	wg         sync.WaitGroup
 	stopped    atomic.Bool
@@ -131,6 +230,8 @@ func newBatchEngine(m *Model, nSlots int) *batchEngine {
 		slots:      slots,
 		batch:      batch,
 		requestQ:   make(chan *chatJob, nSlots*2),
+		served:     make(map[string]uint64),
+		cmdQ:       make(chan func()),
 		shutdownCh: make(chan struct{}),
 	}
 }
@@ -151,6 +252,23 @@ func (e *batchEngine) stop(ctx context.Context) {
 	close(e.shutdownCh)
 	e.wg.Wait()
 
+	// Write any session state that hasn't been saved before the KV cache
+	// is released.
+	for _, s := range e.slots {
+		if s.sessionID == "" || !s.sessionDirty || e.model.sessions == nil {
+			continue
+		}
+
+		state, err := e.snapshot(s)
+		if err == nil {
+			err = e.model.sessions.save(s.sessionID, state)
+		}
+
+		if err != nil {
+			e.model.log(ctx, "batch-engine", "status", "save-session-error", "session", s.sessionID, "err", err)
+		}
+	}
+
 	// Free samplers - batch is freed separately in Unload.
 	for _, s := range e.slots {
 		if s.sampler != 0 {
@@ -205,6 +323,10 @@ func (e *batchEngine) processLoop(ctx context.Context) {
 			e.drainSlots()
 			return
 
+		case cmd := <-e.cmdQ:
+			// Commands need exclusive access to the model context.
+			cmd()
+
 		case job := <-e.requestQ:
 			// Requeue job for fillSlots to handle in correct order
 			// (after batchClear but before decode).
@@ -215,7 +337,7 @@ func (e *batchEngine) processLoop(ctx context.Context) {
 			timer.Reset(0)
 
 		case <-timer.C:
-			switch e.hasActiveSlots() || len(e.requestQ) > 0 {
+			switch e.hasActiveSlots() || e.pending != nil || len(e.waiting) > 0 || len(e.requestQ) > 0 {
 			case true:
 				e.processBatch(ctx, buf)
 				timer.Reset(activeInterval)
@@ -241,6 +363,7 @@ func (e *batchEngine) hasActiveSlots() bool {
 func (e *batchEngine) processBatch(ctx context.Context, buf []byte) {
 	// Clear the batch.
 	batchClear(&e.batch)
+	e.prefilling = e.prefilling[:0]
 
 	// Continue prefill for slots that are still prefilling.
 	for _, s := range e.slots {
@@ -258,6 +381,7 @@ func (e *batchEngine) processBatch(ctx context.Context, buf []byte) {
 	}
 
 	// Add tokens from active slots that have completed prefill.
+	var generating []*slot
 	for _, s := range e.slots {
 		if !s.active || !s.prefillDone {
 			continue
@@ -269,10 +393,21 @@ func (e *batchEngine) processBatch(ctx context.Context, buf []byte) {
 			continue
 		}
 
+		generating = append(generating, s)
+
 		s.iBatch = e.batch.NTokens
 		batchAdd(&e.batch, s.sampled, s.nPast, []llama.SeqId{s.seqID}, true)
+		s.cachedTokens = append(s.cachedTokens, s.sampled)
 		s.nPast++
 		s.nDecoded++
+
+		// Add the tokens proposed by the draft model so the main model
+		// can verify them in this decode.
+		s.drafted = e.draft(s)
+		for _, token := range s.drafted {
+			batchAdd(&e.batch, token, s.nPast, []llama.SeqId{s.seqID}, true)
+			s.nPast++
+		}
 	}
 
 	// Fill empty slots from queue.
@@ -287,9 +422,29 @@ func (e *batchEngine) processBatch(ctx context.Context, buf []byte) {
 	ret, err := llama.Decode(e.model.lctx, e.batch)
 	if err != nil || ret != 0 {
 		e.model.log(ctx, "batch-engine", "status", "decode-error", "ret", ret, "err", err)
+
+		// Undo this step so the slots add the same tokens again on the
+		// next iteration.
+		for _, s := range generating {
+			s.rollbackStep()
+			llama.MemorySeqRm(e.model.mem, s.seqID, s.nPast, -1)
+		}
+
+		for _, s := range e.prefilling {
+			s.rollbackChunk()
+			llama.MemorySeqRm(e.model.mem, s.seqID, s.nPast, -1)
+		}
+
 		return
 	}
 
+	// Hand the processed prompt to the slots generating the other choices.
+	for _, s := range e.slots {
+		if s.active && s.iBatch >= 0 && len(s.forks) > 0 {
+			e.fork(s)
+		}
+	}
+
 	// Sample tokens for each active slot.
 	for _, s := range e.slots {
 		if s.iBatch < 0 || !s.active {
@@ -302,25 +457,206 @@ func (e *batchEngine) processBatch(ctx context.Context, buf []byte) {
 
 // fillSlots assigns pending requests to available slots.
 func (e *batchEngine) fillSlots() {
+	// A job waiting for enough idle slots goes before anything else in
+	// the queue.
+	job := e.pending
+	e.pending = nil
+
+	if job == nil {
+		if !e.hasIdleSlots() {
+			return
+		}
+
+		job = e.nextJob()
+		if job == nil {
+			return
+		}
+	}
+
+	if job.ctx.Err() != nil {
+		e.failJob(job, job.ctx.Err())
+		return
+	}
+
+	// Every choice of the job needs its own slot.
+	if e.idleSlots() < job.params.N {
+		e.pending = job
+		return
+	}
+
+	job.nActive = 1
+
+	tokens := llama.Tokenize(e.model.vocab, job.prompt, true, true)
+	s := e.pickSlot(tokens)
+	e.startSlot(s, job, tokens)
+
+	if !s.active {
+		return
+	}
+
+	for i := 1; i < job.params.N; i++ {
+		e.startFork(s, e.pickSlot(nil), i)
+	}
+
+	// Only prefill one slot per iteration to avoid exceeding NBatch.
+}
+
+// nextJob removes the job to start next from the waiting jobs. Jobs in a
+// higher priority class go first. Within a class, the subject that was served
+// least recently goes first, so the subjects take turns.
+func (e *batchEngine) nextJob() *chatJob {
+	for len(e.requestQ) > 0 {
+		e.waiting = append(e.waiting, <-e.requestQ)
+	}
+
+	if len(e.waiting) == 0 {
+		return nil
+	}
+
+	next := 0
+	nextSch := getSchedule(e.waiting[0].ctx)
+
+	for i, job := range e.waiting[1:] {
+		sch := getSchedule(job.ctx)
+
+		switch {
+		case sch.priority > nextSch.priority,
+			sch.priority == nextSch.priority && e.served[sch.subject] < e.served[nextSch.subject]:
+			next, nextSch = i+1, sch
+		}
+	}
+
+	job := e.waiting[next]
+	e.waiting = slices.Delete(e.waiting, next, next+1)
+
+	// The turns only matter while jobs are waiting.
+	e.turn++
+	e.served[nextSch.subject] = e.turn
+	if len(e.waiting) == 0 {
+		clear(e.served)
+	}
+
+	return job
+}
+
+// hasIdleSlots returns true if any slot is available for a new request.
+func (e *batchEngine) hasIdleSlots() bool {
+	for _, s := range e.slots {
+		if !s.active {
+			return true
+		}
+	}
+	return false
+}
+
+// idleSlots returns the number of slots available for a new request.
+func (e *batchEngine) idleSlots() int {
+	var n int
+	for _, s := range e.slots {
+		if !s.active {
+			n++
+		}
+	}
+	return n
+}
+
+// pickSlot selects the idle slot that should process the specified tokens.
+// The slot already holding the longest matching prefix wins. When no slot
+// shares a prefix, the slot holding the least cached data is picked so the
+// caches of other slots survive for future requests.
+func (e *batchEngine) pickSlot(tokens []llama.Token) *slot {
+	var best *slot
+	bestMatch := -1
+
 	for _, s := range e.slots {
 		if s.active {
 			continue
 		}
 
-		// Try to get a request from the queue.
-		select {
-		case job := <-e.requestQ:
-			e.startSlot(s, job)
-			return // Only prefill one slot per iteration to avoid exceeding NBatch
+		n := commonPrefix(s.cachedTokens, tokens)
 
-		default:
-			return
+		switch {
+		case best == nil:
+			best, bestMatch = s, n
+
+		case n > bestMatch:
+			best, bestMatch = s, n
+
+		case n == bestMatch && len(s.cachedTokens) < len(best.cachedTokens):
+			best = s
 		}
 	}
+
+	return best
+}
+
+// reusePrefix prepares the slot's sequence for the specified tokens by keeping
+// the KV entries of the longest prefix already computed, either by this slot
+// or by another slot whose entries are copied over. The remaining entries are
+// removed from the sequence. It returns the number of reused tokens.
+func (e *batchEngine) reusePrefix(s *slot, tokens []llama.Token) int {
+	mem := e.model.mem
+
+	src := s
+	nCommon := commonPrefix(s.cachedTokens, tokens)
+
+	for _, o := range e.slots {
+		if o == s {
+			continue
+		}
+
+		// Only tokens that have been decoded can be shared. For an active
+		// slot that is the prompt once its prefill is complete.
+		committed := o.cachedTokens
+		if o.active {
+			if !o.prefillDone {
+				continue
+			}
+			committed = committed[:min(o.nPrompt, len(committed))]
+		}
+
+		if n := commonPrefix(committed, tokens); n > nCommon {
+			src, nCommon = o, n
+		}
+	}
+
+	// At least one token must be decoded to produce logits for sampling.
+	nCommon = min(nCommon, len(tokens)-1)
+
+	switch {
+	case nCommon <= 0:
+		llama.MemorySeqRm(mem, s.seqID, -1, -1)
+		nCommon = 0
+
+	case src == s:
+		if ok, err := llama.MemorySeqRm(mem, s.seqID, llama.Pos(nCommon), -1); !ok || err != nil {
+			// Some memory types can't remove a partial range.
+			llama.MemorySeqRm(mem, s.seqID, -1, -1)
+			nCommon = 0
+		}
+
+	default:
+		llama.MemorySeqRm(mem, s.seqID, -1, -1)
+		if err := llama.MemorySeqCp(mem, src.seqID, s.seqID, 0, llama.Pos(nCommon)); err != nil {
+			nCommon = 0
+			break
+		}
+
+		// Not every memory type supports a partial copy, so trim anything
+		// past the shared prefix.
+		if ok, err := llama.MemorySeqRm(mem, s.seqID, llama.Pos(nCommon), -1); !ok || err != nil {
+			llama.MemorySeqRm(mem, s.seqID, -1, -1)
+			nCommon = 0
+		}
+	}
+
+	s.cachedTokens = append(s.cachedTokens[:0], tokens[:nCommon]...)
+
+	return nCommon
 }
 
 // startSlot initializes a slot with a new request.
-func (e *batchEngine) startSlot(s *slot, job *chatJob) {
+func (e *batchEngine) startSlot(s *slot, job *chatJob, tokens []llama.Token) {
 	s.reset()
 	s.active = true
 	s.job = job
@@ -335,9 +671,12 @@ func (e *batchEngine) startSlot(s *slot, job *chatJob) {
 
 	// Create sampler for this request.
 	s.sampler = e.model.toSampler(job.params)
+	s.stop = newStopMatcher(job.params.Stop)
+
+	if job.params.Grammar != "" && e.model.modelInfo.IsGPTModel {
+		s.proc.startFinal()
+	}
 
-	// Tokenize the prompt.
-	tokens := llama.Tokenize(e.model.vocab, job.prompt, true, true)
 	s.nPrompt = len(tokens)
 
 	// Check context window.
@@ -348,14 +687,89 @@ func (e *batchEngine) startSlot(s *slot, job *chatJob) {
 		return
 	}
 
-	// Store tokens for chunked prefill.
-	s.prefillTokens = tokens
+	// The slot can only hold the cache for one session.
+	if s.sessionID != job.params.SessionID {
+		e.releaseSession(job.ctx, s)
+	}
+
+	if job.params.SessionID != "" {
+		e.restoreSession(job.ctx, s, job, tokens)
+		e.bindSession(s, job.params.SessionID)
+	}
+
+	// Reuse any prefix already held in the KV cache.
+	s.nCached = e.reusePrefix(s, tokens)
+	s.nPast = llama.Pos(s.nCached)
+
+	// Store the remaining tokens for chunked prefill.
+	s.prefillTokens = tokens[s.nCached:]
 	s.nPrefilled = 0
 
 	// Add first chunk of prompt tokens to batch.
 	e.addPrefillChunk(s)
 
-	e.model.log(job.ctx, "batch-engine", "status", "slot-started", "slot", s.id, "id", job.id, "prompt_tokens", s.nPrompt)
+	e.model.log(job.ctx, "batch-engine", "status", "slot-started", "slot", s.id, "id", job.id, "prompt_tokens", s.nPrompt, "cached_tokens", s.nCached)
+}
+
+// startFork assigns a slot to generate another choice for the job of the
+// parent slot. The slot waits until the parent has processed the prompt.
+func (e *batchEngine) startFork(parent *slot, s *slot, choice int) {
+	job := parent.job
+
+	// The slot's cache is replaced by the parent's, so the session it
+	// holds has to be released first.
+	e.releaseSession(job.ctx, s)
+
+	s.reset()
+	s.active = true
+	s.job = job
+	s.choice = choice
+	s.startTime = time.Now()
+	s.seqID = llama.SeqId(s.id + 1)
+	s.nPrompt = parent.nPrompt
+	s.nCached = parent.nCached
+
+	job.nActive++
+
+	_, s.span = otel.AddSpan(job.ctx, "batch-chat-request",
+		attribute.String("id", job.id),
+		attribute.Int("slot", s.id),
+		attribute.Int("choice", choice),
+	)
+
+	// Each choice needs its own seed or every choice would be the same.
+	// The llama default seed means random, so it's skipped.
+	p := job.params
+	p.Seed = (p.Seed + uint32(choice)) % llama.DefaultSeed
+	s.sampler = e.model.toSampler(p)
+	s.stop = newStopMatcher(p.Stop)
+
+	if p.Grammar != "" && e.model.modelInfo.IsGPTModel {
+		s.proc.startFinal()
+	}
+
+	parent.forks = append(parent.forks, s)
+}
+
+// fork copies the KV cache of the slot's processed prompt into the sequences
+// of its forks, which sample their first token from the same logits.
+func (e *batchEngine) fork(s *slot) {
+	mem := e.model.mem
+
+	for _, f := range s.forks {
+		llama.MemorySeqRm(mem, f.seqID, -1, -1)
+		if err := llama.MemorySeqCp(mem, s.seqID, f.seqID, -1, -1); err != nil {
+			f.cachedTokens = f.cachedTokens[:0]
+			e.finishSlot(f, fmt.Errorf("fork: unable to copy prompt cache: %w", err))
+			continue
+		}
+
+		f.cachedTokens = append(f.cachedTokens[:0], s.cachedTokens...)
+		f.nPast = s.nPast
+		f.iBatch = s.iBatch
+	}
+
+	s.forks = nil
 }
 
 // addPrefillChunk adds the next chunk of prefill tokens to the batch.
@@ -367,15 +781,28 @@ func (e *batchEngine) addPrefillChunk(s *slot) {
 
 	prefillStart := time.Now()
 
-	nBatch := e.model.cfg.NBatch
+	// The chunk only gets the space left in the batch.
 	remaining := len(s.prefillTokens) - s.nPrefilled
-	chunkSize := min(remaining, nBatch)
+	chunkSize := min(remaining, e.batchSpace())
+	if chunkSize <= 0 {
+		s.iBatch = -1
+		return
+	}
+
+	s.chunkStart = prefillState{
+		tokens:     s.prefillTokens,
+		nPrefilled: s.nPrefilled,
+		nPast:      s.nPast,
+		nCached:    len(s.cachedTokens),
+	}
+	e.prefilling = append(e.prefilling, s)
 
 	// Add chunk of tokens to batch.
 	for i := 0; i < chunkSize; i++ {
 		tok := s.prefillTokens[s.nPrefilled+i]
 		isLast := s.nPrefilled+i == len(s.prefillTokens)-1
 		batchAdd(&e.batch, tok, s.nPast, []llama.SeqId{s.seqID}, isLast)
+		s.cachedTokens = append(s.cachedTokens, tok)
 		s.nPast++
 	}
 	s.nPrefilled += chunkSize
@@ -393,12 +820,69 @@ func (e *batchEngine) addPrefillChunk(s *slot) {
 	}
 }
 
-// processSlotToken handles a sampled token for a slot.
+// draft returns the tokens proposed by the draft model for the slot. The
+// number of tokens is bounded by the space left in the batch, the context
+// window and the max tokens of the request.
+func (e *batchEngine) draft(s *slot) []llama.Token {
+	d := e.model.draft
+	if d == nil {
+		return nil
+	}
+
+	n := min(
+		d.nDraft,
+		e.batchSpace(),
+		e.model.cfg.ContextWindow-int(s.nPast)-1,
+		s.job.params.MaxTokens-s.nDecoded,
+	)
+
+	return d.propose(s.seqID, s.cachedTokens, n)
+}
+
+// batchSpace returns the number of tokens that can still be added to the batch.
+func (e *batchEngine) batchSpace() int {
+	return e.model.cfg.NBatch - int(e.batch.NTokens)
+}
+
+// processSlotToken handles the sampled tokens for a slot.
 func (e *batchEngine) processSlotToken(s *slot, buf []byte) {
-	// Sample the next token.
-	token := llama.SamplerSample(s.sampler, e.model.lctx, s.iBatch)
-	llama.SamplerAccept(s.sampler, token)
+	if len(s.drafted) == 0 {
+		// Sampling also accepts the token into the sampler state, so
+		// accepting it again would advance stateful samplers like the
+		// grammar twice.
+		token := llama.SamplerSample(s.sampler, e.model.lctx, s.iBatch)
+		e.processToken(s, token, s.iBatch, buf)
+		return
+	}
+
+	draft := s.drafted
+	s.drafted = nil
+
+	iBatch := s.iBatch
+	tokens := verifyDraft(func(i int32) llama.Token {
+		return llama.SamplerSample(s.sampler, e.model.lctx, i)
+	}, iBatch, draft)
+
+	// Only keep the KV entries for the draft tokens that were accepted.
+	s.acceptDraft(draft, len(tokens)-1)
+	llama.MemorySeqRm(e.model.mem, s.seqID, s.nPast, -1)
+
+	for i, token := range tokens {
+		// Every accepted token before this one was decoded.
+		if i > 0 {
+			s.nDecoded++
+		}
+
+		e.processToken(s, token, iBatch+int32(i), buf)
+		if !s.active {
+			return
+		}
+	}
+}
 
+// processToken handles a token for a slot that was sampled from the logits at
+// index i of the batch.
+func (e *batchEngine) processToken(s *slot, token llama.Token, i int32, buf []byte) {
 	// Check for end of generation.
 	if llama.VocabIsEOG(e.model.vocab, token) {
 		e.finishSlot(s, nil)
@@ -423,8 +907,11 @@ func (e *batchEngine) processSlotToken(s *slot, buf []byte) {
 	var resp response
 	var eog bool
 
-	switch isGPT {
-	case true:
+	switch {
+	case s.job.raw:
+		resp = response{status: statusCompletion, content: content}
+
+	case isGPT:
 		resp, eog = s.proc.stepGPT(content)
 
 	default:
@@ -464,40 +951,57 @@ func (e *batchEngine) processSlotToken(s *slot, buf []byte) {
 	outputTokens := s.reasonTokens + s.completionTokens
 	tokensPerSecond := float64(outputTokens) / elapsedSeconds
 
+	text := resp.content
+	var stopped bool
+
 	// Stream response if not tooling.
 	if s.toolFlag == 0 {
 		// Skip unnecessary CRLF at mode transitions.
-		if e.model.isUnncessaryCRLF(s.reasonFlag, s.completionFlag, resp.content) {
+		if !s.job.raw && e.model.isUnncessaryCRLF(s.reasonFlag, s.completionFlag, resp.content) {
 			s.iBatch = -1
 			return
 		}
 
+		// Completion content is checked for stop strings, which can hold
+		// back text until the next token shows it isn't a stop string.
+		if s.reasonFlag == 0 {
+			text, stopped = s.stop.process(text)
+		}
+
 		usage := Usage{
-			PromptTokens:     s.nPrompt,
-			ReasoningTokens:  s.reasonTokens,
-			CompletionTokens: s.completionTokens,
-			OutputTokens:     outputTokens,
-			TotalTokens:      s.nPrompt + outputTokens,
-			TokensPerSecond:  tokensPerSecond,
+			PromptTokens:        s.nPrompt,
+			PromptTokensDetails: PromptTokensDetails{CachedTokens: s.nCached},
+			ReasoningTokens:     s.reasonTokens,
+			CompletionTokens:    s.completionTokens,
+			OutputTokens:        outputTokens,
+			TotalTokens:         s.nPrompt + outputTokens,
+			TokensPerSecond:     tokensPerSecond,
 		}
 
-		err := e.model.sendDeltaResponse(s.job.ctx, s.job.ch, s.job.id, s.job.object, 0, "", resp.content, s.reasonFlag, usage)
-		if err != nil {
-			e.finishSlot(s, err)
-			return
+		// Only completion content carries log probabilities.
+		if s.job.params.Logprobs && s.reasonFlag == 0 {
+			s.logprobs = append(s.logprobs, e.model.tokenLogprob(e.model.lctx, i, token, s.job.params.TopLogprobs))
+		}
+
+		if text != "" {
+			err := e.model.sendDeltaResponse(s.job.ctx, s.job.ch, s.job.id, s.job.object, s.choice, "", text, s.reasonFlag, usage, s.job.params.Seed, s.unsentLogprobs())
+			if err != nil {
+				e.finishSlot(s, err)
+				return
+			}
 		}
 	}
 
 	// Store content for final response.
 	switch {
 	case s.reasonFlag > 0:
-		s.finalReasoning.WriteString(resp.content)
+		s.finalReasoning.WriteString(text)
 
 	case s.toolFlag > 0:
-		s.finalTooling.WriteString(resp.content)
+		s.finalTooling.WriteString(text)
 
 	default:
-		s.finalContent.WriteString(resp.content)
+		s.finalContent.WriteString(text)
 	}
 
 	// Update token counts.
@@ -509,8 +1013,14 @@ func (e *batchEngine) processSlotToken(s *slot, buf []byte) {
 		s.completionTokens++
 	}
 
+	if stopped {
+		e.finishSlot(s, nil)
+		return
+	}
+
 	// Check max tokens.
 	if s.nDecoded >= s.job.params.MaxTokens {
+		s.truncated = true
 		e.finishSlot(s, nil)
 		return
 	}
@@ -524,47 +1034,73 @@ func (e *batchEngine) finishSlot(s *slot, err error) {
 		return
 	}
 
+	// Forks still waiting on the prompt can't generate their choices.
+	for _, f := range s.forks {
+		forkErr := err
+		if forkErr == nil {
+			forkErr = errors.New("finish-slot: prompt processing stopped")
+		}
+		e.finishSlot(f, forkErr)
+	}
+
 	defer func() {
-		close(s.job.ch)
+		job := s.job
 		s.span.End()
 		s.reset()
 		e.freeSlotResources(s)
-		e.model.activeStreams.Add(-1)
+		e.endJob(job)
 	}()
 
 	ctx := s.job.ctx
 	elapsed := time.Since(s.startTime)
 
-	// Clear KV cache for this slot's sequence.
-	llama.MemorySeqRm(e.model.mem, s.seqID, -1, -1)
-
 	// Handle error case.
 	if err != nil {
+		// The KV cache may hold entries that were never decoded, so it
+		// can't be trusted for reuse.
+		llama.MemorySeqRm(e.model.mem, s.seqID, -1, -1)
+		s.cachedTokens = s.cachedTokens[:0]
+		s.sessionID = ""
+		s.sessionDirty = false
+
 		usage := Usage{
-			PromptTokens:     s.nPrompt,
-			ReasoningTokens:  s.reasonTokens,
-			CompletionTokens: s.completionTokens,
-			OutputTokens:     s.reasonTokens + s.completionTokens,
-			TotalTokens:      s.nPrompt + s.reasonTokens + s.completionTokens,
+			PromptTokens:        s.nPrompt,
+			PromptTokensDetails: PromptTokensDetails{CachedTokens: s.nCached},
+			ReasoningTokens:     s.reasonTokens,
+			CompletionTokens:    s.completionTokens,
+			OutputTokens:        s.reasonTokens + s.completionTokens,
+			TotalTokens:         s.nPrompt + s.reasonTokens + s.completionTokens,
 		}
 
-		e.model.sendErrorResponse(ctx, s.job.ch, s.job.id, s.job.object, 0, "", err, usage)
+		e.model.sendErrorResponse(ctx, s.job.ch, s.job.id, s.job.object, s.choice, "", err, usage)
 
 		return
 	}
 
+	if s.sessionID != "" {
+		s.sessionDirty = true
+	}
+
+	// Text the processor held back for a possible tool call marker is content
+	// after all, unless it holds a tool call that runs to the end.
+	var heldContent string
+	switch held := s.proc.flush(); held.status {
+	case statusTooling:
+		if held.content != "" {
+			s.toolFlag++
+			s.finalTooling.WriteString(held.content)
+		}
+
+	default:
+		heldContent, _ = s.stop.process(held.content)
+	}
+
 	// Process tool calls if any. Token counts are already tracked
 	// per-token in processSlotToken, so no re-tokenization needed.
 	if s.toolFlag > 0 {
 		content := strings.TrimSuffix(s.finalTooling.String(), "\n")
 		if len(content) > 0 {
-			switch {
-			case e.model.modelInfo.IsGPTModel:
-				s.respToolCalls = parseGPTToolCall(content)
-
-			default:
-				s.respToolCalls = parseToolCall(content)
-			}
+			s.respToolCalls = e.model.toolParser.parse(content)
 		}
 	}
 
@@ -574,17 +1110,19 @@ func (e *batchEngine) finishSlot(s *slot, err error) {
 	tokensPerSecond := float64(outputTokens) / elapsed.Seconds()
 
 	usage := Usage{
-		PromptTokens:     s.nPrompt,
-		ReasoningTokens:  s.reasonTokens,
-		CompletionTokens: s.completionTokens,
-		OutputTokens:     outputTokens,
-		TotalTokens:      totalTokens,
-		TokensPerSecond:  tokensPerSecond,
+		PromptTokens:        s.nPrompt,
+		PromptTokensDetails: PromptTokensDetails{CachedTokens: s.nCached},
+		ReasoningTokens:     s.reasonTokens,
+		CompletionTokens:    s.completionTokens,
+		OutputTokens:        outputTokens,
+		TotalTokens:         totalTokens,
+		TokensPerSecond:     tokensPerSecond,
 	}
 
 	// Add span attributes and end span.
 	s.span.SetAttributes(
 		attribute.Int("prompt_tokens", s.nPrompt),
+		attribute.Int("cached_tokens", s.nCached),
 		attribute.Int("reasoning_tokens", s.reasonTokens),
 		attribute.Int("completion_tokens", s.completionTokens),
 		attribute.Int("output_tokens", outputTokens),
@@ -595,17 +1133,24 @@ func (e *batchEngine) finishSlot(s *slot, err error) {
 	// Add metrics.
 	metrics.AddChatCompletionsUsage(s.nPrompt, s.reasonTokens, s.completionTokens, outputTokens, totalTokens, tokensPerSecond)
 
+	// Text held back for a possible stop string is content after all when
+	// generation ended some other way.
+	if held := heldContent + s.stop.flush(); held != "" {
+		s.finalContent.WriteString(held)
+		e.model.sendDeltaResponse(ctx, s.job.ch, s.job.id, s.job.object, s.choice, "", held, 0, usage, s.job.params.Seed, s.unsentLogprobs())
+	}
+
 	// Send final response.
 	returnPrompt := ""
 	if s.job.params.ReturnPrompt {
 		returnPrompt = s.job.prompt
 	}
 
-	e.model.sendFinalResponse(ctx, s.job.ch, s.job.id, s.job.object, 0, returnPrompt,
-		&s.finalContent, &s.finalReasoning, s.respToolCalls, usage)
+	e.model.sendFinalResponse(ctx, s.job.ch, s.job.id, s.job.object, s.choice, returnPrompt,
+		&s.finalContent, &s.finalReasoning, s.respToolCalls, s.truncated, usage, s.job.params.Seed, toLogprobs(s.job.params.Logprobs, s.logprobs))
 
-	e.model.log(ctx, "batch-engine", "status", "slot-finished", "slot", s.id, "id", s.job.id,
-		"prompt", s.nPrompt, "output", outputTokens, "time", elapsed.String())
+	e.model.log(ctx, "batch-engine", "status", "slot-finished", "slot", s.id, "id", s.job.id, "choice", s.choice,
+		"prompt", s.nPrompt, "cached", s.nCached, "output", outputTokens, "time", elapsed.String())
 }
 
 func (e *batchEngine) freeSlotResources(s *slot) {
@@ -617,8 +1162,27 @@ func (e *batchEngine) freeSlotResources(s *slot) {
 
 func (e *batchEngine) sendSlotError(s *slot, err error) {
 	usage := Usage{PromptTokens: s.nPrompt}
-	e.model.sendErrorResponse(s.job.ctx, s.job.ch, s.job.id, s.job.object, 0, "", err, usage)
-	close(s.job.ch)
+	e.model.sendErrorResponse(s.job.ctx, s.job.ch, s.job.id, s.job.object, s.choice, "", err, usage)
+	e.endJob(s.job)
+}
+
+// failJob sends the error for a job that was never assigned a slot.
+func (e *batchEngine) failJob(job *chatJob, err error) {
+	e.model.sendErrorResponse(job.ctx, job.ch, job.id, job.object, 0, "", err, Usage{})
+	job.nActive = 1
+	e.endJob(job)
+}
+
+// endJob releases the job for a slot that finished with it. Once no slot is
+// generating a choice for the job, its channel is closed.
+func (e *batchEngine) endJob(job *chatJob) {
+	job.nActive--
+	if job.nActive > 0 {
+		return
+	}
+
+	close(job.ch)
+	e.model.activeStreams.Add(-1)
 }
 
 // drainSlots finishes all active slots during shutdown.
@@ -628,11 +1192,236 @@ func (e *batchEngine) drainSlots() {
 			e.finishSlot(s, fmt.Errorf("darin-slots: engine shutting down"))
 		}
 	}
+
+	if e.pending != nil {
+		e.failJob(e.pending, fmt.Errorf("darin-slots: engine shutting down"))
+		e.pending = nil
+	}
+
+	for len(e.requestQ) > 0 {
+		e.waiting = append(e.waiting, <-e.requestQ)
+	}
+
+	for _, job := range e.waiting {
+		e.failJob(job, fmt.Errorf("darin-slots: engine shutting down"))
+	}
+	e.waiting = nil
+}
+
+// =============================================================================
+// Session helpers
+
+// sessionSlot returns the idle slot holding the cache for the session, or an
+// active one when no idle slot holds it.
+func (e *batchEngine) sessionSlot(sessionID string) *slot {
+	var found *slot
+	for _, s := range e.slots {
+		if s.sessionID != sessionID {
+			continue
+		}
+
+		if !s.active {
+			return s
+		}
+
+		found = s
+	}
+
+	return found
+}
+
+// bindSession makes the slot the only one holding the session. Another slot
+// that held it keeps its cache, but its state is no longer saved for the
+// session, so it can't overwrite the newer state of this slot.
+func (e *batchEngine) bindSession(s *slot, sessionID string) {
+	for _, o := range e.slots {
+		if o != s && o.sessionID == sessionID {
+			o.sessionID = ""
+			o.sessionDirty = false
+		}
+	}
+
+	s.sessionID = sessionID
+}
+
+// snapshot copies the KV state of the slot's sequence and the tokens it holds.
+func (e *batchEngine) snapshot(s *slot) (sessionState, error) {
+	size := llama.StateSeqGetSize(e.model.lctx, s.seqID)
+	if size == 0 {
+		return sessionState{}, fmt.Errorf("snapshot: no state for sequence[%d]", s.seqID)
+	}
+
+	data := make([]byte, size)
+	if n := llama.StateSeqGetData(e.model.lctx, data, s.seqID); n == 0 {
+		return sessionState{}, fmt.Errorf("snapshot: unable to copy state for sequence[%d]", s.seqID)
+	}
+
+	state := sessionState{
+		tokens:  slices.Clone(s.cachedTokens),
+		data:    data,
+		version: e.model.sessions.version(),
+	}
+
+	return state, nil
+}
+
+// releaseSession detaches the slot from its session, writing the state to
+// disk in the background when it changed since the last save.
+func (e *batchEngine) releaseSession(ctx context.Context, s *slot) {
+	sessionID := s.sessionID
+	dirty := s.sessionDirty
+
+	s.sessionID = ""
+	s.sessionDirty = false
+
+	if sessionID == "" || !dirty || e.model.sessions == nil {
+		return
+	}
+
+	state, err := e.snapshot(s)
+	if err != nil {
+		e.model.log(ctx, "batch-engine", "status", "save-session-error", "session", sessionID, "err", err)
+		return
+	}
+
+	go func() {
+		if err := e.model.sessions.save(sessionID, state); err != nil {
+			e.model.log(ctx, "batch-engine", "status", "save-session-error", "session", sessionID, "err", err)
+		}
+	}()
+}
+
+// restoreSession loads the session state read from disk into the slot's
+// sequence when it holds a longer prefix of the tokens than the slot does.
+func (e *batchEngine) restoreSession(ctx context.Context, s *slot, job *chatJob, tokens []llama.Token) {
+	if job.session == nil || s.sessionID == job.params.SessionID {
+		return
+	}
+
+	if commonPrefix(job.session.tokens, tokens) <= commonPrefix(s.cachedTokens, tokens) {
+		return
+	}
+
+	llama.MemorySeqRm(e.model.mem, s.seqID, -1, -1)
+
+	if n := llama.StateSeqSetData(e.model.lctx, job.session.data, s.seqID); n == 0 {
+		s.cachedTokens = s.cachedTokens[:0]
+		e.model.log(ctx, "batch-engine", "status", "restore-session-error", "session", job.params.SessionID)
+		return
+	}
+
+	s.cachedTokens = append(s.cachedTokens[:0], job.session.tokens...)
+
+	e.model.log(ctx, "batch-engine", "status", "session-restored", "slot", s.id, "session", job.params.SessionID, "tokens", len(s.cachedTokens))
+}
+
+// hasSession reports whether a slot holds the cache for the session.
+func (e *batchEngine) hasSession(ctx context.Context, sessionID string) bool {
+	var found bool
+	e.run(ctx, func() {
+		found = e.sessionSlot(sessionID) != nil
+	})
+
+	return found
+}
+
+// saveSession writes the cache held for the session to disk.
+func (e *batchEngine) saveSession(ctx context.Context, sessionID string) error {
+	var state sessionState
+	var err error
+	var found bool
+
+	cmd := func() {
+		s := e.sessionSlot(sessionID)
+
+		switch {
+		case s == nil:
+			return
+
+		case s.active:
+			found = true
+			err = fmt.Errorf("save-session: session[%s] is processing a request", sessionID)
+
+		default:
+			found = true
+			if state, err = e.snapshot(s); err == nil {
+				s.sessionDirty = false
+			}
+		}
+	}
+
+	if err := e.run(ctx, cmd); err != nil {
+		return fmt.Errorf("save-session: %w", err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The slot was released, so the state is either already on disk or
+	// the session doesn't exist.
+	if !found {
+		if _, err := os.Stat(e.model.sessions.path(sessionID)); err != nil {
+			return fmt.Errorf("save-session: session[%s] not found", sessionID)
+		}
+		return nil
+	}
+
+	return e.model.sessions.save(sessionID, state)
+}
+
+// deleteSession detaches the session from any slot holding it so the state
+// isn't written to disk again.
+func (e *batchEngine) deleteSession(ctx context.Context, sessionID string) error {
+	cmd := func() {
+		for _, s := range e.slots {
+			if s.sessionID == sessionID {
+				s.sessionID = ""
+				s.sessionDirty = false
+			}
+		}
+	}
+
+	return e.run(ctx, cmd)
+}
+
+// run executes the command on the processing goroutine and waits for it to
+// complete.
+func (e *batchEngine) run(ctx context.Context, cmd func()) error {
+	done := make(chan struct{})
+
+	f := func() {
+		defer close(done)
+		cmd()
+	}
+
+	select {
+	case e.cmdQ <- f:
+	case <-e.shutdownCh:
+		return errors.New("run: engine shutting down")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	<-done
+
+	return nil
 }
 
 // =============================================================================
 // Batch manipulation helpers
 
+// commonPrefix returns the number of leading tokens shared by a and b.
+func commonPrefix(a, b []llama.Token) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
 func batchClear(batch *llama.Batch) {
 	batch.NTokens = 0
 }
@@ -0,0 +1,263 @@
+package model
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []llama.Token
+		b    []llama.Token
+		want int
+	}{
+		{"both empty", nil, nil, 0},
+		{"one empty", []llama.Token{1, 2, 3}, nil, 0},
+		{"identical", []llama.Token{1, 2, 3}, []llama.Token{1, 2, 3}, 3},
+		{"shorter a", []llama.Token{1, 2}, []llama.Token{1, 2, 3, 4}, 2},
+		{"shorter b", []llama.Token{1, 2, 3, 4}, []llama.Token{1, 2}, 2},
+		{"diverge", []llama.Token{1, 2, 3, 4}, []llama.Token{1, 2, 9, 4}, 2},
+		{"no match", []llama.Token{5, 2, 3}, []llama.Token{1, 2, 3}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commonPrefix(tt.a, tt.b); got != tt.want {
+				t.Errorf("commonPrefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickSlot(t *testing.T) {
+	newEngine := func(cached ...[]llama.Token) *batchEngine {
+		e := batchEngine{}
+		for i, c := range cached {
+			e.slots = append(e.slots, &slot{id: i, seqID: llama.SeqId(i + 1), cachedTokens: c})
+		}
+		return &e
+	}
+
+	t.Run("longest prefix", func(t *testing.T) {
+		e := newEngine(
+			[]llama.Token{1, 2, 9},
+			[]llama.Token{1, 2, 3, 4, 8},
+			[]llama.Token{1},
+		)
+
+		if got := e.pickSlot([]llama.Token{1, 2, 3, 4, 5}); got.id != 1 {
+			t.Errorf("pickSlot() = slot %d, want slot 1", got.id)
+		}
+	})
+
+	t.Run("skip active", func(t *testing.T) {
+		e := newEngine(
+			[]llama.Token{1, 2, 9},
+			[]llama.Token{1, 2, 3, 4, 8},
+		)
+		e.slots[1].active = true
+
+		if got := e.pickSlot([]llama.Token{1, 2, 3, 4, 5}); got.id != 0 {
+			t.Errorf("pickSlot() = slot %d, want slot 0", got.id)
+		}
+	})
+
+	t.Run("least cached on miss", func(t *testing.T) {
+		e := newEngine(
+			[]llama.Token{7, 8, 9},
+			nil,
+			[]llama.Token{6},
+		)
+
+		if got := e.pickSlot([]llama.Token{1, 2, 3}); got.id != 1 {
+			t.Errorf("pickSlot() = slot %d, want slot 1", got.id)
+		}
+	})
+}
+
+func TestBindSession(t *testing.T) {
+	e := batchEngine{}
+	for i := range 3 {
+		e.slots = append(e.slots, &slot{id: i})
+	}
+
+	e.slots[0].sessionID = "a"
+	e.slots[0].sessionDirty = true
+	e.slots[2].sessionID = "b"
+
+	e.bindSession(e.slots[1], "a")
+
+	if e.slots[0].sessionID != "" || e.slots[0].sessionDirty {
+		t.Errorf("expected slot 0 to be released from the session, got %q dirty %v", e.slots[0].sessionID, e.slots[0].sessionDirty)
+	}
+
+	if e.slots[1].sessionID != "a" {
+		t.Errorf("expected slot 1 to hold the session, got %q", e.slots[1].sessionID)
+	}
+
+	if e.slots[2].sessionID != "b" {
+		t.Errorf("expected slot 2 to keep its session, got %q", e.slots[2].sessionID)
+	}
+}
+
+func TestAcceptDraft(t *testing.T) {
+	tests := []struct {
+		name      string
+		nAccepted int
+		wantPast  llama.Pos
+		wantCache []llama.Token
+	}{
+		{"none accepted", 0, 11, []llama.Token{1, 2, 3}},
+		{"some accepted", 2, 13, []llama.Token{1, 2, 3, 4, 5}},
+		{"all accepted", 3, 14, []llama.Token{1, 2, 3, 4, 5, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The sampled token 3 is at position 10 and the draft tokens
+			// were added after it.
+			s := slot{nPast: 14, cachedTokens: []llama.Token{1, 2, 3}}
+
+			s.acceptDraft([]llama.Token{4, 5, 6}, tt.nAccepted)
+
+			if s.nPast != tt.wantPast {
+				t.Errorf("nPast = %d, want %d", s.nPast, tt.wantPast)
+			}
+
+			if !slices.Equal(s.cachedTokens, tt.wantCache) {
+				t.Errorf("cachedTokens = %v, want %v", s.cachedTokens, tt.wantCache)
+			}
+		})
+	}
+}
+
+func TestRollbackStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		drafted []llama.Token
+	}{
+		{"no draft", nil},
+		{"draft", []llama.Token{4, 5, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The state before the step: two tokens held, one decoded.
+			s := slot{nPast: 2, cachedTokens: []llama.Token{1, 2}, nDecoded: 1, sampled: 3}
+
+			// Add the sampled token and the draft like processBatch does.
+			s.iBatch = 0
+			s.cachedTokens = append(s.cachedTokens, s.sampled)
+			s.nPast++
+			s.nDecoded++
+			s.drafted = tt.drafted
+			s.nPast += llama.Pos(len(tt.drafted))
+
+			s.rollbackStep()
+
+			if s.nPast != 2 {
+				t.Errorf("nPast = %d, want 2", s.nPast)
+			}
+
+			if !slices.Equal(s.cachedTokens, []llama.Token{1, 2}) {
+				t.Errorf("cachedTokens = %v, want [1 2]", s.cachedTokens)
+			}
+
+			if s.nDecoded != 1 {
+				t.Errorf("nDecoded = %d, want 1", s.nDecoded)
+			}
+
+			if s.drafted != nil || s.iBatch != -1 {
+				t.Errorf("expected the draft and batch index to be cleared, got %v %d", s.drafted, s.iBatch)
+			}
+		})
+	}
+}
+
+func TestRollbackChunk(t *testing.T) {
+	prompt := []llama.Token{4, 5, 6, 7, 8}
+
+	tests := []struct {
+		name      string
+		chunkSize int
+	}{
+		{"earlier chunk", 2},
+		{"last chunk", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Two tokens were reused from the cache and two were prefilled
+			// by an earlier chunk.
+			s := slot{nPast: 4, cachedTokens: []llama.Token{1, 2, 4, 5}, prefillTokens: prompt, nPrefilled: 2}
+
+			// Add the chunk like addPrefillChunk does.
+			s.chunkStart = prefillState{tokens: s.prefillTokens, nPrefilled: s.nPrefilled, nPast: s.nPast, nCached: len(s.cachedTokens)}
+			s.cachedTokens = append(s.cachedTokens, prompt[2:2+tt.chunkSize]...)
+			s.nPast += llama.Pos(tt.chunkSize)
+			s.nPrefilled += tt.chunkSize
+			s.iBatch = -1
+			if s.nPrefilled == len(prompt) {
+				s.iBatch = 4
+				s.prefillTokens = nil
+			}
+
+			s.rollbackChunk()
+
+			if !slices.Equal(s.prefillTokens, prompt) || s.nPrefilled != 2 {
+				t.Errorf("expected the prefill to continue at token 2, got %v at %d", s.prefillTokens, s.nPrefilled)
+			}
+
+			if s.nPast != 4 {
+				t.Errorf("nPast = %d, want 4", s.nPast)
+			}
+
+			if !slices.Equal(s.cachedTokens, []llama.Token{1, 2, 4, 5}) {
+				t.Errorf("cachedTokens = %v, want [1 2 4 5]", s.cachedTokens)
+			}
+
+			if s.iBatch != -1 {
+				t.Errorf("iBatch = %d, want -1", s.iBatch)
+			}
+		})
+	}
+}
+
+func TestNextJob(t *testing.T) {
+	e := batchEngine{
+		requestQ: make(chan *chatJob, 10),
+		served:   make(map[string]uint64),
+	}
+
+	submit := func(id string, priority int, subject string) {
+		e.requestQ <- &chatJob{
+			id:  id,
+			ctx: SetSchedule(context.Background(), priority, subject),
+		}
+	}
+
+	submit("batch-1", 0, "batch")
+	submit("batch-2", 0, "batch")
+	submit("batch-3", 0, "batch")
+	submit("user-1", 0, "user")
+	submit("low-1", -1, "user")
+	submit("high-1", 1, "batch")
+
+	var order []string
+	for job := e.nextJob(); job != nil; job = e.nextJob() {
+		order = append(order, job.id)
+	}
+
+	want := []string{"high-1", "user-1", "batch-1", "batch-2", "batch-3", "low-1"}
+	if !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	if len(e.served) != 0 {
+		t.Errorf("served = %v, want it cleared once no job waits", e.served)
+	}
+}
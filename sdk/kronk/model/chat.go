@@ -1,15 +1,18 @@
 package model
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"path"
+	"slices"
 	"time"
 
 	"github.com/ardanlabs/kronk/sdk/kronk/observ/metrics"
 	"github.com/ardanlabs/kronk/sdk/kronk/observ/otel"
 	"github.com/google/uuid"
+	"github.com/hybridgroup/yzma/pkg/llama"
 	"github.com/hybridgroup/yzma/pkg/mtmd"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -22,23 +25,67 @@ import (
 func (m *Model) Chat(ctx context.Context, d D) (ChatResponse, error) {
 	ch := m.ChatStreaming(ctx, d)
 
+	// Each choice ends with its own final response when more than one
+	// choice is requested.
 	var lastMsg ChatResponse
+	var finals []ChatResponse
 	for msg := range ch {
 		lastMsg = msg
+		if len(msg.Choice) > 0 && msg.Choice[0].FinishReason != "" {
+			finals = append(finals, msg)
+		}
+	}
+
+	if len(finals) > 1 {
+		lastMsg = mergeChoices(finals)
 	}
 
 	if lastMsg.Object == ObjectChatText {
 		lastMsg.Object = ObjectChatTextFinal
 	}
 
-	if len(lastMsg.Choice) > 0 {
+	switch len(lastMsg.Choice) {
+	case 1:
 		lastMsg.Choice[0].Index = 0
 		lastMsg.Choice[0].Delta = nil
+
+	default:
+		for i := range lastMsg.Choice {
+			lastMsg.Choice[i].Delta = nil
+		}
 	}
 
 	return lastMsg, nil
 }
 
+// mergeChoices combines the final responses of the choices into a single
+// response ordered by choice index. The prompt is only processed once, so it
+// is only counted once in the usage.
+func mergeChoices(finals []ChatResponse) ChatResponse {
+	slices.SortStableFunc(finals, func(a, b ChatResponse) int {
+		return cmp.Compare(a.Choice[0].Index, b.Choice[0].Index)
+	})
+
+	resp := finals[0]
+	resp.Choice = make([]Choice, 0, len(finals))
+	resp.Usage = Usage{
+		PromptTokens:        finals[0].Usage.PromptTokens,
+		PromptTokensDetails: finals[0].Usage.PromptTokensDetails,
+	}
+
+	for _, f := range finals {
+		resp.Choice = append(resp.Choice, f.Choice[0])
+		resp.Usage.ReasoningTokens += f.Usage.ReasoningTokens
+		resp.Usage.CompletionTokens += f.Usage.CompletionTokens
+		resp.Usage.OutputTokens += f.Usage.OutputTokens
+		resp.Usage.TokensPerSecond += f.Usage.TokensPerSecond
+	}
+
+	resp.Usage.TotalTokens = resp.Usage.PromptTokens + resp.Usage.OutputTokens
+
+	return resp
+}
+
 // ChatStreaming performs a chat request and streams the response.
 // Text inference requests can run concurrently based on the NSeqMax config
 // value, which controls parallel sequence processing. However, requests that
@@ -83,7 +130,12 @@ func (m *Model) ChatStreaming(ctx context.Context, d D) <-chan ChatResponse {
 					mtmd.Free(mtmdCtx)
 				}
 
-				m.resetContext()
+				// The batch engine owns the KV cache and keeps prompt
+				// prefixes cached across requests, so only clear it when
+				// the sequential path is in use.
+				if m.batch == nil {
+					m.resetContext()
+				}
 			}
 		}()
 
@@ -93,10 +145,21 @@ func (m *Model) ChatStreaming(ctx context.Context, d D) <-chan ChatResponse {
 			return
 		}
 
+		// A grammar can't produce the GPT channel tokens, so the final
+		// channel is opened in the prompt before generation starts.
+		if params.Grammar != "" && m.modelInfo.IsGPTModel {
+			prompt += gptFinalChannel
+		}
+
 		// ---------------------------------------------------------------------
 
 		// Use batch engine for text-only requests when available.
 		if m.batch != nil && object == ObjectChatText {
+			if params.N > m.batch.nSlots {
+				m.sendChatError(ctx, ch, id, fmt.Errorf("chat-streaming: n[%d] exceeds the number of parallel sequences[%d]", params.N, m.batch.nSlots))
+				return
+			}
+
 			job := chatJob{
 				id:      id,
 				ctx:     ctx,
@@ -109,6 +172,12 @@ func (m *Model) ChatStreaming(ctx context.Context, d D) <-chan ChatResponse {
 				ch:      ch,
 			}
 
+			// Read any saved session state here so the engine isn't
+			// blocked on disk access.
+			if params.SessionID != "" {
+				job.session = m.loadSession(ctx, params.SessionID)
+			}
+
 			// Engine manages activeStreams for submitted jobs.
 			if err := m.batch.submit(&job); err != nil {
 				m.sendChatError(ctx, ch, id, err)
@@ -126,6 +195,11 @@ func (m *Model) ChatStreaming(ctx context.Context, d D) <-chan ChatResponse {
 
 		// Sequential path for media requests or when engine is not available.
 
+		if params.N > 1 {
+			m.sendChatError(ctx, ch, id, fmt.Errorf("chat-streaming: n[%d] requires parallel sequences, which media requests don't support", params.N))
+			return
+		}
+
 		m.sequentialChatRequest(ctx, id, m.lctx, mtmdCtx, object, prompt, media, params, ch)
 	}()
 
@@ -233,11 +307,30 @@ func (m *Model) validateDocument(d D) (params, error) {
 		return params{}, errors.New("validate-document: messages is not a slice of documents")
 	}
 
+	return m.validateParams(d)
+}
+
+func (m *Model) validateParams(d D) (params, error) {
 	p, err := m.parseParams(d)
 	if err != nil {
 		return params{}, err
 	}
 
+	if p.Grammar != "" {
+		sampler := llama.SamplerInitGrammar(m.vocab, p.Grammar, grammarRoot)
+		if sampler == 0 {
+			return params{}, errors.New("validate-params: unable to parse grammar")
+		}
+		llama.SamplerFree(sampler)
+	}
+
+	nVocab := llama.VocabNTokens(m.vocab)
+	for token := range p.LogitBias {
+		if int32(token) >= nVocab {
+			return params{}, fmt.Errorf("validate-params: logit_bias token[%d] is not in the vocabulary", token)
+		}
+	}
+
 	return p, nil
 }
 
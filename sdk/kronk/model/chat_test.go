@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestMergeChoices(t *testing.T) {
+	final := func(index int, content string, output int) ChatResponse {
+		return chatResponseFinal("id", ObjectChatText, "model", index, "", content, "", nil, false,
+			Usage{
+				PromptTokens:     10,
+				CompletionTokens: output,
+				OutputTokens:     output,
+				TotalTokens:      10 + output,
+			},
+			42, nil)
+	}
+
+	resp := mergeChoices([]ChatResponse{
+		final(2, "c", 3),
+		final(0, "a", 1),
+		final(1, "b", 2),
+	})
+
+	if len(resp.Choice) != 3 {
+		t.Fatalf("len(Choice) = %d, want 3", len(resp.Choice))
+	}
+
+	for i, want := range []string{"a", "b", "c"} {
+		if resp.Choice[i].Index != i {
+			t.Errorf("Choice[%d].Index = %d, want %d", i, resp.Choice[i].Index, i)
+		}
+
+		if resp.Choice[i].Message.Content != want {
+			t.Errorf("Choice[%d].Content = %q, want %q", i, resp.Choice[i].Message.Content, want)
+		}
+	}
+
+	want := Usage{
+		PromptTokens:     10,
+		CompletionTokens: 6,
+		OutputTokens:     6,
+		TotalTokens:      16,
+	}
+
+	if resp.Usage != want {
+		t.Errorf("Usage = %+v, want %+v", resp.Usage, want)
+	}
+
+	if resp.Seed != 42 {
+		t.Errorf("Seed = %d, want 42", resp.Seed)
+	}
+}
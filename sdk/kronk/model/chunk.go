@@ -0,0 +1,183 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+// Set of ways the chunk vectors of an input are combined.
+const (
+	ChunkAggregateNone     = "none"
+	ChunkAggregateMean     = "mean"
+	ChunkAggregateWeighted = "weighted"
+)
+
+// chunkOptions holds the chunking options of an embeddings call.
+type chunkOptions struct {
+	enabled   bool
+	size      int
+	overlap   int
+	aggregate string
+}
+
+// parseChunkOptions reads the chunking options from d. The chunk size defaults
+// to the largest input the model can embed and the overlap to a tenth of it.
+func parseChunkOptions(d D, maxTokens int) (chunkOptions, error) {
+	enabled, _ := d["chunking"].(bool)
+	if !enabled {
+		return chunkOptions{}, nil
+	}
+
+	opts := chunkOptions{
+		enabled:   true,
+		size:      maxTokens,
+		aggregate: ChunkAggregateMean,
+	}
+
+	if v, exists := d["chunk_size"]; exists {
+		size, err := parseInt("chunk_size", v)
+		if err != nil {
+			return chunkOptions{}, fmt.Errorf("parse-chunk-options: %w", err)
+		}
+
+		if size <= 0 || size > maxTokens {
+			return chunkOptions{}, fmt.Errorf("parse-chunk-options: chunk_size must be between 1 and %d", maxTokens)
+		}
+
+		opts.size = size
+	}
+
+	opts.overlap = opts.size / 10
+
+	if v, exists := d["chunk_overlap"]; exists {
+		overlap, err := parseInt("chunk_overlap", v)
+		if err != nil {
+			return chunkOptions{}, fmt.Errorf("parse-chunk-options: %w", err)
+		}
+
+		if overlap < 0 || overlap >= opts.size {
+			return chunkOptions{}, fmt.Errorf("parse-chunk-options: chunk_overlap must be at least 0 and less than the chunk size of %d", opts.size)
+		}
+
+		opts.overlap = overlap
+	}
+
+	if v, exists := d["chunk_aggregate"]; exists {
+		aggregate, err := parseString("chunk_aggregate", v)
+		if err != nil {
+			return chunkOptions{}, fmt.Errorf("parse-chunk-options: %w", err)
+		}
+
+		switch aggregate {
+		case ChunkAggregateNone, ChunkAggregateMean, ChunkAggregateWeighted:
+			opts.aggregate = aggregate
+
+		default:
+			return chunkOptions{}, fmt.Errorf("parse-chunk-options: invalid chunk_aggregate %q (expected none, mean or weighted)", aggregate)
+		}
+	}
+
+	return opts, nil
+}
+
+// specialTokens returns the number of special tokens the tokenizer added at
+// the start and the end of the tokens. Each chunk gets its own copy of them.
+func (m *Model) specialTokens(tokens []llama.Token) (int, int) {
+	var pre, post int
+
+	if len(tokens) > 0 && llama.VocabGetAddBOS(m.vocab) && tokens[0] == llama.VocabBOS(m.vocab) {
+		pre = 1
+	}
+
+	if len(tokens) > pre {
+		last := tokens[len(tokens)-1]
+
+		switch {
+		case llama.VocabGetAddEOS(m.vocab) && last == llama.VocabEOS(m.vocab):
+			post = 1
+
+		case llama.VocabGetAddSEP(m.vocab) && last == llama.VocabSEP(m.vocab):
+			post = 1
+		}
+	}
+
+	return pre, post
+}
+
+// splitChunks splits the tokens into overlapping windows of at most size
+// tokens, keeping the pre leading and post trailing special tokens on every
+// window. The offsets of the chunks are relative to the tokens between them.
+func splitChunks(tokens []llama.Token, pre int, post int, size int, overlap int) ([][]llama.Token, []EmbedChunk, error) {
+	body := tokens[pre : len(tokens)-post]
+
+	if len(tokens) <= size {
+		return [][]llama.Token{tokens}, []EmbedChunk{{Index: 0, Start: 0, End: len(body)}}, nil
+	}
+
+	bodySize := size - pre - post
+	if bodySize <= overlap {
+		return nil, nil, fmt.Errorf("split-chunks: chunk size %d leaves no room for new tokens past an overlap of %d", size, overlap)
+	}
+
+	windows := chunkWindows(len(body), bodySize, overlap)
+
+	chunks := make([][]llama.Token, len(windows))
+	infos := make([]EmbedChunk, len(windows))
+
+	for i, w := range windows {
+		chunk := make([]llama.Token, 0, pre+w[1]-w[0]+post)
+		chunk = append(chunk, tokens[:pre]...)
+		chunk = append(chunk, body[w[0]:w[1]]...)
+		chunk = append(chunk, tokens[len(tokens)-post:]...)
+
+		chunks[i] = chunk
+		infos[i] = EmbedChunk{Index: i, Start: w[0], End: w[1]}
+	}
+
+	return chunks, infos, nil
+}
+
+// chunkWindows returns the start and end offsets of the windows of size tokens
+// covering n tokens, where each window shares overlap tokens with the one
+// before it.
+func chunkWindows(n int, size int, overlap int) [][2]int {
+	var windows [][2]int
+
+	step := max(size-overlap, 1)
+
+	for start := 0; ; start += step {
+		end := min(start+size, n)
+		windows = append(windows, [2]int{start, end})
+
+		if end == n {
+			break
+		}
+	}
+
+	return windows
+}
+
+// aggregateVectors combines the vectors into their average using the weights.
+func aggregateVectors(vectors [][]float32, weights []float64) []float32 {
+	if len(vectors) == 1 {
+		return vectors[0]
+	}
+
+	sum := make([]float64, len(vectors[0]))
+
+	var total float64
+	for i, vec := range vectors {
+		for j, v := range vec {
+			sum[j] += float64(v) * weights[i]
+		}
+		total += weights[i]
+	}
+
+	result := make([]float32, len(sum))
+	for j, v := range sum {
+		result[j] = float32(v / total)
+	}
+
+	return result
+}
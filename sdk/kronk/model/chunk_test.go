@@ -0,0 +1,120 @@
+package model
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+func TestParseChunkOptions(t *testing.T) {
+	opts, err := parseChunkOptions(D{}, 512)
+	if err != nil || opts.enabled {
+		t.Fatalf("expected chunking to be disabled by default, got %+v, %v", opts, err)
+	}
+
+	opts, err = parseChunkOptions(D{"chunking": true}, 512)
+	if err != nil {
+		t.Fatalf("parse: %s", err)
+	}
+
+	if opts.size != 512 || opts.overlap != 51 || opts.aggregate != ChunkAggregateMean {
+		t.Errorf("unexpected defaults: %+v", opts)
+	}
+
+	opts, err = parseChunkOptions(D{"chunking": true, "chunk_size": float64(100), "chunk_overlap": 20, "chunk_aggregate": "none"}, 512)
+	if err != nil {
+		t.Fatalf("parse: %s", err)
+	}
+
+	if opts.size != 100 || opts.overlap != 20 || opts.aggregate != ChunkAggregateNone {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	for _, d := range []D{
+		{"chunking": true, "chunk_size": 1024},
+		{"chunking": true, "chunk_size": 0},
+		{"chunking": true, "chunk_size": 100, "chunk_overlap": 100},
+		{"chunking": true, "chunk_aggregate": "max"},
+	} {
+		if _, err := parseChunkOptions(d, 512); err == nil {
+			t.Errorf("expected an error for %v", d)
+		}
+	}
+}
+
+func TestChunkWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		size    int
+		overlap int
+		want    [][2]int
+	}{
+		{"fits", 5, 10, 2, [][2]int{{0, 5}}},
+		{"no overlap", 10, 4, 0, [][2]int{{0, 4}, {4, 8}, {8, 10}}},
+		{"overlap", 10, 4, 2, [][2]int{{0, 4}, {2, 6}, {4, 8}, {6, 10}}},
+		{"exact", 8, 4, 0, [][2]int{{0, 4}, {4, 8}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chunkWindows(tt.n, tt.size, tt.overlap); !slices.Equal(got, tt.want) {
+				t.Errorf("chunkWindows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitChunks(t *testing.T) {
+	// BOS 1..8 EOS
+	tokens := []llama.Token{100, 1, 2, 3, 4, 5, 6, 7, 8, 101}
+
+	chunks, infos, err := splitChunks(tokens, 1, 1, 6, 1)
+	if err != nil {
+		t.Fatalf("split: %s", err)
+	}
+
+	wantChunks := [][]llama.Token{
+		{100, 1, 2, 3, 4, 101},
+		{100, 4, 5, 6, 7, 101},
+		{100, 7, 8, 101},
+	}
+
+	wantInfos := []EmbedChunk{
+		{Index: 0, Start: 0, End: 4},
+		{Index: 1, Start: 3, End: 7},
+		{Index: 2, Start: 6, End: 8},
+	}
+
+	if len(chunks) != len(wantChunks) {
+		t.Fatalf("expected %d chunks, got %v", len(wantChunks), chunks)
+	}
+
+	for i := range chunks {
+		if !slices.Equal(chunks[i], wantChunks[i]) || infos[i] != wantInfos[i] {
+			t.Errorf("chunk %d: got %v %+v, want %v %+v", i, chunks[i], infos[i], wantChunks[i], wantInfos[i])
+		}
+	}
+
+	chunks, infos, err = splitChunks(tokens, 1, 1, 10, 1)
+	if err != nil || len(chunks) != 1 || infos[0] != (EmbedChunk{Start: 0, End: 8}) {
+		t.Errorf("expected a single chunk, got %v %+v %v", chunks, infos, err)
+	}
+
+	if _, _, err := splitChunks(tokens, 1, 1, 3, 1); err == nil {
+		t.Error("expected an error when the overlap leaves no room")
+	}
+}
+
+func TestAggregateVectors(t *testing.T) {
+	vectors := [][]float32{{1, 0}, {3, 4}}
+
+	if got := aggregateVectors(vectors, []float64{1, 1}); !slices.Equal(got, []float32{2, 2}) {
+		t.Errorf("mean = %v, want [2 2]", got)
+	}
+
+	if got := aggregateVectors(vectors, []float64{3, 1}); !slices.Equal(got, []float32{1.5, 1}) {
+		t.Errorf("weighted = %v, want [1.5 1]", got)
+	}
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+// CompletionStreaming performs a completion request for a raw prompt and
+// streams the response. The prompt is given to the model as is, without the
+// chat template, and the output isn't parsed for reasoning or tool calls.
+// When the request has a suffix, the model fills in the text between the
+// prompt and the suffix, which requires a model with fill-in-the-middle
+// tokens.
+func (m *Model) CompletionStreaming(ctx context.Context, d D) <-chan ChatResponse {
+	ch := make(chan ChatResponse, 1)
+
+	go func() {
+		m.activeStreams.Add(1)
+
+		id := fmt.Sprintf("cmpl-%s", uuid.New().String())
+
+		batching := false
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				m.sendChatError(ctx, ch, id, fmt.Errorf("%v", rec))
+			}
+
+			if !batching {
+				close(ch)
+				m.activeStreams.Add(-1)
+			}
+		}()
+
+		// Raw prompts are only processed by the batch engine, which is
+		// available for text models.
+		if m.batch == nil {
+			m.sendChatError(ctx, ch, id, errors.New("completion-streaming: model doesn't support completions"))
+			return
+		}
+
+		params, err := m.validateParams(d)
+		if err != nil {
+			m.sendChatError(ctx, ch, id, err)
+			return
+		}
+
+		prompt, err := m.completionPrompt(d)
+		if err != nil {
+			m.sendChatError(ctx, ch, id, err)
+			return
+		}
+
+		if params.N > m.batch.nSlots {
+			m.sendChatError(ctx, ch, id, fmt.Errorf("completion-streaming: n[%d] exceeds the number of parallel sequences[%d]", params.N, m.batch.nSlots))
+			return
+		}
+
+		job := chatJob{
+			id:     id,
+			ctx:    ctx,
+			d:      d,
+			object: ObjectTextCompletion,
+			prompt: prompt,
+			params: params,
+			ch:     ch,
+			raw:    true,
+		}
+
+		if params.SessionID != "" {
+			job.session = m.loadSession(ctx, params.SessionID)
+		}
+
+		// Engine manages activeStreams for submitted jobs.
+		if err := m.batch.submit(&job); err != nil {
+			m.sendChatError(ctx, ch, id, err)
+			return
+		}
+
+		batching = true
+	}()
+
+	return ch
+}
+
+// completionPrompt returns the prompt for a completion request. With a suffix
+// the prompt is laid out for fill-in-the-middle using the FIM tokens of the
+// model, so the model generates the text that goes between the prompt and the
+// suffix.
+func (m *Model) completionPrompt(d D) (string, error) {
+	val, exists := d["prompt"]
+	if !exists {
+		return "", errors.New("completion-prompt: no prompt found in request")
+	}
+
+	prompt, ok := val.(string)
+	if !ok {
+		return "", errors.New("completion-prompt: prompt is not a string")
+	}
+
+	suffix, _ := d["suffix"].(string)
+	if suffix == "" {
+		return prompt, nil
+	}
+
+	pre := llama.VocabFIMPre(m.vocab)
+	suf := llama.VocabFIMSuf(m.vocab)
+	mid := llama.VocabFIMMid(m.vocab)
+
+	if pre == llama.TokenNull || suf == llama.TokenNull || mid == llama.TokenNull {
+		return "", fmt.Errorf("completion-prompt: model[%s] has no fill-in-the-middle tokens, suffix isn't supported", m.modelInfo.ID)
+	}
+
+	// The special tokens are parsed from the text when the prompt is
+	// tokenized.
+	fim := llama.VocabGetText(m.vocab, pre) + prompt +
+		llama.VocabGetText(m.vocab, suf) + suffix +
+		llama.VocabGetText(m.vocab, mid)
+
+	return fim, nil
+}
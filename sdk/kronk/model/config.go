@@ -29,6 +29,8 @@ const (
 	defNBatch        = 2 * 1024
 	defNUBatch       = 512
 	defNUBatchVision = 2 * 1024
+	defSessionBytes  = 4 * 1024 * 1024 * 1024
+	defDraftTokens   = 8
 )
 
 // Logger provides a function for logging messages from different APIs.
@@ -109,7 +111,9 @@ type Logger func(ctx context.Context, msg string, args ...any)
 // models, it sets the maximum number of sequences processed in parallel within
 // a single model instance (batched inference). For sequential models (embeddings,
 // reranking, vision, audio), it creates that many model instances in a pool for
-// concurrent request handling. When set to 0, a default of 1 is used.
+// concurrent request handling. An embedding instance also decodes up to
+// NSeqMax+1 inputs of a call in a single pass. When set to 0, a default of 1
+// is used.
 //
 // OffloadKQV controls whether the KV cache is offloaded to the GPU. When nil or
 // true, the KV cache is stored on the GPU (default behavior). Set to false to
@@ -130,6 +134,32 @@ type Logger func(ctx context.Context, msg string, args ...any)
 //     (recommended for MoE models like Qwen3-MoE, Mixtral, DeepSeek)
 //
 // When not set, defaults to SplitModeRow for optimal MoE performance.
+//
+// SessionDir is the folder where session KV cache state is saved. Each model
+// uses its own sub-folder. When empty, sessions are not saved to disk.
+//
+// SessionMaxBytes bounds the total size of the saved sessions for a model. When
+// exceeded, the least recently used sessions are removed. When set to 0, the
+// default value is 4GB.
+//
+// DraftModelFiles is the path to the files of a small model used for
+// speculative decoding. The draft model proposes tokens that the main model
+// verifies in a single decode, which speeds up generation when the draft
+// predicts well. The draft model must share the vocabulary of the main model.
+// When empty, speculative decoding is not used.
+//
+// DraftTokens is the maximum number of tokens the draft model proposes at a
+// time. When set to 0, the default value is 8.
+//
+// ToolParser is the name of the parser used to find the tool calls in the
+// generated text: hermes, mistral, llama3, functionary, granite, deepseek or
+// gpt-oss. When empty, the parser is selected from the chat template and the
+// model metadata, with hermes used when nothing matches.
+//
+// FixedPooling rejects embedding calls that ask for a pooling other than the
+// one of the model. Each other pooling needs a context of its own, which
+// EstimateMemory doesn't count, so set this when memory use must stay within
+// the estimate.
 type Config struct {
 	Log                  Logger
 	ModelFiles           []string
@@ -151,6 +181,12 @@ type Config struct {
 	OpOffload            *bool
 	NGpuLayers           *int32
 	SplitMode            SplitMode
+	SessionDir           string
+	SessionMaxBytes      int64
+	DraftModelFiles      []string
+	DraftTokens          int
+	ToolParser           string
+	FixedPooling         bool
 }
 
 func validateConfig(ctx context.Context, cfg Config, log Logger) error {
@@ -158,6 +194,10 @@ func validateConfig(ctx context.Context, cfg Config, log Logger) error {
 		return fmt.Errorf("validate-config: model file is required")
 	}
 
+	if err := validateToolParser(cfg.ToolParser); err != nil {
+		return fmt.Errorf("validate-config: %w", err)
+	}
+
 	if !cfg.IgnoreIntegrityCheck {
 		for _, modelFile := range cfg.ModelFiles {
 			log(ctx, "validate-config", "model-file", modelFile)
@@ -174,6 +214,14 @@ func validateConfig(ctx context.Context, cfg Config, log Logger) error {
 				return fmt.Errorf("validate-config: prog-file[%s]: %w", cfg.ProjFile, err)
 			}
 		}
+
+		for _, draftFile := range cfg.DraftModelFiles {
+			log(ctx, "validate-config", "draft-file", draftFile)
+
+			if err := CheckModel(draftFile, true); err != nil {
+				return fmt.Errorf("validate-config: draft-file[%s]: %w", draftFile, err)
+			}
+		}
 	}
 
 	return nil
@@ -215,6 +263,14 @@ func adjustConfig(cfg Config, model llama.Model) Config {
 		cfg.NSeqMax = 1
 	}
 
+	if cfg.SessionMaxBytes <= 0 {
+		cfg.SessionMaxBytes = defSessionBytes
+	}
+
+	if cfg.DraftTokens <= 0 {
+		cfg.DraftTokens = defDraftTokens
+	}
+
 	return cfg
 }
 
@@ -254,19 +310,8 @@ func modelCtxParams(cfg Config, mi ModelInfo) llama.ContextParams {
 		ctxParams.NThreadsBatch = int32(cfg.NThreadsBatch)
 	}
 
-	switch {
-	case cfg.CacheTypeK > -2 && cfg.CacheTypeK < 41:
-		ctxParams.TypeK = cfg.CacheTypeK.ToYZMAType()
-	default:
-		ctxParams.TypeK = GGMLTypeQ8_0.ToYZMAType()
-	}
-
-	switch {
-	case cfg.CacheTypeV > -2 && cfg.CacheTypeV < 41:
-		ctxParams.TypeV = cfg.CacheTypeV.ToYZMAType()
-	default:
-		ctxParams.TypeV = GGMLTypeQ8_0.ToYZMAType()
-	}
+	ctxParams.TypeK = kvCacheType(cfg.CacheTypeK).ToYZMAType()
+	ctxParams.TypeV = kvCacheType(cfg.CacheTypeV).ToYZMAType()
 
 	switch cfg.FlashAttention {
 	case FlashAttentionDisabled:
@@ -324,6 +369,16 @@ func searchModelMeta(model llama.Model, find string) (string, bool) {
 
 // =============================================================================
 
+// kvCacheType returns the type used for the KV cache for the configured type.
+func kvCacheType(t GGMLType) GGMLType {
+	switch {
+	case t > -2 && t < 41:
+		return t
+	default:
+		return GGMLTypeQ8_0
+	}
+}
+
 // GGMLType represents a ggml data type for the KV cache.
 // These values correspond to the ggml_type enum in llama.cpp.
 type GGMLType int32
@@ -375,6 +430,33 @@ func (t GGMLType) String() string {
 	}
 }
 
+// bytesPerElement returns the average size of a value of the type, since the
+// quantized types store blocks of 32 values with a scale.
+func (t GGMLType) bytesPerElement() float64 {
+	switch t {
+	case GGMLTypeF32:
+		return 4
+
+	case GGMLTypeQ4_0:
+		return 18.0 / 32
+
+	case GGMLTypeQ4_1:
+		return 20.0 / 32
+
+	case GGMLTypeQ5_0:
+		return 22.0 / 32
+
+	case GGMLTypeQ5_1:
+		return 24.0 / 32
+
+	case GGMLTypeQ8_0:
+		return 34.0 / 32
+
+	default:
+		return 2
+	}
+}
+
 func (t GGMLType) ToYZMAType() llama.GGMLType {
 	return llama.GGMLType(t)
 }
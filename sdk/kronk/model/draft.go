@@ -0,0 +1,300 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+// These values match the checks llama.cpp performs before it allows a draft
+// model to be used with a target model.
+const (
+	draftVocabMaxDiff    = 128
+	draftVocabCheckStart = 5
+)
+
+// draftModel is a small model that proposes tokens for the main model to
+// verify. It keeps its own KV cache using the same sequence ids as the main
+// model so every slot has a matching draft sequence.
+type draftModel struct {
+	model   llama.Model
+	vocab   llama.Vocab
+	lctx    llama.Context
+	mem     llama.Memory
+	batch   llama.Batch
+	sampler llama.Sampler
+	nBatch  int
+	nDraft  int
+	nVocab  int32
+
+	// tokens holds the tokens whose KV entries are stored in each sequence.
+	tokens map[llama.SeqId][]llama.Token
+}
+
+func newDraftModel(ctx context.Context, log Logger, cfg Config, mParams llama.ModelParams, ctxParams llama.ContextParams, vocab llama.Vocab) (*draftModel, error) {
+	mdl, err := loadModelFromFiles(ctx, log, cfg.DraftModelFiles, mParams)
+	if err != nil {
+		return nil, fmt.Errorf("new-draft-model: unable to load model: %w", err)
+	}
+
+	draftVocab := llama.ModelGetVocab(mdl)
+
+	if err := checkDraftVocab(vocab, draftVocab); err != nil {
+		llama.ModelFree(mdl)
+		return nil, fmt.Errorf("new-draft-model: %w", err)
+	}
+
+	lctx, err := llama.InitFromModel(mdl, ctxParams)
+	if err != nil {
+		llama.ModelFree(mdl)
+		return nil, fmt.Errorf("new-draft-model: unable to init context: %w", err)
+	}
+
+	mem, err := llama.GetMemory(lctx)
+	if err != nil {
+		llama.Free(lctx)
+		llama.ModelFree(mdl)
+		return nil, fmt.Errorf("new-draft-model: unable to get memory: %w", err)
+	}
+
+	d := draftModel{
+		model:   mdl,
+		vocab:   draftVocab,
+		lctx:    lctx,
+		mem:     mem,
+		batch:   llama.BatchInit(int32(cfg.NBatch), 0, 1),
+		sampler: llama.SamplerInitGreedy(),
+		nBatch:  cfg.NBatch,
+		nDraft:  cfg.DraftTokens,
+		nVocab:  llama.VocabNTokens(vocab),
+		tokens:  make(map[llama.SeqId][]llama.Token),
+	}
+
+	log(ctx, "new-draft-model", "status", "loaded", "draft-tokens", d.nDraft)
+
+	return &d, nil
+}
+
+// free releases the draft model and its context.
+func (d *draftModel) free() {
+	llama.SamplerFree(d.sampler)
+	llama.BatchFree(d.batch)
+	llama.Synchronize(d.lctx)
+	llama.Free(d.lctx)
+	llama.ModelFree(d.model)
+}
+
+// propose generates up to n tokens that are likely to follow the specified
+// tokens. The draft sequence is brought in line with the tokens first, only
+// decoding the ones it doesn't already hold.
+func (d *draftModel) propose(seqID llama.SeqId, tokens []llama.Token, n int) []llama.Token {
+	if n <= 0 || len(tokens) == 0 {
+		return nil
+	}
+
+	if err := d.sync(seqID, tokens); err != nil {
+		d.clear(seqID)
+		return nil
+	}
+
+	draft := make([]llama.Token, 0, n)
+
+	for {
+		token := llama.SamplerSample(d.sampler, d.lctx, -1)
+
+		// The main model can't decode tokens outside of its vocabulary.
+		if token < 0 || int32(token) >= d.nVocab || llama.VocabIsEOG(d.vocab, token) {
+			break
+		}
+
+		draft = append(draft, token)
+		if len(draft) == n {
+			break
+		}
+
+		batchClear(&d.batch)
+		batchAdd(&d.batch, token, llama.Pos(len(d.tokens[seqID])), []llama.SeqId{seqID}, true)
+
+		if ret, err := llama.Decode(d.lctx, d.batch); err != nil || ret != 0 {
+			d.clear(seqID)
+			break
+		}
+
+		d.tokens[seqID] = append(d.tokens[seqID], token)
+	}
+
+	return draft
+}
+
+// sync makes the sequence hold the KV entries for the tokens. The last token
+// is always decoded so its logits are available for sampling.
+func (d *draftModel) sync(seqID llama.SeqId, tokens []llama.Token) error {
+	held := d.tokens[seqID]
+
+	nCommon := min(commonPrefix(held, tokens), len(tokens)-1)
+
+	if ok, err := llama.MemorySeqRm(d.mem, seqID, llama.Pos(nCommon), -1); !ok || err != nil {
+		llama.MemorySeqRm(d.mem, seqID, -1, -1)
+		nCommon = 0
+	}
+
+	held = held[:nCommon]
+
+	for i := nCommon; i < len(tokens); i += d.nBatch {
+		end := min(i+d.nBatch, len(tokens))
+
+		batchClear(&d.batch)
+		for j := i; j < end; j++ {
+			batchAdd(&d.batch, tokens[j], llama.Pos(j), []llama.SeqId{seqID}, j == len(tokens)-1)
+		}
+
+		if ret, err := llama.Decode(d.lctx, d.batch); err != nil || ret != 0 {
+			return fmt.Errorf("sync: unable to decode draft tokens: ret[%d]: %w", ret, err)
+		}
+
+		held = append(held, tokens[i:end]...)
+	}
+
+	d.tokens[seqID] = held
+
+	return nil
+}
+
+// clear removes the sequence from the draft cache.
+func (d *draftModel) clear(seqID llama.SeqId) {
+	llama.MemorySeqRm(d.mem, seqID, -1, -1)
+	delete(d.tokens, seqID)
+}
+
+// checkDraftVocab validates that tokens produced by the draft model mean the
+// same thing to the main model.
+func checkDraftVocab(main llama.Vocab, draft llama.Vocab) error {
+	if llama.GetVocabType(main) != llama.GetVocabType(draft) {
+		return fmt.Errorf("check-draft-vocab: vocab type of the draft model doesn't match the main model")
+	}
+
+	if llama.VocabBOS(main) != llama.VocabBOS(draft) || llama.VocabEOS(main) != llama.VocabEOS(draft) {
+		return fmt.Errorf("check-draft-vocab: special tokens of the draft model don't match the main model")
+	}
+
+	nMain := llama.VocabNTokens(main)
+	nDraft := llama.VocabNTokens(draft)
+
+	if diff := max(nMain, nDraft) - min(nMain, nDraft); diff > draftVocabMaxDiff {
+		return fmt.Errorf("check-draft-vocab: vocab size of the draft model differs by %d tokens, max is %d", diff, draftVocabMaxDiff)
+	}
+
+	for i := int32(draftVocabCheckStart); i < min(nMain, nDraft); i++ {
+		if llama.VocabGetText(main, llama.Token(i)) != llama.VocabGetText(draft, llama.Token(i)) {
+			return fmt.Errorf("check-draft-vocab: token %d of the draft model doesn't match the main model", i)
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// verifyDraft samples the main model's token at each position of a decoded
+// draft, starting at iBatch which holds the logits for the token before the
+// draft. Sampling stops at the first token that doesn't match the draft, so
+// all the returned tokens except the last were accepted from the draft. The
+// sample function returns the main model's token for a batch index.
+func verifyDraft(sample func(i int32) llama.Token, iBatch int32, draft []llama.Token) []llama.Token {
+	tokens := make([]llama.Token, 0, len(draft)+1)
+
+	for i := 0; i <= len(draft); i++ {
+		token := sample(iBatch + int32(i))
+		tokens = append(tokens, token)
+
+		if i == len(draft) || token != draft[i] {
+			break
+		}
+	}
+
+	return tokens
+}
+
+// speculation holds the state of speculative decoding for the sequential path.
+// The tokens are the ones decoded into the main context, which the draft model
+// uses as context. The pending tokens have been verified and decoded but not
+// yet handed to the caller.
+type speculation struct {
+	batch   llama.Batch
+	tokens  []llama.Token
+	pending []llama.Token
+}
+
+// speculativeResponse is the speculative version of batchResponse for the
+// sequential path. The batch holds the last sampled token. When earlier
+// verified tokens are pending, that token is already decoded and the next
+// pending token is returned without touching the model. Otherwise the draft
+// model proposes tokens which are decoded along with the sampled token, and
+// the ones the main model agrees with become pending.
+func (m *Model) speculativeResponse(lctx llama.Context, batch llama.Batch, sampler llama.Sampler, buf []byte) (string, llama.Token, error) {
+	spec := &m.spec
+
+	if len(spec.pending) > 0 {
+		token := spec.pending[0]
+		spec.pending = spec.pending[1:]
+
+		if m.logprobs.enabled {
+			m.logprobs.last = m.logprobs.pending[0]
+			m.logprobs.pending = m.logprobs.pending[1:]
+		}
+
+		return m.tokenContent(token, buf)
+	}
+
+	last := *batch.Token
+	spec.tokens = append(spec.tokens, last)
+
+	pos, err := llama.MemorySeqPosMax(m.mem, 0)
+	if err != nil {
+		llama.Decode(lctx, batch)
+		return m.sampleToken(lctx, sampler, buf)
+	}
+	pos++
+
+	n := min(m.draft.nDraft, m.cfg.ContextWindow-int(pos)-1)
+
+	draft := m.draft.propose(0, spec.tokens, n)
+	if len(draft) == 0 {
+		llama.Decode(lctx, batch)
+		return m.sampleToken(lctx, sampler, buf)
+	}
+
+	batchClear(&spec.batch)
+	batchAdd(&spec.batch, last, pos, []llama.SeqId{0}, true)
+	for i, token := range draft {
+		batchAdd(&spec.batch, token, pos+llama.Pos(i+1), []llama.SeqId{0}, true)
+	}
+
+	if ret, err := llama.Decode(lctx, spec.batch); err != nil || ret != 0 {
+		return "", 0, fmt.Errorf("speculative-response: unable to decode draft: ret[%d]: %w", ret, err)
+	}
+
+	tokens := verifyDraft(func(i int32) llama.Token {
+		return llama.SamplerSample(sampler, lctx, i)
+	}, 0, draft)
+
+	// Only keep the KV entries for the draft tokens that were accepted.
+	nAccepted := len(tokens) - 1
+	llama.MemorySeqRm(m.mem, 0, pos+llama.Pos(nAccepted+1), -1)
+
+	spec.tokens = append(spec.tokens, draft[:nAccepted]...)
+	spec.pending = tokens[1:]
+
+	if m.logprobs.enabled {
+		m.logprobs.pending = m.logprobs.pending[:0]
+		for i, token := range tokens {
+			m.logprobs.pending = append(m.logprobs.pending, m.tokenLogprob(lctx, int32(i), token, m.logprobs.top))
+		}
+
+		m.logprobs.last = m.logprobs.pending[0]
+		m.logprobs.pending = m.logprobs.pending[1:]
+	}
+
+	return m.tokenContent(tokens[0], buf)
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+func TestVerifyDraft(t *testing.T) {
+	tests := []struct {
+		name   string
+		iBatch int32
+		draft  []llama.Token
+		main   []llama.Token
+		want   []llama.Token
+	}{
+		{"no draft", 0, nil, []llama.Token{7}, []llama.Token{7}},
+		{"all accepted", 0, []llama.Token{1, 2, 3}, []llama.Token{1, 2, 3, 4}, []llama.Token{1, 2, 3, 4}},
+		{"first rejected", 0, []llama.Token{1, 2, 3}, []llama.Token{9, 2, 3, 4}, []llama.Token{9}},
+		{"middle rejected", 0, []llama.Token{1, 2, 3}, []llama.Token{1, 8, 3, 4}, []llama.Token{1, 8}},
+		{"last rejected", 0, []llama.Token{1, 2, 3}, []llama.Token{1, 2, 7, 4}, []llama.Token{1, 2, 7}},
+		{"batch offset", 2, []llama.Token{1, 2}, []llama.Token{0, 0, 1, 5, 6}, []llama.Token{1, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sampled []int32
+			sample := func(i int32) llama.Token {
+				sampled = append(sampled, i)
+				return tt.main[i]
+			}
+
+			got := verifyDraft(sample, tt.iBatch, tt.draft)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("verifyDraft() = %v, want %v", got, tt.want)
+			}
+
+			// Every returned token is sampled from the next batch index.
+			for i, idx := range sampled {
+				if idx != tt.iBatch+int32(i) {
+					t.Errorf("sample %d used index %d, want %d", i, idx, tt.iBatch+int32(i))
+				}
+			}
+
+			if len(sampled) != len(got) {
+				t.Errorf("sampled %d tokens, returned %d", len(sampled), len(got))
+			}
+		})
+	}
+}
@@ -4,24 +4,33 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/hybridgroup/yzma/pkg/llama"
 )
 
-// Embeddings performs batch embedding for multiple inputs in a single
-// forward pass. This is more efficient than calling Embeddings multiple times.
+// Embeddings performs batch embedding for multiple inputs. The inputs are
+// packed into as few forward passes as possible, each input on its own
+// sequence, and the model context is reused across calls.
 //
 // Supported options in d:
 //   - input ([]string): the texts to embed (required)
 //   - truncate (bool): if true, truncate inputs to fit context window (default: false)
 //   - truncate_direction (string): "right" (default) or "left"
 //   - dimensions (int): reduce output to first N dimensions (for Matryoshka models)
+//   - pooling (string): "mean", "cls" or "last" (default: the model's pooling)
+//   - normalize (bool): if false, return the vectors without L2 normalization (default: true)
+//   - encoding_format (string): "float" (default) or "base64"
+//   - chunking (bool): if true, split long inputs into overlapping token windows (default: false)
+//   - chunk_size (int): tokens per window (default: the largest input the model can embed)
+//   - chunk_overlap (int): tokens shared by consecutive windows (default: a tenth of chunk_size)
+//   - chunk_aggregate (string): "mean" (default), "weighted" by window length, or "none"
+//     to return each window's vector with its token offsets
 //
-// Each model instance processes calls sequentially (llama.cpp only supports
-// sequence 0 for embedding extraction). Use NSeqMax > 1 to create multiple
-// model instances for concurrent request handling. Batch multiple texts in the
-// input parameter for better performance within a single request.
+// Each model instance processes calls sequentially. Use NSeqMax > 1 to create
+// multiple model instances for concurrent request handling. Batch multiple
+// texts in the input parameter for better performance within a single request.
 func (m *Model) Embeddings(ctx context.Context, d D) (EmbedReponse, error) {
 	if !m.modelInfo.IsEmbedModel {
 		return EmbedReponse{}, fmt.Errorf("embeddings: model doesn't support embedding")
@@ -54,21 +63,21 @@ func (m *Model) Embeddings(ctx context.Context, d D) (EmbedReponse, error) {
 		return EmbedReponse{}, fmt.Errorf("embeddings: input cannot be empty")
 	}
 
-	// -------------------------------------------------------------------------
-
-	lctx, err := llama.InitFromModel(m.model, m.ctxParams)
+	pooling, err := parsePooling(d)
 	if err != nil {
-		return EmbedReponse{}, fmt.Errorf("embeddings: unable to init from model: %w", err)
+		return EmbedReponse{}, fmt.Errorf("embeddings: %w", err)
 	}
 
-	defer func() {
-		llama.Synchronize(lctx)
-		llama.Free(lctx)
-	}()
+	normalize := true
+	if v, ok := d["normalize"].(bool); ok {
+		normalize = v
+	}
 
-	mem, err := llama.GetMemory(lctx)
-	if err != nil {
-		return EmbedReponse{}, fmt.Errorf("embeddings: unable to get memory: %w", err)
+	encodingFormat, _ := d["encoding_format"].(string)
+	switch encodingFormat {
+	case "", EncodingFormatFloat, EncodingFormatBase64:
+	default:
+		return EmbedReponse{}, fmt.Errorf("embeddings: invalid encoding_format %q (expected float or base64)", encodingFormat)
 	}
 
 	select {
@@ -78,10 +87,23 @@ func (m *Model) Embeddings(ctx context.Context, d D) (EmbedReponse, error) {
 	default:
 	}
 
-	maxTokens := int(llama.NUBatch(lctx))
-	ctxTokens := int(llama.NCtx(lctx))
-	if ctxTokens < maxTokens {
-		maxTokens = ctxTokens
+	lctx, mem, err := m.embedContext(pooling)
+	if err != nil {
+		return EmbedReponse{}, fmt.Errorf("embeddings: %w", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	// A single input must fit in one micro batch, since non-causal models
+	// attend over the whole input at once. Models with a KV cache also split
+	// the context between the sequences.
+	nSeqs := max(int(llama.NSeqMax(lctx)), 1)
+	maxTokens := min(int(llama.NUBatch(lctx)), int(llama.NCtx(lctx)))
+	batchTokens := int(llama.NUBatch(lctx))
+
+	if mem != 0 {
+		maxTokens = min(maxTokens, int(llama.NCtx(lctx))/nSeqs)
+		batchTokens = int(llama.NBatch(lctx))
 	}
 
 	truncate, _ := d["truncate"].(bool)
@@ -93,16 +115,45 @@ func (m *Model) Embeddings(ctx context.Context, d D) (EmbedReponse, error) {
 		return EmbedReponse{}, fmt.Errorf("embeddings: requested %d dimensions but model only has %d", int(requestedDim), nativeDim)
 	}
 
+	chunking, err := parseChunkOptions(d, maxTokens)
+	if err != nil {
+		return EmbedReponse{}, fmt.Errorf("embeddings: %w", err)
+	}
+
 	// -------------------------------------------------------------------------
 
-	// Tokenize all inputs upfront.
-	allTokens := make([][]llama.Token, len(inputs))
+	// Tokenize all inputs upfront. With chunking, a long input is split into
+	// several segments that are embedded separately.
+	var segments []embedSegment
 	for i, input := range inputs {
 		tokens := llama.Tokenize(m.vocab, input, true, true)
 
+		if len(tokens) == 0 {
+			return EmbedReponse{}, fmt.Errorf("embeddings: input[%d] has no tokens", i)
+		}
+
+		if chunking.enabled {
+			pre, post := m.specialTokens(tokens)
+
+			chunks, infos, err := splitChunks(tokens, pre, post, chunking.size, chunking.overlap)
+			if err != nil {
+				return EmbedReponse{}, fmt.Errorf("embeddings: input[%d]: %w", i, err)
+			}
+
+			if len(chunks) > 1 {
+				m.log(ctx, "embeddings", "status", "chunked input", "index", i, "tokens", len(tokens), "chunks", len(chunks), "chunk_size", chunking.size, "overlap", chunking.overlap)
+			}
+
+			for j := range chunks {
+				segments = append(segments, embedSegment{input: i, chunk: infos[j], tokens: chunks[j]})
+			}
+
+			continue
+		}
+
 		if len(tokens) > maxTokens {
 			if !truncate {
-				return EmbedReponse{}, fmt.Errorf("embeddings: input[%d] has %d tokens but max is %d (set truncate=true to auto-truncate)", i, len(tokens), maxTokens)
+				return EmbedReponse{}, fmt.Errorf("embeddings: input[%d] has %d tokens but max is %d (set truncate=true to auto-truncate or chunking=true to split)", i, len(tokens), maxTokens)
 			}
 
 			originalLen := len(tokens)
@@ -118,60 +169,85 @@ func (m *Model) Embeddings(ctx context.Context, d D) (EmbedReponse, error) {
 			m.log(ctx, "embeddings", "status", "truncated input", "index", i, "original_tokens", originalLen, "max_tokens", maxTokens, "direction", direction, "truncated_tokens", len(tokens))
 		}
 
-		allTokens[i] = tokens
+		segments = append(segments, embedSegment{input: i, tokens: tokens})
 	}
 
 	// -------------------------------------------------------------------------
 
-	// Process each input sequentially within the same context.
+	allTokens := make([][]llama.Token, len(segments))
+	for i, seg := range segments {
+		allTokens[i] = seg.tokens
+	}
 
-	embedData := make([]EmbedData, len(inputs))
-	totalPromptTokens := 0
+	vectors, err := m.embedBatches(ctx, lctx, mem, allTokens, packEmbedBatches(allTokens, nSeqs, batchTokens), nativeDim)
+	if err != nil {
+		return EmbedReponse{}, err
+	}
 
-	for i, tokens := range allTokens {
-		select {
-		case <-ctx.Done():
-			return EmbedReponse{}, ctx.Err()
+	finish := func(vec []float32) []float32 {
+		if requestedDim > 0 {
+			vec = vec[:int(requestedDim)]
+		}
 
-		default:
+		if normalize {
+			vec = normalizeVector(vec)
 		}
 
-		totalPromptTokens += len(tokens)
+		return vec
+	}
 
-		batch := llama.BatchGetOne(tokens)
+	totalPromptTokens := 0
+	for _, seg := range segments {
+		totalPromptTokens += len(seg.tokens)
+	}
 
-		ret, err := llama.Decode(lctx, batch)
-		if err != nil {
-			return EmbedReponse{}, fmt.Errorf("embeddings: decode failed for input[%d]: %w", i, err)
-		}
+	var embedData []EmbedData
 
-		if ret != 0 {
-			return EmbedReponse{}, fmt.Errorf("embeddings: decode returned non-zero for input[%d]: %d", i, ret)
-		}
+	switch chunking.aggregate {
+	case ChunkAggregateNone:
+		embedData = make([]EmbedData, len(segments))
 
-		rawVec, err := llama.GetEmbeddingsSeq(lctx, 0, nativeDim)
-		if err != nil {
-			return EmbedReponse{}, fmt.Errorf("embeddings: unable to get embeddings for input[%d]: %w", i, err)
+		for i, seg := range segments {
+			chunk := seg.chunk
+
+			embedData[i] = EmbedData{
+				Object:         "embedding",
+				Index:          seg.input,
+				Embedding:      finish(vectors[i]),
+				Chunk:          &chunk,
+				EncodingFormat: encodingFormat,
+			}
 		}
 
-		// Copy the vector since llama memory is invalidated by MemoryClear.
-		vec := make([]float32, len(rawVec))
-		copy(vec, rawVec)
+	default:
+		embedData = make([]EmbedData, len(inputs))
 
-		if requestedDim > 0 {
-			vec = vec[:int(requestedDim)]
-		}
+		// The segments of an input are next to each other.
+		for start := 0; start < len(segments); {
+			input := segments[start].input
 
-		vec = normalizeVector(vec)
+			end := start + 1
+			for end < len(segments) && segments[end].input == input {
+				end++
+			}
 
-		embedData[i] = EmbedData{
-			Object:    "embedding",
-			Index:     i,
-			Embedding: vec,
-		}
+			weights := make([]float64, end-start)
+			for j, seg := range segments[start:end] {
+				weights[j] = 1
+				if chunking.aggregate == ChunkAggregateWeighted {
+					weights[j] = float64(seg.chunk.End - seg.chunk.Start)
+				}
+			}
+
+			embedData[input] = EmbedData{
+				Object:         "embedding",
+				Index:          input,
+				Embedding:      finish(aggregateVectors(vectors[start:end], weights)),
+				EncodingFormat: encodingFormat,
+			}
 
-		// Clear KV cache before next input.
-		llama.MemoryClear(mem, true)
+			start = end
+		}
 	}
 
 	// -------------------------------------------------------------------------
@@ -190,6 +266,156 @@ func (m *Model) Embeddings(ctx context.Context, d D) (EmbedReponse, error) {
 	return er, nil
 }
 
+// embedSegment is a part of an input that is embedded on its own. Without
+// chunking each input is a single segment.
+type embedSegment struct {
+	input  int
+	chunk  EmbedChunk
+	tokens []llama.Token
+}
+
+// embedBatches decodes each group of inputs in a single pass, with every input
+// of the group on its own sequence, and returns the pooled vector per input.
+func (m *Model) embedBatches(ctx context.Context, lctx llama.Context, mem llama.Memory, allTokens [][]llama.Token, groups []int, nativeDim int32) ([][]float32, error) {
+	vectors := make([][]float32, len(allTokens))
+
+	start := 0
+	for _, end := range groups {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		default:
+		}
+
+		// Encoder only models have no memory to clear.
+		if mem != 0 {
+			llama.MemoryClear(mem, true)
+		}
+
+		batchClear(&m.embedBatch)
+		for i := start; i < end; i++ {
+			seqID := llama.SeqId(i - start)
+			for pos, token := range allTokens[i] {
+				batchAdd(&m.embedBatch, token, llama.Pos(pos), []llama.SeqId{seqID}, true)
+			}
+		}
+
+		ret, err := llama.Decode(lctx, m.embedBatch)
+		if err != nil {
+			return nil, fmt.Errorf("embeddings: decode failed for input[%d:%d]: %w", start, end, err)
+		}
+
+		if ret != 0 {
+			return nil, fmt.Errorf("embeddings: decode returned non-zero for input[%d:%d]: %d", start, end, ret)
+		}
+
+		for i := start; i < end; i++ {
+			rawVec, err := llama.GetEmbeddingsSeq(lctx, llama.SeqId(i-start), nativeDim)
+			if err != nil {
+				return nil, fmt.Errorf("embeddings: unable to get embeddings for input[%d]: %w", i, err)
+			}
+
+			// Copy the vector since llama memory is reused by the next batch.
+			vec := make([]float32, len(rawVec))
+			copy(vec, rawVec)
+
+			vectors[i] = vec
+		}
+
+		start = end
+	}
+
+	return vectors, nil
+}
+
+// poolingContext is a context created for a pooling type other than the one
+// of the model context.
+type poolingContext struct {
+	lctx llama.Context
+	mem  llama.Memory
+}
+
+// embedContext returns the context for the pooling type. The model context is
+// used unless the call asks for a different pooling, in which case a context
+// is created with that pooling and kept for later calls. With FixedPooling
+// set, only the pooling of the model context can be used.
+func (m *Model) embedContext(pooling llama.PoolingType) (llama.Context, llama.Memory, error) {
+	if pooling == llama.PoolingTypeUnspecified || pooling == llama.GetPoolingType(m.lctx) {
+		return m.lctx, m.mem, nil
+	}
+
+	if m.cfg.FixedPooling {
+		return 0, 0, fmt.Errorf("embed-context: only the pooling of the model can be used")
+	}
+
+	if ec, exists := m.embedCtxs[pooling]; exists {
+		return ec.lctx, ec.mem, nil
+	}
+
+	ctxParams := m.ctxParams
+	ctxParams.PoolingType = pooling
+
+	lctx, err := llama.InitFromModel(m.model, ctxParams)
+	if err != nil {
+		return 0, 0, fmt.Errorf("embed-context: unable to init context: %w", err)
+	}
+
+	mem, err := llama.GetMemory(lctx)
+	if err != nil {
+		llama.Free(lctx)
+		return 0, 0, fmt.Errorf("embed-context: unable to get memory: %w", err)
+	}
+
+	if m.embedCtxs == nil {
+		m.embedCtxs = make(map[llama.PoolingType]poolingContext)
+	}
+	m.embedCtxs[pooling] = poolingContext{lctx: lctx, mem: mem}
+
+	return lctx, mem, nil
+}
+
+// parsePooling returns the pooling type asked for by the call.
+func parsePooling(d D) (llama.PoolingType, error) {
+	v, _ := d["pooling"].(string)
+
+	switch strings.ToLower(v) {
+	case "":
+		return llama.PoolingTypeUnspecified, nil
+	case "mean":
+		return llama.PoolingTypeMean, nil
+	case "cls":
+		return llama.PoolingTypeCLS, nil
+	case "last":
+		return llama.PoolingTypeLast, nil
+	}
+
+	return llama.PoolingTypeUnspecified, fmt.Errorf("parse-pooling: invalid pooling %q (expected mean, cls or last)", v)
+}
+
+// packEmbedBatches groups the inputs in order so each group holds at most
+// maxSeqs inputs and maxTokens tokens. It returns the end index of each group.
+func packEmbedBatches(allTokens [][]llama.Token, maxSeqs int, maxTokens int) []int {
+	var groups []int
+
+	var seqs, tokens int
+	for i, t := range allTokens {
+		if seqs > 0 && (seqs == maxSeqs || tokens+len(t) > maxTokens) {
+			groups = append(groups, i)
+			seqs, tokens = 0, 0
+		}
+
+		seqs++
+		tokens += len(t)
+	}
+
+	if seqs > 0 {
+		groups = append(groups, len(allTokens))
+	}
+
+	return groups
+}
+
 // normalizeVector applies L2 normalization to the embedding vector.
 func normalizeVector(vec []float32) []float32 {
 	var sum float64
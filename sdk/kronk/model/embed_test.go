@@ -0,0 +1,98 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+func TestPackEmbedBatches(t *testing.T) {
+	tokens := func(sizes ...int) [][]llama.Token {
+		all := make([][]llama.Token, len(sizes))
+		for i, n := range sizes {
+			all[i] = make([]llama.Token, n)
+		}
+		return all
+	}
+
+	tests := []struct {
+		name      string
+		tokens    [][]llama.Token
+		maxSeqs   int
+		maxTokens int
+		want      []int
+	}{
+		{"empty", nil, 2, 10, nil},
+		{"one batch", tokens(3, 3, 3), 4, 10, []int{3}},
+		{"seq limit", tokens(1, 1, 1, 1, 1), 2, 10, []int{2, 4, 5}},
+		{"token limit", tokens(4, 4, 4), 4, 10, []int{2, 3}},
+		{"exact fit", tokens(5, 5, 5), 4, 10, []int{2, 3}},
+		{"one per batch", tokens(8, 8), 4, 10, []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := packEmbedBatches(tt.tokens, tt.maxSeqs, tt.maxTokens); !slices.Equal(got, tt.want) {
+				t.Errorf("packEmbedBatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePooling(t *testing.T) {
+	tests := []struct {
+		pooling any
+		want    llama.PoolingType
+		wantErr bool
+	}{
+		{nil, llama.PoolingTypeUnspecified, false},
+		{"mean", llama.PoolingTypeMean, false},
+		{"CLS", llama.PoolingTypeCLS, false},
+		{"last", llama.PoolingTypeLast, false},
+		{"max", llama.PoolingTypeUnspecified, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePooling(D{"pooling": tt.pooling})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePooling(%v) error = %v, wantErr %v", tt.pooling, err, tt.wantErr)
+		}
+
+		if got != tt.want {
+			t.Errorf("parsePooling(%v) = %v, want %v", tt.pooling, got, tt.want)
+		}
+	}
+}
+
+func TestEmbedDataJSON(t *testing.T) {
+	vec := []float32{0.5, -1.25, 3}
+
+	for _, format := range []string{"", EncodingFormatBase64} {
+		ed := EmbedData{Object: "embedding", Index: 1, Embedding: vec, EncodingFormat: format}
+
+		data, err := json.Marshal(ed)
+		if err != nil {
+			t.Fatalf("marshal %q: %s", format, err)
+		}
+
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			t.Fatalf("unmarshal raw %q: %s", format, err)
+		}
+
+		if _, isString := raw["embedding"].(string); isString != (format == EncodingFormatBase64) {
+			t.Errorf("format %q: unexpected embedding encoding %s", format, data)
+		}
+
+		var got EmbedData
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("unmarshal %q: %s", format, err)
+		}
+
+		if got.Index != 1 || !slices.Equal(got.Embedding, vec) {
+			t.Errorf("format %q: got %+v, want the original vector", format, got)
+		}
+	}
+}
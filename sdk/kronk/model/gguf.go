@@ -0,0 +1,315 @@
+package model
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// These are the value types used in the GGUF metadata.
+const (
+	ggufTypeUint8   = 0
+	ggufTypeInt8    = 1
+	ggufTypeUint16  = 2
+	ggufTypeInt16   = 3
+	ggufTypeUint32  = 4
+	ggufTypeInt32   = 5
+	ggufTypeFloat32 = 6
+	ggufTypeBool    = 7
+	ggufTypeString  = 8
+	ggufTypeArray   = 9
+	ggufTypeUint64  = 10
+	ggufTypeInt64   = 11
+	ggufTypeFloat64 = 12
+)
+
+const (
+	ggufMagic = "GGUF"
+
+	// ggufMaxArray is the largest integer array that is kept. Arrays with a
+	// value per layer are small, where the tokenizer arrays are huge.
+	ggufMaxArray = 4096
+
+	// ggufMaxString protects against reading a corrupt length.
+	ggufMaxString = 64 * 1024 * 1024
+)
+
+// ggufMeta holds the metadata of a GGUF file. Integers are stored as int64,
+// floats as float64, small integer arrays as []int64 and strings as string.
+// Other arrays are skipped.
+type ggufMeta map[string]any
+
+// readGGUFMeta reads the metadata from the header of a GGUF file without
+// reading any of the tensor data.
+func readGGUFMeta(path string) (ggufMeta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read-gguf-meta: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+
+	magic := make([]byte, len(ggufMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, fmt.Errorf("read-gguf-meta: reading magic: %w", err)
+	}
+
+	if string(magic) != ggufMagic {
+		return nil, fmt.Errorf("read-gguf-meta: file[%s] is not a gguf file", path)
+	}
+
+	var header struct {
+		Version     uint32
+		TensorCount uint64
+		KVCount     uint64
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("read-gguf-meta: reading header: %w", err)
+	}
+
+	// Version 1 used 32 bit lengths and is no longer produced.
+	if header.Version < 2 {
+		return nil, fmt.Errorf("read-gguf-meta: gguf version[%d] is not supported", header.Version)
+	}
+
+	meta := make(ggufMeta, header.KVCount)
+
+	for range header.KVCount {
+		key, err := readGGUFString(r)
+		if err != nil {
+			return nil, fmt.Errorf("read-gguf-meta: reading key: %w", err)
+		}
+
+		var typ uint32
+		if err := binary.Read(r, binary.LittleEndian, &typ); err != nil {
+			return nil, fmt.Errorf("read-gguf-meta: reading type of key[%s]: %w", key, err)
+		}
+
+		val, err := readGGUFValue(r, typ)
+		if err != nil {
+			return nil, fmt.Errorf("read-gguf-meta: reading value of key[%s]: %w", key, err)
+		}
+
+		if val != nil {
+			meta[key] = val
+		}
+	}
+
+	return meta, nil
+}
+
+func readGGUFValue(r *bufio.Reader, typ uint32) (any, error) {
+	switch typ {
+	case ggufTypeString:
+		return readGGUFString(r)
+
+	case ggufTypeArray:
+		return readGGUFArray(r)
+
+	case ggufTypeBool:
+		b, err := r.ReadByte()
+		return b != 0, err
+
+	case ggufTypeFloat32:
+		var v float32
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return float64(v), err
+
+	case ggufTypeFloat64:
+		var v float64
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	}
+
+	return readGGUFInt(r, typ)
+}
+
+func readGGUFInt(r *bufio.Reader, typ uint32) (int64, error) {
+	var err error
+
+	switch typ {
+	case ggufTypeUint8:
+		var v uint8
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+
+	case ggufTypeInt8:
+		var v int8
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+
+	case ggufTypeUint16:
+		var v uint16
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+
+	case ggufTypeInt16:
+		var v int16
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+
+	case ggufTypeUint32:
+		var v uint32
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+
+	case ggufTypeInt32:
+		var v int32
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+
+	case ggufTypeUint64:
+		var v uint64
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+
+	case ggufTypeInt64:
+		var v int64
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	}
+
+	return 0, fmt.Errorf("read-gguf-int: unknown type[%d]", typ)
+}
+
+func readGGUFString(r *bufio.Reader) (string, error) {
+	var n uint64
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return "", err
+	}
+
+	if n > ggufMaxString {
+		return "", fmt.Errorf("read-gguf-string: length[%d] is too large", n)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// readGGUFArray returns small integer arrays and skips everything else, which
+// returns a nil value.
+func readGGUFArray(r *bufio.Reader) (any, error) {
+	var header struct {
+		Type  uint32
+		Count uint64
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+
+	if size := ggufTypeSize(header.Type); size > 0 {
+		if header.Type == ggufTypeFloat32 || header.Type == ggufTypeFloat64 || header.Type == ggufTypeBool || header.Count > ggufMaxArray {
+			_, err := io.CopyN(io.Discard, r, int64(size)*int64(header.Count))
+			return nil, err
+		}
+
+		vals := make([]int64, header.Count)
+		for i := range vals {
+			v, err := readGGUFInt(r, header.Type)
+			if err != nil {
+				return nil, err
+			}
+			vals[i] = v
+		}
+
+		return vals, nil
+	}
+
+	for range header.Count {
+		switch header.Type {
+		case ggufTypeString:
+			if _, err := readGGUFString(r); err != nil {
+				return nil, err
+			}
+
+		case ggufTypeArray:
+			if _, err := readGGUFArray(r); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("read-gguf-array: unknown type[%d]", header.Type)
+		}
+	}
+
+	return nil, nil
+}
+
+// ggufTypeSize returns the size in bytes of the fixed size value types and 0
+// for strings and arrays.
+func ggufTypeSize(typ uint32) int {
+	switch typ {
+	case ggufTypeUint8, ggufTypeInt8, ggufTypeBool:
+		return 1
+
+	case ggufTypeUint16, ggufTypeInt16:
+		return 2
+
+	case ggufTypeUint32, ggufTypeInt32, ggufTypeFloat32:
+		return 4
+
+	case ggufTypeUint64, ggufTypeInt64, ggufTypeFloat64:
+		return 8
+	}
+
+	return 0
+}
+
+// =============================================================================
+
+// str returns the string value for the key.
+func (m ggufMeta) str(key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// num returns the integer value for the key. For a per layer array, the
+// largest value is returned.
+func (m ggufMeta) num(key string) int64 {
+	switch v := m[key].(type) {
+	case int64:
+		return v
+
+	case []int64:
+		var result int64
+		for _, n := range v {
+			result = max(result, n)
+		}
+		return result
+	}
+
+	return 0
+}
+
+// perLayer returns the value of the key for each of the layers. A key can
+// hold a single value for all layers or an array with a value per layer.
+func (m ggufMeta) perLayer(key string, nLayer int64) ([]int64, error) {
+	result := make([]int64, nLayer)
+
+	switch v := m[key].(type) {
+	case int64:
+		for i := range result {
+			result[i] = v
+		}
+
+	case []int64:
+		if int64(len(v)) != nLayer {
+			return nil, fmt.Errorf("per-layer: key[%s] has %d values for %d layers", key, len(v), nLayer)
+		}
+		copy(result, v)
+
+	default:
+		return nil, fmt.Errorf("per-layer: key[%s] not found", key)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,563 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// grammarRoot is the name of the rule where grammar evaluation begins.
+const grammarRoot = "root"
+
+// grammarSpace bounds the whitespace a model can emit between JSON tokens so
+// generation can't get stuck producing an endless run of blanks.
+const grammarSpace = `| " " | "\n"{1,2} [ \t]{0,20}`
+
+// grammarPrimitives are the GBNF rules for the JSON primitive types along with
+// the other rules each one depends on.
+var grammarPrimitives = map[string]struct {
+	rule string
+	deps []string
+}{
+	"boolean":       {`("true" | "false") space`, nil},
+	"char":          {`[^"\\\x7F\x00-\x1F] | [\\] (["\\bfnrt] | "u" [0-9a-fA-F]{4})`, nil},
+	"decimal-part":  {`[0-9]{1,16}`, nil},
+	"integral-part": {`[0] | [1-9] [0-9]{0,15}`, nil},
+	"integer":       {`("-"? integral-part) space`, []string{"integral-part"}},
+	"number":        {`("-"? integral-part) ("." decimal-part)? ([eE] [-+]? integral-part)? space`, []string{"integral-part", "decimal-part"}},
+	"null":          {`"null" space`, nil},
+	"string":        {`"\"" char* "\"" space`, []string{"char"}},
+	"object":        {`"{" space ( string ":" space value ("," space string ":" space value)* )? "}" space`, []string{"string", "value"}},
+	"array":         {`"[" space ( value ("," space value)* )? "]" space`, []string{"value"}},
+	"value":         {`object | array | string | number | boolean | null`, []string{"object", "array", "string", "number", "boolean", "null"}},
+}
+
+var invalidRuleChars = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// jsonObjectGrammar returns a grammar that accepts any JSON object.
+func jsonObjectGrammar() string {
+	c := newSchemaConverter(nil)
+	c.addRule(grammarRoot, c.primitive("object"))
+
+	return c.format()
+}
+
+// jsonSchemaToGrammar compiles a JSON schema into a GBNF grammar. The schema
+// can be provided as a JSON string or as a decoded document.
+//
+// Object properties are emitted in alphabetical order with required properties
+// first. Constraints that can't be expressed in the grammar, such as pattern
+// or minimum, are ignored.
+func jsonSchemaToGrammar(schema any) (string, error) {
+	var data []byte
+
+	switch v := schema.(type) {
+	case string:
+		data = []byte(v)
+
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return "", fmt.Errorf("json-schema-to-grammar: unable to marshal schema: %w", err)
+		}
+	}
+
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return "", fmt.Errorf("json-schema-to-grammar: unable to unmarshal schema: %w", err)
+	}
+
+	c := newSchemaConverter(root)
+
+	if _, err := c.visit(root, grammarRoot); err != nil {
+		return "", fmt.Errorf("json-schema-to-grammar: %w", err)
+	}
+
+	return c.format(), nil
+}
+
+// =============================================================================
+
+type schemaConverter struct {
+	root  any
+	rules map[string]string
+	refs  map[string]string
+}
+
+func newSchemaConverter(root any) *schemaConverter {
+	c := schemaConverter{
+		root:  root,
+		rules: map[string]string{"space": grammarSpace},
+		refs:  make(map[string]string),
+	}
+
+	return &c
+}
+
+// format renders the rules with the root rule first followed by the rest in
+// alphabetical order.
+func (c *schemaConverter) format() string {
+	names := make([]string, 0, len(c.rules))
+	for name := range c.rules {
+		if name != grammarRoot {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s ::= %s\n", grammarRoot, c.rules[grammarRoot])
+
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s ::= %s\n", name, c.rules[name])
+	}
+
+	return b.String()
+}
+
+// addRule stores the rule under the specified name, picking a unique name when
+// a different rule already uses it. It returns the name the rule was stored as.
+func (c *schemaConverter) addRule(name string, rule string) string {
+	name = invalidRuleChars.ReplaceAllString(name, "-")
+
+	key := name
+	for i := 0; ; i++ {
+		existing, exists := c.rules[key]
+		if !exists || existing == rule {
+			break
+		}
+		key = fmt.Sprintf("%s%d", name, i)
+	}
+
+	c.rules[key] = rule
+
+	return key
+}
+
+// primitive adds the named primitive rule and its dependencies.
+func (c *schemaConverter) primitive(name string) string {
+	p := grammarPrimitives[name]
+
+	c.rules[name] = p.rule
+	for _, dep := range p.deps {
+		if _, exists := c.rules[dep]; !exists {
+			c.primitive(dep)
+		}
+	}
+
+	return name
+}
+
+// visit converts the schema into a rule and returns the name of that rule.
+func (c *schemaConverter) visit(schema any, name string) (string, error) {
+	var s map[string]any
+
+	switch v := schema.(type) {
+	case nil:
+		return c.addRule(name, c.primitive("value")), nil
+
+	case bool:
+		if !v {
+			return "", fmt.Errorf("visit: schema[%s] accepts no values", name)
+		}
+		return c.addRule(name, c.primitive("value")), nil
+
+	case map[string]any:
+		s = v
+
+	default:
+		return "", fmt.Errorf("visit: schema[%s] is not an object", name)
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		return c.visitRef(ref, name)
+	}
+
+	if v, exists := s["const"]; exists {
+		return c.addRule(name, jsonLiteral(v)+" space"), nil
+	}
+
+	if v, ok := s["enum"].([]any); ok {
+		alts := make([]string, len(v))
+		for i, e := range v {
+			alts[i] = jsonLiteral(e)
+		}
+		return c.addRule(name, "("+strings.Join(alts, " | ")+") space"), nil
+	}
+
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if v, ok := s[key].([]any); ok {
+			return c.visitAlternatives(v, name)
+		}
+	}
+
+	if v, ok := s["allOf"].([]any); ok {
+		merged, err := c.mergeAllOf(v)
+		if err != nil {
+			return "", err
+		}
+		return c.visit(merged, name)
+	}
+
+	switch typ := s["type"].(type) {
+	case []any:
+		alts := make([]any, len(typ))
+		for i, t := range typ {
+			sub := make(map[string]any, len(s))
+			for k, v := range s {
+				sub[k] = v
+			}
+			sub["type"] = t
+			alts[i] = sub
+		}
+		return c.visitAlternatives(alts, name)
+
+	case string:
+		return c.visitType(typ, s, name)
+
+	case nil:
+		switch {
+		case s["properties"] != nil:
+			return c.visitType("object", s, name)
+
+		case s["items"] != nil || s["prefixItems"] != nil:
+			return c.visitType("array", s, name)
+		}
+
+		return c.addRule(name, c.primitive("value")), nil
+
+	default:
+		return "", fmt.Errorf("visit: schema[%s] has an invalid type", name)
+	}
+}
+
+func (c *schemaConverter) visitType(typ string, s map[string]any, name string) (string, error) {
+	switch typ {
+	case "object":
+		return c.visitObject(s, name)
+
+	case "array":
+		return c.visitArray(s, name)
+
+	case "string":
+		minLen, hasMin := schemaInt(s, "minLength")
+		maxLen, hasMax := schemaInt(s, "maxLength")
+		if !hasMin && !hasMax {
+			return c.addRule(name, c.primitive("string")), nil
+		}
+
+		c.primitive("char")
+		rule := fmt.Sprintf(`"\"" %s "\"" space`, repeat("char", minLen, maxLen, hasMax))
+		return c.addRule(name, rule), nil
+
+	case "integer", "number", "boolean", "null":
+		return c.addRule(name, c.primitive(typ)), nil
+
+	default:
+		return "", fmt.Errorf("visit-type: schema[%s] has an unsupported type[%s]", name, typ)
+	}
+}
+
+func (c *schemaConverter) visitRef(ref string, name string) (string, error) {
+	if rule, exists := c.refs[ref]; exists {
+		return rule, nil
+	}
+
+	target, err := c.resolveRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	// Reserve the rule name before visiting so recursive schemas terminate.
+	refName := invalidRuleChars.ReplaceAllString("ref"+ref[1:], "-")
+	c.refs[ref] = refName
+
+	if _, err := c.visit(target, refName); err != nil {
+		return "", err
+	}
+
+	if name == grammarRoot {
+		return c.addRule(name, refName), nil
+	}
+
+	return refName, nil
+}
+
+func (c *schemaConverter) resolveRef(ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("resolve-ref: only local references are supported[%s]", ref)
+	}
+
+	var target any = c.root
+	for part := range strings.SplitSeq(strings.TrimPrefix(ref, "#/"), "/") {
+		m, ok := target.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("resolve-ref: unable to resolve reference[%s]", ref)
+		}
+
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		if target, ok = m[part]; !ok {
+			return nil, fmt.Errorf("resolve-ref: unable to resolve reference[%s]", ref)
+		}
+	}
+
+	return target, nil
+}
+
+func (c *schemaConverter) visitAlternatives(schemas []any, name string) (string, error) {
+	alts := make([]string, len(schemas))
+	for i, sub := range schemas {
+		rule, err := c.visit(sub, fmt.Sprintf("%s-%d", name, i))
+		if err != nil {
+			return "", err
+		}
+		alts[i] = rule
+	}
+
+	return c.addRule(name, strings.Join(alts, " | ")), nil
+}
+
+func (c *schemaConverter) visitObject(s map[string]any, name string) (string, error) {
+	props, _ := s["properties"].(map[string]any)
+
+	if len(props) == 0 {
+		switch ap := s["additionalProperties"].(type) {
+		case map[string]any:
+			valueRule, err := c.visit(ap, name+"-value")
+			if err != nil {
+				return "", err
+			}
+
+			c.primitive("string")
+			kv := fmt.Sprintf(`string ":" space %s`, valueRule)
+			rule := fmt.Sprintf(`"{" space ( %s ( "," space %s )* )? "}" space`, kv, kv)
+			return c.addRule(name, rule), nil
+
+		case bool:
+			if !ap {
+				return c.addRule(name, `"{" space "}" space`), nil
+			}
+		}
+
+		return c.addRule(name, c.primitive("object")), nil
+	}
+
+	requiredSet := make(map[string]bool)
+	if req, ok := s["required"].([]any); ok {
+		for _, r := range req {
+			if key, ok := r.(string); ok {
+				requiredSet[key] = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var required, optional []string
+	kvRules := make(map[string]string, len(keys))
+
+	for _, key := range keys {
+		valueRule, err := c.visit(props[key], name+"-"+key)
+		if err != nil {
+			return "", err
+		}
+
+		kvRules[key] = c.addRule(name+"-"+key+"-kv", fmt.Sprintf(`%s space ":" space %s`, jsonLiteral(key), valueRule))
+
+		switch requiredSet[key] {
+		case true:
+			required = append(required, key)
+		default:
+			optional = append(optional, key)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`"{" space `)
+
+	for i, key := range required {
+		if i > 0 {
+			b.WriteString(` "," space `)
+		}
+		b.WriteString(kvRules[key])
+	}
+
+	if len(optional) > 0 {
+		b.WriteString(" (")
+		if len(required) > 0 {
+			b.WriteString(` "," space ( `)
+		}
+
+		// Each alternative starts with a different optional property and
+		// allows any subset of the properties that follow it.
+		alts := make([]string, len(optional))
+		for i := range optional {
+			alts[i] = c.optionalProps(name, optional[i:], kvRules, false)
+		}
+		b.WriteString(strings.Join(alts, " | "))
+
+		if len(required) > 0 {
+			b.WriteString(" )")
+		}
+		b.WriteString(" )?")
+	}
+
+	b.WriteString(` "}" space`)
+
+	return c.addRule(name, b.String()), nil
+}
+
+func (c *schemaConverter) optionalProps(name string, keys []string, kvRules map[string]string, firstIsOptional bool) string {
+	kv := kvRules[keys[0]]
+
+	var rule string
+	switch firstIsOptional {
+	case true:
+		rule = fmt.Sprintf(`( "," space %s )?`, kv)
+	default:
+		rule = kv
+	}
+
+	if len(keys) > 1 {
+		rest := c.optionalProps(name, keys[1:], kvRules, true)
+		rule += " " + c.addRule(name+"-"+keys[0]+"-rest", rest)
+	}
+
+	return rule
+}
+
+func (c *schemaConverter) visitArray(s map[string]any, name string) (string, error) {
+	if prefix, ok := s["prefixItems"].([]any); ok {
+		items := make([]string, len(prefix))
+		for i, sub := range prefix {
+			rule, err := c.visit(sub, fmt.Sprintf("%s-tuple-%d", name, i))
+			if err != nil {
+				return "", err
+			}
+			items[i] = rule
+		}
+
+		rule := `"[" space ` + strings.Join(items, ` "," space `) + ` "]" space`
+		return c.addRule(name, rule), nil
+	}
+
+	itemRule, err := c.visit(s["items"], name+"-item")
+	if err != nil {
+		return "", err
+	}
+
+	minItems, _ := schemaInt(s, "minItems")
+	maxItems, hasMax := schemaInt(s, "maxItems")
+
+	if hasMax && maxItems == 0 {
+		return c.addRule(name, `"[" space "]" space`), nil
+	}
+
+	var more string
+	switch {
+	case hasMax:
+		more = repeat(fmt.Sprintf(`( "," space %s )`, itemRule), max(minItems-1, 0), maxItems-1, true)
+	default:
+		more = repeat(fmt.Sprintf(`( "," space %s )`, itemRule), max(minItems-1, 0), 0, false)
+	}
+
+	items := itemRule + " " + more
+	if minItems == 0 {
+		items = "( " + items + " )?"
+	}
+
+	return c.addRule(name, `"[" space `+items+` "]" space`), nil
+}
+
+func (c *schemaConverter) mergeAllOf(schemas []any) (map[string]any, error) {
+	props := make(map[string]any)
+	var required []any
+
+	for _, sub := range schemas {
+		s, ok := sub.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("merge-all-of: schema is not an object")
+		}
+
+		if ref, ok := s["$ref"].(string); ok {
+			target, err := c.resolveRef(ref)
+			if err != nil {
+				return nil, err
+			}
+
+			if s, ok = target.(map[string]any); !ok {
+				return nil, fmt.Errorf("merge-all-of: reference[%s] is not an object", ref)
+			}
+		}
+
+		if p, ok := s["properties"].(map[string]any); ok {
+			for k, v := range p {
+				props[k] = v
+			}
+		}
+
+		if r, ok := s["required"].([]any); ok {
+			required = append(required, r...)
+		}
+	}
+
+	merged := map[string]any{
+		"type":       "object",
+		"properties": props,
+		"required":   required,
+	}
+
+	return merged, nil
+}
+
+// =============================================================================
+
+// jsonLiteral renders the value as JSON inside a GBNF string literal.
+func jsonLiteral(v any) string {
+	data, _ := json.Marshal(v)
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+	return `"` + r.Replace(string(data)) + `"`
+}
+
+// repeat renders the GBNF repetition of the item with min and max bounds.
+func repeat(item string, minN int, maxN int, hasMax bool) string {
+	switch {
+	case hasMax && maxN == 0:
+		return ""
+
+	case !hasMax && minN == 0:
+		return item + "*"
+
+	case !hasMax && minN == 1:
+		return item + "+"
+
+	case !hasMax:
+		return fmt.Sprintf("%s{%d,}", item, minN)
+
+	case minN == 0 && maxN == 1:
+		return item + "?"
+
+	case minN == maxN:
+		return fmt.Sprintf("%s{%d}", item, minN)
+
+	default:
+		return fmt.Sprintf("%s{%d,%d}", item, minN, maxN)
+	}
+}
+
+func schemaInt(s map[string]any, key string) (int, bool) {
+	v, ok := s[key].(float64)
+	if !ok || v < 0 {
+		return 0, false
+	}
+
+	return int(v), true
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONSchemaToGrammar(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"age": {"type": "integer"},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 3},
+			"kind": {"enum": ["cat", "dog"]}
+		},
+		"required": ["name", "age"]
+	}`
+
+	grammar, err := jsonSchemaToGrammar(schema)
+	if err != nil {
+		t.Fatalf("jsonSchemaToGrammar() error = %v", err)
+	}
+
+	want := []string{
+		`root ::= "{" space root-age-kv "," space root-name-kv ( "," space ( root-kind-kv root-kind-rest | root-tags-kv ) )? "}" space`,
+		`root-age-kv ::= "\"age\"" space ":" space root-age`,
+		`root-age ::= integer`,
+		`root-kind ::= ("\"cat\"" | "\"dog\"") space`,
+		`root-kind-rest ::= ( "," space root-tags-kv )?`,
+		`root-tags ::= "[" space ( root-tags-item ( "," space root-tags-item ){0,2} )? "]" space`,
+		`space ::= | " " | "\n"{1,2} [ \t]{0,20}`,
+		`string ::= "\"" char* "\"" space`,
+	}
+
+	for _, w := range want {
+		if !strings.Contains(grammar, w+"\n") {
+			t.Errorf("grammar missing rule:\n%s\ngot:\n%s", w, grammar)
+		}
+	}
+
+	if !strings.HasPrefix(grammar, "root ::= ") {
+		t.Errorf("grammar should start with the root rule, got:\n%s", grammar)
+	}
+}
+
+func TestJSONSchemaToGrammarRef(t *testing.T) {
+	schema := D{
+		"$defs": D{
+			"node": D{
+				"type": "object",
+				"properties": D{
+					"value":    D{"type": "number"},
+					"children": D{"type": "array", "items": D{"$ref": "#/$defs/node"}},
+				},
+				"required": []any{"value", "children"},
+			},
+		},
+		"$ref": "#/$defs/node",
+	}
+
+	grammar, err := jsonSchemaToGrammar(schema)
+	if err != nil {
+		t.Fatalf("jsonSchemaToGrammar() error = %v", err)
+	}
+
+	want := []string{
+		`root ::= ref-defs-node`,
+		`ref-defs-node-children ::= "[" space ( ref-defs-node ( "," space ref-defs-node )* )? "]" space`,
+	}
+
+	for _, w := range want {
+		if !strings.Contains(grammar, w+"\n") {
+			t.Errorf("grammar missing rule:\n%s\ngot:\n%s", w, grammar)
+		}
+	}
+}
+
+func TestJSONSchemaToGrammarErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{"invalid json", `{"type": `},
+		{"unknown type", `{"type": "date"}`},
+		{"remote ref", `{"$ref": "https://example.com/schema.json"}`},
+		{"missing ref", `{"$ref": "#/$defs/missing"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := jsonSchemaToGrammar(tt.schema); err == nil {
+				t.Errorf("jsonSchemaToGrammar() expected error")
+			}
+		})
+	}
+}
+
+func TestParseResponseFormat(t *testing.T) {
+	schema := D{"type": "object", "properties": D{"ok": D{"type": "boolean"}}, "required": []any{"ok"}}
+
+	tests := []struct {
+		name    string
+		val     any
+		want    string
+		wantErr bool
+	}{
+		{"text", D{"type": "text"}, "", false},
+		{"json object", D{"type": "json_object"}, `root ::= object`, false},
+		{"chat schema", D{"type": "json_schema", "json_schema": D{"name": "result", "schema": schema}}, `root ::= "{" space root-ok-kv "}" space`, false},
+		{"responses schema", map[string]any{"type": "json_schema", "name": "result", "schema": schema}, `root ::= "{" space root-ok-kv "}" space`, false},
+		{"missing schema", D{"type": "json_schema"}, "", true},
+		{"unknown type", D{"type": "xml"}, "", true},
+		{"invalid type", "json", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseResponseFormat("response_format", tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseResponseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("parseResponseFormat() = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
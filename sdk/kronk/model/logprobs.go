@@ -0,0 +1,158 @@
+package model
+
+import (
+	"math"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+// maxTopLogprobs is the largest number of alternatives that can be requested
+// per token, which matches the OpenAI API.
+const maxTopLogprobs = 20
+
+// tokenProb is a token along with its log probability.
+type tokenProb struct {
+	token   llama.Token
+	logprob float32
+}
+
+// computeLogprobs converts the logits into the log probability of the token
+// and of the n most likely tokens, ordered from most to least likely.
+func computeLogprobs(logits []float32, token llama.Token, n int) (float32, []tokenProb) {
+	maxLogit := float32(math.Inf(-1))
+	for _, l := range logits {
+		maxLogit = max(maxLogit, l)
+	}
+
+	var sum float64
+	for _, l := range logits {
+		sum += math.Exp(float64(l - maxLogit))
+	}
+
+	logSum := float32(math.Log(sum)) + maxLogit
+
+	var logprob float32
+	if token >= 0 && int(token) < len(logits) {
+		logprob = logits[token] - logSum
+	}
+
+	if n <= 0 {
+		return logprob, nil
+	}
+
+	// Keep the best n tokens sorted with an insertion into a small slice,
+	// which is cheaper than sorting the whole vocabulary.
+	top := make([]tokenProb, 0, n+1)
+	for i, l := range logits {
+		if len(top) == n && l <= top[n-1].logprob {
+			continue
+		}
+
+		j := len(top)
+		for j > 0 && top[j-1].logprob < l {
+			j--
+		}
+
+		top = append(top, tokenProb{})
+		copy(top[j+1:], top[j:])
+		top[j] = tokenProb{token: llama.Token(i), logprob: l}
+
+		if len(top) > n {
+			top = top[:n]
+		}
+	}
+
+	for i := range top {
+		top[i].logprob -= logSum
+	}
+
+	return logprob, top
+}
+
+// tokenLogprob returns the log probability details for the token sampled
+// from the logits at index i of the last decode.
+func (m *Model) tokenLogprob(lctx llama.Context, i int32, token llama.Token, n int) ContentLogprob {
+	piece := m.tokenPiece(token)
+
+	cl := ContentLogprob{
+		Token:       piece,
+		Bytes:       toBytes(piece),
+		TopLogprobs: []TopLogprob{},
+	}
+
+	logits, err := llama.GetLogitsIth(lctx, i, int(llama.VocabNTokens(m.vocab)))
+	if err != nil || logits == nil {
+		return cl
+	}
+
+	logprob, top := computeLogprobs(logits, token, n)
+	cl.Logprob = logprob
+
+	for _, tp := range top {
+		piece := m.tokenPiece(tp.token)
+		cl.TopLogprobs = append(cl.TopLogprobs, TopLogprob{
+			Token:   piece,
+			Logprob: tp.logprob,
+			Bytes:   toBytes(piece),
+		})
+	}
+
+	return cl
+}
+
+// tokenPiece returns the text for the token.
+func (m *Model) tokenPiece(token llama.Token) string {
+	buf := make([]byte, 256)
+	l := llama.TokenToPiece(m.vocab, token, buf, 0, true)
+	if l <= 0 {
+		return ""
+	}
+
+	return string(buf[:l])
+}
+
+// toBytes returns the UTF-8 bytes of the text as ints like the OpenAI API.
+func toBytes(s string) []int {
+	b := make([]int, len(s))
+	for i := range len(s) {
+		b[i] = int(s[i])
+	}
+
+	return b
+}
+
+// toLogprobs returns the log probabilities for a response, which are only
+// provided when the request asks for them.
+func toLogprobs(enabled bool, content []ContentLogprob) *Logprobs {
+	if !enabled {
+		return nil
+	}
+
+	if content == nil {
+		content = []ContentLogprob{}
+	}
+
+	return &Logprobs{Content: content}
+}
+
+// =============================================================================
+
+// logprobState records the log probabilities of the tokens sampled on the
+// sequential path. The pending values belong to the verified draft tokens
+// waiting in the speculation state.
+type logprobState struct {
+	enabled bool
+	top     int
+	last    ContentLogprob
+	pending []ContentLogprob
+}
+
+// recordLogprob stores the log probability details of the sampled token when
+// the request asks for them.
+func (m *Model) recordLogprob(lctx llama.Context, i int32, token llama.Token) {
+	if !m.logprobs.enabled {
+		return
+	}
+
+	m.logprobs.last = m.tokenLogprob(lctx, i, token, m.logprobs.top)
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+func TestComputeLogprobs(t *testing.T) {
+	logits := []float32{1, 3, 2, 0}
+
+	var sum float64
+	for _, l := range logits {
+		sum += math.Exp(float64(l))
+	}
+	logSum := math.Log(sum)
+
+	want := func(i int) float32 {
+		return float32(float64(logits[i]) - logSum)
+	}
+
+	const eps = 1e-5
+
+	t.Run("token only", func(t *testing.T) {
+		lp, top := computeLogprobs(logits, 2, 0)
+		if math.Abs(float64(lp-want(2))) > eps {
+			t.Errorf("logprob = %v, want %v", lp, want(2))
+		}
+
+		if top != nil {
+			t.Errorf("top = %v, want nil", top)
+		}
+	})
+
+	t.Run("top tokens ordered", func(t *testing.T) {
+		lp, top := computeLogprobs(logits, 0, 3)
+		if math.Abs(float64(lp-want(0))) > eps {
+			t.Errorf("logprob = %v, want %v", lp, want(0))
+		}
+
+		wantTokens := []llama.Token{1, 2, 0}
+		if len(top) != len(wantTokens) {
+			t.Fatalf("len(top) = %d, want %d", len(top), len(wantTokens))
+		}
+
+		for i, tp := range top {
+			if tp.token != wantTokens[i] {
+				t.Errorf("top[%d].token = %d, want %d", i, tp.token, wantTokens[i])
+			}
+
+			if math.Abs(float64(tp.logprob-want(int(tp.token)))) > eps {
+				t.Errorf("top[%d].logprob = %v, want %v", i, tp.logprob, want(int(tp.token)))
+			}
+		}
+	})
+
+	t.Run("more than vocab", func(t *testing.T) {
+		_, top := computeLogprobs(logits, 0, 10)
+		if len(top) != len(logits) {
+			t.Errorf("len(top) = %d, want %d", len(top), len(logits))
+		}
+	})
+}
+
+func TestToBytes(t *testing.T) {
+	got := toBytes("hé")
+	want := []int{104, 195, 169}
+
+	if len(got) != len(want) {
+		t.Fatalf("toBytes() = %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("toBytes() = %v, want %v", got, want)
+		}
+	}
+}
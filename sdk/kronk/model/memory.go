@@ -0,0 +1,143 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EstimateMemory returns an estimate of the bytes needed to load the model
+// described by the config. It covers the weights, which are sized by the model
+// files, and the KV cache, which is calculated from the GGUF metadata, the
+// context window and the cache types. Models that are loaded as a pool of
+// instances are counted once per instance.
+func EstimateMemory(cfg Config) (int64, error) {
+	if len(cfg.ModelFiles) == 0 {
+		return 0, fmt.Errorf("estimate-memory: model file is required")
+	}
+
+	total, err := estimateModel(cfg, cfg.ModelFiles)
+	if err != nil {
+		return 0, fmt.Errorf("estimate-memory: %w", err)
+	}
+
+	if cfg.ProjFile != "" {
+		size, err := filesSize([]string{cfg.ProjFile})
+		if err != nil {
+			return 0, fmt.Errorf("estimate-memory: %w", err)
+		}
+		total += size
+	}
+
+	modelID := modelIDFromFiles(cfg.ModelFiles)
+	isEmbedOrRerank := strings.Contains(modelID, "embed") || strings.Contains(modelID, "rerank")
+
+	// The draft model isn't loaded for embedding and reranking models.
+	if len(cfg.DraftModelFiles) > 0 && !isEmbedOrRerank {
+		size, err := estimateModel(cfg, cfg.DraftModelFiles)
+		if err != nil {
+			return 0, fmt.Errorf("estimate-memory: draft: %w", err)
+		}
+		total += size
+	}
+
+	// These models handle concurrency with NSeqMax instances of the model
+	// instead of parallel sequences in a single instance.
+	if cfg.ProjFile != "" || isEmbedOrRerank {
+		total *= int64(max(cfg.NSeqMax, 1))
+	}
+
+	return total, nil
+}
+
+// estimateModel returns the size of the weights and the KV cache for the
+// model files.
+func estimateModel(cfg Config, modelFiles []string) (int64, error) {
+	weights, err := filesSize(modelFiles)
+	if err != nil {
+		return 0, err
+	}
+
+	// Split models hold the metadata in the first file.
+	meta, err := readGGUFMeta(modelFiles[0])
+	if err != nil {
+		return 0, fmt.Errorf("estimate-model: %w", err)
+	}
+
+	kv, err := estimateKVCache(cfg, meta)
+	if err != nil {
+		return 0, fmt.Errorf("estimate-model: %w", err)
+	}
+
+	return weights + kv, nil
+}
+
+// estimateKVCache returns the size of the KV cache. Each layer stores a key
+// and a value vector per KV head for every cell of the context window.
+func estimateKVCache(cfg Config, meta ggufMeta) (int64, error) {
+	arch := meta.str("general.architecture")
+	if arch == "" {
+		return 0, fmt.Errorf("estimate-kv-cache: architecture not found")
+	}
+
+	nLayer := meta.num(arch + ".block_count")
+	if nLayer <= 0 || nLayer > ggufMaxArray {
+		return 0, fmt.Errorf("estimate-kv-cache: invalid layer count[%d]", nLayer)
+	}
+
+	// Models without attention, like recurrent models, have no KV cache.
+	nHead, err := meta.perLayer(arch+".attention.head_count", nLayer)
+	if err != nil {
+		return 0, nil
+	}
+
+	nHeadKV, err := meta.perLayer(arch+".attention.head_count_kv", nLayer)
+	if err != nil {
+		nHeadKV = nHead
+	}
+
+	nEmbd := meta.num(arch + ".embedding_length")
+	maxHead := max(meta.num(arch+".attention.head_count"), 1)
+
+	keyLen := meta.num(arch + ".attention.key_length")
+	if keyLen <= 0 {
+		keyLen = nEmbd / maxHead
+	}
+
+	valLen := meta.num(arch + ".attention.value_length")
+	if valLen <= 0 {
+		valLen = nEmbd / maxHead
+	}
+
+	nCtx := int64(cfg.ContextWindow)
+	if nCtx <= 0 {
+		nCtx = meta.num(arch + ".context_length")
+	}
+	if nCtx <= 0 {
+		nCtx = defContextWindow
+	}
+
+	kSize := kvCacheType(cfg.CacheTypeK).bytesPerElement()
+	vSize := kvCacheType(cfg.CacheTypeV).bytesPerElement()
+
+	var perCell float64
+	for i := range nLayer {
+		perCell += float64(nHeadKV[i]) * (float64(keyLen)*kSize + float64(valLen)*vSize)
+	}
+
+	return int64(perCell * float64(nCtx)), nil
+}
+
+// filesSize returns the total size of the files.
+func filesSize(files []string) (int64, error) {
+	var total int64
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return 0, fmt.Errorf("files-size: %w", err)
+		}
+		total += info.Size()
+	}
+
+	return total, nil
+}
@@ -0,0 +1,236 @@
+package model
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// ggufKV is a metadata key/value written by writeGGUF.
+type ggufKV struct {
+	key string
+	val any
+}
+
+// writeGGUF writes a GGUF file that only holds metadata.
+func writeGGUF(t *testing.T, kvs []ggufKV) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := func(v any) {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	str := func(s string) {
+		w(uint64(len(s)))
+		buf.WriteString(s)
+	}
+
+	buf.WriteString(ggufMagic)
+	w(uint32(3))
+	w(uint64(0))
+	w(uint64(len(kvs)))
+
+	for _, kv := range kvs {
+		str(kv.key)
+
+		switch v := kv.val.(type) {
+		case string:
+			w(uint32(ggufTypeString))
+			str(v)
+
+		case uint32:
+			w(uint32(ggufTypeUint32))
+			w(v)
+
+		case float32:
+			w(uint32(ggufTypeFloat32))
+			w(v)
+
+		case []int32:
+			w(uint32(ggufTypeArray))
+			w(uint32(ggufTypeInt32))
+			w(uint64(len(v)))
+			w(v)
+
+		case []string:
+			w(uint32(ggufTypeArray))
+			w(uint32(ggufTypeString))
+			w(uint64(len(v)))
+			for _, s := range v {
+				str(s)
+			}
+
+		default:
+			t.Fatalf("writeGGUF: unsupported type %T", v)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "model.gguf")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("writeGGUF: %v", err)
+	}
+
+	return path
+}
+
+func TestReadGGUFMeta(t *testing.T) {
+	path := writeGGUF(t, []ggufKV{
+		{"general.architecture", "llama"},
+		{"tokenizer.ggml.tokens", []string{"a", "b", "c"}},
+		{"llama.block_count", uint32(2)},
+		{"llama.rope.freq_base", float32(10000)},
+		{"llama.attention.head_count_kv", []int32{2, 1}},
+	})
+
+	meta, err := readGGUFMeta(path)
+	if err != nil {
+		t.Fatalf("readGGUFMeta() error = %v", err)
+	}
+
+	if got := meta.str("general.architecture"); got != "llama" {
+		t.Errorf("architecture = %q, want %q", got, "llama")
+	}
+
+	if _, exists := meta["tokenizer.ggml.tokens"]; exists {
+		t.Errorf("string arrays should be skipped")
+	}
+
+	if got := meta.num("llama.block_count"); got != 2 {
+		t.Errorf("block_count = %d, want 2", got)
+	}
+
+	if got := meta["llama.rope.freq_base"]; got != float64(10000) {
+		t.Errorf("freq_base = %v, want 10000", got)
+	}
+
+	got, err := meta.perLayer("llama.attention.head_count_kv", 2)
+	if err != nil {
+		t.Fatalf("perLayer() error = %v", err)
+	}
+
+	if !slices.Equal(got, []int64{2, 1}) {
+		t.Errorf("head_count_kv = %v, want [2 1]", got)
+	}
+
+	if _, err := readGGUFMeta(writeTempFile(t, []byte("not a gguf file"))); err == nil {
+		t.Errorf("readGGUFMeta() expected an error for a file that isn't gguf")
+	}
+}
+
+func TestReadModelInfo(t *testing.T) {
+	path := writeGGUF(t, []ggufKV{
+		{"general.architecture", "llama"},
+		{"llama.block_count", uint32(2)},
+		{"tokenizer.chat_template", "{{ messages }}"},
+	})
+
+	mi, err := ReadModelInfo(nil, Config{ModelFiles: []string{path}})
+	if err != nil {
+		t.Fatalf("ReadModelInfo() error = %v", err)
+	}
+
+	if mi.ID != "model" {
+		t.Errorf("ID = %q, want %q", mi.ID, "model")
+	}
+
+	if got := mi.Metadata["general.architecture"]; got != "llama" {
+		t.Errorf("architecture = %q, want %q", got, "llama")
+	}
+
+	if got := mi.Metadata["llama.block_count"]; got != "2" {
+		t.Errorf("block_count = %q, want %q", got, "2")
+	}
+
+	if mi.Template.Script != "{{ messages }}" {
+		t.Errorf("template = %q, want %q", mi.Template.Script, "{{ messages }}")
+	}
+
+	if mi.Size == 0 {
+		t.Errorf("size should be the size of the model file")
+	}
+
+	if _, err := ReadModelInfo(nil, Config{}); err == nil {
+		t.Errorf("ReadModelInfo() expected an error without model files")
+	}
+}
+
+func TestEstimateMemory(t *testing.T) {
+	path := writeGGUF(t, []ggufKV{
+		{"general.architecture", "llama"},
+		{"llama.block_count", uint32(2)},
+		{"llama.embedding_length", uint32(64)},
+		{"llama.attention.head_count", uint32(4)},
+		{"llama.attention.head_count_kv", uint32(2)},
+		{"llama.context_length", uint32(1024)},
+	})
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weights := info.Size()
+
+	// Each cell holds 2 layers * 2 kv heads * (16 key + 16 value) values.
+	const valuesPerCell = 2 * 2 * (16 + 16)
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want int64
+	}{
+		{"f16", Config{ContextWindow: 512, CacheTypeK: GGMLTypeF16, CacheTypeV: GGMLTypeF16}, weights + 512*valuesPerCell*2},
+		{"q8_0", Config{ContextWindow: 512, CacheTypeK: GGMLTypeQ8_0, CacheTypeV: GGMLTypeQ8_0}, weights + 512*valuesPerCell*34/32},
+		{"metadata context", Config{CacheTypeK: GGMLTypeF16, CacheTypeV: GGMLTypeF16}, weights + 1024*valuesPerCell*2},
+		{"parallel sequences share the cache", Config{ContextWindow: 512, NSeqMax: 4, CacheTypeK: GGMLTypeF16, CacheTypeV: GGMLTypeF16}, weights + 512*valuesPerCell*2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.ModelFiles = []string{path}
+
+			got, err := EstimateMemory(tt.cfg)
+			if err != nil {
+				t.Fatalf("EstimateMemory() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("EstimateMemory() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("instances", func(t *testing.T) {
+		cfg := Config{
+			ModelFiles:    []string{path},
+			ProjFile:      path,
+			ContextWindow: 512,
+			NSeqMax:       2,
+			CacheTypeK:    GGMLTypeF16,
+			CacheTypeV:    GGMLTypeF16,
+		}
+
+		got, err := EstimateMemory(cfg)
+		if err != nil {
+			t.Fatalf("EstimateMemory() error = %v", err)
+		}
+
+		want := 2 * (2*weights + 512*valuesPerCell*2)
+		if got != want {
+			t.Errorf("EstimateMemory() = %d, want %d", got, want)
+		}
+	})
+}
+
+func writeTempFile(t *testing.T, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
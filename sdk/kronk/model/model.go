@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -33,7 +34,14 @@ type Model struct {
 	lctx          llama.Context
 	mem           llama.Memory
 	batch         *batchEngine
+	sessions      *sessionStore
+	draft         *draftModel
+	spec          speculation
+	embedBatch    llama.Batch
+	embedCtxs     map[llama.PoolingType]poolingContext
+	logprobs      logprobState
 	template      Template
+	toolParser    toolParser
 	projFile      string
 	modelInfo     ModelInfo
 	activeStreams atomic.Int32
@@ -106,6 +114,11 @@ func NewModel(ctx context.Context, tmplRetriever TemplateRetriever, cfg Config)
 
 	modelInfo.Template = template
 
+	toolParser := selectToolParser(cfg, modelInfo)
+	modelInfo.ToolParser = toolParser.name
+
+	l(ctx, "tool-parser", "name", toolParser.name)
+
 	// -------------------------------------------------------------------------
 
 	ctxParams := modelCtxParams(cfg, modelInfo)
@@ -126,21 +139,63 @@ func NewModel(ctx context.Context, tmplRetriever TemplateRetriever, cfg Config)
 	}
 
 	m := Model{
-		cfg:       cfg,
-		log:       l,
-		model:     mdl,
-		vocab:     llama.ModelGetVocab(mdl),
-		ctxParams: ctxParams,
-		lctx:      lctx,
-		mem:       mem,
-		template:  template,
-		projFile:  cfg.ProjFile,
-		modelInfo: modelInfo,
+		cfg:        cfg,
+		log:        l,
+		model:      mdl,
+		vocab:      llama.ModelGetVocab(mdl),
+		ctxParams:  ctxParams,
+		lctx:       lctx,
+		mem:        mem,
+		template:   template,
+		toolParser: toolParser,
+		projFile:   cfg.ProjFile,
+		modelInfo:  modelInfo,
+	}
+
+	// Load the draft model for speculative decoding. Rejected draft tokens
+	// are removed from the KV cache, which recurrent models can't do.
+	if len(cfg.DraftModelFiles) > 0 && !modelInfo.IsEmbedModel && !modelInfo.IsRerankModel {
+		switch {
+		case llama.ModelIsRecurrent(mdl) || llama.ModelIsHybrid(mdl):
+			l(ctx, "new-model", "status", "speculative decoding not supported for recurrent models")
+
+		default:
+			draft, err := newDraftModel(ctx, l, cfg, mParams, ctxParams, m.vocab)
+			if err != nil {
+				llama.Free(lctx)
+				llama.ModelFree(mdl)
+				return nil, fmt.Errorf("new-model: %w", err)
+			}
+			m.draft = draft
+
+			if cfg.ProjFile != "" {
+				m.spec.batch = llama.BatchInit(int32(cfg.DraftTokens+1), 0, 1)
+			}
+		}
+	}
+
+	// Embedding calls pack their inputs into one batch across sequences and
+	// reuse the model context between calls.
+	if modelInfo.IsEmbedModel {
+		m.embedBatch = llama.BatchInit(int32(llama.NBatch(lctx)), 0, 1)
 	}
 
 	// Initialize batch engine for text-only models (no ProjFile).
 	// Batching is faster even for single-sequence inference.
 	if cfg.ProjFile == "" {
+		if cfg.SessionDir != "" && !modelInfo.IsEmbedModel && !modelInfo.IsRerankModel {
+			sessions, err := newSessionStore(filepath.Join(cfg.SessionDir, modelInfo.ID), cfg.SessionMaxBytes, cfg.ContextWindow)
+			if err != nil {
+				if m.draft != nil {
+					m.draft.free()
+				}
+				llama.Free(lctx)
+				llama.ModelFree(mdl)
+				return nil, fmt.Errorf("new-model: %w", err)
+			}
+			m.sessions = sessions
+		}
+
 		nSlots := max(cfg.NSeqMax, 1)
 		m.batch = newBatchEngine(&m, nSlots)
 		m.batch.start(ctx)
@@ -251,6 +306,22 @@ func (m *Model) Unload(ctx context.Context) error {
 		m.batch.freeBatch()
 	}
 
+	if m.draft != nil {
+		if m.projFile != "" {
+			llama.BatchFree(m.spec.batch)
+		}
+		m.draft.free()
+	}
+
+	if m.modelInfo.IsEmbedModel {
+		llama.BatchFree(m.embedBatch)
+
+		for _, ec := range m.embedCtxs {
+			llama.Synchronize(ec.lctx)
+			llama.Free(ec.lctx)
+		}
+	}
+
 	// Synchronize ensures all GPU operations complete before freeing.
 	llama.Synchronize(m.lctx)
 	llama.Free(m.lctx)
@@ -309,6 +380,16 @@ func (m *Model) sequentialChatRequest(ctx context.Context, id string, lctx llama
 		finalTooling   strings.Builder
 	)
 
+	// This holds the log probabilities of the content tokens when the
+	// request asks for them.
+	var (
+		finalLogprobs []ContentLogprob
+		nSentLogprobs int
+	)
+
+	// This holds back content that could be the start of a stop string.
+	stop := newStopMatcher(params.Stop)
+
 	// The buffer is used to process tokens.
 	const bufferSize = 32 * 1024
 	buf := make([]byte, bufferSize)
@@ -359,11 +440,17 @@ func (m *Model) sequentialChatRequest(ctx context.Context, id string, lctx llama
 
 	// Create a processor to process the tokens.
 	processor := newProcessor(m)
+	if params.Grammar != "" && isGTP {
+		processor.startFinal()
+	}
 
 	// Track whether this is the first iteration. After prefill, the logits are
 	// already computed so we sample directly without re-decoding the prompt.
 	firstIteration := true
 
+	// Set when generation ends by reaching the max tokens.
+	var truncated bool
+
 loop:
 	for outputTokens <= params.MaxTokens {
 		var err error
@@ -456,6 +543,9 @@ loop:
 
 		// ---------------------------------------------------------------------
 
+		text := resp.content
+		var stopped bool
+
 		// Do this if we are not processing tooling tokens.
 		if toolFlag == 0 {
 			// At the start or end of a mode we might have an extra CRLF we don't need.
@@ -464,20 +554,41 @@ loop:
 				continue
 			}
 
-			// We have reasoning or completion content to return to the client.
-			err = m.sendDeltaResponse(ctx, ch, id, object, 0, prompt, resp.content, reasonFlag,
-				Usage{
-					PromptTokens:     inputTokens,
-					ReasoningTokens:  reasonTokens,
-					CompletionTokens: completionTokens,
-					OutputTokens:     outputTokens,
-					TotalTokens:      inputTokens + outputTokens,
-					TokensPerSecond:  tokensPerSecond,
-				},
-			)
+			// Completion content is checked for stop strings, which can hold
+			// back text until the next token shows it isn't a stop string.
+			if reasonFlag == 0 {
+				text, stopped = stop.process(text)
+			}
 
-			if err != nil {
-				return
+			// Only completion content carries log probabilities.
+			if params.Logprobs && reasonFlag == 0 {
+				finalLogprobs = append(finalLogprobs, m.logprobs.last)
+			}
+
+			// We have reasoning or completion content to return to the client.
+			if text != "" {
+				var deltaLogprobs *Logprobs
+				if params.Logprobs && nSentLogprobs < len(finalLogprobs) {
+					deltaLogprobs = &Logprobs{Content: finalLogprobs[nSentLogprobs:]}
+					nSentLogprobs = len(finalLogprobs)
+				}
+
+				err = m.sendDeltaResponse(ctx, ch, id, object, 0, prompt, text, reasonFlag,
+					Usage{
+						PromptTokens:     inputTokens,
+						ReasoningTokens:  reasonTokens,
+						CompletionTokens: completionTokens,
+						OutputTokens:     outputTokens,
+						TotalTokens:      inputTokens + outputTokens,
+						TokensPerSecond:  tokensPerSecond,
+					},
+					params.Seed,
+					deltaLogprobs,
+				)
+
+				if err != nil {
+					return
+				}
 			}
 		}
 
@@ -486,13 +597,13 @@ loop:
 		// Store content for the final response.
 		switch {
 		case reasonFlag > 0:
-			finalReasoning.WriteString(resp.content)
+			finalReasoning.WriteString(text)
 
 		case toolFlag > 0:
-			finalTooling.WriteString(resp.content)
+			finalTooling.WriteString(text)
 
 		default:
-			finalContent.WriteString(resp.content)
+			finalContent.WriteString(text)
 		}
 
 		// ---------------------------------------------------------------------
@@ -512,6 +623,54 @@ loop:
 		}
 
 		outputTokens = reasonTokens + completionTokens
+
+		if stopped {
+			break loop
+		}
+
+		// The loop ends when this is set.
+		truncated = outputTokens > params.MaxTokens
+	}
+
+	// -------------------------------------------------------------------------
+
+	// Text the processor held back for a possible tool call marker is content
+	// after all, unless it holds a tool call that runs to the end.
+	var heldContent string
+	switch held := processor.flush(); held.status {
+	case statusTooling:
+		if held.content != "" {
+			toolFlag++
+			finalTooling.WriteString(held.content)
+		}
+
+	default:
+		heldContent, _ = stop.process(held.content)
+	}
+
+	// Text held back for a possible stop string is content after all when
+	// generation ended some other way.
+	if held := heldContent + stop.flush(); held != "" {
+		finalContent.WriteString(held)
+
+		var deltaLogprobs *Logprobs
+		if params.Logprobs && nSentLogprobs < len(finalLogprobs) {
+			deltaLogprobs = &Logprobs{Content: finalLogprobs[nSentLogprobs:]}
+			nSentLogprobs = len(finalLogprobs)
+		}
+
+		m.sendDeltaResponse(ctx, ch, id, object, 0, prompt, held, 0,
+			Usage{
+				PromptTokens:     inputTokens,
+				ReasoningTokens:  reasonTokens,
+				CompletionTokens: completionTokens,
+				OutputTokens:     outputTokens,
+				TotalTokens:      inputTokens + outputTokens,
+				TokensPerSecond:  tokensPerSecond,
+			},
+			params.Seed,
+			deltaLogprobs,
+		)
 	}
 
 	// -------------------------------------------------------------------------
@@ -533,12 +692,7 @@ loop:
 			outputTokens = reasonTokens + completionTokens
 		}
 
-		switch isGTP {
-		case true:
-			respToolCalls = parseGPTToolCall(content)
-		default:
-			respToolCalls = parseToolCall(content)
-		}
+		respToolCalls = m.toolParser.parse(content)
 	}
 
 	// -------------------------------------------------------------------------
@@ -566,7 +720,7 @@ loop:
 		returnPrompt = prompt
 	}
 
-	m.sendFinalResponse(ctx, ch, id, object, 0, returnPrompt, &finalContent, &finalReasoning, respToolCalls,
+	m.sendFinalResponse(ctx, ch, id, object, 0, returnPrompt, &finalContent, &finalReasoning, respToolCalls, truncated,
 		Usage{
 			PromptTokens:     inputTokens,
 			ReasoningTokens:  reasonTokens,
@@ -575,6 +729,8 @@ loop:
 			TotalTokens:      totalTokens,
 			TokensPerSecond:  tokensPerSecond,
 		},
+		params.Seed,
+		toLogprobs(params.Logprobs, finalLogprobs),
 	)
 }
 
@@ -594,6 +750,17 @@ func (m *Model) processInputTokens(ctx context.Context, lctx llama.Context, mtmd
 	tokens := llama.Tokenize(m.vocab, prompt, true, true)
 	inputTokens := len(tokens)
 
+	// The draft model only sees the text of the prompt.
+	if m.draft != nil {
+		m.spec.tokens = tokens
+		m.spec.pending = nil
+	}
+
+	m.logprobs = logprobState{
+		enabled: params.Logprobs,
+		top:     params.TopLogprobs,
+	}
+
 	var batch llama.Batch
 	var outputTokens int
 	var bitmaps []mtmd.Bitmap
@@ -680,6 +847,10 @@ func (m *Model) nextBatch(token llama.Token) llama.Batch {
 // next token, and returns its string representation. Returns io.EOF when an
 // end-of-generation token is sampled.
 func (m *Model) batchResponse(lctx llama.Context, batch llama.Batch, sampler llama.Sampler, buf []byte) (string, llama.Token, error) {
+	if m.draft != nil {
+		return m.speculativeResponse(lctx, batch, sampler, buf)
+	}
+
 	llama.Decode(lctx, batch)
 	return m.sampleToken(lctx, sampler, buf)
 }
@@ -688,7 +859,14 @@ func (m *Model) batchResponse(lctx llama.Context, batch llama.Batch, sampler lla
 // Use this after prefill when logits are already computed.
 func (m *Model) sampleToken(lctx llama.Context, sampler llama.Sampler, buf []byte) (string, llama.Token, error) {
 	token := llama.SamplerSample(sampler, lctx, -1)
+	m.recordLogprob(lctx, -1, token)
+
+	return m.tokenContent(token, buf)
+}
 
+// tokenContent returns the string representation of the token. Returns io.EOF
+// when the token ends generation.
+func (m *Model) tokenContent(token llama.Token, buf []byte) (string, llama.Token, error) {
 	if llama.VocabIsEOG(m.vocab, token) {
 		return "", 0, io.EOF
 	}
@@ -717,7 +895,7 @@ func (m *Model) isUnncessaryCRLF(reasonFlag int, completionFlag int, content str
 	return false
 }
 
-func (m *Model) sendDeltaResponse(ctx context.Context, ch chan<- ChatResponse, id string, object string, choiceIndex int, prompt string, content string, reasonFlag int, usage Usage) error {
+func (m *Model) sendDeltaResponse(ctx context.Context, ch chan<- ChatResponse, id string, object string, choiceIndex int, prompt string, content string, reasonFlag int, usage Usage, seed uint32, logprobs *Logprobs) error {
 	if usage.OutputTokens%500 == 0 {
 		m.log(ctx, "chat-completion", "status", "delta", "id", id, "tokens", usage.OutputTokens, "object", object, "reasoning", reasonFlag, "content", len(content))
 	}
@@ -731,13 +909,13 @@ func (m *Model) sendDeltaResponse(ctx context.Context, ch chan<- ChatResponse, i
 
 		return ctx.Err()
 
-	case ch <- chatResponseDelta(id, object, m.modelInfo.ID, choiceIndex, content, reasonFlag > 0, usage):
+	case ch <- chatResponseDelta(id, object, m.modelInfo.ID, choiceIndex, content, reasonFlag > 0, usage, seed, logprobs):
 	}
 
 	return nil
 }
 
-func (m *Model) sendFinalResponse(ctx context.Context, ch chan<- ChatResponse, id string, object string, choiceIndex int, prompt string, finalContent *strings.Builder, finalReasoning *strings.Builder, respToolCalls []ResponseToolCall, usage Usage) {
+func (m *Model) sendFinalResponse(ctx context.Context, ch chan<- ChatResponse, id string, object string, choiceIndex int, prompt string, finalContent *strings.Builder, finalReasoning *strings.Builder, respToolCalls []ResponseToolCall, truncated bool, usage Usage, seed uint32, logprobs *Logprobs) {
 	m.log(ctx, "chat-completion", "status", "final", "id", id, "tokens", usage.OutputTokens, "object", object, "tooling", len(respToolCalls) > 0, "reasoning", finalReasoning.Len(), "content", finalContent.Len())
 
 	select {
@@ -751,7 +929,10 @@ func (m *Model) sendFinalResponse(ctx context.Context, ch chan<- ChatResponse, i
 		finalContent.String(),
 		finalReasoning.String(),
 		respToolCalls,
-		usage):
+		truncated,
+		usage,
+		seed,
+		logprobs):
 	}
 
 	contextTokens := usage.PromptTokens + usage.CompletionTokens
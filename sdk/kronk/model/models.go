@@ -2,9 +2,11 @@ package model
 
 import (
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"maps"
+	"math"
 	"net/url"
 	"path"
 	"path/filepath"
@@ -16,10 +18,11 @@ import (
 
 // Objects represent the different types of data that is being processed.
 const (
-	ObjectChatUnknown   = "chat.unknown"
-	ObjectChatText      = "chat.completion.chunk"
-	ObjectChatTextFinal = "chat.completion"
-	ObjectChatMedia     = "chat.media"
+	ObjectChatUnknown    = "chat.unknown"
+	ObjectChatText       = "chat.completion.chunk"
+	ObjectChatTextFinal  = "chat.completion"
+	ObjectChatMedia      = "chat.media"
+	ObjectTextCompletion = "text_completion"
 )
 
 // Roles represent the different roles that can be used in a chat.
@@ -31,9 +34,10 @@ const (
 
 // FinishReasons represent the different reasons a response can be finished.
 const (
-	FinishReasonStop  = "stop"
-	FinishReasonTool  = "tool_calls"
-	FinishReasonError = "error"
+	FinishReasonStop   = "stop"
+	FinishReasonLength = "length"
+	FinishReasonTool   = "tool_calls"
+	FinishReasonError  = "error"
 )
 
 // =============================================================================
@@ -54,6 +58,7 @@ type ModelInfo struct {
 	Metadata      map[string]string
 	TemplateFile  string
 	Template      Template
+	ToolParser    string
 }
 
 func toModelInfo(cfg Config, model llama.Model) ModelInfo {
@@ -88,15 +93,7 @@ func toModelInfo(cfg Config, model llama.Model) ModelInfo {
 		}()
 	}
 
-	var filename string
-	switch len(cfg.ModelFiles) {
-	case 1:
-		filename = filepath.Base(cfg.ModelFiles[0])
-	default:
-		filename = extractFolderName(cfg.ModelFiles[0])
-	}
-
-	modelID := strings.TrimSuffix(filename, path.Ext(filename))
+	modelID := modelIDFromFiles(cfg.ModelFiles)
 
 	var isGPTModel bool
 	if strings.Contains(modelID, "gpt") {
@@ -129,6 +126,72 @@ func toModelInfo(cfg Config, model llama.Model) ModelInfo {
 	}
 }
 
+// modelIDFromFiles returns the id of the model, which is the name of the model
+// file or the folder holding the split files.
+func modelIDFromFiles(modelFiles []string) string {
+	var filename string
+	switch len(modelFiles) {
+	case 1:
+		filename = filepath.Base(modelFiles[0])
+	default:
+		filename = extractFolderName(modelFiles[0])
+	}
+
+	return strings.TrimSuffix(filename, path.Ext(filename))
+}
+
+// ReadModelInfo provides the model information from the GGUF metadata on disk
+// without loading the model. The description and the details that need the
+// model loaded, like the encoder and decoder flags, are left empty.
+func ReadModelInfo(tmplRetriever TemplateRetriever, cfg Config) (ModelInfo, error) {
+	if len(cfg.ModelFiles) == 0 {
+		return ModelInfo{}, fmt.Errorf("read-model-info: model file is required")
+	}
+
+	size, err := filesSize(cfg.ModelFiles)
+	if err != nil {
+		return ModelInfo{}, fmt.Errorf("read-model-info: %w", err)
+	}
+
+	// Split models hold the metadata in the first file.
+	meta, err := readGGUFMeta(cfg.ModelFiles[0])
+	if err != nil {
+		return ModelInfo{}, fmt.Errorf("read-model-info: %w", err)
+	}
+
+	metadata := make(map[string]string, len(meta))
+	for key, value := range meta {
+		metadata[key] = fmt.Sprint(value)
+	}
+
+	modelID := modelIDFromFiles(cfg.ModelFiles)
+
+	mi := ModelInfo{
+		ID:            modelID,
+		HasProjection: cfg.ProjFile != "",
+		Size:          uint64(size),
+		IsGPTModel:    strings.Contains(modelID, "gpt"),
+		IsEmbedModel:  strings.Contains(modelID, "embed"),
+		IsRerankModel: strings.Contains(modelID, "rerank"),
+		Metadata:      metadata,
+	}
+
+	mi.Template = Template{
+		FileName: "tokenizer.chat_template",
+		Script:   metadata["tokenizer.chat_template"],
+	}
+
+	if tmplRetriever != nil {
+		if template, err := tmplRetriever.Retrieve(modelID); err == nil {
+			mi.Template = template
+		}
+	}
+
+	mi.ToolParser = selectToolParser(cfg, mi).name
+
+	return mi, nil
+}
+
 func extractFolderName(rawURL string) string {
 	u, err := url.Parse(rawURL)
 	if err != nil {
@@ -418,17 +481,45 @@ type Choice struct {
 	Index        int              `json:"index"`
 	Message      ResponseMessage  `json:"message,omitempty"`
 	Delta        *ResponseMessage `json:"delta,omitempty"`
+	Logprobs     *Logprobs        `json:"logprobs,omitempty"`
 	FinishReason string           `json:"finish_reason"`
 }
 
+// Logprobs provides the log probabilities of the content tokens.
+type Logprobs struct {
+	Content []ContentLogprob `json:"content"`
+}
+
+// ContentLogprob provides the log probability of a generated token and the
+// most likely tokens at that position.
+type ContentLogprob struct {
+	Token       string       `json:"token"`
+	Logprob     float32      `json:"logprob"`
+	Bytes       []int        `json:"bytes"`
+	TopLogprobs []TopLogprob `json:"top_logprobs"`
+}
+
+// TopLogprob provides the log probability of one of the most likely tokens.
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float32 `json:"logprob"`
+	Bytes   []int   `json:"bytes"`
+}
+
+// PromptTokensDetails provides a breakdown of the prompt tokens.
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
 // Usage provides details usage information for the request.
 type Usage struct {
-	PromptTokens     int     `json:"prompt_tokens"`
-	ReasoningTokens  int     `json:"reasoning_tokens"`
-	CompletionTokens int     `json:"completion_tokens"`
-	OutputTokens     int     `json:"output_tokens"`
-	TotalTokens      int     `json:"total_tokens"`
-	TokensPerSecond  float64 `json:"tokens_per_second"`
+	PromptTokens        int                 `json:"prompt_tokens"`
+	PromptTokensDetails PromptTokensDetails `json:"prompt_tokens_details"`
+	ReasoningTokens     int                 `json:"reasoning_tokens"`
+	CompletionTokens    int                 `json:"completion_tokens"`
+	OutputTokens        int                 `json:"output_tokens"`
+	TotalTokens         int                 `json:"total_tokens"`
+	TokensPerSecond     float64             `json:"tokens_per_second"`
 }
 
 // ChatResponse represents output for inference models.
@@ -440,9 +531,10 @@ type ChatResponse struct {
 	Choice  []Choice `json:"choices"`
 	Usage   Usage    `json:"usage"`
 	Prompt  string   `json:"prompt"`
+	Seed    uint32   `json:"seed"`
 }
 
-func chatResponseDelta(id string, object string, model string, index int, content string, reasoning bool, u Usage) ChatResponse {
+func chatResponseDelta(id string, object string, model string, index int, content string, reasoning bool, u Usage, seed uint32, logprobs *Logprobs) ChatResponse {
 	return ChatResponse{
 		ID:      id,
 		Object:  object,
@@ -456,10 +548,12 @@ func chatResponseDelta(id string, object string, model string, index int, conten
 					Content:   forContent(content, reasoning),
 					Reasoning: forReasoning(content, reasoning),
 				},
+				Logprobs:     logprobs,
 				FinishReason: "",
 			},
 		},
 		Usage: u,
+		Seed:  seed,
 	}
 }
 
@@ -479,10 +573,13 @@ func forReasoning(content string, reasoning bool) string {
 	return ""
 }
 
-func chatResponseFinal(id string, object string, model string, index int, prompt string, content string, reasoning string, respToolCalls []ResponseToolCall, u Usage) ChatResponse {
+func chatResponseFinal(id string, object string, model string, index int, prompt string, content string, reasoning string, respToolCalls []ResponseToolCall, truncated bool, u Usage, seed uint32, logprobs *Logprobs) ChatResponse {
 	finishReason := FinishReasonStop
-	if len(respToolCalls) > 0 {
+	switch {
+	case len(respToolCalls) > 0:
 		finishReason = FinishReasonTool
+	case truncated:
+		finishReason = FinishReasonLength
 	}
 
 	return ChatResponse{
@@ -502,11 +599,13 @@ func chatResponseFinal(id string, object string, model string, index int, prompt
 				Delta: &ResponseMessage{
 					ToolCalls: respToolCalls,
 				},
+				Logprobs:     logprobs,
 				FinishReason: finishReason,
 			},
 		},
 		Usage:  u,
 		Prompt: prompt,
+		Seed:   seed,
 	}
 }
 
@@ -533,11 +632,105 @@ func ChatResponseErr(id string, object string, model string, index int, prompt s
 
 // =============================================================================
 
-// EmbedData represents the data associated with an embedding call.
+// Set of encoding formats for the embedding vectors.
+const (
+	EncodingFormatFloat  = "float"
+	EncodingFormatBase64 = "base64"
+)
+
+// EmbedChunk identifies the window of an input a chunk embedding was made
+// from. Start and End are the token offsets of the window within the input,
+// not counting the special tokens.
+type EmbedChunk struct {
+	Index int `json:"index"`
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// EmbedData represents the data associated with an embedding call. When the
+// EncodingFormat is base64, the embedding marshals as a base64 string of the
+// little-endian float32 values. Chunk is set when the chunk vectors of an
+// input are returned without being aggregated.
 type EmbedData struct {
-	Object    string    `json:"object"`
-	Index     int       `json:"index"`
-	Embedding []float32 `json:"embedding"`
+	Object         string      `json:"object"`
+	Index          int         `json:"index"`
+	Embedding      []float32   `json:"embedding"`
+	Chunk          *EmbedChunk `json:"chunk,omitempty"`
+	EncodingFormat string      `json:"-"`
+}
+
+func (ed EmbedData) MarshalJSON() ([]byte, error) {
+	type embedData EmbedData
+
+	if ed.EncodingFormat != EncodingFormatBase64 {
+		return json.Marshal(embedData(ed))
+	}
+
+	buf := make([]byte, 4*len(ed.Embedding))
+	for i, v := range ed.Embedding {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+
+	return json.Marshal(struct {
+		Object    string      `json:"object"`
+		Index     int         `json:"index"`
+		Embedding string      `json:"embedding"`
+		Chunk     *EmbedChunk `json:"chunk,omitempty"`
+	}{
+		Object:    ed.Object,
+		Index:     ed.Index,
+		Embedding: base64.StdEncoding.EncodeToString(buf),
+		Chunk:     ed.Chunk,
+	})
+}
+
+func (ed *EmbedData) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Object    string          `json:"object"`
+		Index     int             `json:"index"`
+		Embedding json.RawMessage `json:"embedding"`
+		Chunk     *EmbedChunk     `json:"chunk"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*ed = EmbedData{
+		Object: v.Object,
+		Index:  v.Index,
+		Chunk:  v.Chunk,
+	}
+
+	switch {
+	case len(v.Embedding) == 0:
+		return nil
+
+	case v.Embedding[0] != '"':
+		return json.Unmarshal(v.Embedding, &ed.Embedding)
+	}
+
+	var s string
+	if err := json.Unmarshal(v.Embedding, &s); err != nil {
+		return err
+	}
+
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("unmarshal-embed-data: decode base64: %w", err)
+	}
+
+	if len(buf)%4 != 0 {
+		return fmt.Errorf("unmarshal-embed-data: invalid base64 embedding length %d", len(buf))
+	}
+
+	ed.EncodingFormat = EncodingFormatBase64
+	ed.Embedding = make([]float32, len(buf)/4)
+	for i := range ed.Embedding {
+		ed.Embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+
+	return nil
 }
 
 // EmbedUsage provides token usage information for embeddings.
@@ -2,7 +2,13 @@ package model
 
 import (
 	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"slices"
 	"strconv"
+	"unsafe"
 
 	"github.com/hybridgroup/yzma/pkg/llama"
 )
@@ -17,16 +23,43 @@ import (
 // dry_penalty_last_n limits how many recent tokens DRY considers. Default of 0
 // means full context.
 //
+// dry_sequence_breakers is the list of strings that stop the matching of a
+// repeated sequence in DRY. Default is ["\n", ":", "\"", "*"].
+//
+// grammar is a GBNF grammar that constrains the tokens the model can generate.
+// When provided, it takes precedence over response_format. Default is "".
+//
 // enable_thinking determines if the model should think or not. It is used for
 // most non-GPT models. It accepts 1, t, T, TRUE, true, True, 0, f, F, FALSE,
 // false, False. Default is "true".
 //
+// logit_bias maps token ids to a bias between -100 and 100 that is added to
+// the logit of the token before sampling. A bias of -100 bans the token.
+// Default is no bias.
+//
+// logit_bias_text maps text to a bias like logit_bias. The text is tokenized
+// with the model's vocabulary and the bias applies to each of its tokens, so
+// " yes" and "yes" are different entries. A token id in logit_bias takes
+// precedence. Default is no bias.
+//
+// logprobs determines whether to return the log probability of each content
+// token. Default is false.
+//
 // min_p is a dynamic sampling threshold that helps balance the coherence
 // (quality) and diversity (creativity) of the generated text. Default is 0.0.
 //
+// n is the number of choices to generate for the request. The prompt is
+// processed once and each choice is sampled in its own sequence, so n can't
+// exceed the number of parallel sequences of the model. Default is 1.
+//
 // reasoning_effort is a string that specifies the level of reasoning effort to
 // use for GPT models. Default is ReasoningEffortMedium
 //
+// response_format constrains the output to JSON. Use {"type": "json_object"}
+// for any JSON object or {"type": "json_schema", "json_schema": {"schema": {...}}}
+// to match a JSON schema. The schema is compiled into a grammar. Default is
+// {"type": "text"}.
+//
 // repeat_last_n specifies how many recent tokens to consider when applying the
 // repetition penalty. A larger value considers more context but may be slower.
 // Default is 64.
@@ -39,6 +72,24 @@ import (
 // return_prompt determines whether to include the prompt in the final response.
 // When set to true, the prompt will be included. Default is false.
 //
+// samplers sets which samplers are used and the order they are applied in.
+// The valid names are penalties, dry, top_k, typical_p, top_p, min_p, xtc and
+// temperature. Samplers left out of the list are not used. Default is all of
+// them in that order.
+//
+// seed initializes the random number generator used by the samplers. Requests
+// with the same seed, prompt and parameters produce the same output. The seed
+// used is returned in the response so a request can be replayed. Default is a
+// random seed.
+//
+// session_id binds the request to a session. The KV cache state for the
+// session can be saved to disk and is restored for a later request with the
+// same session_id instead of processing the prompt again. Default is "".
+//
+// stop is a string or a list of up to 4 strings that end generation when the
+// model produces one of them. The stop string isn't included in the content.
+// Default is no stop strings.
+//
 // temperature controls the randomness of the output. It rescales the probability
 // distribution of possible next tokens. Default is 0.8.
 //
@@ -47,12 +98,20 @@ import (
 // means only the 50 tokens with the highest probabilities are considered for
 // selection (after temperature scaling). The rest are ignored. Default is 40.
 //
+// top_logprobs is the number of most likely tokens to return at each token
+// position along with their log probabilities. Requires logprobs to be true
+// and must be between 0 and 20. Default is 0.
+//
 // top_p, also known as nucleus sampling, works differently than top_k by
 // selecting a dynamic pool of tokens whose cumulative probability exceeds a
 // threshold P. Instead of a fixed number of tokens (K), it selects the minimum
 // number of most probable tokens required to reach the cumulative probability P.
 // Default is 0.9.
 //
+// typical_p enables locally typical sampling, which keeps the tokens whose
+// probability is close to the expected probability. A value of 1.0 disables
+// it. Default is 1.0.
+//
 // xtc_min_keep is the minimum tokens to keep after XTC culling. Default is 1.
 //
 // xtc_probability controls XTC (eXtreme Token Culling) which randomly removes
@@ -67,6 +126,7 @@ const (
 	defDryPenaltyLast  = 0
 	defEnableThinking  = ThinkingEnabled
 	defMinP            = 0.0
+	defN               = 1
 	defReasoningEffort = ReasoningEffortMedium
 	defRepeatLastN     = 64
 	defRepeatPenalty   = 1.1
@@ -74,11 +134,44 @@ const (
 	defTemp            = 0.8
 	defTopK            = 40
 	defTopP            = 0.9
+	defTypicalP        = 1.0
 	defXtcMinKeep      = 1
 	defXtcProbability  = 0.0
 	defXtcThreshold    = 0.1
 )
 
+// These are the names of the samplers that can be provided in the samplers
+// param.
+const (
+	samplerPenalties   = "penalties"
+	samplerDry         = "dry"
+	samplerTopK        = "top_k"
+	samplerTypicalP    = "typical_p"
+	samplerTopP        = "top_p"
+	samplerMinP        = "min_p"
+	samplerXtc         = "xtc"
+	samplerTemperature = "temperature"
+)
+
+var (
+	defSamplers = []string{
+		samplerPenalties,
+		samplerDry,
+		samplerTopK,
+		samplerTypicalP,
+		samplerTopP,
+		samplerMinP,
+		samplerXtc,
+		samplerTemperature,
+	}
+
+	defDrySequenceBreakers = []string{"\n", ":", "\"", "*"}
+)
+
+// maxLogitBias is the largest bias that can be applied to a token, which
+// matches the OpenAI API.
+const maxLogitBias = 100
+
 const (
 	// The model will perform thinking. This is the default setting.
 	ThinkingEnabled = "true"
@@ -113,23 +206,35 @@ const (
 )
 
 type params struct {
-	Temperature     float32 `json:"temperature"`
-	TopK            int32   `json:"top_k"`
-	TopP            float32 `json:"top_p"`
-	MinP            float32 `json:"min_p"`
-	MaxTokens       int     `json:"max_tokens"`
-	RepeatPenalty   float32 `json:"repeat_penalty"`
-	RepeatLastN     int32   `json:"repeat_last_n"`
-	DryMultiplier   float32 `json:"dry_multiplier"`
-	DryBase         float32 `json:"dry_base"`
-	DryAllowedLen   int32   `json:"dry_allowed_length"`
-	DryPenaltyLast  int32   `json:"dry_penalty_last_n"`
-	XtcProbability  float32 `json:"xtc_probability"`
-	XtcThreshold    float32 `json:"xtc_threshold"`
-	XtcMinKeep      uint32  `json:"xtc_min_keep"`
-	Thinking        string  `json:"enable_thinking"`
-	ReasoningEffort string  `json:"reasoning_effort"`
-	ReturnPrompt    bool    `json:"return_prompt"`
+	Temperature         float32                 `json:"temperature"`
+	TopK                int32                   `json:"top_k"`
+	TopP                float32                 `json:"top_p"`
+	MinP                float32                 `json:"min_p"`
+	TypicalP            float32                 `json:"typical_p"`
+	MaxTokens           int                     `json:"max_tokens"`
+	RepeatPenalty       float32                 `json:"repeat_penalty"`
+	RepeatLastN         int32                   `json:"repeat_last_n"`
+	DryMultiplier       float32                 `json:"dry_multiplier"`
+	DryBase             float32                 `json:"dry_base"`
+	DryAllowedLen       int32                   `json:"dry_allowed_length"`
+	DryPenaltyLast      int32                   `json:"dry_penalty_last_n"`
+	DrySequenceBreakers []string                `json:"dry_sequence_breakers"`
+	XtcProbability      float32                 `json:"xtc_probability"`
+	XtcThreshold        float32                 `json:"xtc_threshold"`
+	XtcMinKeep          uint32                  `json:"xtc_min_keep"`
+	Samplers            []string                `json:"samplers"`
+	Thinking            string                  `json:"enable_thinking"`
+	ReasoningEffort     string                  `json:"reasoning_effort"`
+	ReturnPrompt        bool                    `json:"return_prompt"`
+	Grammar             string                  `json:"grammar"`
+	SessionID           string                  `json:"session_id"`
+	Seed                uint32                  `json:"seed"`
+	Logprobs            bool                    `json:"logprobs"`
+	TopLogprobs         int                     `json:"top_logprobs"`
+	N                   int                     `json:"n"`
+	Stop                []string                `json:"stop"`
+	LogitBias           map[llama.Token]float32 `json:"logit_bias"`
+	LogitBiasText       map[string]float32      `json:"logit_bias_text"`
 }
 
 func (m *Model) parseParams(d D) (params, error) {
@@ -169,6 +274,15 @@ func (m *Model) parseParams(d D) (params, error) {
 		}
 	}
 
+	var typicalP float32
+	if val, exists := d["typical_p"]; exists {
+		var err error
+		typicalP, err = parseFloat32("typical_p", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
 	var maxTokens int
 	if maxTokensVal, exists := d["max_tokens"]; exists {
 		var err error
@@ -259,6 +373,15 @@ func (m *Model) parseParams(d D) (params, error) {
 		}
 	}
 
+	var drySequenceBreakers []string
+	if val, exists := d["dry_sequence_breakers"]; exists {
+		var err error
+		drySequenceBreakers, err = parseStrings("dry_sequence_breakers", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
 	var xtcProbability float32
 	if val, exists := d["xtc_probability"]; exists {
 		var err error
@@ -286,24 +409,146 @@ func (m *Model) parseParams(d D) (params, error) {
 		}
 	}
 
+	var samplers []string
+	if val, exists := d["samplers"]; exists {
+		var err error
+		samplers, err = parseSamplers("samplers", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
+	var grammar string
+	if val, exists := d["grammar"]; exists {
+		var err error
+		grammar, err = parseString("grammar", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
+	if val, exists := d["response_format"]; exists && grammar == "" {
+		var err error
+		grammar, err = parseResponseFormat("response_format", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
+	var sessionID string
+	if val, exists := d["session_id"]; exists {
+		var err error
+		sessionID, err = parseString("session_id", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
+	seed := randomSeed()
+	if val, exists := d["seed"]; exists {
+		var err error
+		seed, err = parseSeed("seed", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
+	var logprobs bool
+	if val, exists := d["logprobs"]; exists && val != nil {
+		var err error
+		logprobs, err = parseBool("logprobs", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
+	var topLogprobs int
+	if val, exists := d["top_logprobs"]; exists && val != nil {
+		var err error
+		topLogprobs, err = parseInt("top_logprobs", val)
+		if err != nil {
+			return params{}, err
+		}
+
+		switch {
+		case topLogprobs < 0 || topLogprobs > maxTopLogprobs:
+			return params{}, fmt.Errorf("parse-params: field-name[top_logprobs] must be between 0 and %d", maxTopLogprobs)
+
+		case topLogprobs > 0 && !logprobs:
+			return params{}, fmt.Errorf("parse-params: field-name[top_logprobs] requires logprobs to be true")
+		}
+	}
+
+	var n int
+	if val, exists := d["n"]; exists && val != nil {
+		var err error
+		n, err = parseInt("n", val)
+		if err != nil {
+			return params{}, err
+		}
+
+		if n < 1 {
+			return params{}, fmt.Errorf("parse-params: field-name[n] must be at least 1")
+		}
+	}
+
+	var stop []string
+	if val, exists := d["stop"]; exists && val != nil {
+		var err error
+		stop, err = parseStop("stop", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
+	var logitBias map[llama.Token]float32
+	if val, exists := d["logit_bias"]; exists && val != nil {
+		var err error
+		logitBias, err = parseTokenBias("logit_bias", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
+	var logitBiasText map[string]float32
+	if val, exists := d["logit_bias_text"]; exists && val != nil {
+		var err error
+		logitBiasText, err = parseLogitBias("logit_bias_text", val)
+		if err != nil {
+			return params{}, err
+		}
+	}
+
 	p := params{
-		Temperature:     temp,
-		TopK:            int32(topK),
-		TopP:            topP,
-		MinP:            minP,
-		MaxTokens:       maxTokens,
-		RepeatPenalty:   repeatPenalty,
-		RepeatLastN:     int32(repeatLastN),
-		DryMultiplier:   dryMultiplier,
-		DryBase:         dryBase,
-		DryAllowedLen:   int32(dryAllowedLen),
-		DryPenaltyLast:  int32(dryPenaltyLast),
-		XtcProbability:  xtcProbability,
-		XtcThreshold:    xtcThreshold,
-		XtcMinKeep:      uint32(xtcMinKeep),
-		Thinking:        strconv.FormatBool(enableThinking),
-		ReasoningEffort: reasoningEffort,
-		ReturnPrompt:    returnPrompt,
+		Temperature:         temp,
+		TopK:                int32(topK),
+		TopP:                topP,
+		MinP:                minP,
+		TypicalP:            typicalP,
+		MaxTokens:           maxTokens,
+		RepeatPenalty:       repeatPenalty,
+		RepeatLastN:         int32(repeatLastN),
+		DryMultiplier:       dryMultiplier,
+		DryBase:             dryBase,
+		DryAllowedLen:       int32(dryAllowedLen),
+		DryPenaltyLast:      int32(dryPenaltyLast),
+		DrySequenceBreakers: drySequenceBreakers,
+		XtcProbability:      xtcProbability,
+		XtcThreshold:        xtcThreshold,
+		XtcMinKeep:          uint32(xtcMinKeep),
+		Samplers:            samplers,
+		Thinking:            strconv.FormatBool(enableThinking),
+		ReasoningEffort:     reasoningEffort,
+		ReturnPrompt:        returnPrompt,
+		Grammar:             grammar,
+		SessionID:           sessionID,
+		Seed:                seed,
+		Logprobs:            logprobs,
+		TopLogprobs:         topLogprobs,
+		N:                   n,
+		Stop:                stop,
+		LogitBias:           logitBias,
+		LogitBiasText:       logitBiasText,
 	}
 
 	return m.adjustParams(p), nil
@@ -326,10 +571,18 @@ func (m *Model) adjustParams(p params) params {
 		p.MinP = defMinP
 	}
 
+	if p.TypicalP <= 0 {
+		p.TypicalP = defTypicalP
+	}
+
 	if p.MaxTokens <= 0 {
 		p.MaxTokens = m.cfg.ContextWindow
 	}
 
+	if p.N <= 0 {
+		p.N = defN
+	}
+
 	if p.RepeatPenalty <= 0 {
 		p.RepeatPenalty = defRepeatPenalty
 	}
@@ -354,6 +607,10 @@ func (m *Model) adjustParams(p params) params {
 		p.DryPenaltyLast = defDryPenaltyLast
 	}
 
+	if p.DrySequenceBreakers == nil {
+		p.DrySequenceBreakers = defDrySequenceBreakers
+	}
+
 	if p.XtcProbability <= 0 {
 		p.XtcProbability = defXtcProbability
 	}
@@ -366,6 +623,10 @@ func (m *Model) adjustParams(p params) params {
 		p.XtcMinKeep = defXtcMinKeep
 	}
 
+	if p.Samplers == nil {
+		p.Samplers = defSamplers
+	}
+
 	if p.Thinking == "" {
 		p.Thinking = defEnableThinking
 	}
@@ -380,21 +641,105 @@ func (m *Model) adjustParams(p params) params {
 func (m *Model) toSampler(p params) llama.Sampler {
 	sampler := llama.SamplerChainInit(llama.SamplerChainDefaultParams())
 
-	// TODO: DRY sampler disabled - yzma crashes when seqBreakers is nil.
-	// Waiting for yzma fix to properly handle empty sequence breakers.
-	// if p.DryMultiplier > 0 {
-	// 	llama.SamplerChainAdd(sampler, llama.SamplerInitDry(m.vocab, int32(m.cfg.ContextWindow), p.DryMultiplier, p.DryBase, p.DryAllowedLen, p.DryPenaltyLast, nil, 0))
-	// }
+	// The grammar goes first so every other sampler only sees valid tokens.
+	if p.Grammar != "" {
+		llama.SamplerChainAdd(sampler, llama.SamplerInitGrammar(m.vocab, p.Grammar, grammarRoot))
+	}
+
+	if lb := m.logitBiasSampler(p); lb != 0 {
+		llama.SamplerChainAdd(sampler, lb)
+	}
+
+	for _, name := range p.Samplers {
+		switch name {
+		case samplerPenalties:
+			llama.SamplerChainAdd(sampler, llama.SamplerInitPenalties(p.RepeatLastN, p.RepeatPenalty, 0, 0))
+
+		case samplerDry:
+			if p.DryMultiplier > 0 {
+				llama.SamplerChainAdd(sampler, m.drySampler(p))
+			}
+
+		case samplerTopK:
+			llama.SamplerChainAdd(sampler, llama.SamplerInitTopK(p.TopK))
+
+		case samplerTypicalP:
+			if p.TypicalP < 1 {
+				llama.SamplerChainAdd(sampler, llama.SamplerInitTypical(p.TypicalP, 1))
+			}
+
+		case samplerTopP:
+			llama.SamplerChainAdd(sampler, llama.SamplerInitTopP(p.TopP, 0))
+
+		case samplerMinP:
+			llama.SamplerChainAdd(sampler, llama.SamplerInitMinP(p.MinP, 0))
+
+		case samplerXtc:
+			if p.XtcProbability > 0 {
+				llama.SamplerChainAdd(sampler, llama.SamplerInitXTC(p.XtcProbability, p.XtcThreshold, p.XtcMinKeep, p.Seed))
+			}
+
+		case samplerTemperature:
+			llama.SamplerChainAdd(sampler, llama.SamplerInitTempExt(p.Temperature, 0, 1.0))
+		}
+	}
+
+	llama.SamplerChainAdd(sampler, llama.SamplerInitDist(p.Seed))
+
+	return sampler
+}
+
+// logitBiasSampler creates the sampler for the token and text biases. It
+// returns 0 when the request has no biases. A bias of -100 bans the token, so
+// it becomes negative infinity to make sure the token is never picked.
+func (m *Model) logitBiasSampler(p params) llama.Sampler {
+	biases := make(map[llama.Token]float32, len(p.LogitBias))
+	for text, bias := range p.LogitBiasText {
+		for _, token := range llama.Tokenize(m.vocab, text, false, true) {
+			biases[token] = bias
+		}
+	}
+
+	maps.Copy(biases, p.LogitBias)
 
-	llama.SamplerChainAdd(sampler, llama.SamplerInitPenalties(p.RepeatLastN, p.RepeatPenalty, 0, 0))
-	llama.SamplerChainAdd(sampler, llama.SamplerInitTopK(p.TopK))
-	llama.SamplerChainAdd(sampler, llama.SamplerInitTopP(p.TopP, 0))
-	llama.SamplerChainAdd(sampler, llama.SamplerInitMinP(p.MinP, 0))
-	if p.XtcProbability > 0 {
-		llama.SamplerChainAdd(sampler, llama.SamplerInitXTC(p.XtcProbability, p.XtcThreshold, p.XtcMinKeep, llama.DefaultSeed))
+	if len(biases) == 0 {
+		return 0
 	}
-	llama.SamplerChainAdd(sampler, llama.SamplerInitTempExt(p.Temperature, 0, 1.0))
-	llama.SamplerChainAdd(sampler, llama.SamplerInitDist(llama.DefaultSeed))
+
+	lbs := make([]llama.LogitBias, 0, len(biases))
+	for token, bias := range biases {
+		if bias <= -maxLogitBias {
+			bias = float32(math.Inf(-1))
+		}
+
+		lbs = append(lbs, llama.LogitBias{Token: token, Bias: bias})
+	}
+
+	return llama.SamplerInitLogitBias(llama.VocabNTokens(m.vocab), int32(len(lbs)), &lbs[0])
+}
+
+// drySampler creates the DRY sampler. yzma passes the sequence breakers
+// argument to the C call as a pointer to the argument value, so it must point
+// at a variable holding the address of the array of C strings rather than at
+// the array itself.
+func (m *Model) drySampler(p params) llama.Sampler {
+	cstrs := make([][]byte, len(p.DrySequenceBreakers))
+	ptrs := make([]*byte, len(p.DrySequenceBreakers))
+	for i, sb := range p.DrySequenceBreakers {
+		cstrs[i] = append([]byte(sb), 0)
+		ptrs[i] = &cstrs[i][0]
+	}
+
+	var arr *byte
+	if len(ptrs) > 0 {
+		arr = (*byte)(unsafe.Pointer(&ptrs[0]))
+	}
+
+	nCtxTrain := llama.ModelNCtxTrain(m.model)
+	sampler := llama.SamplerInitDry(m.vocab, nCtxTrain, p.DryMultiplier, p.DryBase, p.DryAllowedLen, p.DryPenaltyLast, &arr, uint64(len(ptrs)))
+
+	runtime.KeepAlive(cstrs)
+	runtime.KeepAlive(ptrs)
 
 	return sampler
 }
@@ -465,10 +810,65 @@ func parseInt(fieldName string, val any) (int, error) {
 	return result, nil
 }
 
+// parseSeed accepts any value that fits in 32 bits. A negative value asks for
+// a random seed, like llama.cpp does.
+func parseSeed(fieldName string, val any) (uint32, error) {
+	var seed int64
+
+	switch v := val.(type) {
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse-seed: field-name[%s] is not valid: %w", fieldName, err)
+		}
+		seed = n
+
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("parse-seed: field-name[%s] is not an integer", fieldName)
+		}
+		seed = int64(v)
+
+	case int:
+		seed = int64(v)
+
+	case int32:
+		seed = int64(v)
+
+	case int64:
+		seed = v
+
+	case uint32:
+		seed = int64(v)
+
+	default:
+		return 0, fmt.Errorf("parse-seed: field-name[%s] is not a valid type", fieldName)
+	}
+
+	switch {
+	case seed < 0:
+		return randomSeed(), nil
+
+	case seed >= llama.DefaultSeed:
+		return 0, fmt.Errorf("parse-seed: field-name[%s] must be less than %d", fieldName, uint32(llama.DefaultSeed))
+	}
+
+	return uint32(seed), nil
+}
+
+// randomSeed returns a seed that llama.cpp won't replace with its own random
+// seed, so the value can be reported back to the caller.
+func randomSeed() uint32 {
+	return rand.Uint32N(llama.DefaultSeed)
+}
+
 func parseBool(fieldName string, val any) (bool, error) {
 	result := true
 
 	switch v := val.(type) {
+	case bool:
+		result = v
+
 	case string:
 		if v == "" {
 			break
@@ -485,6 +885,193 @@ func parseBool(fieldName string, val any) (bool, error) {
 	return result, nil
 }
 
+func parseString(fieldName string, val any) (string, error) {
+	v, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("parse-string: field-name[%s] is not a valid type", fieldName)
+	}
+
+	return v, nil
+}
+
+func parseStrings(fieldName string, val any) ([]string, error) {
+	var result []string
+
+	switch v := val.(type) {
+	case []string:
+		result = v
+
+	case []any:
+		result = make([]string, len(v))
+		for i, e := range v {
+			str, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("parse-strings: field-name[%s] has an element that is not a string", fieldName)
+			}
+			result[i] = str
+		}
+
+	default:
+		return nil, fmt.Errorf("parse-strings: field-name[%s] is not a valid type", fieldName)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("parse-strings: field-name[%s] must not be empty", fieldName)
+	}
+
+	return result, nil
+}
+
+// parseStop accepts a single stop string or a list of them.
+func parseStop(fieldName string, val any) ([]string, error) {
+	if str, ok := val.(string); ok {
+		if str == "" {
+			return nil, nil
+		}
+		return []string{str}, nil
+	}
+
+	stop, err := parseStrings(fieldName, val)
+	if err != nil {
+		return nil, fmt.Errorf("parse-stop: %w", err)
+	}
+
+	if len(stop) > maxStopSequences {
+		return nil, fmt.Errorf("parse-stop: field-name[%s] can't have more than %d strings", fieldName, maxStopSequences)
+	}
+
+	if slices.Contains(stop, "") {
+		return nil, fmt.Errorf("parse-stop: field-name[%s] can't have an empty string", fieldName)
+	}
+
+	return stop, nil
+}
+
+// parseLogitBias accepts a map of keys to a bias between -100 and 100.
+func parseLogitBias(fieldName string, val any) (map[string]float32, error) {
+	var biases map[string]any
+
+	switch v := val.(type) {
+	case D:
+		biases = v
+
+	case map[string]any:
+		biases = v
+
+	default:
+		return nil, fmt.Errorf("parse-logit-bias: field-name[%s] is not a valid type", fieldName)
+	}
+
+	result := make(map[string]float32, len(biases))
+	for key, v := range biases {
+		bias, err := parseFloat32(fieldName, v)
+		if err != nil {
+			return nil, fmt.Errorf("parse-logit-bias: %w", err)
+		}
+
+		if bias < -maxLogitBias || bias > maxLogitBias {
+			return nil, fmt.Errorf("parse-logit-bias: field-name[%s] bias for key[%s] must be between %d and %d", fieldName, key, -maxLogitBias, maxLogitBias)
+		}
+
+		result[key] = bias
+	}
+
+	return result, nil
+}
+
+// parseTokenBias accepts a map of token ids to a bias. JSON object keys are
+// always strings, so the ids are parsed from the keys.
+func parseTokenBias(fieldName string, val any) (map[llama.Token]float32, error) {
+	biases, err := parseLogitBias(fieldName, val)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[llama.Token]float32, len(biases))
+	for key, bias := range biases {
+		id, err := strconv.ParseInt(key, 10, 32)
+		if err != nil || id < 0 {
+			return nil, fmt.Errorf("parse-token-bias: field-name[%s] key[%s] is not a token id", fieldName, key)
+		}
+
+		result[llama.Token(id)] = bias
+	}
+
+	return result, nil
+}
+
+// parseSamplers validates the names of the samplers in the chain.
+func parseSamplers(fieldName string, val any) ([]string, error) {
+	samplers, err := parseStrings(fieldName, val)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range samplers {
+		if !slices.Contains(defSamplers, name) {
+			return nil, fmt.Errorf("parse-samplers: field-name[%s] has an unknown sampler[%s]", fieldName, name)
+		}
+	}
+
+	return samplers, nil
+}
+
+// parseResponseFormat converts a response format into a grammar. It accepts
+// the chat completions form where the schema lives under json_schema and the
+// responses form where the schema is provided next to the type.
+func parseResponseFormat(fieldName string, val any) (string, error) {
+	rf, ok := asMap(val)
+	if !ok {
+		return "", fmt.Errorf("parse-response-format: field-name[%s] is not a valid type", fieldName)
+	}
+
+	typ, _ := rf["type"].(string)
+
+	switch typ {
+	case "", "text":
+		return "", nil
+
+	case "json_object":
+		if schema, exists := rf["schema"]; exists {
+			return jsonSchemaToGrammar(schema)
+		}
+
+		return jsonObjectGrammar(), nil
+
+	case "json_schema":
+		schema, exists := rf["schema"]
+		if js, ok := asMap(rf["json_schema"]); ok {
+			schema, exists = js["schema"]
+		}
+
+		if !exists {
+			return "", fmt.Errorf("parse-response-format: field-name[%s] is missing a schema", fieldName)
+		}
+
+		grammar, err := jsonSchemaToGrammar(schema)
+		if err != nil {
+			return "", fmt.Errorf("parse-response-format: field-name[%s] is not valid: %w", fieldName, err)
+		}
+
+		return grammar, nil
+
+	default:
+		return "", fmt.Errorf("parse-response-format: field-name[%s] has an unsupported type[%s]", fieldName, typ)
+	}
+}
+
+func asMap(val any) (map[string]any, bool) {
+	switch v := val.(type) {
+	case D:
+		return v, true
+
+	case map[string]any:
+		return v, true
+	}
+
+	return nil, false
+}
+
 func parseReasoningString(fieldName string, val any) (string, error) {
 	result := ReasoningEffortMedium
 
@@ -0,0 +1,275 @@
+package model
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+func TestParseSamplers(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     any
+		want    []string
+		wantErr bool
+	}{
+		{"any slice", []any{"temperature", "top_k"}, []string{"temperature", "top_k"}, false},
+		{"string slice", []string{"dry", "min_p"}, []string{"dry", "min_p"}, false},
+		{"unknown sampler", []any{"top_k", "mirostat"}, nil, true},
+		{"non string element", []any{"top_k", 1}, nil, true},
+		{"empty", []any{}, nil, true},
+		{"wrong type", "top_k", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSamplers("samplers", tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSamplers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseSamplers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseParamsSamplerDefaults(t *testing.T) {
+	m := Model{cfg: Config{ContextWindow: 4096}}
+
+	p, err := m.parseParams(D{})
+	if err != nil {
+		t.Fatalf("parseParams() error = %v", err)
+	}
+
+	if !slices.Equal(p.Samplers, defSamplers) {
+		t.Errorf("Samplers = %v, want %v", p.Samplers, defSamplers)
+	}
+
+	if !slices.Equal(p.DrySequenceBreakers, defDrySequenceBreakers) {
+		t.Errorf("DrySequenceBreakers = %v, want %v", p.DrySequenceBreakers, defDrySequenceBreakers)
+	}
+
+	p, err = m.parseParams(D{"dry_sequence_breakers": []any{"\n\n"}, "samplers": []any{"top_k", "temperature"}})
+	if err != nil {
+		t.Fatalf("parseParams() error = %v", err)
+	}
+
+	if !slices.Equal(p.DrySequenceBreakers, []string{"\n\n"}) {
+		t.Errorf("DrySequenceBreakers = %q, want %q", p.DrySequenceBreakers, []string{"\n\n"})
+	}
+
+	if !slices.Equal(p.Samplers, []string{"top_k", "temperature"}) {
+		t.Errorf("Samplers = %v, want %v", p.Samplers, []string{"top_k", "temperature"})
+	}
+}
+
+func TestParseSeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     any
+		want    uint32
+		wantErr bool
+	}{
+		{"json number", float64(42), 42, false},
+		{"int", 7, 7, false},
+		{"string", "1234", 1234, false},
+		{"zero", float64(0), 0, false},
+		{"max", float64(0xFFFFFFFE), 0xFFFFFFFE, false},
+		{"llama default seed", float64(0xFFFFFFFF), 0, true},
+		{"fraction", 1.5, 0, true},
+		{"bad string", "abc", 0, true},
+		{"wrong type", true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSeed("seed", tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSeed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseSeed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("negative is random", func(t *testing.T) {
+		got, err := parseSeed("seed", float64(-1))
+		if err != nil {
+			t.Fatalf("parseSeed() error = %v", err)
+		}
+
+		if got == 0xFFFFFFFF {
+			t.Errorf("parseSeed() = %v, want a seed other than the llama default", got)
+		}
+	})
+}
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		name string
+		val  any
+		want bool
+	}{
+		{"true", true, true},
+		{"false", false, false},
+		{"string true", "true", true},
+		{"string false", "false", false},
+		{"empty string", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBool("field", tt.val)
+			if err != nil {
+				t.Fatalf("parseBool() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseParamsLogprobs(t *testing.T) {
+	m := Model{cfg: Config{ContextWindow: 4096}}
+
+	tests := []struct {
+		name     string
+		d        D
+		logprobs bool
+		top      int
+		wantErr  bool
+	}{
+		{"not set", D{}, false, 0, false},
+		{"enabled", D{"logprobs": true}, true, 0, false},
+		{"disabled", D{"logprobs": false}, false, 0, false},
+		{"top", D{"logprobs": true, "top_logprobs": float64(5)}, true, 5, false},
+		{"top without logprobs", D{"top_logprobs": float64(5)}, false, 0, true},
+		{"top too large", D{"logprobs": true, "top_logprobs": float64(21)}, false, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := m.parseParams(tt.d)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if p.Logprobs != tt.logprobs || p.TopLogprobs != tt.top {
+				t.Errorf("parseParams() logprobs = %v, top = %d, want %v, %d", p.Logprobs, p.TopLogprobs, tt.logprobs, tt.top)
+			}
+		})
+	}
+}
+
+func TestParseParamsN(t *testing.T) {
+	m := Model{cfg: Config{ContextWindow: 4096}}
+
+	tests := []struct {
+		name    string
+		d       D
+		want    int
+		wantErr bool
+	}{
+		{"not set", D{}, 1, false},
+		{"set", D{"n": float64(3)}, 3, false},
+		{"zero", D{"n": float64(0)}, 0, true},
+		{"negative", D{"n": float64(-2)}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := m.parseParams(tt.d)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && p.N != tt.want {
+				t.Errorf("N = %d, want %d", p.N, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStop(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     any
+		want    []string
+		wantErr bool
+	}{
+		{"string", "END", []string{"END"}, false},
+		{"empty string", "", nil, false},
+		{"list", []any{"a", "b"}, []string{"a", "b"}, false},
+		{"too many", []any{"a", "b", "c", "d", "e"}, nil, true},
+		{"empty element", []any{"a", ""}, nil, true},
+		{"wrong type", float64(1), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStop("stop", tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStop() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseStop() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseParamsLogitBias(t *testing.T) {
+	m := Model{cfg: Config{ContextWindow: 4096}}
+
+	tests := []struct {
+		name     string
+		d        D
+		wantBias map[llama.Token]float32
+		wantText map[string]float32
+		wantErr  bool
+	}{
+		{"not set", D{}, nil, nil, false},
+		{"token ids", D{"logit_bias": D{"15": float64(-100), "42": float64(5)}}, map[llama.Token]float32{15: -100, 42: 5}, nil, false},
+		{"json map", D{"logit_bias": map[string]any{"7": float64(1.5)}}, map[llama.Token]float32{7: 1.5}, nil, false},
+		{"text", D{"logit_bias_text": D{" yes": float64(10), " no": float64(10)}}, nil, map[string]float32{" yes": 10, " no": 10}, false},
+		{"key not an id", D{"logit_bias": D{"yes": float64(1)}}, nil, nil, true},
+		{"negative id", D{"logit_bias": D{"-1": float64(1)}}, nil, nil, true},
+		{"bias too large", D{"logit_bias": D{"1": float64(101)}}, nil, nil, true},
+		{"bias too small", D{"logit_bias_text": D{"a": float64(-101)}}, nil, nil, true},
+		{"wrong type", D{"logit_bias": []any{1}}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := m.parseParams(tt.d)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !maps.Equal(p.LogitBias, tt.wantBias) {
+				t.Errorf("LogitBias = %v, want %v", p.LogitBias, tt.wantBias)
+			}
+
+			if !maps.Equal(p.LogitBiasText, tt.wantText) {
+				t.Errorf("LogitBiasText = %v, want %v", p.LogitBiasText, tt.wantText)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package model
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -11,6 +10,9 @@ import (
 	"github.com/hybridgroup/yzma/pkg/llama"
 )
 
+// gptFinalChannel opens the final channel for GPT models.
+const gptFinalChannel = "<|channel|>final<|message|>"
+
 const (
 	statusNone       = 0
 	statusReasoning  = 1
@@ -29,15 +31,23 @@ type processor struct {
 	collecting      bool
 	awaitingChannel bool
 
-	// For accumulating tool call content across tokens (batch engine use).
+	// For accumulating tool call content across tokens.
+	parser      toolParser
 	toolCallBuf strings.Builder
 	inToolCall  bool
+
+	// Set once the completion is known not to open with a bare tool call.
+	completing bool
+
+	// Text held back because it could be the start of a tool call marker.
+	held string
 }
 
 func newProcessor(m *Model) *processor {
 	return &processor{
 		model:  m,
 		status: statusCompletion,
+		parser: m.toolParser,
 	}
 }
 
@@ -49,7 +59,7 @@ func (p *processor) standardFirst(lctx llama.Context, sampler llama.Sampler, buf
 		return response{}, token, err
 	}
 
-	return p.standardProcess(lctx, content, token, sampler, buf)
+	return p.standardProcess(content, token)
 }
 
 func (p *processor) standard(lctx llama.Context, batch llama.Batch, sampler llama.Sampler, buf []byte) (response, llama.Token, error) {
@@ -58,79 +68,13 @@ func (p *processor) standard(lctx llama.Context, batch llama.Batch, sampler llam
 		return response{}, token, err
 	}
 
-	return p.standardProcess(lctx, content, token, sampler, buf)
+	return p.standardProcess(content, token)
 }
 
 // standardProcess handles token content for standard (non-GPT) models.
-func (p *processor) standardProcess(lctx llama.Context, content string, token llama.Token, sampler llama.Sampler, buf []byte) (response, llama.Token, error) {
-	switch content {
-	case "<think>":
-		p.status = statusReasoning
-		return response{}, token, nil
-
-	case "</think>":
-		p.status = statusCompletion
-		return response{}, token, nil
-
-	case "<tool_call>":
-		p.status = statusTooling
-		var w strings.Builder
-
-		for {
-			batch, content, err := p.standardToolCall(lctx, token, sampler, buf)
-			if err != nil {
-				return response{}, token, err
-			}
-
-			w.WriteString(content)
-
-			_, token, err = p.model.batchResponse(lctx, batch, sampler, buf)
-			if err != nil {
-				if errors.Is(err, io.EOF) {
-					break
-				}
-
-				return response{}, token, err
-			}
-		}
-
-		return response{status: p.status, content: w.String()}, token, nil
-
-	default:
-		return response{status: p.status, content: content}, token, nil
-	}
-}
-
-func (p *processor) standardToolCall(lctx llama.Context, token llama.Token, sampler llama.Sampler, buf []byte) (llama.Batch, string, error) {
-	var batch llama.Batch
-	var content string
-	var err error
-	var data strings.Builder
-
-	for {
-		batch = p.model.nextBatch(token)
-		content, token, err = p.model.batchResponse(lctx, batch, sampler, buf)
-		if err != nil {
-			return batch, "", err
-		}
-
-		if content == "<tool_call>" {
-			continue
-		}
-
-		if content == "</tool_call>" {
-			break
-		}
-
-		data.WriteString(content)
-	}
-
-	content = strings.Trim(data.String(), "\n")
-	content = fmt.Sprintf("%s\n", content)
-
-	batch = p.model.nextBatch(token)
-
-	return batch, content, nil
+func (p *processor) standardProcess(content string, token llama.Token) (response, llama.Token, error) {
+	resp, _ := p.stepStandard(content)
+	return resp, token, nil
 }
 
 // =============================================================================
@@ -368,50 +312,178 @@ func parseJSONFormat(content string) []ResponseToolCall {
 func (p *processor) stepStandard(content string) (response, bool) {
 	// Handle tool call accumulation mode.
 	if p.inToolCall {
-		switch content {
-		case "<tool_call>":
-			// Nested or repeated tag, skip.
-			return response{}, false
-
-		case "</tool_call>":
-			// End of one tool call block. Check if we have accumulated content.
-			toolContent := strings.Trim(p.toolCallBuf.String(), "\n")
-			if toolContent != "" {
-				toolContent = fmt.Sprintf("%s\n", toolContent)
-			}
-
-			p.toolCallBuf.Reset()
-
-			// Stay in tool call mode in case there are more tool calls.
-			// The caller will handle EOG detection separately.
+		if toolContent := p.collectToolCall(content); toolContent != "" {
 			return response{status: statusTooling, content: toolContent}, false
-
-		default:
-			// Accumulate tool call content.
-			p.toolCallBuf.WriteString(content)
-			return response{}, false
 		}
+
+		return response{}, false
 	}
 
-	// Normal token processing.
+	// Normal token processing. Text held back for a tool call marker belongs
+	// to the mode that is ending.
 	switch content {
 	case "<think>":
+		resp := p.takeHeld()
 		p.status = statusReasoning
-		return response{}, false
+		return resp, false
 
 	case "</think>":
+		resp := p.takeHeld()
 		p.status = statusCompletion
-		return response{}, false
+		return resp, false
+
+	default:
+		return p.startToolCall(content), false
+	}
+}
+
+// startToolCall looks for the start marker of the tool parser in the content.
+// A marker can span several tokens, so completion text that could be the
+// start of one is held back until the next token shows if it is.
+func (p *processor) startToolCall(content string) response {
+	if p.parser.bare != "" && !p.completing && p.status == statusCompletion {
+		if resp, started := p.startBareToolCall(content); started {
+			return resp
+		}
+	}
 
-	case "<tool_call>":
+	start := p.parser.start
+	if start == "" {
+		return response{status: p.status, content: content}
+	}
+
+	text := p.held + content
+	p.held = ""
+
+	idx := strings.Index(text, start)
+	if idx == -1 {
+		var n int
+		if p.status != statusReasoning {
+			n = partialStop(text, start)
+		}
+
+		p.held = text[len(text)-n:]
+
+		return response{status: p.status, content: text[:len(text)-n]}
+	}
+
+	status := p.status
+
+	p.status = statusTooling
+	p.inToolCall = true
+	p.toolCallBuf.Reset()
+
+	// The text in front of the marker is returned first and the tool call
+	// is collected with the next token.
+	if idx > 0 {
+		p.toolCallBuf.WriteString(text[idx+len(start):])
+		return response{status: status, content: text[:idx]}
+	}
+
+	if toolContent := p.collectToolCall(text[len(start):]); toolContent != "" {
+		return response{status: statusTooling, content: toolContent}
+	}
+
+	return response{}
+}
+
+// startBareToolCall looks for a tool call without the start marker at the
+// opening of the completion. Text that could still open one is held back. It
+// reports false once the completion opens with anything else, and the text
+// is then processed as usual.
+func (p *processor) startBareToolCall(content string) (response, bool) {
+	text := p.held + content
+	trimmed := strings.TrimLeft(text, " \t\r\n")
+
+	switch {
+	case strings.HasPrefix(trimmed, p.parser.bare):
+		p.held = ""
 		p.status = statusTooling
 		p.inToolCall = true
 		p.toolCallBuf.Reset()
-		return response{}, false
+		p.toolCallBuf.WriteString(trimmed)
+		return response{}, true
 
-	default:
-		return response{status: p.status, content: content}, false
+	case strings.HasPrefix(p.parser.bare, trimmed):
+		p.held = text
+		return response{}, true
 	}
+
+	p.completing = true
+
+	return response{}, false
+}
+
+// collectToolCall adds the content to the tool call and returns the content
+// of the tool calls the end marker closed, one per line. A start marker
+// between tool calls opens the next one.
+func (p *processor) collectToolCall(content string) string {
+	p.toolCallBuf.WriteString(content)
+
+	if p.parser.end == "" {
+		return ""
+	}
+
+	var w strings.Builder
+
+	for {
+		buf := p.toolCallBuf.String()
+
+		idx := strings.Index(buf, p.parser.end)
+		if idx == -1 {
+			break
+		}
+
+		block := buf[:idx]
+		if i := strings.LastIndex(block, p.parser.start); i != -1 {
+			block = block[i+len(p.parser.start):]
+		}
+
+		if block = strings.Trim(block, "\n"); block != "" {
+			fmt.Fprintf(&w, "%s\n", block)
+		}
+
+		p.toolCallBuf.Reset()
+		p.toolCallBuf.WriteString(buf[idx+len(p.parser.end):])
+	}
+
+	return w.String()
+}
+
+// flush returns what the processor still holds once generation ends. That is
+// the text held back for a tool call marker or the tool calls of a parser
+// without an end marker, one per line.
+func (p *processor) flush() response {
+	if !p.inToolCall {
+		return p.takeHeld()
+	}
+
+	if p.parser.end != "" {
+		return response{status: statusTooling, content: p.collectToolCall("")}
+	}
+
+	var w strings.Builder
+
+	for block := range strings.SplitSeq(p.toolCallBuf.String(), p.parser.start) {
+		if block = strings.Trim(block, "\n"); block != "" {
+			fmt.Fprintf(&w, "%s\n", block)
+		}
+	}
+
+	p.toolCallBuf.Reset()
+
+	return response{status: statusTooling, content: w.String()}
+}
+
+func (p *processor) takeHeld() response {
+	if p.held == "" {
+		return response{}
+	}
+
+	held := p.held
+	p.held = ""
+
+	return response{status: p.status, content: held}
 }
 
 // stepGPT processes a single token for GPT models without calling llama.
@@ -475,6 +547,14 @@ func (p *processor) stepGPT(content string) (response, bool) {
 	}
 }
 
+// startFinal puts the processor in the state the GPT final channel leaves it
+// in. Use this when the final channel is opened as part of the prompt.
+func (p *processor) startFinal() {
+	p.status = statusCompletion
+	p.collecting = true
+	p.awaitingChannel = false
+}
+
 // resetState resets the processor state for reuse in a new slot.
 func (p *processor) resetState() {
 	p.status = statusCompletion
@@ -482,4 +562,6 @@ func (p *processor) resetState() {
 	p.awaitingChannel = false
 	p.toolCallBuf.Reset()
 	p.inToolCall = false
+	p.completing = false
+	p.held = ""
 }
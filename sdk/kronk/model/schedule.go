@@ -0,0 +1,23 @@
+package model
+
+import "context"
+
+// schedule is how the batch engine orders the requests waiting for a slot.
+type schedule struct {
+	priority int
+	subject  string
+}
+
+type scheduleKey struct{}
+
+// SetSchedule returns a context that carries the priority class and subject
+// of the requests made with it. The batch engine gives a slot to the waiting
+// request in the highest class first, and the subjects in a class take turns.
+func SetSchedule(ctx context.Context, priority int, subject string) context.Context {
+	return context.WithValue(ctx, scheduleKey{}, schedule{priority: priority, subject: subject})
+}
+
+func getSchedule(ctx context.Context) schedule {
+	s, _ := ctx.Value(scheduleKey{}).(schedule)
+	return s
+}
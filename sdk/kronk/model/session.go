@@ -0,0 +1,377 @@
+package model
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+// sessionMagic identifies a kronk session file and sessionVersion is bumped
+// whenever the file layout changes.
+const (
+	sessionMagic   = "KRNS"
+	sessionVersion = uint32(1)
+	sessionExt     = ".session"
+)
+
+// sessionState is the KV state of a sequence along with the tokens that
+// produced it. The version orders the states taken for a session, so an
+// older state never replaces a newer one on disk.
+type sessionState struct {
+	tokens  []llama.Token
+	data    []byte
+	version uint64
+}
+
+// SaveSession writes the KV cache state and the tokens held for the session to
+// disk. A later request that provides the same session_id restores this state
+// instead of processing the prompt again. The state is also saved when the slot
+// holding it is reused by another session and when the model is unloaded.
+func (m *Model) SaveSession(ctx context.Context, sessionID string) error {
+	if m.batch == nil || m.sessions == nil {
+		return errors.New("save-session: sessions are not enabled for this model")
+	}
+
+	if sessionID == "" {
+		return errors.New("save-session: session id is required")
+	}
+
+	return m.batch.saveSession(ctx, sessionID)
+}
+
+// DeleteSession removes the saved state for the session.
+func (m *Model) DeleteSession(ctx context.Context, sessionID string) error {
+	if m.batch == nil || m.sessions == nil {
+		return errors.New("delete-session: sessions are not enabled for this model")
+	}
+
+	if err := m.batch.deleteSession(ctx, sessionID); err != nil {
+		return fmt.Errorf("delete-session: %w", err)
+	}
+
+	if err := m.sessions.delete(sessionID); err != nil {
+		return fmt.Errorf("delete-session: %w", err)
+	}
+
+	return nil
+}
+
+// loadSession reads the saved state for the session when it isn't already
+// held by the batch engine.
+func (m *Model) loadSession(ctx context.Context, sessionID string) *sessionState {
+	if m.sessions == nil || m.batch.hasSession(ctx, sessionID) {
+		return nil
+	}
+
+	state, err := m.sessions.load(sessionID)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			m.log(ctx, "load-session", "status", "error", "session", sessionID, "err", err)
+		}
+		return nil
+	}
+
+	return &state
+}
+
+// =============================================================================
+
+// sessionStore persists session state to disk. The total size of the files in
+// the store is bounded by maxBytes, removing the least recently used files
+// first when the bound is exceeded. A saved session can't hold more than
+// maxTokens tokens, which is the context window of the model.
+//
+// Saves run in the background, so the store keeps the version last written
+// or deleted for each session and skips the saves of older states.
+type sessionStore struct {
+	dir       string
+	maxBytes  int64
+	maxTokens int
+	mu        sync.Mutex
+	seq       uint64
+	latest    map[string]uint64
+}
+
+func newSessionStore(dir string, maxBytes int64, maxTokens int) (*sessionStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("new-session-store: unable to create directory[%s]: %w", dir, err)
+	}
+
+	ss := sessionStore{
+		dir:       dir,
+		maxBytes:  maxBytes,
+		maxTokens: maxTokens,
+		latest:    make(map[string]uint64),
+	}
+
+	return &ss, nil
+}
+
+// path returns the file location for the session. The session id is hashed so
+// any string can be used as an id without escaping the directory.
+func (ss *sessionStore) path(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return filepath.Join(ss.dir, hex.EncodeToString(sum[:])+sessionExt)
+}
+
+// version returns the version for a state taken now.
+func (ss *sessionStore) version() uint64 {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.seq++
+
+	return ss.seq
+}
+
+// save writes the session state to disk and then enforces the size bound. A
+// state older than the one last saved or deleted for the session is skipped.
+func (ss *sessionStore) save(sessionID string, state sessionState) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if state.version < ss.latest[sessionID] {
+		return nil
+	}
+
+	ss.latest[sessionID] = state.version
+
+	path := ss.path(sessionID)
+
+	// Write to a temporary file first so readers never see a partial file.
+	f, err := os.CreateTemp(ss.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("save: unable to create file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := writeSession(f, state); err != nil {
+		f.Close()
+		return fmt.Errorf("save: unable to write session[%s]: %w", sessionID, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("save: unable to close file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("save: unable to rename file: %w", err)
+	}
+
+	return ss.evict(path)
+}
+
+// load reads the session state from disk. It returns os.ErrNotExist when no
+// state is saved for the session.
+func (ss *sessionStore) load(sessionID string) (sessionState, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	path := ss.path(sessionID)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return sessionState{}, fmt.Errorf("load: unable to open session[%s]: %w", sessionID, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return sessionState{}, fmt.Errorf("load: unable to stat session[%s]: %w", sessionID, err)
+	}
+
+	state, err := readSession(f, info.Size(), ss.maxTokens)
+	if err != nil {
+		return sessionState{}, fmt.Errorf("load: unable to read session[%s]: %w", sessionID, err)
+	}
+
+	// Mark the session as recently used for eviction.
+	now := time.Now()
+	os.Chtimes(path, now, now)
+
+	return state, nil
+}
+
+// delete removes any saved state for the session. States taken before the
+// delete are not saved afterwards.
+func (ss *sessionStore) delete(sessionID string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.seq++
+	ss.latest[sessionID] = ss.seq
+
+	if err := os.Remove(ss.path(sessionID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete: unable to remove session[%s]: %w", sessionID, err)
+	}
+
+	return nil
+}
+
+// evict removes the least recently used session files until the store fits
+// within maxBytes. The file at keep is never removed.
+func (ss *sessionStore) evict(keep string) error {
+	if ss.maxBytes <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(ss.dir)
+	if err != nil {
+		return fmt.Errorf("evict: unable to read directory: %w", err)
+	}
+
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var files []file
+	var total int64
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), sessionExt) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		files = append(files, file{
+			path:    filepath.Join(ss.dir, entry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+	}
+
+	slices.SortFunc(files, func(a, b file) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	for _, f := range files {
+		if total <= ss.maxBytes {
+			break
+		}
+
+		if f.path == keep {
+			continue
+		}
+
+		if err := os.Remove(f.path); err != nil {
+			return fmt.Errorf("evict: unable to remove file[%s]: %w", f.path, err)
+		}
+
+		total -= f.size
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// writeSession encodes the state using the layout:
+//
+//	magic[4] | version uint32 | nTokens uint32 | tokens int32[nTokens] |
+//	nData uint64 | data[nData]
+func writeSession(w io.Writer, state sessionState) error {
+	bw := bufio.NewWriter(w)
+
+	if _, err := bw.WriteString(sessionMagic); err != nil {
+		return err
+	}
+
+	header := []any{sessionVersion, uint32(len(state.tokens)), state.tokens, uint64(len(state.data))}
+	for _, v := range header {
+		if err := binary.Write(bw, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+
+	if _, err := bw.Write(state.data); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// readSession decodes a state written by writeSession. The lengths in the
+// header are checked against the size of the file and the token limit before
+// anything is allocated, so a corrupt file can't cause a huge allocation.
+func readSession(r io.Reader, size int64, maxTokens int) (sessionState, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(sessionMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return sessionState{}, err
+	}
+
+	if string(magic) != sessionMagic {
+		return sessionState{}, errors.New("not a session file")
+	}
+
+	var version, nTokens uint32
+	if err := binary.Read(br, binary.LittleEndian, &version); err != nil {
+		return sessionState{}, err
+	}
+
+	if version != sessionVersion {
+		return sessionState{}, fmt.Errorf("unsupported version[%d]", version)
+	}
+
+	if err := binary.Read(br, binary.LittleEndian, &nTokens); err != nil {
+		return sessionState{}, err
+	}
+
+	if int64(nTokens) > int64(maxTokens) {
+		return sessionState{}, fmt.Errorf("session holds %d tokens, more than the limit of %d", nTokens, maxTokens)
+	}
+
+	// The size of the magic, version and token count read so far.
+	offset := int64(len(sessionMagic) + 8)
+
+	if int64(nTokens)*4 > size-offset {
+		return sessionState{}, fmt.Errorf("session holds %d tokens, more than the file size allows", nTokens)
+	}
+
+	offset += int64(nTokens) * 4
+
+	tokens := make([]llama.Token, nTokens)
+	if err := binary.Read(br, binary.LittleEndian, tokens); err != nil {
+		return sessionState{}, err
+	}
+
+	var nData uint64
+	if err := binary.Read(br, binary.LittleEndian, &nData); err != nil {
+		return sessionState{}, err
+	}
+
+	offset += 8
+
+	if nData > uint64(max(size-offset, 0)) {
+		return sessionState{}, fmt.Errorf("session holds %d bytes of state, more than the file size allows", nData)
+	}
+
+	data := make([]byte, nData)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return sessionState{}, err
+	}
+
+	return sessionState{tokens: tokens, data: data}, nil
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hybridgroup/yzma/pkg/llama"
+)
+
+func TestSessionStore(t *testing.T) {
+	ss, err := newSessionStore(t.TempDir(), 0, 4096)
+	if err != nil {
+		t.Fatalf("newSessionStore() error = %v", err)
+	}
+
+	state := sessionState{
+		tokens: []llama.Token{1, 2, 3, 4},
+		data:   []byte("kv-cache-state"),
+	}
+
+	if err := ss.save("session/../1", state); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	got, err := ss.load("session/../1")
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	if !slices.Equal(got.tokens, state.tokens) {
+		t.Errorf("load() tokens = %v, want %v", got.tokens, state.tokens)
+	}
+
+	if !bytes.Equal(got.data, state.data) {
+		t.Errorf("load() data = %q, want %q", got.data, state.data)
+	}
+
+	if err := ss.delete("session/../1"); err != nil {
+		t.Fatalf("delete() error = %v", err)
+	}
+
+	if _, err := ss.load("session/../1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("load() after delete error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestSessionStoreEvict(t *testing.T) {
+	state := sessionState{
+		tokens: []llama.Token{1, 2, 3, 4},
+		data:   make([]byte, 1024),
+	}
+
+	var buf bytes.Buffer
+	if err := writeSession(&buf, state); err != nil {
+		t.Fatalf("writeSession() error = %v", err)
+	}
+	size := int64(buf.Len())
+
+	// Room for two sessions.
+	ss, err := newSessionStore(t.TempDir(), size*2, 4096)
+	if err != nil {
+		t.Fatalf("newSessionStore() error = %v", err)
+	}
+
+	for i, id := range []string{"a", "b"} {
+		if err := ss.save(id, state); err != nil {
+			t.Fatalf("save(%s) error = %v", id, err)
+		}
+
+		past := time.Now().Add(time.Duration(i-10) * time.Minute)
+		os.Chtimes(ss.path(id), past, past)
+	}
+
+	// Using session a makes b the least recently used.
+	if _, err := ss.load("a"); err != nil {
+		t.Fatalf("load(a) error = %v", err)
+	}
+
+	if err := ss.save("c", state); err != nil {
+		t.Fatalf("save(c) error = %v", err)
+	}
+
+	for id, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		_, err := os.Stat(ss.path(id))
+		if got := err == nil; got != want {
+			t.Errorf("session %s exists = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestSessionStoreVersions(t *testing.T) {
+	ss, err := newSessionStore(t.TempDir(), 0, 4096)
+	if err != nil {
+		t.Fatalf("newSessionStore() error = %v", err)
+	}
+
+	older := sessionState{tokens: []llama.Token{1}, data: []byte("old"), version: ss.version()}
+	newer := sessionState{tokens: []llama.Token{1, 2}, data: []byte("new"), version: ss.version()}
+
+	if err := ss.save("a", newer); err != nil {
+		t.Fatalf("save(newer) error = %v", err)
+	}
+
+	if err := ss.save("a", older); err != nil {
+		t.Fatalf("save(older) error = %v", err)
+	}
+
+	got, err := ss.load("a")
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	if string(got.data) != "new" {
+		t.Errorf("load() data = %q, want the newer state", got.data)
+	}
+
+	// A state taken before a delete isn't written back.
+	stale := sessionState{tokens: []llama.Token{1}, data: []byte("stale"), version: ss.version()}
+
+	if err := ss.delete("a"); err != nil {
+		t.Fatalf("delete() error = %v", err)
+	}
+
+	if err := ss.save("a", stale); err != nil {
+		t.Fatalf("save(stale) error = %v", err)
+	}
+
+	if _, err := ss.load("a"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("load() after delete error = %v, want not exist", err)
+	}
+
+	fresh := sessionState{tokens: []llama.Token{1}, data: []byte("fresh"), version: ss.version()}
+	if err := ss.save("a", fresh); err != nil {
+		t.Fatalf("save(fresh) error = %v", err)
+	}
+
+	if _, err := ss.load("a"); err != nil {
+		t.Errorf("load() after a new save error = %v", err)
+	}
+}
+
+func TestReadSessionInvalid(t *testing.T) {
+	if _, err := readSession(bytes.NewReader([]byte("NOPE")), 4, 4096); err == nil {
+		t.Errorf("readSession() expected error for bad magic")
+	}
+
+	var buf bytes.Buffer
+	writeSession(&buf, sessionState{tokens: []llama.Token{1}, data: []byte("data")})
+
+	truncated := buf.Bytes()[:buf.Len()-2]
+	if _, err := readSession(bytes.NewReader(truncated), int64(len(truncated)), 4096); err == nil {
+		t.Errorf("readSession() expected error for truncated file")
+	}
+
+	if _, err := readSession(bytes.NewReader(buf.Bytes()), int64(buf.Len()), 0); err == nil {
+		t.Errorf("readSession() expected error for more tokens than the limit")
+	}
+}
+
+func TestReadSessionCorruptLengths(t *testing.T) {
+	header := func(nTokens uint32, nData uint64) []byte {
+		var buf bytes.Buffer
+		buf.WriteString(sessionMagic)
+		binary.Write(&buf, binary.LittleEndian, sessionVersion)
+		binary.Write(&buf, binary.LittleEndian, nTokens)
+		binary.Write(&buf, binary.LittleEndian, make([]llama.Token, min(nTokens, 1)))
+		binary.Write(&buf, binary.LittleEndian, nData)
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"tokens", header(math.MaxUint32, 0)},
+		{"data", header(1, math.MaxUint64)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readSession(bytes.NewReader(tt.data), int64(len(tt.data)), math.MaxInt32); err == nil {
+				t.Errorf("readSession() expected error for a corrupt length")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package model
+
+import "strings"
+
+// maxStopSequences is the largest number of stop strings a request can have,
+// which matches the OpenAI API.
+const maxStopSequences = 4
+
+// stopMatcher looks for stop strings in the generated content. A stop string
+// can span several tokens, so text that could be the start of one is held back
+// until it's known not to be, which keeps it from ever reaching the client.
+type stopMatcher struct {
+	stops   []string
+	held    string
+	stopped bool
+}
+
+func newStopMatcher(stops []string) stopMatcher {
+	return stopMatcher{
+		stops: stops,
+	}
+}
+
+// process adds the content to the held text and returns the text that is safe
+// to send. When a stop string is found, the text in front of it is returned
+// and stopped is true. Content after a stop string is dropped.
+func (sm *stopMatcher) process(content string) (string, bool) {
+	if sm.stopped {
+		return "", true
+	}
+
+	if len(sm.stops) == 0 {
+		return content, false
+	}
+
+	text := sm.held + content
+	sm.held = ""
+
+	// The held text never contains a full stop string, so the first match
+	// ends in the new content.
+	idx := -1
+	for _, stop := range sm.stops {
+		if i := strings.Index(text, stop); i >= 0 && (idx < 0 || i < idx) {
+			idx = i
+		}
+	}
+
+	if idx >= 0 {
+		sm.stopped = true
+		return text[:idx], true
+	}
+
+	var n int
+	for _, stop := range sm.stops {
+		n = max(n, partialStop(text, stop))
+	}
+
+	sm.held = text[len(text)-n:]
+
+	return text[:len(text)-n], false
+}
+
+// flush returns the held text once generation ends without a stop string.
+func (sm *stopMatcher) flush() string {
+	held := sm.held
+	sm.held = ""
+
+	return held
+}
+
+// partialStop returns the length of the longest suffix of the text that is
+// the start of the stop string.
+func partialStop(text string, stop string) int {
+	for n := min(len(text), len(stop)-1); n > 0; n-- {
+		if strings.HasSuffix(text, stop[:n]) {
+			return n
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStopMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		stops   []string
+		tokens  []string
+		want    []string
+		stopped bool
+		held    string
+	}{
+		{"no stops", nil, []string{"Hello", " world"}, []string{"Hello", " world"}, false, ""},
+		{"single token", []string{"END"}, []string{"Hello", "END", "more"}, []string{"Hello", ""}, true, ""},
+		{"inside token", []string{"END"}, []string{"Hi", " thereEND now"}, []string{"Hi", " there"}, true, ""},
+		{"across tokens", []string{"</answer>"}, []string{"42", "</", "ans", "wer>"}, []string{"42", "", "", ""}, true, ""},
+		{"partial then miss", []string{"</answer>"}, []string{"a <", "/b"}, []string{"a ", "</b"}, false, ""},
+		{"held at end", []string{"STOP"}, []string{"go ST"}, []string{"go "}, false, "ST"},
+		{"earliest stop wins", []string{"cd", "b"}, []string{"abcd"}, []string{"a"}, true, ""},
+		{"overlapping prefix", []string{"aab"}, []string{"aa", "aab"}, []string{"", "aa"}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := newStopMatcher(tt.stops)
+
+			var stopped bool
+			for i, token := range tt.tokens {
+				var got string
+				got, stopped = sm.process(token)
+
+				if got != tt.want[i] {
+					t.Errorf("process(%q) = %q, want %q", token, got, tt.want[i])
+				}
+
+				if stopped {
+					break
+				}
+			}
+
+			if stopped != tt.stopped {
+				t.Errorf("stopped = %v, want %v", stopped, tt.stopped)
+			}
+
+			if held := sm.flush(); held != tt.held {
+				t.Errorf("flush() = %q, want %q", held, tt.held)
+			}
+		})
+	}
+}
+
+func TestStopMatcherNeverLeaks(t *testing.T) {
+	const stop = "<|end|>"
+
+	text := "The answer is 42.<|end|> ignored"
+
+	sm := newStopMatcher([]string{stop})
+
+	var sent strings.Builder
+	for i := range len(text) {
+		out, stopped := sm.process(text[i : i+1])
+		sent.WriteString(out)
+
+		if stopped {
+			break
+		}
+	}
+
+	if got := sent.String(); got != "The answer is 42." {
+		t.Errorf("sent = %q, want %q", got, "The answer is 42.")
+	}
+}
+
+func TestStopMatcherAfterStop(t *testing.T) {
+	sm := newStopMatcher([]string{"END"})
+
+	if _, stopped := sm.process("doneEND"); !stopped {
+		t.Fatal("expected the stop string to be found")
+	}
+
+	if out, stopped := sm.process("more"); out != "" || !stopped {
+		t.Errorf("process after stop = %q, %v, want empty and stopped", out, stopped)
+	}
+}
@@ -0,0 +1,366 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Set of tool call parsers that can be selected in the config.
+const (
+	ToolParserHermes      = "hermes"
+	ToolParserMistral     = "mistral"
+	ToolParserLlama3      = "llama3"
+	ToolParserFunctionary = "functionary"
+	ToolParserGranite     = "granite"
+	ToolParserDeepSeek    = "deepseek"
+	ToolParserGPT         = "gpt-oss"
+)
+
+// toolParser finds and parses the tool calls of a model family. The start
+// marker opens a tool call in the generated text and the end marker closes
+// it. Without an end marker the tool call runs to the end of generation.
+// The content of the tool calls is handed to parse one per line. Some models
+// can leave out the start marker, in which case a completion that opens with
+// the bare text is a tool call that runs to the end of generation.
+type toolParser struct {
+	name  string
+	start string
+	end   string
+	bare  string
+	parse func(content string) []ResponseToolCall
+}
+
+var toolParsers = map[string]toolParser{
+	ToolParserHermes: {
+		name:  ToolParserHermes,
+		start: "<tool_call>",
+		end:   "</tool_call>",
+		parse: parseToolCall,
+	},
+	ToolParserMistral: {
+		name:  ToolParserMistral,
+		start: "[TOOL_CALLS]",
+		parse: parseMistralToolCall,
+	},
+	ToolParserLlama3: {
+		name:  ToolParserLlama3,
+		start: "<|python_tag|>",
+		bare:  `{"name"`,
+		parse: parseJSONToolCalls,
+	},
+	ToolParserFunctionary: {
+		name:  ToolParserFunctionary,
+		start: "<function=",
+		end:   "</function>",
+		parse: parseFunctionaryToolCall,
+	},
+	ToolParserGranite: {
+		name:  ToolParserGranite,
+		start: "<|tool_call|>",
+		parse: parseJSONToolCalls,
+	},
+	ToolParserDeepSeek: {
+		name:  ToolParserDeepSeek,
+		start: "<｜tool▁calls▁begin｜>",
+		end:   "<｜tool▁calls▁end｜>",
+		parse: parseDeepSeekToolCall,
+	},
+	ToolParserGPT: {
+		name:  ToolParserGPT,
+		parse: parseGPTToolCall,
+	},
+}
+
+// validateToolParser checks the tool parser set in the config is known.
+func validateToolParser(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	if _, exists := toolParsers[name]; !exists {
+		return fmt.Errorf("validate-tool-parser: unknown tool parser: %s", name)
+	}
+
+	return nil
+}
+
+// templateToolParsers is the order the chat template is searched for the
+// start marker of a parser. Templates that wrap the functionary format in
+// hermes tags are found as hermes first.
+var templateToolParsers = []string{
+	ToolParserHermes,
+	ToolParserDeepSeek,
+	ToolParserMistral,
+	ToolParserGranite,
+	ToolParserFunctionary,
+	ToolParserLlama3,
+}
+
+// metadataToolParsers maps the names found in the model metadata to the
+// parser of the model family.
+var metadataToolParsers = []struct {
+	parser string
+	names  []string
+}{
+	{ToolParserDeepSeek, []string{"deepseek"}},
+	{ToolParserMistral, []string{"mistral", "mixtral", "devstral", "magistral"}},
+	{ToolParserGranite, []string{"granite"}},
+	{ToolParserFunctionary, []string{"functionary"}},
+	{ToolParserLlama3, []string{"llama-3", "llama 3", "llama3"}},
+}
+
+// selectToolParser returns the tool parser for the model. The parser set in
+// the config is used first, then the parser whose start marker is found in
+// the chat template and then the one named by the model metadata. Models
+// that match nothing use the hermes parser.
+func selectToolParser(cfg Config, mi ModelInfo) toolParser {
+	if tp, exists := toolParsers[cfg.ToolParser]; exists {
+		return tp
+	}
+
+	if mi.IsGPTModel {
+		return toolParsers[ToolParserGPT]
+	}
+
+	for _, name := range templateToolParsers {
+		tp := toolParsers[name]
+		if strings.Contains(mi.Template.Script, tp.start) {
+			return tp
+		}
+	}
+
+	meta := strings.ToLower(strings.Join([]string{
+		mi.Metadata["general.architecture"],
+		mi.Metadata["general.name"],
+		mi.Metadata["general.basename"],
+	}, " "))
+
+	for _, mp := range metadataToolParsers {
+		for _, name := range mp.names {
+			if strings.Contains(meta, name) {
+				return toolParsers[mp.parser]
+			}
+		}
+	}
+
+	return toolParsers[ToolParserHermes]
+}
+
+// =============================================================================
+
+// jsonToolCall is a tool call written as a JSON object. Llama models name
+// the arguments parameters.
+type jsonToolCall struct {
+	Name       string            `json:"name"`
+	Arguments  ToolCallArguments `json:"arguments"`
+	Parameters ToolCallArguments `json:"parameters"`
+}
+
+func newToolCall(name string, args map[string]any, raw string) ResponseToolCall {
+	return ResponseToolCall{
+		ID:   uuid.NewString(),
+		Type: "function",
+		Function: ResponseToolCallFunction{
+			Name:      name,
+			Arguments: args,
+		},
+		Raw: raw,
+	}
+}
+
+func newToolCallError(err error, raw string) ResponseToolCall {
+	return ResponseToolCall{
+		ID:     uuid.NewString(),
+		Type:   "function",
+		Status: 2,
+		Error:  err.Error(),
+		Raw:    raw,
+	}
+}
+
+// decodeJSON decodes the JSON value at the start of the content and returns
+// the number of bytes it used.
+func decodeJSON(content string, v any) (int, error) {
+	dec := json.NewDecoder(strings.NewReader(content))
+	if err := dec.Decode(v); err != nil {
+		return 0, err
+	}
+
+	return int(dec.InputOffset()), nil
+}
+
+// parseJSONToolCalls parses a run of tool calls written as JSON objects or
+// arrays of objects, which can be separated by white space, commas or
+// semicolons.
+//
+// [{"name":"get_weather","arguments":{"location":"NYC"}}]
+// {"name":"get_weather","parameters":{"location":"NYC"}}; {"name":...}
+func parseJSONToolCalls(content string) []ResponseToolCall {
+	var toolCalls []ResponseToolCall
+
+	for {
+		content = strings.TrimLeft(content, " \t\r\n,;")
+		if content == "" {
+			break
+		}
+
+		var value json.RawMessage
+		n, err := decodeJSON(content, &value)
+		if err != nil {
+			toolCalls = append(toolCalls, newToolCallError(err, content))
+			break
+		}
+
+		content = content[n:]
+
+		elems := []json.RawMessage{value}
+		if value[0] == '[' {
+			if err := json.Unmarshal(value, &elems); err != nil {
+				toolCalls = append(toolCalls, newToolCallError(err, string(value)))
+				continue
+			}
+		}
+
+		for _, elem := range elems {
+			var call jsonToolCall
+			if err := json.Unmarshal(elem, &call); err != nil {
+				toolCalls = append(toolCalls, newToolCallError(err, string(elem)))
+				continue
+			}
+
+			args := call.Arguments
+			if args == nil {
+				args = call.Parameters
+			}
+
+			toolCalls = append(toolCalls, newToolCall(call.Name, args, string(elem)))
+		}
+	}
+
+	return toolCalls
+}
+
+// parseMistralToolCall parses the tool calls of Mistral models, which are a
+// JSON array in older models and the name followed by the arguments in newer
+// ones.
+//
+// [{"name":"get_weather","arguments":{"location":"NYC"}}]
+// get_weather[ARGS]{"location":"NYC"}
+// get_weather[CALL_ID]a1b2c3d4e[ARGS]{"location":"NYC"}
+func parseMistralToolCall(content string) []ResponseToolCall {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "[") || strings.HasPrefix(content, "{") {
+		return parseJSONToolCalls(content)
+	}
+
+	var toolCalls []ResponseToolCall
+
+	for {
+		content = strings.TrimSpace(content)
+		if content == "" {
+			break
+		}
+
+		name, args, found := strings.Cut(content, "[ARGS]")
+		if !found {
+			toolCalls = append(toolCalls, newToolCallError(fmt.Errorf("arguments missing"), content))
+			break
+		}
+
+		name, _, _ = strings.Cut(name, "[CALL_ID]")
+
+		var arguments ToolCallArguments
+		n, err := decodeJSON(args, &arguments)
+		if err != nil {
+			toolCalls = append(toolCalls, newToolCallError(err, content))
+			break
+		}
+
+		raw := content[:len(content)-len(args)+n]
+		toolCalls = append(toolCalls, newToolCall(strings.TrimSpace(name), arguments, raw))
+
+		content = args[n:]
+	}
+
+	return toolCalls
+}
+
+// parseFunctionaryToolCall parses the tool calls of Functionary models. The
+// start marker holds the opening of the function tag, so the content starts
+// with the name.
+//
+// get_weather>{"location":"NYC"}
+func parseFunctionaryToolCall(content string) []ResponseToolCall {
+	var toolCalls []ResponseToolCall
+
+	for {
+		content = strings.TrimSpace(content)
+		if content == "" {
+			break
+		}
+
+		name, args, found := strings.Cut(content, ">")
+		if !found {
+			toolCalls = append(toolCalls, newToolCallError(fmt.Errorf("arguments missing"), content))
+			break
+		}
+
+		var arguments ToolCallArguments
+		n, err := decodeJSON(args, &arguments)
+		if err != nil {
+			toolCalls = append(toolCalls, newToolCallError(err, content))
+			break
+		}
+
+		raw := content[:len(content)-len(args)+n]
+		toolCalls = append(toolCalls, newToolCall(strings.TrimSpace(name), arguments, raw))
+
+		content = args[n:]
+	}
+
+	return toolCalls
+}
+
+// parseDeepSeekToolCall parses the tool calls of DeepSeek models. Older
+// models write the type before the separator and the arguments in a JSON
+// code block, newer ones the name before it.
+//
+// <｜tool▁call▁begin｜>function<｜tool▁sep｜>get_weather\n```json\n{"location":"NYC"}\n```<｜tool▁call▁end｜>
+// <｜tool▁call▁begin｜>get_weather<｜tool▁sep｜>{"location":"NYC"}<｜tool▁call▁end｜>
+func parseDeepSeekToolCall(content string) []ResponseToolCall {
+	var toolCalls []ResponseToolCall
+
+	for call := range strings.SplitSeq(content, "<｜tool▁call▁begin｜>") {
+		call, _, _ = strings.Cut(call, "<｜tool▁call▁end｜>")
+		call = strings.TrimSpace(call)
+		if call == "" {
+			continue
+		}
+
+		name, args, found := strings.Cut(call, "<｜tool▁sep｜>")
+		if !found {
+			toolCalls = append(toolCalls, newToolCallError(fmt.Errorf("separator missing"), call))
+			continue
+		}
+
+		if strings.TrimSpace(name) == "function" {
+			name, args, _ = strings.Cut(args, "\n")
+			args = strings.TrimSpace(args)
+			args = strings.TrimPrefix(args, "```json")
+			args = strings.TrimSuffix(args, "```")
+		}
+
+		var arguments ToolCallArguments
+		if _, err := decodeJSON(strings.TrimSpace(args), &arguments); err != nil {
+			toolCalls = append(toolCalls, newToolCallError(err, call))
+			continue
+		}
+
+		toolCalls = append(toolCalls, newToolCall(strings.TrimSpace(name), arguments, call))
+	}
+
+	return toolCalls
+}
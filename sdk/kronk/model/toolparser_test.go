@@ -0,0 +1,155 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToolParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		parser  string
+		content string
+		calls   []string
+		args    []string
+	}{
+		{"hermes json", ToolParserHermes, `{"name":"get_weather","arguments":{"location":"NYC"}}`, []string{"get_weather"}, []string{"NYC"}},
+		{"mistral array", ToolParserMistral, `[{"name":"get_weather","arguments":{"location":"NYC"}},{"name":"get_time","arguments":{"location":"LA"}}]`, []string{"get_weather", "get_time"}, []string{"NYC", "LA"}},
+		{"mistral args", ToolParserMistral, "get_weather[ARGS]{\"location\":\"NYC\"}\nget_time[CALL_ID]a1b2c3d4e[ARGS]{\"location\":\"LA\"}", []string{"get_weather", "get_time"}, []string{"NYC", "LA"}},
+		{"llama3", ToolParserLlama3, `{"name":"get_weather","parameters":{"location":"NYC"}}; {"name":"get_time","parameters":{"location":"LA"}}`, []string{"get_weather", "get_time"}, []string{"NYC", "LA"}},
+		{"granite", ToolParserGranite, `[{"name":"get_weather","arguments":{"location":"NYC"}}]`, []string{"get_weather"}, []string{"NYC"}},
+		{"functionary", ToolParserFunctionary, "get_weather>{\"location\":\"NYC\"}\nget_time>{\"location\":\"LA\"}", []string{"get_weather", "get_time"}, []string{"NYC", "LA"}},
+		{"deepseek", ToolParserDeepSeek, "<｜tool▁call▁begin｜>function<｜tool▁sep｜>get_weather\n```json\n{\"location\":\"NYC\"}\n```<｜tool▁call▁end｜>", []string{"get_weather"}, []string{"NYC"}},
+		{"deepseek v3.1", ToolParserDeepSeek, `<｜tool▁call▁begin｜>get_weather<｜tool▁sep｜>{"location":"NYC"}<｜tool▁call▁end｜><｜tool▁call▁begin｜>get_time<｜tool▁sep｜>{"location":"LA"}<｜tool▁call▁end｜>`, []string{"get_weather", "get_time"}, []string{"NYC", "LA"}},
+		{"gpt-oss", ToolParserGPT, `.get_weather <|constrain|>json<|message|>{"location":"NYC"}`, []string{"get_weather"}, []string{"NYC"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := toolParsers[tt.parser].parse(tt.content)
+
+			if len(calls) != len(tt.calls) {
+				t.Fatalf("expected %d tool calls, got %d: %+v", len(tt.calls), len(calls), calls)
+			}
+
+			for i, call := range calls {
+				if call.Status != 0 {
+					t.Errorf("call %d: unexpected error: %s", i, call.Error)
+				}
+
+				if call.Function.Name != tt.calls[i] {
+					t.Errorf("call %d: expected name %q, got %q", i, tt.calls[i], call.Function.Name)
+				}
+
+				if call.Function.Arguments["location"] != tt.args[i] {
+					t.Errorf("call %d: expected location %q, got %v", i, tt.args[i], call.Function.Arguments["location"])
+				}
+			}
+		})
+	}
+}
+
+func TestToolParserErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		parser  string
+		content string
+	}{
+		{"llama3 bad json", ToolParserLlama3, `{"name":"get_weather",`},
+		{"mistral missing args", ToolParserMistral, `get_weather{"location":"NYC"}`},
+		{"functionary bad json", ToolParserFunctionary, `get_weather>{location}`},
+		{"deepseek missing separator", ToolParserDeepSeek, `<｜tool▁call▁begin｜>get_weather<｜tool▁call▁end｜>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := toolParsers[tt.parser].parse(tt.content)
+
+			if len(calls) != 1 || calls[0].Status == 0 || calls[0].Error == "" {
+				t.Errorf("expected a tool call with an error, got %+v", calls)
+			}
+		})
+	}
+}
+
+func TestSelectToolParser(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		mi   ModelInfo
+		want string
+	}{
+		{"config", Config{ToolParser: ToolParserGranite}, ModelInfo{Template: Template{Script: "<tool_call>"}}, ToolParserGranite},
+		{"gpt", Config{}, ModelInfo{IsGPTModel: true}, ToolParserGPT},
+		{"hermes template", Config{}, ModelInfo{Template: Template{Script: "<tool_call>\n<function="}}, ToolParserHermes},
+		{"mistral template", Config{}, ModelInfo{Template: Template{Script: "[AVAILABLE_TOOLS][TOOL_CALLS]"}}, ToolParserMistral},
+		{"functionary template", Config{}, ModelInfo{Template: Template{Script: "<function=\n<|python_tag|>"}}, ToolParserFunctionary},
+		{"deepseek metadata", Config{}, ModelInfo{Metadata: map[string]string{"general.architecture": "deepseek2"}}, ToolParserDeepSeek},
+		{"llama3 metadata", Config{}, ModelInfo{Metadata: map[string]string{"general.architecture": "llama", "general.name": "Meta Llama 3.1 8B Instruct"}}, ToolParserLlama3},
+		{"default", Config{}, ModelInfo{Metadata: map[string]string{"general.architecture": "qwen3"}}, ToolParserHermes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectToolParser(tt.cfg, tt.mi).name; got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	if err := validateToolParser("unknown"); err == nil {
+		t.Error("expected an error for an unknown tool parser")
+	}
+}
+
+func TestProcessorToolCalls(t *testing.T) {
+	tests := []struct {
+		name    string
+		parser  string
+		tokens  []string
+		content string
+		tooling string
+	}{
+		{"hermes", ToolParserHermes, []string{"Sure", "<tool_call>", "\n{\"a\":1}", "\n", "</tool_call>", "\n", "<tool_call>", "{\"b\":2}", "</tool_call>"}, "Sure", "{\"a\":1}\n{\"b\":2}\n"},
+		{"split marker", ToolParserFunctionary, []string{"Ok ", "<", "function", "=get", "_weather>{}", "</", "function>"}, "Ok ", "get_weather>{}\n"},
+		{"held then miss", ToolParserFunctionary, []string{"a <", "b"}, "a <b", ""},
+		{"held at end", ToolParserFunctionary, []string{"a <func"}, "a <func", ""},
+		{"to end", ToolParserMistral, []string{"[TOOL_CALLS]", "get_weather[ARGS]{}", "[TOOL_CALLS]", "get_time[ARGS]{}"}, "", "get_weather[ARGS]{}\nget_time[ARGS]{}\n"},
+		{"llama3 tagged", ToolParserLlama3, []string{"<|python_tag|>", "{\"name\":\"a\"}"}, "", "{\"name\":\"a\"}\n"},
+		{"llama3 untagged", ToolParserLlama3, []string{"\n", "{\"", "name\": \"get_weather\",", " \"parameters\": {}}"}, "", "{\"name\": \"get_weather\", \"parameters\": {}}\n"},
+		{"llama3 not a call", ToolParserLlama3, []string{"{\"", "id\": 1}"}, "{\"id\": 1}", ""},
+		{"llama3 call in text", ToolParserLlama3, []string{"Use ", "{\"name\": 1}"}, "Use {\"name\": 1}", ""},
+		{"reasoning", ToolParserHermes, []string{"<think>", "x <", "</think>", "<tool", "_call>", "{}", "</tool_call>"}, "", "{}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProcessor(&Model{toolParser: toolParsers[tt.parser]})
+
+			var content, tooling strings.Builder
+			add := func(resp response) {
+				switch resp.status {
+				case statusCompletion:
+					content.WriteString(resp.content)
+				case statusTooling:
+					tooling.WriteString(resp.content)
+				}
+			}
+
+			for _, token := range tt.tokens {
+				resp, _ := p.stepStandard(token)
+				add(resp)
+			}
+
+			add(p.flush())
+
+			if got := content.String(); got != tt.content {
+				t.Errorf("content = %q, want %q", got, tt.content)
+			}
+
+			if got := tooling.String(); got != tt.tooling {
+				t.Errorf("tooling = %q, want %q", got, tt.tooling)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ardanlabs/kronk/sdk/kronk/model"
@@ -41,6 +42,7 @@ type ResponseResponse struct {
 	Usage            ResponseUsage          `json:"usage"`
 	User             *string                `json:"user"`
 	Metadata         map[string]interface{} `json:"metadata"`
+	Seed             uint32                 `json:"seed"`
 }
 
 // ResponseError represents an error in the response.
@@ -141,6 +143,7 @@ func (krn *Kronk) Response(ctx context.Context, d model.D) (ResponseResponse, er
 	}
 
 	d = convertInputToMessages(d)
+	d = convertTextFormat(d)
 
 	f := func(m *model.Model) (model.ChatResponse, error) {
 		return m.Chat(ctx, d)
@@ -164,6 +167,7 @@ func (krn *Kronk) ResponseStreaming(ctx context.Context, d model.D) (<-chan Resp
 	}
 
 	d = convertInputToMessages(d)
+	d = convertTextFormat(d)
 
 	f := func(m *model.Model) <-chan model.ChatResponse {
 		return m.ChatStreaming(ctx, d)
@@ -234,15 +238,14 @@ func (krn *Kronk) ResponseStreamingHTTP(ctx context.Context, w http.ResponseWrit
 		return ResponseResponse{}, fmt.Errorf("responses-streaming-http: streaming not supported")
 	}
 
-	ch, err := krn.ResponseStreaming(ctx, d)
+	es := eventStream{w: w, f: f}
+
+	ch, err := krn.ResponseStreaming(es.reportWaiting(ctx), d)
 	if err != nil {
-		return ResponseResponse{}, fmt.Errorf("responses-streaming-http: stream-response: %w", err)
+		return ResponseResponse{}, es.fail(fmt.Errorf("responses-streaming-http: stream-response: %w", err))
 	}
 
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Transfer-Encoding", "chunked")
-	w.WriteHeader(http.StatusOK)
-	f.Flush()
+	es.writeHeaders()
 
 	var lr ResponseResponse
 
@@ -381,7 +384,7 @@ func (ss *streamState) buildInProgressResponse() ResponseResponse {
 		Reasoning:        ResponseReasoning{},
 		Store:            ss.params.Store,
 		Temperature:      ss.params.Temperature,
-		Text:             ResponseTextFormat{Format: ResponseFormatType{Type: "text"}},
+		Text:             ResponseTextFormat{Format: ResponseFormatType{Type: ss.params.TextFormat}},
 		ToolChoice:       ss.params.ToolChoice,
 		Tools:            ss.tools,
 		TopP:             ss.params.TopP,
@@ -658,7 +661,7 @@ func toChatResponseToResponses(chatResp model.ChatResponse, d model.D) ResponseR
 		Model:            chatResp.Model,
 		Output:           outputItems,
 		ParallelToolCall: inputParams.ParallelToolCalls,
-		PrevResponseID:   nil,
+		PrevResponseID:   inputParams.PrevResponseID,
 		Reasoning: ResponseReasoning{
 			Effort:  nil,
 			Summary: reasoningSummary,
@@ -667,7 +670,7 @@ func toChatResponseToResponses(chatResp model.ChatResponse, d model.D) ResponseR
 		Temperature: inputParams.Temperature,
 		Text: ResponseTextFormat{
 			Format: ResponseFormatType{
-				Type: "text",
+				Type: inputParams.TextFormat,
 			},
 		},
 		ToolChoice: inputParams.ToolChoice,
@@ -677,7 +680,7 @@ func toChatResponseToResponses(chatResp model.ChatResponse, d model.D) ResponseR
 		Usage: ResponseUsage{
 			InputTokens: chatResp.Usage.PromptTokens,
 			InputTokensDetails: InputTokensDetails{
-				CachedTokens: 0,
+				CachedTokens: chatResp.Usage.PromptTokensDetails.CachedTokens,
 			},
 			OutputTokens: chatResp.Usage.CompletionTokens,
 			OutputTokenDetail: OutputTokensDetails{
@@ -687,6 +690,7 @@ func toChatResponseToResponses(chatResp model.ChatResponse, d model.D) ResponseR
 		},
 		User:     nil,
 		Metadata: map[string]interface{}{},
+		Seed:     chatResp.Seed,
 	}
 }
 
@@ -740,6 +744,8 @@ type inputParams struct {
 	ParallelToolCalls bool
 	Store             bool
 	Instructions      *string
+	TextFormat        string
+	PrevResponseID    *string
 }
 
 func extractInputParams(d model.D) inputParams {
@@ -750,6 +756,7 @@ func extractInputParams(d model.D) inputParams {
 		Truncation:        "disabled",
 		ParallelToolCalls: true,
 		Store:             true,
+		TextFormat:        "text",
 	}
 
 	if v, ok := d["temperature"].(float64); ok {
@@ -784,6 +791,16 @@ func extractInputParams(d model.D) inputParams {
 		params.Instructions = &v
 	}
 
+	if v, ok := d["previous_response_id"].(string); ok && v != "" {
+		params.PrevResponseID = &v
+	}
+
+	if format, ok := textFormat(d); ok {
+		if v, ok := format["type"].(string); ok {
+			params.TextFormat = v
+		}
+	}
+
 	return params
 }
 
@@ -822,17 +839,64 @@ func convertInputToMessages(d model.D) model.D {
 	return d
 }
 
-func inputToMessages(input any) []model.D {
-	inputItems, ok := input.([]any)
+// convertTextFormat maps the Responses API text.format field onto the
+// response_format field the model understands.
+func convertTextFormat(d model.D) model.D {
+	if _, exists := d["response_format"]; exists {
+		return d
+	}
+
+	format, ok := textFormat(d)
 	if !ok {
-		if str, ok := input.(string); ok {
-			return []model.D{
-				{"role": "user", "content": str},
-			}
+		return d
+	}
+
+	d["response_format"] = format
+
+	return d
+}
+
+func textFormat(d model.D) (map[string]any, bool) {
+	var text map[string]any
+
+	switch v := d["text"].(type) {
+	case model.D:
+		text = v
+	case map[string]any:
+		text = v
+	default:
+		return nil, false
+	}
+
+	switch v := text["format"].(type) {
+	case model.D:
+		return v, true
+	case map[string]any:
+		return v, true
+	}
+
+	return nil, false
+}
+
+func inputToMessages(input any) []model.D {
+	var inputItems []any
+
+	switch v := input.(type) {
+	case string:
+		return []model.D{
+			{"role": "user", "content": v},
 		}
-		if docs, ok := input.([]model.D); ok {
-			return docs
+
+	case []any:
+		inputItems = v
+
+	case []model.D:
+		inputItems = make([]any, len(v))
+		for i, doc := range v {
+			inputItems[i] = doc
 		}
+
+	default:
 		return nil
 	}
 
@@ -840,28 +904,18 @@ func inputToMessages(input any) []model.D {
 		return nil
 	}
 
-	firstItem, ok := inputItems[0].(map[string]any)
-	if !ok {
-		if firstDoc, ok := inputItems[0].(model.D); ok {
-			firstItem = firstDoc
-		} else {
-			return nil
-		}
-	}
+	// Input parts are collected into a user message until an item that is a
+	// message of its own shows up.
+	var messages []model.D
+	var content []model.D
 
-	if _, hasRole := firstItem["role"]; hasRole {
-		var messages []model.D
-		for _, item := range inputItems {
-			if itemMap, ok := item.(map[string]any); ok {
-				messages = append(messages, model.D(itemMap))
-			} else if itemDoc, ok := item.(model.D); ok {
-				messages = append(messages, itemDoc)
-			}
+	flush := func() {
+		if len(content) > 0 {
+			messages = append(messages, model.D{"role": "user", "content": content})
+			content = nil
 		}
-		return messages
 	}
 
-	var content []model.D
 	for _, item := range inputItems {
 		var itemMap map[string]any
 		if m, ok := item.(map[string]any); ok {
@@ -872,12 +926,19 @@ func inputToMessages(input any) []model.D {
 			continue
 		}
 
+		if _, hasRole := itemMap["role"]; hasRole {
+			flush()
+			messages = append(messages, model.D(itemMap))
+			continue
+		}
+
 		switch itemMap["type"] {
 		case "input_text":
 			content = append(content, model.D{
 				"type": "text",
 				"text": itemMap["text"],
 			})
+
 		case "input_image":
 			content = append(content, model.D{
 				"type": "image_url",
@@ -885,10 +946,91 @@ func inputToMessages(input any) []model.D {
 					"url": itemMap["image_url"],
 				},
 			})
+
+		case "function_call":
+			flush()
+			callID, _ := itemMap["call_id"].(string)
+			name, _ := itemMap["name"].(string)
+			args, _ := itemMap["arguments"].(string)
+			messages = appendToolCall(messages, callID, name, args)
+
+		case "function_call_output":
+			flush()
+			messages = append(messages, model.D{
+				"role":         "tool",
+				"tool_call_id": itemMap["call_id"],
+				"content":      itemMap["output"],
+			})
+		}
+	}
+
+	flush()
+
+	return messages
+}
+
+// ResponseInputMessages converts the input of a Responses API request into
+// chat messages.
+func ResponseInputMessages(input any) []model.D {
+	return inputToMessages(input)
+}
+
+// ResponseOutputMessages converts the output items of a response into the
+// chat messages of the assistant, so a later request can continue the
+// conversation.
+func ResponseOutputMessages(items []ResponseOutputItem) []model.D {
+	var messages []model.D
+
+	for _, item := range items {
+		switch item.Type {
+		case "message":
+			var text strings.Builder
+			for _, c := range item.Content {
+				text.WriteString(c.Text)
+			}
+
+			messages = append(messages, model.D{
+				"role":    model.RoleAssistant,
+				"content": text.String(),
+			})
+
+		case "function_call":
+			messages = appendToolCall(messages, item.CallID, item.Name, item.Arguments)
 		}
 	}
 
-	return []model.D{
-		{"role": "user", "content": content},
+	return messages
+}
+
+// appendToolCall adds the tool call to the assistant message at the end of
+// the messages, so calls made together stay in one message.
+func appendToolCall(messages []model.D, callID string, name string, arguments string) []model.D {
+	var args any = arguments
+	var argsMap map[string]any
+	if err := json.Unmarshal([]byte(arguments), &argsMap); err == nil {
+		args = argsMap
+	}
+
+	toolCall := model.D{
+		"id":   callID,
+		"type": "function",
+		"function": model.D{
+			"name":      name,
+			"arguments": args,
+		},
+	}
+
+	if n := len(messages); n > 0 {
+		last := messages[n-1]
+		if toolCalls, ok := last["tool_calls"].([]model.D); ok && last["role"] == model.RoleAssistant {
+			last["tool_calls"] = append(toolCalls, toolCall)
+			return messages
+		}
 	}
+
+	return append(messages, model.D{
+		"role":       model.RoleAssistant,
+		"content":    "",
+		"tool_calls": []model.D{toolCall},
+	})
 }
@@ -0,0 +1,60 @@
+package kronk
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+func Test_ResponseInputMessages(t *testing.T) {
+	input := []model.D{
+		{"type": "input_text", "text": "Describe the image"},
+		{"type": "input_image", "image_url": "data:image/png;base64,AAAA"},
+		{"type": "function_call", "call_id": "call_1", "name": "get_weather", "arguments": `{"location":"London"}`},
+		{"type": "function_call", "call_id": "call_2", "name": "get_weather", "arguments": `{"location":"Paris"}`},
+		{"type": "function_call_output", "call_id": "call_1", "output": "Sunny"},
+		{"role": "user", "content": "Thanks"},
+	}
+
+	msgs := ResponseInputMessages(input)
+
+	roles := []string{"user", "assistant", "tool", "user"}
+	if len(msgs) != len(roles) {
+		t.Fatalf("expected %d messages, got %d: %v", len(roles), len(msgs), msgs)
+	}
+
+	for i, role := range roles {
+		if msgs[i]["role"] != role {
+			t.Errorf("message %d: expected role %s, got %v", i, role, msgs[i]["role"])
+		}
+	}
+
+	if content, ok := msgs[0]["content"].([]model.D); !ok || len(content) != 2 {
+		t.Errorf("expected the text and image parts in the user message, got %v", msgs[0]["content"])
+	}
+
+	toolCalls, ok := msgs[1]["tool_calls"].([]model.D)
+	if !ok || len(toolCalls) != 2 {
+		t.Fatalf("expected both tool calls in one assistant message, got %v", msgs[1])
+	}
+
+	fn := toolCalls[0]["function"].(model.D)
+	if args, ok := fn["arguments"].(map[string]any); !ok || args["location"] != "London" {
+		t.Errorf("expected the arguments as an object, got %v", fn["arguments"])
+	}
+
+	if msgs[2]["tool_call_id"] != "call_1" || msgs[2]["content"] != "Sunny" {
+		t.Errorf("unexpected tool message: %v", msgs[2])
+	}
+}
+
+func Test_ResponseOutputMessages(t *testing.T) {
+	items := []ResponseOutputItem{
+		{Type: "message", Content: []ResponseContentItem{{Type: "output_text", Text: "Hello"}}},
+	}
+
+	msgs := ResponseOutputMessages(items)
+	if len(msgs) != 1 || msgs[0]["role"] != model.RoleAssistant || msgs[0]["content"] != "Hello" {
+		t.Errorf("unexpected messages: %v", msgs)
+	}
+}
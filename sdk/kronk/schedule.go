@@ -0,0 +1,429 @@
+package kronk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+// ErrQueueFull is returned when a subject has too many requests waiting for
+// a slot.
+var ErrQueueFull = errors.New("queue full")
+
+// ErrStreamStarted is returned by the streaming http calls when a request
+// fails after the headers of the event stream were written. The error was
+// sent to the client as an error event, so no other response can be written.
+var ErrStreamStarted = errors.New("event stream started")
+
+// Priority represents the class a request is scheduled in. Requests in a
+// higher class are given a slot before the requests in a lower class.
+type Priority int
+
+// Set of priority classes.
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+// ParsePriority parses the name of a priority class. An empty name is the
+// normal class.
+func ParsePriority(name string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "low":
+		return PriorityLow, nil
+	case "", "normal":
+		return PriorityNormal, nil
+	case "high":
+		return PriorityHigh, nil
+	}
+
+	return PriorityNormal, fmt.Errorf("parse-priority: unknown priority[%s], use low, normal or high", name)
+}
+
+// String implements the fmt.Stringer interface.
+func (p Priority) String() string {
+	switch {
+	case p < PriorityNormal:
+		return "low"
+	case p > PriorityNormal:
+		return "high"
+	}
+
+	return "normal"
+}
+
+// =============================================================================
+
+// Schedule represents how a request is scheduled while it waits for a slot.
+// Within a priority class, the slots are shared fairly across the subjects
+// by taking turns. Waiting is called while the request waits with its
+// position in the queue and an estimate of the time until it gets a slot.
+type Schedule struct {
+	Subject  string
+	Priority Priority
+	Waiting  func(position int, eta time.Duration)
+}
+
+type scheduleKey struct{}
+
+// SetSchedule returns a context that carries the schedule for the requests
+// made with it. The batch engine uses the same schedule to order the requests
+// that were let in and wait for a slot of their own.
+func SetSchedule(ctx context.Context, s Schedule) context.Context {
+	ctx = model.SetSchedule(ctx, int(s.Priority), s.Subject)
+	return context.WithValue(ctx, scheduleKey{}, s)
+}
+
+func getSchedule(ctx context.Context) Schedule {
+	s, _ := ctx.Value(scheduleKey{}).(Schedule)
+	return s
+}
+
+// =============================================================================
+
+// eventStream writes the headers of a server-sent event stream once. While a
+// streaming request waits for a slot, the client is told its place in the
+// queue with comment lines, which clients ignore. So the headers can be
+// written before the request has a slot, and a wait that fails after that
+// is reported with an error event.
+type eventStream struct {
+	w       http.ResponseWriter
+	f       http.Flusher
+	once    sync.Once
+	started bool
+}
+
+func (es *eventStream) writeHeaders() {
+	es.once.Do(func() {
+		es.w.Header().Set("Content-Type", "text/event-stream")
+		es.w.Header().Set("Transfer-Encoding", "chunked")
+		es.w.WriteHeader(http.StatusOK)
+		es.f.Flush()
+		es.started = true
+	})
+}
+
+// fail returns the error of a request that couldn't start streaming. If the
+// headers were already written, the error is sent as an error event and the
+// returned error wraps ErrStreamStarted.
+func (es *eventStream) fail(err error) error {
+	if !es.started {
+		return err
+	}
+
+	data, _ := json.Marshal(map[string]any{
+		"type": "error",
+		"error": map[string]string{
+			"message": err.Error(),
+		},
+	})
+
+	fmt.Fprintf(es.w, "event: error\ndata: %s\n\n", data)
+	es.f.Flush()
+
+	return fmt.Errorf("%w: %w", ErrStreamStarted, err)
+}
+
+// reportWaiting returns a context whose schedule reports the place in the
+// queue to the client.
+func (es *eventStream) reportWaiting(ctx context.Context) context.Context {
+	sch := getSchedule(ctx)
+	next := sch.Waiting
+
+	sch.Waiting = func(position int, eta time.Duration) {
+		es.writeHeaders()
+
+		fmt.Fprintf(es.w, ": queued position=%d eta=%s\n", position, eta.Round(time.Second))
+		es.f.Flush()
+
+		if next != nil {
+			next(position, eta)
+		}
+	}
+
+	return SetSchedule(ctx, sch)
+}
+
+// =============================================================================
+
+// waitingInterval is how often a waiting request is told its position.
+const waitingInterval = time.Second
+
+type waiter struct {
+	subject string
+	ready   chan struct{}
+	granted bool
+}
+
+// class holds the requests waiting in a priority class. The subjects take
+// turns in the order of the turns slice.
+type class struct {
+	priority Priority
+	turns    []string
+	queues   map[string][]*waiter
+}
+
+// scheduler hands out a fixed number of slots. When all slots are in use,
+// requests wait in their priority class and the subjects in a class take
+// turns, so one subject with many requests doesn't starve the others.
+type scheduler struct {
+	mu            sync.Mutex
+	slots         int
+	inUse         int
+	maxPerSubject int
+	classes       []*class
+	avgHold       time.Duration
+}
+
+func newScheduler(slots int, maxPerSubject int) *scheduler {
+	return &scheduler{
+		slots:         slots,
+		maxPerSubject: maxPerSubject,
+	}
+}
+
+// acquire waits for a slot. The returned function gives the slot back.
+func (s *scheduler) acquire(ctx context.Context, sch Schedule) (func(), error) {
+	s.mu.Lock()
+
+	if s.inUse < s.slots && s.waiting() == 0 {
+		s.inUse++
+		s.mu.Unlock()
+		return s.releaseFunc(), nil
+	}
+
+	c := s.class(sch.Priority)
+
+	if s.maxPerSubject > 0 && len(c.queues[sch.Subject]) >= s.maxPerSubject {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("acquire: subject[%s] has %d requests waiting: %w", sch.Subject, s.maxPerSubject, ErrQueueFull)
+	}
+
+	w := waiter{
+		subject: sch.Subject,
+		ready:   make(chan struct{}),
+	}
+
+	if _, exists := c.queues[sch.Subject]; !exists {
+		c.turns = append(c.turns, sch.Subject)
+	}
+	c.queues[sch.Subject] = append(c.queues[sch.Subject], &w)
+
+	s.mu.Unlock()
+
+	// -------------------------------------------------------------------------
+
+	var tick <-chan time.Time
+	if sch.Waiting != nil {
+		sch.Waiting(s.position(c, &w))
+
+		ticker := time.NewTicker(waitingInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-w.ready:
+			return s.releaseFunc(), nil
+
+		case <-tick:
+			sch.Waiting(s.position(c, &w))
+
+		case <-ctx.Done():
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			// The slot may have been granted while the context was
+			// canceled, in which case it's handed to the next request.
+			if w.granted {
+				s.inUse--
+				s.grant()
+				return nil, ctx.Err()
+			}
+
+			s.remove(c, &w)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *scheduler) releaseFunc() func() {
+	start := time.Now()
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// Keep a moving average of how long a slot is held for the
+		// waiting estimates.
+		hold := time.Since(start)
+		switch s.avgHold {
+		case 0:
+			s.avgHold = hold
+		default:
+			s.avgHold = (s.avgHold*4 + hold) / 5
+		}
+
+		s.inUse--
+		s.grant()
+	}
+}
+
+// grant hands the free slots to the waiting requests, highest class first
+// and taking turns between the subjects in a class.
+func (s *scheduler) grant() {
+	for s.inUse < s.slots {
+		w := s.next()
+		if w == nil {
+			return
+		}
+
+		s.inUse++
+		w.granted = true
+		close(w.ready)
+	}
+}
+
+func (s *scheduler) next() *waiter {
+	for _, c := range s.classes {
+		if len(c.turns) == 0 {
+			continue
+		}
+
+		subject := c.turns[0]
+		queue := c.queues[subject]
+		w := queue[0]
+
+		c.turns = c.turns[1:]
+
+		switch len(queue) {
+		case 1:
+			delete(c.queues, subject)
+		default:
+			c.queues[subject] = queue[1:]
+			c.turns = append(c.turns, subject)
+		}
+
+		return w
+	}
+
+	return nil
+}
+
+func (s *scheduler) remove(c *class, w *waiter) {
+	queue := c.queues[w.subject]
+
+	for i, qw := range queue {
+		if qw != w {
+			continue
+		}
+
+		queue = append(queue[:i:i], queue[i+1:]...)
+		break
+	}
+
+	if len(queue) > 0 {
+		c.queues[w.subject] = queue
+		return
+	}
+
+	delete(c.queues, w.subject)
+	for i, subject := range c.turns {
+		if subject == w.subject {
+			c.turns = append(c.turns[:i:i], c.turns[i+1:]...)
+			break
+		}
+	}
+}
+
+// class returns the queue for the priority, keeping the classes ordered from
+// the highest priority to the lowest.
+func (s *scheduler) class(p Priority) *class {
+	for i, c := range s.classes {
+		switch {
+		case c.priority == p:
+			return c
+
+		case c.priority < p:
+			nc := class{priority: p, queues: make(map[string][]*waiter)}
+			s.classes = append(s.classes[:i], append([]*class{&nc}, s.classes[i:]...)...)
+			return &nc
+		}
+	}
+
+	nc := class{priority: p, queues: make(map[string][]*waiter)}
+	s.classes = append(s.classes, &nc)
+
+	return &nc
+}
+
+func (s *scheduler) waiting() int {
+	var n int
+	for _, c := range s.classes {
+		for _, queue := range c.queues {
+			n += len(queue)
+		}
+	}
+
+	return n
+}
+
+// position returns the place of the request in the queue and an estimate of
+// when it gets a slot. The place counts every request in a higher class and,
+// as the subjects take turns, the requests of other subjects in the same class
+// that get a turn first.
+func (s *scheduler) position(c *class, w *waiter) (int, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w.granted {
+		return 0, 0
+	}
+
+	var ahead int
+	for _, hc := range s.classes {
+		if hc == c {
+			break
+		}
+
+		for _, queue := range hc.queues {
+			ahead += len(queue)
+		}
+	}
+
+	var turn int
+	for i, qw := range c.queues[w.subject] {
+		if qw == w {
+			turn = i
+			break
+		}
+	}
+
+	// The subjects before this one in the turns get one more turn.
+	ahead += turn
+	before := true
+	for _, subject := range c.turns {
+		switch {
+		case subject == w.subject:
+			before = false
+		case before:
+			ahead += min(len(c.queues[subject]), turn+1)
+		default:
+			ahead += min(len(c.queues[subject]), turn)
+		}
+	}
+
+	position := ahead + 1
+	eta := s.avgHold * time.Duration((position+s.slots-1)/s.slots)
+
+	return position, eta
+}
@@ -0,0 +1,242 @@
+package kronk
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type grant struct {
+	name    string
+	release func()
+}
+
+// enqueue starts a request that waits for a slot and blocks until the request
+// is in the queue, so the requests queue in the order they're enqueued.
+func enqueue(t *testing.T, s *scheduler, name string, sch Schedule, granted chan<- grant) {
+	t.Helper()
+
+	s.mu.Lock()
+	n := s.waiting()
+	s.mu.Unlock()
+
+	go func() {
+		release, err := s.acquire(context.Background(), sch)
+		if err != nil {
+			t.Errorf("%s: acquire: %s", name, err)
+			return
+		}
+		granted <- grant{name: name, release: release}
+	}()
+
+	for {
+		s.mu.Lock()
+		queued := s.waiting() > n
+		s.mu.Unlock()
+
+		if queued {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func grantOrder(t *testing.T, s *scheduler, first func(), granted <-chan grant, n int) []string {
+	t.Helper()
+
+	first()
+
+	var order []string
+	for range n {
+		select {
+		case g := <-granted:
+			order = append(order, g.name)
+			g.release()
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for a grant, got %v", order)
+		}
+	}
+
+	return order
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func Test_Scheduler(t *testing.T) {
+	t.Run("priority", func(t *testing.T) {
+		s := newScheduler(1, 0)
+
+		first, err := s.acquire(context.Background(), Schedule{})
+		if err != nil {
+			t.Fatalf("acquire: %s", err)
+		}
+
+		granted := make(chan grant)
+		enqueue(t, s, "low", Schedule{Subject: "a", Priority: PriorityLow}, granted)
+		enqueue(t, s, "normal", Schedule{Subject: "b", Priority: PriorityNormal}, granted)
+		enqueue(t, s, "high", Schedule{Subject: "c", Priority: PriorityHigh}, granted)
+
+		order := grantOrder(t, s, first, granted, 3)
+		if exp := []string{"high", "normal", "low"}; !equal(order, exp) {
+			t.Errorf("expected %v, got %v", exp, order)
+		}
+	})
+
+	t.Run("fair share", func(t *testing.T) {
+		s := newScheduler(1, 0)
+
+		first, err := s.acquire(context.Background(), Schedule{})
+		if err != nil {
+			t.Fatalf("acquire: %s", err)
+		}
+
+		granted := make(chan grant)
+		enqueue(t, s, "batch-1", Schedule{Subject: "batch"}, granted)
+		enqueue(t, s, "batch-2", Schedule{Subject: "batch"}, granted)
+		enqueue(t, s, "batch-3", Schedule{Subject: "batch"}, granted)
+		enqueue(t, s, "user-1", Schedule{Subject: "user"}, granted)
+
+		order := grantOrder(t, s, first, granted, 4)
+		if exp := []string{"batch-1", "user-1", "batch-2", "batch-3"}; !equal(order, exp) {
+			t.Errorf("expected %v, got %v", exp, order)
+		}
+	})
+
+	t.Run("position", func(t *testing.T) {
+		s := newScheduler(1, 0)
+
+		first, err := s.acquire(context.Background(), Schedule{})
+		if err != nil {
+			t.Fatalf("acquire: %s", err)
+		}
+
+		granted := make(chan grant)
+		enqueue(t, s, "batch-1", Schedule{Subject: "batch"}, granted)
+		enqueue(t, s, "batch-2", Schedule{Subject: "batch"}, granted)
+
+		positions := make(chan int, 10)
+		waiting := func(position int, eta time.Duration) {
+			positions <- position
+		}
+
+		enqueue(t, s, "user-1", Schedule{Subject: "user", Waiting: waiting}, granted)
+
+		if position := <-positions; position != 2 {
+			t.Errorf("expected position 2, got %d", position)
+		}
+
+		grantOrder(t, s, first, granted, 3)
+	})
+
+	t.Run("queue limit", func(t *testing.T) {
+		s := newScheduler(1, 1)
+
+		first, err := s.acquire(context.Background(), Schedule{})
+		if err != nil {
+			t.Fatalf("acquire: %s", err)
+		}
+
+		granted := make(chan grant)
+		enqueue(t, s, "batch-1", Schedule{Subject: "batch"}, granted)
+
+		if _, err := s.acquire(context.Background(), Schedule{Subject: "batch"}); !errors.Is(err, ErrQueueFull) {
+			t.Errorf("expected ErrQueueFull, got %v", err)
+		}
+
+		grantOrder(t, s, first, granted, 1)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		s := newScheduler(1, 0)
+
+		first, err := s.acquire(context.Background(), Schedule{})
+		if err != nil {
+			t.Fatalf("acquire: %s", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := s.acquire(ctx, Schedule{Subject: "batch"}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected a deadline error, got %v", err)
+		}
+
+		first()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.inUse != 0 || s.waiting() != 0 {
+			t.Errorf("expected no slots in use or waiting, got in-use[%d] waiting[%d]", s.inUse, s.waiting())
+		}
+	})
+}
+
+func Test_EventStream(t *testing.T) {
+	t.Run("fail before headers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		es := eventStream{w: w, f: w}
+
+		err := errors.New("failed")
+		if got := es.fail(err); got != err {
+			t.Errorf("expected the error as is, got %v", got)
+		}
+
+		if w.Body.Len() != 0 {
+			t.Errorf("expected nothing written, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("fail while waiting", func(t *testing.T) {
+		s := newScheduler(1, 0)
+
+		first, err := s.acquire(context.Background(), Schedule{})
+		if err != nil {
+			t.Fatalf("acquire: %s", err)
+		}
+		defer first()
+
+		w := httptest.NewRecorder()
+		es := eventStream{w: w, f: w}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		ctx = es.reportWaiting(ctx)
+
+		_, err = s.acquire(ctx, getSchedule(ctx))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a deadline error, got %v", err)
+		}
+
+		err = es.fail(err)
+		if !errors.Is(err, ErrStreamStarted) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected ErrStreamStarted wrapping the deadline error, got %v", err)
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("expected an event stream, got %q", ct)
+		}
+
+		if body := w.Body.String(); !strings.Contains(body, ": queued position=1") || !strings.Contains(body, "event: error\ndata: ") {
+			t.Errorf("expected the queue position and an error event, got %q", body)
+		}
+	})
+}
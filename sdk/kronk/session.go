@@ -0,0 +1,41 @@
+package kronk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+)
+
+// SaveSession writes the KV cache state and tokens held for the session to
+// disk under the configured SessionDir. A later request that provides the same
+// session_id restores this state instead of processing the prompt again, even
+// after the model has been unloaded or the process restarted.
+func (krn *Kronk) SaveSession(ctx context.Context, sessionID string) error {
+	if _, exists := ctx.Deadline(); !exists {
+		return fmt.Errorf("save-session: context has no deadline, provide a reasonable timeout")
+	}
+
+	f := func(m *model.Model) (struct{}, error) {
+		return struct{}{}, m.SaveSession(ctx, sessionID)
+	}
+
+	_, err := nonStreaming(ctx, krn, f)
+
+	return err
+}
+
+// DeleteSession removes the saved state for the session.
+func (krn *Kronk) DeleteSession(ctx context.Context, sessionID string) error {
+	if _, exists := ctx.Deadline(); !exists {
+		return fmt.Errorf("delete-session: context has no deadline, provide a reasonable timeout")
+	}
+
+	f := func(m *model.Model) (struct{}, error) {
+		return struct{}{}, m.DeleteSession(ctx, sessionID)
+	}
+
+	_, err := nonStreaming(ctx, krn, f)
+
+	return err
+}
@@ -0,0 +1,176 @@
+package kronk_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kronk/sdk/kronk"
+	"github.com/ardanlabs/kronk/sdk/kronk/model"
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+var dCompletion = model.D{
+	"prompt":      []any{"The capital of France is", "The capital of Japan is"},
+	"echo":        true,
+	"max_tokens":  16,
+	"temperature": 0.0,
+}
+
+func testCompletion(t *testing.T, krn *kronk.Kronk) {
+	if runInParallel {
+		t.Parallel()
+	}
+
+	f := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+		defer cancel()
+
+		id := uuid.New().String()
+		now := time.Now()
+		defer func() {
+			done := time.Now()
+			t.Logf("%s: %s, st: %v, en: %v, Duration: %s", id, krn.ModelInfo().ID, now.Format("15:04:05.000"), done.Format("15:04:05.000"), done.Sub(now))
+		}()
+
+		resp, err := krn.Completion(ctx, dCompletion.Clone())
+		if err != nil {
+			return fmt.Errorf("completion: %w", err)
+		}
+
+		if err := testCompletionResponse(resp, krn.ModelInfo().ID); err != nil {
+			t.Logf("%#v", resp)
+			return err
+		}
+
+		return nil
+	}
+
+	var g errgroup.Group
+	for range goroutines {
+		g.Go(f)
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("error: %v", err)
+	}
+}
+
+func testCompletionStreaming(t *testing.T, krn *kronk.Kronk) {
+	if runInParallel {
+		t.Parallel()
+	}
+
+	f := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+		defer cancel()
+
+		id := uuid.New().String()
+		now := time.Now()
+		defer func() {
+			done := time.Now()
+			t.Logf("%s: %s, st: %v, en: %v, Duration: %s", id, krn.ModelInfo().ID, now.Format("15:04:05.000"), done.Format("15:04:05.000"), done.Sub(now))
+		}()
+
+		ch, err := krn.CompletionStreaming(ctx, dCompletion.Clone())
+		if err != nil {
+			return fmt.Errorf("completion streaming: %w", err)
+		}
+
+		texts := make([]strings.Builder, 2)
+		finished := make([]string, 2)
+
+		var lastResp kronk.CompletionResponse
+		for resp := range ch {
+			if len(resp.Choices) != 1 {
+				return fmt.Errorf("expected 1 choice per chunk, got %d", len(resp.Choices))
+			}
+
+			c := resp.Choices[0]
+			if c.FinishReason == model.FinishReasonError {
+				return fmt.Errorf("completion streaming: %s", c.Text)
+			}
+
+			if c.Index < 0 || c.Index > 1 {
+				return fmt.Errorf("expected choice index 0 or 1, got %d", c.Index)
+			}
+
+			texts[c.Index].WriteString(c.Text)
+			if c.FinishReason != "" {
+				finished[c.Index] = c.FinishReason
+			}
+
+			lastResp = resp
+		}
+
+		resp := lastResp
+		resp.Choices = nil
+		for i := range texts {
+			resp.Choices = append(resp.Choices, kronk.CompletionChoice{
+				Text:         texts[i].String(),
+				Index:        i,
+				FinishReason: finished[i],
+			})
+		}
+
+		if err := testCompletionResponse(resp, krn.ModelInfo().ID); err != nil {
+			t.Logf("%#v", resp)
+			return err
+		}
+
+		return nil
+	}
+
+	var g errgroup.Group
+	for range goroutines {
+		g.Go(f)
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("error: %v", err)
+	}
+}
+
+func testCompletionResponse(resp kronk.CompletionResponse, modelID string) error {
+	if resp.Object != model.ObjectTextCompletion {
+		return fmt.Errorf("expected object %s, got %s", model.ObjectTextCompletion, resp.Object)
+	}
+
+	if resp.Model != modelID {
+		return fmt.Errorf("expected model %s, got %s", modelID, resp.Model)
+	}
+
+	if len(resp.Choices) != 2 {
+		return fmt.Errorf("expected 2 choices, got %d", len(resp.Choices))
+	}
+
+	prompts := []string{"The capital of France is", "The capital of Japan is"}
+	answers := []string{"Paris", "Tokyo"}
+
+	for i, c := range resp.Choices {
+		if c.Index != i {
+			return fmt.Errorf("choice %d: expected index %d, got %d", i, i, c.Index)
+		}
+
+		if c.FinishReason == "" {
+			return fmt.Errorf("choice %d: expected a finish reason", i)
+		}
+
+		if !strings.HasPrefix(c.Text, prompts[i]) {
+			return fmt.Errorf("choice %d: expected the prompt to be echoed, got %q", i, c.Text)
+		}
+
+		if !strings.Contains(c.Text, answers[i]) {
+			return fmt.Errorf("choice %d: expected %q in the text, got %q", i, answers[i], c.Text)
+		}
+	}
+
+	if resp.Usage.PromptTokens == 0 || resp.Usage.CompletionTokens == 0 {
+		return fmt.Errorf("expected prompt and completion tokens in the usage, got %+v", resp.Usage)
+	}
+
+	return nil
+}
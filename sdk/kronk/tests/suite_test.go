@@ -26,6 +26,8 @@ func TestSuite(t *testing.T) {
 			t.Run("ThinkStreamingResponse", func(t *testing.T) { testResponseStreaming(t, krn, dResponseNoTool, false) })
 			t.Run("ToolResponse", func(t *testing.T) { testResponse(t, krn, dResponseTool, true) })
 			t.Run("ToolStreamingResponse", func(t *testing.T) { testResponseStreaming(t, krn, dResponseTool, true) })
+			t.Run("Completion", func(t *testing.T) { testCompletion(t, krn) })
+			t.Run("StreamingCompletion", func(t *testing.T) { testCompletionStreaming(t, krn) })
 		})
 	})
 